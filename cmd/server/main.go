@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"io"
 	"io/ioutil"
@@ -13,10 +14,14 @@ import (
 	"time"
 
 	"github.com/sammcj/mcp-graph/internal/api"
+	"github.com/sammcj/mcp-graph/internal/audit"
 	"github.com/sammcj/mcp-graph/internal/config"
+	"github.com/sammcj/mcp-graph/internal/graph"
 	"github.com/sammcj/mcp-graph/internal/graph/neo4j"
 	"github.com/sammcj/mcp-graph/internal/mcp"
 	"github.com/sammcj/mcp-graph/internal/service"
+	"github.com/sammcj/mcp-graph/internal/snapshot"
+	"github.com/sammcj/mcp-graph/internal/usage"
 )
 
 // conditionalLogger is a logger that only logs when enabled
@@ -80,14 +85,49 @@ func main() {
 	logger := newConditionalLogger(cfg.MCP.UseSSE)
 
 	// Initialize graph store
-	graphStore, err := neo4j.NewNeo4jStore(cfg.Neo4j.URI, cfg.Neo4j.Username, cfg.Neo4j.Password)
+	retryPolicy := graph.RetryPolicy{
+		Attempts: cfg.Neo4j.ConnectAttempts,
+		Backoff:  cfg.Neo4j.ConnectBackoff,
+		OnRetry: func(attempt int, err error, wait time.Duration) {
+			logger.Printf("Neo4j not reachable (attempt %d/%d): %v; retrying in %s", attempt, cfg.Neo4j.ConnectAttempts, err, wait)
+		},
+	}
+	var graphStore *neo4j.Neo4jStore
+	if cfg.Neo4j.SchemeFallback {
+		var connectedURI string
+		graphStore, connectedURI, err = neo4j.NewNeo4jStoreWithSchemeFallback(cfg.Neo4j.URI, cfg.Neo4j.Username, cfg.Neo4j.Password, retryPolicy, neo4j.WithMaxConnectionPoolSize(cfg.Neo4j.MaxConnectionPoolSize))
+		if err == nil && connectedURI != cfg.Neo4j.URI {
+			logger.Printf("Routing failed for %s; connected to Neo4j with %s instead. Set neo4j.uri to %s to skip the fallback", cfg.Neo4j.URI, connectedURI, connectedURI)
+		}
+	} else {
+		graphStore, err = neo4j.NewNeo4jStoreWithRetry(cfg.Neo4j.URI, cfg.Neo4j.Username, cfg.Neo4j.Password, retryPolicy, neo4j.WithMaxConnectionPoolSize(cfg.Neo4j.MaxConnectionPoolSize))
+	}
 	if err != nil {
 		log.Fatalf("Failed to connect to Neo4j: %v", err)
 	}
+	graphStore.SetNormalizeRelationshipTypes(cfg.Neo4j.NormalizeRelationshipTypes)
+
+	// Keep batch operations from using more connections than the pool holds
+	batchConcurrency := cfg.Neo4j.BatchConcurrency
+	if poolSize := cfg.Neo4j.MaxConnectionPoolSize; poolSize > 0 && batchConcurrency > poolSize {
+		logger.Printf("Warning: neo4j.batchConcurrency (%d) exceeds neo4j.maxConnectionPoolSize (%d); using %d", batchConcurrency, poolSize, poolSize)
+		batchConcurrency = poolSize
+	}
+	graphStore.SetBatchConcurrency(batchConcurrency)
+	graphStore.SetPropertySizeLimit(cfg.Neo4j.MaxPropertyBytes, cfg.Neo4j.TruncateOversizedProperties)
+	graphStore.SetCoerceTemporalProperties(cfg.Neo4j.CoerceTemporalProperties)
+	graphStore.SetDefaultRelationshipSource(cfg.Neo4j.DefaultRelationshipSource)
+	graphStore.SetAllowedRelationshipTypes(cfg.Neo4j.AllowedRelationshipTypes)
+	graphStore.SetPropertyWhitelist(propertyWhitelist(cfg.Neo4j), cfg.Neo4j.StripUnknownProperties)
+	graphStore.SetIntegrityRules(integrityRules(cfg.Integrity))
+	graphStore.SetLabelTTLs(labelTTLs(cfg.TTL))
 	defer graphStore.Close(context.Background())
 
 	// Create knowledge manager service
 	knowledgeService := service.NewService(graphStore)
+	knowledgeService.SetAssignUUIDs(cfg.Knowledge.AssignUUIDs)
+	knowledgeService.SetAssignTitleKeys(cfg.Knowledge.AssignTitleKeys)
+	knowledgeService.SetPropertyIndexes(propertyIndexes(cfg.Schema))
 
 	// Initialize schema
 	logger.Println("Initialising knowledge graph schema...")
@@ -105,13 +145,51 @@ func main() {
 	// Enable API server logging only in SSE mode
 	apiServer.EnableLogging(cfg.MCP.UseSSE)
 
+	// Protect the API against oversized or slow request bodies
+	apiServer.SetRequestLimits(cfg.API.MaxBodyBytes, cfg.API.ReadTimeout)
+
+	// Hide sensitive property values from API and MCP clients
+	redactor := graph.NewRedactor(cfg.Redaction.Properties)
+	apiServer.SetRedactor(redactor)
+
+	// Record mutating operations in the audit log, if enabled
+	var auditLog *audit.Logger
+	if cfg.Audit.Enabled {
+		var auditCloser io.Closer
+		auditLog, auditCloser, err = audit.Open(cfg.Audit.Path, redactor)
+		if err != nil {
+			log.Fatalf("Failed to open audit log: %v", err)
+		}
+		defer auditCloser.Close()
+		apiServer.SetAuditLogger(auditLog)
+	}
+
+	// Count the calls made by each client, if enabled
+	var usageTracker *usage.Tracker
+	if cfg.Usage.Enabled {
+		usageTracker = usage.NewTracker(cfg.Usage.Window)
+		apiServer.SetUsageTracker(usageTracker, cfg.Usage.ExposeClients)
+	}
+
 	// Create MCP server
 	mcpServer := mcp.NewServer(
 		cfg.App.Name,
 		cfg.App.Version,
 		graphStore,
 	)
+	mcpServer.SetService(knowledgeService)
+	mcpServer.SetRedactor(redactor)
+	mcpServer.SetAuditLogger(auditLog)
+	mcpServer.SetUsageTracker(usageTracker)
+	mcpServer.SetResultSizeWarning(cfg.MCP.ResultSizeWarningBytes, logger)
+	if cfg.Snapshots.Dir != "" {
+		mcpServer.SetSnapshotStore(snapshot.NewStore(cfg.Snapshots.Dir))
+	}
 	mcpServer.SetupTools()
+	mcpServer.SetupResources()
+
+	// Let HTTP clients discover the MCP tools
+	apiServer.SetToolLister(mcpServer)
 
 	// Set up graceful shutdown
 	ctx, cancel := context.WithCancel(context.Background())
@@ -126,6 +204,36 @@ func main() {
 		cancel()
 	}()
 
+	// Watch the database connection in the background, so readiness reflects dropped connections
+	if cfg.Health.CheckInterval > 0 {
+		healthMonitor := graph.NewHealthMonitor(graphStore, cfg.Health.CheckInterval, cfg.Health.FailureThreshold)
+		healthMonitor.OnChange = func(healthy bool, err error) {
+			if healthy {
+				logger.Println("Graph database is reachable again")
+			} else {
+				logger.Printf("Graph database unreachable after %d consecutive health checks: %v", cfg.Health.FailureThreshold, err)
+			}
+		}
+		healthMonitor.OnReconnect = func(err error) {
+			if err != nil {
+				logger.Printf("Failed to reconnect to graph database: %v", err)
+			}
+		}
+		apiServer.SetHealthMonitor(healthMonitor)
+		go healthMonitor.Run(ctx)
+	}
+
+	// Delete expired nodes in the background
+	if cfg.TTL.SweepInterval > 0 {
+		go graph.RunExpirySweeps(ctx, graphStore, nil, cfg.TTL.SweepInterval, func(deleted int, err error) {
+			if err != nil {
+				logger.Printf("Failed to sweep expired nodes: %v", err)
+			} else if deleted > 0 {
+				logger.Printf("Deleted %d expired nodes", deleted)
+			}
+		})
+	}
+
 	// Start API server
 	go func() {
 		logger.Printf("Starting API server on port %d", cfg.API.Port)
@@ -137,10 +245,21 @@ func main() {
 
 	// Start MCP server
 	if cfg.MCP.UseSSE {
+		// Listen before starting the server, so that a port conflict is reported clearly
+		sseListener, sseAddress, err := mcp.ListenSSE(cfg.MCP.Address, cfg.MCP.PortFallbacks)
+		if errors.Is(err, mcp.ErrAddressInUse) {
+			log.Fatalf("Cannot start the MCP SSE server (%v). Stop the process using the port, set mcp.address (MCPGRAPH_MCP_ADDRESS) to a free address, or set mcp.portFallbacks (MCPGRAPH_MCP_PORTFALLBACKS) to try the following ports", err)
+		} else if err != nil {
+			log.Fatalf("Cannot start the MCP SSE server on %s: %v", cfg.MCP.Address, err)
+		}
+		if sseAddress != cfg.MCP.Address {
+			logger.Printf("Warning: %s is already in use; serving MCP over SSE on %s instead. Point MCP clients at the new port", cfg.MCP.Address, sseAddress)
+		}
+
 		// Start SSE server in a goroutine
 		go func() {
-			logger.Printf("Starting MCP SSE server on %s", cfg.MCP.Address)
-			if err := mcpServer.ServeSSE(cfg.MCP.Address); err != nil {
+			logger.Printf("Starting MCP SSE server on %s", sseAddress)
+			if err := mcpServer.ServeSSEListener(sseListener); err != nil {
 				logger.Printf("MCP SSE server error: %v", err)
 				cancel()
 			}
@@ -164,13 +283,15 @@ func main() {
 	// Graceful shutdown
 	logger.Println("Shutting down...")
 
-	// Add a small delay to allow for graceful shutdown
-	time.Sleep(100 * time.Millisecond)
-
 	// Create a shutdown context with timeout
 	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), cfg.Shutdown.Timeout)
 	defer shutdownCancel()
 
+	// Wait for in-flight MCP tool calls to finish before the graph store is closed
+	if err := mcpServer.Drain(shutdownCtx); err != nil {
+		logger.Printf("MCP tool calls did not finish before the shutdown timeout: %v", err)
+	}
+
 	// Shutdown API server
 	if err := apiServer.Shutdown(shutdownCtx); err != nil {
 		logger.Printf("API server shutdown error: %v", err)
@@ -179,6 +300,53 @@ func main() {
 	logger.Println("Shutdown complete")
 }
 
+// integrityRules converts the configured integrity rules into the rules the graph store checks
+func integrityRules(cfg config.IntegrityConfig) graph.IntegrityRules {
+	var rules graph.IntegrityRules
+	for _, r := range cfg.RequiredRelationships {
+		rules.RequiredRelationships = append(rules.RequiredRelationships, graph.RequiredRelationshipRule{
+			Label:            r.Label,
+			RelationshipType: r.RelationshipType,
+			Direction:        r.Direction,
+		})
+	}
+	for _, r := range cfg.RelationshipEndpoints {
+		rules.RelationshipEndpoints = append(rules.RelationshipEndpoints, graph.RelationshipEndpointRule{
+			RelationshipType: r.RelationshipType,
+			StartLabels:      r.StartLabels,
+			EndLabels:        r.EndLabels,
+		})
+	}
+	return rules
+}
+
+// propertyWhitelist converts the configured property whitelists into a map from label to allowed property keys
+func propertyWhitelist(cfg config.Neo4jConfig) map[string][]string {
+	whitelist := make(map[string][]string, len(cfg.PropertyWhitelist))
+	for _, entry := range cfg.PropertyWhitelist {
+		whitelist[entry.Label] = append(whitelist[entry.Label], entry.Properties...)
+	}
+	return whitelist
+}
+
+// labelTTLs converts the configured label TTLs into a map from label to TTL
+func labelTTLs(cfg config.TTLConfig) map[string]time.Duration {
+	ttls := make(map[string]time.Duration, len(cfg.Labels))
+	for _, entry := range cfg.Labels {
+		ttls[entry.Label] = entry.TTL
+	}
+	return ttls
+}
+
+// propertyIndexes converts the configured startup indexes into the indexes the knowledge service creates
+func propertyIndexes(cfg config.SchemaConfig) []service.PropertyIndex {
+	indexes := make([]service.PropertyIndex, 0, len(cfg.Indexes))
+	for _, index := range cfg.Indexes {
+		indexes = append(indexes, service.PropertyIndex{Label: index.Label, Properties: index.Properties})
+	}
+	return indexes
+}
+
 // createDefaultConfig creates a default config file at the specified path
 func createDefaultConfig(path string) error {
 	// Create a basic config file with default values
@@ -190,21 +358,94 @@ app:
 # API settings
 api:
   port: 8080
+  maxBodyBytes: 10485760 # Requests with larger bodies are rejected with 413
+  readTimeout: 15s
 
 # Neo4j settings
 neo4j:
   uri: bolt://localhost:7687
   username: ""
   password: ""
+  normalizeRelationshipTypes: false # Convert relationship types to UPPER_SNAKE_CASE (e.g. dependsOn -> DEPENDS_ON)
+  connectAttempts: 10 # Connection attempts at startup before giving up
+  connectBackoff: 1s # Wait between attempts, doubling each time up to 30s
+  schemeFallback: true # On a routing failure, retry with bolt:// for neo4j:// URIs and vice versa
+  maxConnectionPoolSize: 100 # Maximum open connections to Neo4j
+  batchConcurrency: 10 # Entities or relationships processed at once by batch tools; capped at maxConnectionPoolSize
+  maxPropertyBytes: 0 # Largest string property value nodes may be written with; 0 is unlimited
+  truncateOversizedProperties: false # Truncate oversized values instead of rejecting the write
+  coerceTemporalProperties: false # Store ISO-8601 strings given to find_or_create tools as Neo4j dates/datetimes
+  defaultRelationshipSource: manual # Source recorded on new relationships that don't give one, e.g. agent-inference
+  allowedRelationshipTypes: [] # Relationship types that may be created, e.g. [CALLS, DEPENDS_ON]; any type if empty
+  # Property keys nodes with a label may have, e.g.
+  # - {label: Function, properties: [name, filePath, signature, description]}
+  # Labels that aren't listed are unrestricted
+  propertyWhitelist: []
+  stripUnknownProperties: false # Drop properties not on the whitelist instead of rejecting the write
 
 # MCP settings
 mcp:
   useSSE: true
   address: :3000
+  resultSizeWarningBytes: 1048576 # Log a warning when a tool result is larger than this; 0 disables
+  portFallbacks: 0 # If the address is in use, try this many following ports; 0 exits with an error instead
 
 # Shutdown settings
 shutdown:
-  timeout: 5s
+  timeout: 5s # How long to wait for in-flight MCP tool calls and API requests to finish
+
+# Redaction settings
+redaction:
+  # Property keys whose values are replaced with "***REDACTED***" in API and MCP responses.
+  # Patterns use glob syntax and are case-insensitive, e.g. ["password", "*secret*", "connectionString"]
+  properties: []
+
+# Knowledge settings
+knowledge:
+  assignUUIDs: false # Give new documents and concepts a uuid property that survives export and reimport
+  assignTitleKeys: false # Give documents a lowercased, whitespace-trimmed titleKey used by find_or_create_document
+
+# Audit settings
+audit:
+  enabled: false # Record every mutating MCP tool call and API request as a JSON line
+  path: "" # File to append the audit log to; stderr if empty
+
+# Usage settings
+usage:
+  enabled: false # Count the tool calls and API requests made by each client, reported by usage_stats
+  window: 24h # How long counts are kept, and the longest window usage_stats reports on
+  exposeClients: false # Report each client's requests from GET /api/v1/usage, which is unauthenticated
+
+# Snapshot settings
+snapshots:
+  dir: "" # Directory snapshot_subgraph saves snapshots in (e.g. snapshots); snapshots are disabled if empty
+
+# Health check settings
+health:
+  checkInterval: 30s # How often to ping the database in the background; 0 disables the check
+  failureThreshold: 3 # Consecutive failed pings before the database is reported unavailable
+
+# Integrity rules checked by check_integrity
+integrity:
+  # Every node with the label must have a relationship of the type (direction: outgoing, incoming or both), e.g.
+  # - {label: Function, relationshipType: DEFINED_IN}
+  requiredRelationships: []
+  # Relationships of the type must start at a node with one of startLabels and end at one with one of endLabels, e.g.
+  # - {relationshipType: DEFINED_IN, endLabels: [File]}
+  relationshipEndpoints: []
+
+# Schema settings
+schema:
+  # Properties to index at startup in addition to the built-in indexes, e.g.
+  # - {label: Function, properties: [filePath, name]}
+  indexes: []
+
+# Expiry settings for short-lived nodes
+ttl:
+  # New nodes with the label get an expiresAt property this long after creation, e.g.
+  # - {label: Annotation, ttl: 168h}
+  labels: []
+  sweepInterval: 0 # How often to delete expired nodes in the background; 0 disables the sweep (use sweep_expired)
 `
 	// Create the file
 	return os.WriteFile(path, []byte(configContent), 0644)
@@ -0,0 +1,83 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/sammcj/mcp-graph/internal/audit"
+)
+
+// auditRequestID numbers audited API requests so that started entries can be matched with their outcome
+var auditRequestID atomic.Int64
+
+// SetAuditLogger sets the audit log that mutating (POST, PUT and DELETE) requests are recorded in
+func (s *Server) SetAuditLogger(auditLog *audit.Logger) {
+	s.auditLog = auditLog
+}
+
+// statusRecorder records the status code written by a handler
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+// WriteHeader records the status code before writing it
+func (r *statusRecorder) WriteHeader(code int) {
+	r.status = code
+	r.ResponseWriter.WriteHeader(code)
+}
+
+// auditMiddleware records mutating requests, with their JSON bodies as arguments, in the audit log
+func (s *Server) auditMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !s.auditLog.Enabled() || !isMutatingMethod(r.Method) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		// Read the body so it can be recorded, then hand an identical copy to the handler
+		var arguments interface{}
+		body, err := io.ReadAll(r.Body)
+		if err == nil {
+			_ = json.Unmarshal(body, &arguments)
+		}
+		r.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), r.Body))
+
+		entry := audit.Entry{
+			Source:    "api",
+			RequestID: strconv.FormatInt(auditRequestID.Add(1), 10),
+			Operation: fmt.Sprintf("%s %s", r.Method, r.URL.Path),
+			Arguments: arguments,
+			Outcome:   audit.OutcomeStarted,
+		}
+		s.auditLog.Record(entry)
+
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(recorder, r)
+
+		entry.Arguments = nil
+		entry.Time = time.Time{}
+		entry.Outcome = audit.OutcomeSucceeded
+		if recorder.status >= http.StatusBadRequest {
+			entry.Outcome = audit.OutcomeFailed
+			entry.Error = http.StatusText(recorder.status)
+		}
+		s.auditLog.Record(entry)
+	})
+}
+
+// isMutatingMethod reports whether requests with the given HTTP method can change the graph
+func isMutatingMethod(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
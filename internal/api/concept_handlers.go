@@ -25,7 +25,7 @@ type LinkConceptsRequest struct {
 func (s *Server) createConcept(w http.ResponseWriter, r *http.Request) {
 	var req ConceptRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		respondWithDecodeError(w, err)
 		return
 	}
 	defer r.Body.Close()
@@ -63,11 +63,27 @@ func (s *Server) getConcept(w http.ResponseWriter, r *http.Request) {
 	respondWithJSON(w, http.StatusOK, concept)
 }
 
+// getConceptByUUID handles GET /api/v1/concepts/uuid/{uuid}
+func (s *Server) getConceptByUUID(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	uuid := vars["uuid"]
+
+	// Get concept
+	concept, err := s.service.GetConceptByUUID(r.Context(), uuid)
+	if err != nil {
+		respondWithError(w, http.StatusNotFound, "Concept not found")
+		return
+	}
+
+	// Return the concept
+	respondWithJSON(w, http.StatusOK, concept)
+}
+
 // linkConcepts handles POST /api/v1/concepts/link
 func (s *Server) linkConcepts(w http.ResponseWriter, r *http.Request) {
 	var req LinkConceptsRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		respondWithDecodeError(w, err)
 		return
 	}
 	defer r.Body.Close()
@@ -3,6 +3,7 @@ package api
 import (
 	"encoding/json"
 	"net/http"
+	"strconv"
 
 	"github.com/gorilla/mux"
 )
@@ -14,11 +15,19 @@ type DocumentRequest struct {
 	Metadata map[string]interface{} `json:"metadata,omitempty"`
 }
 
+// LinkDocumentRequest represents a request to link a document to another node
+type LinkDocumentRequest struct {
+	DocumentID       string                 `json:"documentId"`
+	TargetID         string                 `json:"targetId"`
+	RelationshipType string                 `json:"relationshipType,omitempty"`
+	Properties       map[string]interface{} `json:"properties,omitempty"`
+}
+
 // createDocument handles POST /api/v1/documents
 func (s *Server) createDocument(w http.ResponseWriter, r *http.Request) {
 	var req DocumentRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		respondWithDecodeError(w, err)
 		return
 	}
 	defer r.Body.Close()
@@ -56,6 +65,22 @@ func (s *Server) getDocument(w http.ResponseWriter, r *http.Request) {
 	respondWithJSON(w, http.StatusOK, doc)
 }
 
+// getDocumentByUUID handles GET /api/v1/documents/uuid/{uuid}
+func (s *Server) getDocumentByUUID(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	uuid := vars["uuid"]
+
+	// Get document
+	doc, err := s.service.GetDocumentByUUID(r.Context(), uuid)
+	if err != nil {
+		respondWithError(w, http.StatusNotFound, "Document not found")
+		return
+	}
+
+	// Return the document
+	respondWithJSON(w, http.StatusOK, doc)
+}
+
 // updateDocument handles PUT /api/v1/documents/{id}
 func (s *Server) updateDocument(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
@@ -63,7 +88,7 @@ func (s *Server) updateDocument(w http.ResponseWriter, r *http.Request) {
 
 	var req DocumentRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		respondWithDecodeError(w, err)
 		return
 	}
 	defer r.Body.Close()
@@ -101,21 +126,70 @@ func (s *Server) deleteDocument(w http.ResponseWriter, r *http.Request) {
 	respondWithJSON(w, http.StatusOK, map[string]bool{"success": true})
 }
 
-// searchDocuments handles GET /api/v1/documents?query=...
+// linkDocument handles POST /api/v1/documents/link
+func (s *Server) linkDocument(w http.ResponseWriter, r *http.Request) {
+	var req LinkDocumentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithDecodeError(w, err)
+		return
+	}
+	defer r.Body.Close()
+
+	// Validate request
+	if req.DocumentID == "" || req.TargetID == "" {
+		respondWithError(w, http.StatusBadRequest, "DocumentID and TargetID are required")
+		return
+	}
+
+	// Link document
+	id, err := s.service.LinkDocument(r.Context(), req.DocumentID, req.TargetID, req.RelationshipType, req.Properties)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	// Return the edge ID
+	respondWithJSON(w, http.StatusCreated, map[string]string{"id": id})
+}
+
+// searchDocuments handles GET /api/v1/documents?query=...&skip=...&limit=...
 func (s *Server) searchDocuments(w http.ResponseWriter, r *http.Request) {
 	query := r.URL.Query().Get("query")
 	if query == "" {
 		respondWithError(w, http.StatusBadRequest, "Query parameter is required")
 		return
 	}
+	skip, ok := parseQueryInt(w, r, "skip")
+	if !ok {
+		return
+	}
+	limit, ok := parseQueryInt(w, r, "limit")
+	if !ok {
+		return
+	}
 
 	// Search documents
-	docs, err := s.service.SearchDocuments(r.Context(), query)
+	page, err := s.service.SearchDocumentsPage(r.Context(), query, skip, limit)
 	if err != nil {
 		respondWithError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	// Return the documents
-	respondWithJSON(w, http.StatusOK, docs)
+	// Return the page of documents
+	respondWithJSON(w, http.StatusOK, page)
+}
+
+// parseQueryInt parses an optional non-negative integer query parameter, defaulting to 0. If it is invalid, it
+// responds with 400 and returns false.
+func parseQueryInt(w http.ResponseWriter, r *http.Request, name string) (int, bool) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return 0, true
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		respondWithError(w, http.StatusBadRequest, name+" must be a non-negative integer")
+		return 0, false
+	}
+	return n, true
 }
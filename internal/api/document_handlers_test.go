@@ -0,0 +1,62 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sammcj/mcp-graph/internal/mcp/mocks"
+	"github.com/sammcj/mcp-graph/internal/service"
+)
+
+// TestSearchDocuments tests that document search passes skip and limit to the service and returns the page
+func TestSearchDocuments(t *testing.T) {
+	// Create a new mock controller
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockKnowledgeManager(ctrl)
+	server := NewServer(0, mockService, mocks.NewMockStore(ctrl))
+
+	// Set up expectations
+	mockService.EXPECT().SearchDocumentsPage(gomock.Any(), "graph", 20, 10).Return(&service.DocumentPage{
+		Data:  []*service.Document{{ID: "0x1", Title: "Graph Notes"}},
+		Total: 21,
+		Skip:  20,
+		Limit: 10,
+	}, nil)
+
+	// Make the request
+	recorder := httptest.NewRecorder()
+	server.router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/documents?query=graph&skip=20&limit=10", nil))
+
+	// Assert the results
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var page service.DocumentPage
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &page))
+	assert.Equal(t, int64(21), page.Total)
+	assert.Equal(t, 20, page.Skip)
+	assert.Len(t, page.Data, 1)
+}
+
+// TestSearchDocuments_InvalidPagination tests that invalid skip and limit values are rejected without searching
+func TestSearchDocuments_InvalidPagination(t *testing.T) {
+	// Create a new mock controller
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// No calls to the service are expected
+	server := NewServer(0, mocks.NewMockKnowledgeManager(ctrl), mocks.NewMockStore(ctrl))
+
+	for _, query := range []string{"skip=-1", "skip=ten", "limit=-5", "limit=1.5"} {
+		recorder := httptest.NewRecorder()
+		server.router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/documents?query=graph&"+query, nil))
+
+		assert.Equal(t, http.StatusBadRequest, recorder.Code, query)
+		assert.Contains(t, recorder.Body.String(), "must be a non-negative integer", query)
+	}
+}
@@ -0,0 +1,75 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/sammcj/mcp-graph/internal/graph"
+)
+
+// readinessTimeout limits how long the readiness probe waits for the graph database
+const readinessTimeout = 5 * time.Second
+
+// Readiness states reported by GET /readyz
+const (
+	ReadinessReady       = "ready"       // The graph database is reachable with every optional capability
+	ReadinessDegraded    = "degraded"    // The graph database is reachable but some optional capabilities are missing
+	ReadinessUnavailable = "unavailable" // The graph database is unreachable
+)
+
+// ReadinessResponse represents the response to a readiness probe
+type ReadinessResponse struct {
+	Status       string              `json:"status"`
+	Reason       string              `json:"reason,omitempty"` // Why the database is unavailable, e.g. "connection_refused"
+	Error        string              `json:"error,omitempty"`
+	Warnings     []string            `json:"warnings,omitempty"` // Why the server is degraded
+	Capabilities *graph.Capabilities `json:"capabilities,omitempty"`
+}
+
+// readiness handles GET /readyz, returning 503 if the graph database is unreachable and 200 otherwise,
+// with a degraded status when optional capabilities such as APOC are missing
+func (s *Server) readiness(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	// Sustained failures seen by the background health check are reported without waiting for another ping
+	if s.health != nil {
+		if healthy, err := s.health.Healthy(); !healthy {
+			respondWithJSON(w, http.StatusServiceUnavailable, ReadinessResponse{
+				Status: ReadinessUnavailable,
+				Reason: graph.UnavailableReason(err),
+				Error:  err.Error(),
+			})
+			return
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), readinessTimeout)
+	defer cancel()
+
+	if err := s.graph.Ping(ctx); err != nil {
+		respondWithJSON(w, http.StatusServiceUnavailable, ReadinessResponse{
+			Status: ReadinessUnavailable,
+			Reason: graph.UnavailableReason(err),
+			Error:  err.Error(),
+		})
+		return
+	}
+
+	response := ReadinessResponse{Status: ReadinessReady}
+	caps, err := s.graph.Capabilities(ctx)
+	if err != nil {
+		response.Warnings = append(response.Warnings, fmt.Sprintf("could not check optional capabilities: %v", err))
+	} else {
+		response.Capabilities = &caps
+		if !caps.APOC {
+			response.Warnings = append(response.Warnings, "APOC is not installed; subgraph tools are unavailable and some analysis tools fall back to slower queries")
+		}
+	}
+	if len(response.Warnings) > 0 {
+		response.Status = ReadinessDegraded
+	}
+
+	respondWithJSON(w, http.StatusOK, response)
+}
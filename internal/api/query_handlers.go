@@ -2,13 +2,18 @@ package api
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
+
+	"github.com/sammcj/mcp-graph/internal/graph"
 )
 
 // QueryRequest represents a request to query the knowledge graph
 type QueryRequest struct {
 	Query  string                 `json:"query"`
 	Params map[string]interface{} `json:"params,omitempty"`
+	// IncludeTypes returns {columns: [{name, type}], rows: [[...]]} with the type of each column
+	IncludeTypes bool `json:"includeTypes,omitempty"`
 }
 
 // SchemaRequest represents a request to update the schema
@@ -20,7 +25,7 @@ type SchemaRequest struct {
 func (s *Server) query(w http.ResponseWriter, r *http.Request) {
 	var req QueryRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		respondWithDecodeError(w, err)
 		return
 	}
 	defer r.Body.Close()
@@ -31,10 +36,21 @@ func (s *Server) query(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Include column types if requested
+	if req.IncludeTypes {
+		typedResult, err := s.graph.QueryWithTypes(r.Context(), req.Query, req.Params)
+		if err != nil {
+			s.respondWithQueryError(w, req.Query, err)
+			return
+		}
+		respondWithJSON(w, http.StatusOK, typedResult)
+		return
+	}
+
 	// Execute query directly against the graph store
 	results, err := s.graph.Query(r.Context(), req.Query, req.Params)
 	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, err.Error())
+		s.respondWithQueryError(w, req.Query, err)
 		return
 	}
 
@@ -42,11 +58,21 @@ func (s *Server) query(w http.ResponseWriter, r *http.Request) {
 	respondWithJSON(w, http.StatusOK, results)
 }
 
+// respondWithQueryError reports a failed custom query, explaining the failure as a bad request if the
+// query looks like it's written in a different language from the one the backend expects
+func (s *Server) respondWithQueryError(w http.ResponseWriter, query string, err error) {
+	if mismatch := graph.CheckQueryLanguage(s.graph.QueryLanguage(), query); mismatch != nil {
+		respondWithError(w, http.StatusBadRequest, fmt.Sprintf("%v (%v)", mismatch, err))
+		return
+	}
+	respondWithError(w, http.StatusInternalServerError, err.Error())
+}
+
 // upsertSchema handles POST /api/v1/schema
 func (s *Server) upsertSchema(w http.ResponseWriter, r *http.Request) {
 	var req SchemaRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		respondWithDecodeError(w, err)
 		return
 	}
 	defer r.Body.Close()
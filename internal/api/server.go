@@ -1,8 +1,10 @@
 package api
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -10,9 +12,12 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/gorilla/mux"
+	"github.com/sammcj/mcp-graph/internal/audit"
 	"github.com/sammcj/mcp-graph/internal/graph"
 	"github.com/sammcj/mcp-graph/internal/service"
+	"github.com/sammcj/mcp-graph/internal/usage"
 )
 
 // Logger defines the interface for logging
@@ -56,13 +61,23 @@ func (l *conditionalLogger) Println(v ...interface{}) {
 	}
 }
 
+// defaultMaxBodyBytes is the default maximum size of a request body (10 MiB)
+const defaultMaxBodyBytes int64 = 10 << 20
+
 // Server represents the API server
 type Server struct {
-	router   *mux.Router
-	server   *http.Server
-	service  service.KnowledgeManager
-	graph    graph.Store
-	logger   Logger
+	router       *mux.Router
+	server       *http.Server
+	service      service.KnowledgeManager
+	graph        graph.Store
+	logger       Logger
+	maxBodyBytes int64
+	redactor     *graph.Redactor
+	tools        ToolLister
+	auditLog     *audit.Logger
+	usage        *usage.Tracker
+	usageClients bool // Report the requests made by each client, not just the totals
+	health       *graph.HealthMonitor
 }
 
 // NewServer creates a new API server
@@ -73,10 +88,11 @@ func NewServer(port int, service service.KnowledgeManager, graph graph.Store) *S
 	logger := newConditionalLogger(false)
 
 	server := &Server{
-		router:  router,
-		service: service,
-		graph:   graph,
-		logger:  logger,
+		router:       router,
+		service:      service,
+		graph:        graph,
+		logger:       logger,
+		maxBodyBytes: defaultMaxBodyBytes,
 		server: &http.Server{
 			Addr:         fmt.Sprintf(":%d", port),
 			Handler:      router,
@@ -94,6 +110,9 @@ func NewServer(port int, service service.KnowledgeManager, graph graph.Store) *S
 
 // setupRoutes configures the API routes
 func (s *Server) setupRoutes() {
+	// Readiness probe for orchestrators, outside the versioned API
+	s.router.HandleFunc("/readyz", s.readiness).Methods(http.MethodGet)
+
 	// API version prefix
 	api := s.router.PathPrefix("/api/v1").Subrouter()
 
@@ -101,6 +120,8 @@ func (s *Server) setupRoutes() {
 	docs := api.PathPrefix("/documents").Subrouter()
 	docs.HandleFunc("", s.createDocument).Methods(http.MethodPost)
 	docs.HandleFunc("", s.searchDocuments).Methods(http.MethodGet)
+	docs.HandleFunc("/link", s.linkDocument).Methods(http.MethodPost)
+	docs.HandleFunc("/uuid/{uuid}", s.getDocumentByUUID).Methods(http.MethodGet)
 	docs.HandleFunc("/{id}", s.getDocument).Methods(http.MethodGet)
 	docs.HandleFunc("/{id}", s.updateDocument).Methods(http.MethodPut)
 	docs.HandleFunc("/{id}", s.deleteDocument).Methods(http.MethodDelete)
@@ -109,6 +130,7 @@ func (s *Server) setupRoutes() {
 	concepts := api.PathPrefix("/concepts").Subrouter()
 	concepts.HandleFunc("", s.createConcept).Methods(http.MethodPost)
 	concepts.HandleFunc("", s.searchConcepts).Methods(http.MethodGet)
+	concepts.HandleFunc("/uuid/{uuid}", s.getConceptByUUID).Methods(http.MethodGet)
 	concepts.HandleFunc("/{id}", s.getConcept).Methods(http.MethodGet)
 	concepts.HandleFunc("/link", s.linkConcepts).Methods(http.MethodPost)
 
@@ -118,9 +140,20 @@ func (s *Server) setupRoutes() {
 	// Schema route
 	api.HandleFunc("/schema", s.upsertSchema).Methods(http.MethodPost)
 
+	// Tool definitions route
+	api.HandleFunc("/tools", s.listTools).Methods(http.MethodGet)
+
+	// Usage stats route
+	api.HandleFunc("/usage", s.getUsageStats).Methods(http.MethodGet)
+
 	// Add middleware
 	api.Use(s.loggingMiddleware)
+	api.Use(s.queryMetadataMiddleware)
+	api.Use(s.usageMiddleware)
 	api.Use(s.jsonContentTypeMiddleware)
+	api.Use(s.maxBodySizeMiddleware)
+	api.Use(s.auditMiddleware)
+	api.Use(s.redactionMiddleware)
 }
 
 // Start starts the API server
@@ -139,6 +172,33 @@ func (s *Server) EnableLogging(enabled bool) {
 	}
 }
 
+// SetRequestLimits configures the maximum request body size and the time allowed to read a request.
+// Non-positive values leave the current setting unchanged.
+func (s *Server) SetRequestLimits(maxBodyBytes int64, readTimeout time.Duration) {
+	if maxBodyBytes > 0 {
+		s.maxBodyBytes = maxBodyBytes
+	}
+	if readTimeout > 0 {
+		s.server.ReadTimeout = readTimeout
+	}
+}
+
+// SetRedactor sets the redactor applied to every JSON response body before it is returned to the client
+func (s *Server) SetRedactor(redactor *graph.Redactor) {
+	s.redactor = redactor
+}
+
+// SetToolLister sets the source of the tool definitions served by GET /api/v1/tools
+func (s *Server) SetToolLister(tools ToolLister) {
+	s.tools = tools
+}
+
+// SetHealthMonitor sets the background health check consulted by GET /readyz, which then reports the database
+// unavailable as soon as the monitor has marked it unhealthy
+func (s *Server) SetHealthMonitor(monitor *graph.HealthMonitor) {
+	s.health = monitor
+}
+
 // Shutdown gracefully shuts down the API server
 func (s *Server) Shutdown(ctx context.Context) error {
 	return s.server.Shutdown(ctx)
@@ -153,6 +213,80 @@ func (s *Server) loggingMiddleware(next http.Handler) http.Handler {
 	})
 }
 
+// queryMetadataMiddleware tags the database transactions run by a request with the endpoint, request ID and
+// client address, so that operators can attribute running queries to the request that caused them.
+// The request ID is taken from the X-Request-ID header if present.
+func (s *Server) queryMetadataMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		endpoint := r.URL.Path
+		if route := mux.CurrentRoute(r); route != nil {
+			if template, err := route.GetPathTemplate(); err == nil {
+				endpoint = template
+			}
+		}
+		requestID := r.Header.Get("X-Request-ID")
+		if requestID == "" {
+			requestID = uuid.NewString()
+		}
+
+		ctx := graph.WithQueryMetadata(r.Context(), map[string]interface{}{
+			"source":    "api",
+			"endpoint":  r.Method + " " + endpoint,
+			"requestId": requestID,
+			"clientId":  r.RemoteAddr,
+		})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// maxBodySizeMiddleware limits the size of request bodies, rejecting oversized requests with 413
+func (s *Server) maxBodySizeMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Reject early when the declared length is already too large
+		if r.ContentLength > s.maxBodyBytes {
+			respondWithError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("Request body exceeds the maximum size of %d bytes", s.maxBodyBytes))
+			return
+		}
+		// Guard against bodies without a declared length (e.g. chunked encoding)
+		r.Body = http.MaxBytesReader(w, r.Body, s.maxBodyBytes)
+		next.ServeHTTP(w, r)
+	})
+}
+
+// bufferedResponseWriter captures a response so it can be modified before being written
+type bufferedResponseWriter struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+// WriteHeader records the status code
+func (b *bufferedResponseWriter) WriteHeader(code int) {
+	b.status = code
+}
+
+// Write buffers the response body
+func (b *bufferedResponseWriter) Write(p []byte) (int, error) {
+	return b.body.Write(p)
+}
+
+// redactionMiddleware replaces sensitive property values in JSON responses
+func (s *Server) redactionMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !s.redactor.Enabled() {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		buffered := &bufferedResponseWriter{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(buffered, r)
+
+		body, _ := s.redactor.RedactJSON(buffered.body.Bytes())
+		w.WriteHeader(buffered.status)
+		w.Write(body)
+	})
+}
+
 // jsonContentTypeMiddleware sets the Content-Type header to application/json
 func (s *Server) jsonContentTypeMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -166,6 +300,16 @@ func respondWithError(w http.ResponseWriter, code int, message string) {
 	respondWithJSON(w, code, map[string]string{"error": message})
 }
 
+// respondWithDecodeError sends the error response for a request body that couldn't be decoded
+func respondWithDecodeError(w http.ResponseWriter, err error) {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		respondWithError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("Request body exceeds the maximum size of %d bytes", maxBytesErr.Limit))
+		return
+	}
+	respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+}
+
 // respondWithJSON sends a JSON response
 func respondWithJSON(w http.ResponseWriter, code int, payload interface{}) {
 	response, err := json.Marshal(payload)
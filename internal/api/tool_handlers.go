@@ -0,0 +1,30 @@
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// ToolLister provides the definitions of the tools offered over MCP
+type ToolLister interface {
+	ListTools(ctx context.Context) ([]mcp.Tool, error)
+}
+
+// listTools handles GET /api/v1/tools
+func (s *Server) listTools(w http.ResponseWriter, r *http.Request) {
+	if s.tools == nil {
+		respondWithError(w, http.StatusNotFound, "Tool definitions are not available")
+		return
+	}
+
+	tools, err := s.tools.ListTools(r.Context())
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	// Return the tool definitions
+	respondWithJSON(w, http.StatusOK, tools)
+}
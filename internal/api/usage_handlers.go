@@ -0,0 +1,66 @@
+package api
+
+import (
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/sammcj/mcp-graph/internal/usage"
+)
+
+// SetUsageTracker sets the tracker that counts the requests made by each client address. The API isn't
+// authenticated, so GET /api/v1/usage only reports the requests made by each client if exposeClients is set.
+func (s *Server) SetUsageTracker(tracker *usage.Tracker, exposeClients bool) {
+	s.usage = tracker
+	s.usageClients = exposeClients
+}
+
+// usageMiddleware counts each request against its client's address (without the port, which changes between
+// connections) and its method and route
+func (s *Server) usageMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.usage.Enabled() {
+			client, _, err := net.SplitHostPort(r.RemoteAddr)
+			if err != nil {
+				client = r.RemoteAddr
+			}
+			endpoint := r.URL.Path
+			if route := mux.CurrentRoute(r); route != nil {
+				if template, err := route.GetPathTemplate(); err == nil {
+					endpoint = template
+				}
+			}
+			s.usage.Record(client, r.Method+" "+endpoint)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// getUsageStats handles GET /api/v1/usage, reporting the requests made within the window given by the optional
+// window query parameter (e.g. "1h"), which defaults to and is capped at the configured window. The requests made by
+// each client are left out unless exposing them is enabled.
+func (s *Server) getUsageStats(w http.ResponseWriter, r *http.Request) {
+	if !s.usage.Enabled() {
+		respondWithError(w, http.StatusNotFound, "Usage tracking is not enabled")
+		return
+	}
+
+	var window time.Duration
+	if windowStr := r.URL.Query().Get("window"); windowStr != "" {
+		var err error
+		window, err = time.ParseDuration(windowStr)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, "window must be a duration such as '1h'")
+			return
+		}
+	}
+
+	// Return the usage stats
+	stats := s.usage.Stats(window)
+	if !s.usageClients {
+		stats.Clients = nil
+	}
+	respondWithJSON(w, http.StatusOK, stats)
+}
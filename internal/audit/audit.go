@@ -0,0 +1,90 @@
+// Package audit records mutating operations on the knowledge graph so that operators can see, and replay,
+// how the graph changed.
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/sammcj/mcp-graph/internal/graph"
+)
+
+// Outcomes recorded in audit entries. Each operation is recorded as started before it runs, followed by
+// a second entry with the same request ID once it has succeeded or failed.
+const (
+	OutcomeStarted   = "started"
+	OutcomeSucceeded = "succeeded"
+	OutcomeFailed    = "failed"
+)
+
+// Entry is a single audit record.
+type Entry struct {
+	Time      time.Time   `json:"time"`
+	Source    string      `json:"source"`              // "mcp" or "api"
+	RequestID string      `json:"requestId,omitempty"` // Correlates the started entry with its outcome
+	Operation string      `json:"operation"`           // Tool name, or HTTP method and path
+	Arguments interface{} `json:"arguments,omitempty"` // Only recorded on the started entry
+	Outcome   string      `json:"outcome"`
+	Error     string      `json:"error,omitempty"`
+}
+
+// Logger writes audit entries as JSON lines. A nil Logger discards entries.
+type Logger struct {
+	mu       sync.Mutex
+	w        io.Writer
+	redactor *graph.Redactor
+}
+
+// NewLogger creates a logger writing to w. Arguments are redacted with the given redactor, if any.
+func NewLogger(w io.Writer, redactor *graph.Redactor) *Logger {
+	return &Logger{w: w, redactor: redactor}
+}
+
+// Open creates a logger appending to the file at path, or writing to stderr if path is empty.
+// The returned closer closes the file, if one was opened.
+func Open(path string, redactor *graph.Redactor) (*Logger, io.Closer, error) {
+	if path == "" {
+		return NewLogger(os.Stderr, redactor), nopCloser{}, nil
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	return NewLogger(f, redactor), f, nil
+}
+
+// Enabled reports whether entries are being recorded.
+func (l *Logger) Enabled() bool {
+	return l != nil
+}
+
+// Record writes an entry, filling in the time if unset. Failures to write are ignored so that
+// auditing never blocks the operation being audited.
+func (l *Logger) Record(entry Entry) {
+	if !l.Enabled() {
+		return
+	}
+	if entry.Time.IsZero() {
+		entry.Time = time.Now().UTC()
+	}
+	entry.Arguments = l.redactor.Redact(entry.Arguments)
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	line = append(line, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, _ = l.w.Write(line)
+}
+
+// nopCloser is returned by Open when there is no file to close
+type nopCloser struct{}
+
+func (nopCloser) Close() error { return nil }
@@ -0,0 +1,55 @@
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sammcj/mcp-graph/internal/graph"
+)
+
+func TestLogger_Record(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewLogger(&buf, graph.NewRedactor([]string{"password"}))
+
+	l.Record(Entry{
+		Source:    "mcp",
+		RequestID: "1",
+		Operation: "create_node",
+		Arguments: map[string]interface{}{
+			"labels":     []interface{}{"DataStore"},
+			"properties": map[string]interface{}{"name": "orders-db", "password": "hunter2"},
+		},
+		Outcome: OutcomeStarted,
+	})
+	l.Record(Entry{Source: "mcp", RequestID: "1", Operation: "create_node", Outcome: OutcomeFailed, Error: "boom"})
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if !assert.Len(t, lines, 2) {
+		return
+	}
+
+	var started map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(lines[0]), &started))
+	assert.Equal(t, "create_node", started["operation"])
+	assert.Equal(t, OutcomeStarted, started["outcome"])
+	assert.NotEmpty(t, started["time"])
+	props := started["arguments"].(map[string]interface{})["properties"].(map[string]interface{})
+	assert.Equal(t, "orders-db", props["name"])
+	assert.Equal(t, graph.RedactedValue, props["password"])
+
+	var failed map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(lines[1]), &failed))
+	assert.Equal(t, OutcomeFailed, failed["outcome"])
+	assert.Equal(t, "boom", failed["error"])
+	assert.NotContains(t, failed, "arguments")
+}
+
+func TestLogger_Nil(t *testing.T) {
+	var l *Logger
+	assert.False(t, l.Enabled())
+	assert.NotPanics(t, func() { l.Record(Entry{Operation: "create_node"}) })
+}
@@ -9,11 +9,20 @@ import (
 
 // Config represents the application configuration
 type Config struct {
-	App      AppConfig      `mapstructure:"app"`
-	API      APIConfig      `mapstructure:"api"`
-	Neo4j    Neo4jConfig    `mapstructure:"neo4j"`
-	MCP      MCPConfig      `mapstructure:"mcp"`
-	Shutdown ShutdownConfig `mapstructure:"shutdown"`
+	App       AppConfig       `mapstructure:"app"`
+	API       APIConfig       `mapstructure:"api"`
+	Neo4j     Neo4jConfig     `mapstructure:"neo4j"`
+	MCP       MCPConfig       `mapstructure:"mcp"`
+	Shutdown  ShutdownConfig  `mapstructure:"shutdown"`
+	Redaction RedactionConfig `mapstructure:"redaction"`
+	Knowledge KnowledgeConfig `mapstructure:"knowledge"`
+	Audit     AuditConfig     `mapstructure:"audit"`
+	Usage     UsageConfig     `mapstructure:"usage"`
+	Snapshots SnapshotsConfig `mapstructure:"snapshots"`
+	Health    HealthConfig    `mapstructure:"health"`
+	Integrity IntegrityConfig `mapstructure:"integrity"`
+	Schema    SchemaConfig    `mapstructure:"schema"`
+	TTL       TTLConfig       `mapstructure:"ttl"`
 }
 
 // AppConfig contains general application settings
@@ -24,20 +33,44 @@ type AppConfig struct {
 
 // APIConfig contains API server settings
 type APIConfig struct {
-	Port int `mapstructure:"port"`
+	Port         int           `mapstructure:"port"`
+	MaxBodyBytes int64         `mapstructure:"maxBodyBytes"`
+	ReadTimeout  time.Duration `mapstructure:"readTimeout"`
 }
 
 // Neo4jConfig contains Neo4j connection settings
 type Neo4jConfig struct {
-	URI      string `mapstructure:"uri"`
-	Username string `mapstructure:"username"`
-	Password string `mapstructure:"password"`
+	URI                         string                    `mapstructure:"uri"`
+	Username                    string                    `mapstructure:"username"`
+	Password                    string                    `mapstructure:"password"`
+	NormalizeRelationshipTypes  bool                      `mapstructure:"normalizeRelationshipTypes"`
+	ConnectAttempts             int                       `mapstructure:"connectAttempts"`
+	ConnectBackoff              time.Duration             `mapstructure:"connectBackoff"`
+	SchemeFallback              bool                      `mapstructure:"schemeFallback"` // Retry with bolt:// for neo4j:// and vice versa on routing failures
+	MaxConnectionPoolSize       int                       `mapstructure:"maxConnectionPoolSize"`
+	BatchConcurrency            int                       `mapstructure:"batchConcurrency"` // Capped at MaxConnectionPoolSize
+	MaxPropertyBytes            int                       `mapstructure:"maxPropertyBytes"` // Largest string property value written; 0 is unlimited
+	TruncateOversizedProperties bool                      `mapstructure:"truncateOversizedProperties"`
+	CoerceTemporalProperties    bool                      `mapstructure:"coerceTemporalProperties"`  // Store ISO-8601 strings in MERGE inputs as temporal values
+	DefaultRelationshipSource   string                    `mapstructure:"defaultRelationshipSource"` // Source recorded on new relationships that don't give one
+	AllowedRelationshipTypes    []string                  `mapstructure:"allowedRelationshipTypes"`  // Types relationships may be created with; any if empty
+	PropertyWhitelist           []PropertyWhitelistConfig `mapstructure:"propertyWhitelist"`         // Property keys allowed on nodes with a label
+	StripUnknownProperties      bool                      `mapstructure:"stripUnknownProperties"`    // Drop properties not on the whitelist instead of rejecting the write
+}
+
+// PropertyWhitelistConfig lists the property keys nodes with a label may have. It is a list entry rather than a map
+// from label to properties because map keys are lowercased.
+type PropertyWhitelistConfig struct {
+	Label      string   `mapstructure:"label"`
+	Properties []string `mapstructure:"properties"`
 }
 
 // MCPConfig contains MCP server settings
 type MCPConfig struct {
-	UseSSE  bool   `mapstructure:"useSSE"`
-	Address string `mapstructure:"address"`
+	UseSSE                 bool   `mapstructure:"useSSE"`
+	Address                string `mapstructure:"address"`
+	ResultSizeWarningBytes int    `mapstructure:"resultSizeWarningBytes"` // Log a warning for larger tool results; 0 disables
+	PortFallbacks          int    `mapstructure:"portFallbacks"`          // Following ports to try if the address is in use; 0 exits instead
 }
 
 // ShutdownConfig contains graceful shutdown settings
@@ -45,6 +78,88 @@ type ShutdownConfig struct {
 	Timeout time.Duration `mapstructure:"timeout"`
 }
 
+// RedactionConfig contains settings for hiding sensitive property values from clients
+type RedactionConfig struct {
+	Properties []string `mapstructure:"properties"` // Property key patterns (path.Match syntax, case-insensitive)
+}
+
+// KnowledgeConfig contains settings for documents and concepts
+type KnowledgeConfig struct {
+	AssignUUIDs     bool `mapstructure:"assignUUIDs"`     // Give new documents and concepts a portable uuid property
+	AssignTitleKeys bool `mapstructure:"assignTitleKeys"` // Give documents a normalised titleKey property for deduplication
+}
+
+// AuditConfig contains settings for the audit log of mutating operations
+type AuditConfig struct {
+	Enabled bool   `mapstructure:"enabled"`
+	Path    string `mapstructure:"path"` // File to append entries to; stderr if empty
+}
+
+// UsageConfig contains settings for counting the tool calls and API requests made by each client
+type UsageConfig struct {
+	Enabled bool          `mapstructure:"enabled"`
+	Window  time.Duration `mapstructure:"window"` // How long counts are kept, and the longest window usage stats cover
+	// ExposeClients makes GET /api/v1/usage report the requests made by each client, not just the totals. The API
+	// isn't authenticated, so this is off by default.
+	ExposeClients bool `mapstructure:"exposeClients"`
+}
+
+// SnapshotsConfig contains settings for the subgraph snapshots saved by snapshot_subgraph
+type SnapshotsConfig struct {
+	Dir string `mapstructure:"dir"` // Directory snapshots are saved in; snapshots are disabled if empty
+}
+
+// HealthConfig contains settings for the background database health check
+type HealthConfig struct {
+	CheckInterval    time.Duration `mapstructure:"checkInterval"`    // Time between pings; 0 disables the check
+	FailureThreshold int           `mapstructure:"failureThreshold"` // Consecutive failed pings before the database is marked unhealthy
+}
+
+// IntegrityConfig contains the rules check_integrity checks the relationship graph against
+type IntegrityConfig struct {
+	RequiredRelationships []RequiredRelationshipRule `mapstructure:"requiredRelationships"`
+	RelationshipEndpoints []RelationshipEndpointRule `mapstructure:"relationshipEndpoints"`
+}
+
+// RequiredRelationshipRule requires every node with a label to have a relationship of a type
+type RequiredRelationshipRule struct {
+	Label            string `mapstructure:"label"`
+	RelationshipType string `mapstructure:"relationshipType"`
+	Direction        string `mapstructure:"direction"` // outgoing (default), incoming or both
+}
+
+// RelationshipEndpointRule restricts the labels of the nodes relationships of a type may connect
+type RelationshipEndpointRule struct {
+	RelationshipType string   `mapstructure:"relationshipType"`
+	StartLabels      []string `mapstructure:"startLabels"` // Start node must have one of these; unrestricted if empty
+	EndLabels        []string `mapstructure:"endLabels"`   // End node must have one of these; unrestricted if empty
+}
+
+// SchemaConfig contains settings for the schema created at startup
+type SchemaConfig struct {
+	Indexes []IndexConfig `mapstructure:"indexes"`
+}
+
+// IndexConfig lists properties of nodes with a label to index at startup. It is a list entry rather than a map from
+// label to properties because map keys are lowercased.
+type IndexConfig struct {
+	Label      string   `mapstructure:"label"`
+	Properties []string `mapstructure:"properties"`
+}
+
+// TTLConfig contains settings for nodes that expire
+type TTLConfig struct {
+	Labels        []LabelTTLConfig `mapstructure:"labels"`        // Lifetime of new nodes with each label
+	SweepInterval time.Duration    `mapstructure:"sweepInterval"` // Time between background sweeps of expired nodes; 0 disables them
+}
+
+// LabelTTLConfig sets how long new nodes with a label live. It is a list entry rather than a map from label to TTL
+// because map keys are lowercased.
+type LabelTTLConfig struct {
+	Label string        `mapstructure:"label"`
+	TTL   time.Duration `mapstructure:"ttl"`
+}
+
 // LoadConfig loads the configuration from a file and environment variables
 // If the config file doesn't exist, it creates one with default values
 func LoadConfig(configPath string) (*Config, error) {
@@ -108,18 +223,69 @@ func setDefaults(v *viper.Viper) {
 
 	// API defaults
 	v.SetDefault("api.port", 8080)
+	v.SetDefault("api.maxBodyBytes", 10<<20) // 10 MiB
+	v.SetDefault("api.readTimeout", 15*time.Second)
 
 	// Neo4j defaults
 	v.SetDefault("neo4j.uri", "bolt://localhost:7687")
 	v.SetDefault("neo4j.username", "")
 	v.SetDefault("neo4j.password", "")
+	v.SetDefault("neo4j.normalizeRelationshipTypes", false)
+	v.SetDefault("neo4j.connectAttempts", 10)
+	v.SetDefault("neo4j.connectBackoff", time.Second)
+	v.SetDefault("neo4j.schemeFallback", true)
+	v.SetDefault("neo4j.maxConnectionPoolSize", 100)
+	v.SetDefault("neo4j.batchConcurrency", 10)
+	v.SetDefault("neo4j.maxPropertyBytes", 0)
+	v.SetDefault("neo4j.truncateOversizedProperties", false)
+	v.SetDefault("neo4j.coerceTemporalProperties", false)
+	v.SetDefault("neo4j.defaultRelationshipSource", "manual")
+	v.SetDefault("neo4j.allowedRelationshipTypes", []string{})
+	v.SetDefault("neo4j.propertyWhitelist", []map[string]interface{}{})
+	v.SetDefault("neo4j.stripUnknownProperties", false)
 
 	// MCP defaults
 	v.SetDefault("mcp.useSSE", true)
 	v.SetDefault("mcp.address", ":3000")
+	v.SetDefault("mcp.resultSizeWarningBytes", 1<<20) // 1 MiB
+	v.SetDefault("mcp.portFallbacks", 0)
 
 	// Shutdown defaults
 	v.SetDefault("shutdown.timeout", 5*time.Second)
+
+	// Redaction defaults
+	v.SetDefault("redaction.properties", []string{})
+
+	// Knowledge defaults
+	v.SetDefault("knowledge.assignUUIDs", false)
+	v.SetDefault("knowledge.assignTitleKeys", false)
+
+	// Audit defaults
+	v.SetDefault("audit.enabled", false)
+	v.SetDefault("audit.path", "")
+
+	// Usage defaults
+	v.SetDefault("usage.enabled", false)
+	v.SetDefault("usage.window", 24*time.Hour)
+	v.SetDefault("usage.exposeClients", false)
+
+	// Snapshot defaults
+	v.SetDefault("snapshots.dir", "")
+
+	// Health check defaults
+	v.SetDefault("health.checkInterval", 30*time.Second)
+	v.SetDefault("health.failureThreshold", 3)
+
+	// Integrity defaults
+	v.SetDefault("integrity.requiredRelationships", []map[string]interface{}{})
+	v.SetDefault("integrity.relationshipEndpoints", []map[string]interface{}{})
+
+	// Schema defaults
+	v.SetDefault("schema.indexes", []map[string]interface{}{})
+
+	// TTL defaults
+	v.SetDefault("ttl.labels", []map[string]interface{}{})
+	v.SetDefault("ttl.sweepInterval", 0)
 }
 
 // SaveConfigExample saves an example configuration file
@@ -0,0 +1,51 @@
+package graph
+
+import (
+	"context"
+	"sync"
+)
+
+// costEstimateKey is the context key for cost estimates.
+type costEstimateKey struct{}
+
+// QueryCost is the planner's estimate for a single query, taken from its plan without running it.
+type QueryCost struct {
+	Operator        string  `json:"operator"`        // Root operator of the plan, e.g. "ProduceResults"
+	EstimatedRows   float64 `json:"estimatedRows"`   // Rows the planner expects the query to return
+	MaxOperatorRows float64 `json:"maxOperatorRows"` // Largest row estimate of any operator in the plan, a proxy for the work involved
+}
+
+// CostEstimate collects the estimated cost of the queries a store would run for a request. It is safe for
+// concurrent use.
+type CostEstimate struct {
+	mu      sync.Mutex
+	queries []QueryCost
+}
+
+// WithCostEstimate returns a context asking stores to estimate the cost of their traversal queries rather than run
+// them, and the estimate they record into. Traversals run with it return empty results. Stores that can't estimate
+// costs ignore it and run their queries as normal, recording nothing.
+func WithCostEstimate(ctx context.Context) (context.Context, *CostEstimate) {
+	estimate := &CostEstimate{}
+	return context.WithValue(ctx, costEstimateKey{}, estimate), estimate
+}
+
+// CostEstimateFromContext returns the cost estimate being collected for ctx, or nil if costs aren't being estimated.
+func CostEstimateFromContext(ctx context.Context) *CostEstimate {
+	estimate, _ := ctx.Value(costEstimateKey{}).(*CostEstimate)
+	return estimate
+}
+
+// Add records the estimated cost of a query.
+func (e *CostEstimate) Add(cost QueryCost) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.queries = append(e.queries, cost)
+}
+
+// Queries returns the estimated cost of each query recorded, in the order they were recorded.
+func (e *CostEstimate) Queries() []QueryCost {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]QueryCost(nil), e.queries...)
+}
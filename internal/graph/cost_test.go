@@ -0,0 +1,19 @@
+package graph
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithCostEstimate(t *testing.T) {
+	assert.Nil(t, CostEstimateFromContext(context.Background()))
+
+	ctx, estimate := WithCostEstimate(context.Background())
+	assert.Same(t, estimate, CostEstimateFromContext(ctx))
+	assert.Empty(t, estimate.Queries())
+
+	estimate.Add(QueryCost{Operator: "ProduceResults", EstimatedRows: 10, MaxOperatorRows: 250})
+	assert.Equal(t, []QueryCost{{Operator: "ProduceResults", EstimatedRows: 10, MaxOperatorRows: 250}}, estimate.Queries())
+}
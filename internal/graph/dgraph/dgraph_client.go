@@ -2,14 +2,17 @@ package dgraph
 
 import (
 	"context"
+	"sync"
 
 	"github.com/dgraph-io/dgo/v2"
 	"github.com/dgraph-io/dgo/v2/protos/api"
 	"github.com/sammcj/mcp-graph/internal/graph/dgraph/dgraphtest"
 )
 
-// DgraphClientWrapper wraps the Dgraph client to implement the dgraphtest.DgraphClient interface
+// DgraphClientWrapper wraps the Dgraph client to implement the dgraphtest.DgraphClient interface.
+// The wrapped client can be replaced while in use, e.g. after reconnecting.
 type DgraphClientWrapper struct {
+	mu     sync.RWMutex
 	client *dgo.Dgraph
 }
 
@@ -53,18 +56,32 @@ func (w *DgraphTxnWrapper) Commit(ctx context.Context) error {
 // NewTxn creates a new transaction
 func (w *DgraphClientWrapper) NewTxn() dgraphtest.DgraphTxn {
 	return &DgraphTxnWrapper{
-		txn: w.client.NewTxn(),
+		txn: w.current().NewTxn(),
 	}
 }
 
 // NewReadOnlyTxn creates a new read-only transaction
 func (w *DgraphClientWrapper) NewReadOnlyTxn() dgraphtest.DgraphTxn {
 	return &DgraphTxnWrapper{
-		txn: w.client.NewReadOnlyTxn(),
+		txn: w.current().NewReadOnlyTxn(),
 	}
 }
 
 // Alter runs schema operations
 func (w *DgraphClientWrapper) Alter(ctx context.Context, op *api.Operation) error {
-	return w.client.Alter(ctx, op)
+	return w.current().Alter(ctx, op)
+}
+
+// current returns the wrapped client
+func (w *DgraphClientWrapper) current() *dgo.Dgraph {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.client
+}
+
+// replace swaps the wrapped client; transactions already created keep using the old one
+func (w *DgraphClientWrapper) replace(client *dgo.Dgraph) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.client = client
 }
@@ -4,6 +4,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/dgraph-io/dgo/v2"
 	"github.com/dgraph-io/dgo/v2/protos/api"
@@ -17,13 +20,28 @@ import (
 // DgraphStore implements the graph.Store interface using Dgraph
 type DgraphStore struct {
 	client dgraphtest.DgraphClient
+
+	// Set when the store dialled Dgraph itself, so that it can reconnect
+	address string
+	wrapper *DgraphClientWrapper
+	connMu  sync.Mutex
+	conn    *grpc.ClientConn
 }
 
-// Ensure DgraphStore implements graph.Store
-var _ graph.Store = (*DgraphStore)(nil)
+// Ensure DgraphStore implements graph.Store and can reconnect
+var (
+	_ graph.Store       = (*DgraphStore)(nil)
+	_ graph.Reconnecter = (*DgraphStore)(nil)
+)
 
 // NewDgraphStore creates a new Dgraph store
 func NewDgraphStore(address string) (*DgraphStore, error) {
+	return NewDgraphStoreWithRetry(address, graph.RetryPolicy{Attempts: 1})
+}
+
+// NewDgraphStoreWithRetry creates a new Dgraph store, retrying the connection according to the policy
+// so that the server can wait for a database that is still starting up.
+func NewDgraphStoreWithRetry(address string, policy graph.RetryPolicy) (*DgraphStore, error) {
 	// Create a gRPC connection
 	conn, err := grpc.Dial(address, grpc.WithTransportCredentials(insecure.NewCredentials()))
 	if err != nil {
@@ -34,9 +52,20 @@ func NewDgraphStore(address string) (*DgraphStore, error) {
 	dgraphClient := dgo.NewDgraphClient(api.NewDgraphClient(conn))
 	client := NewDgraphClientWrapper(dgraphClient)
 
-	return &DgraphStore{
-		client: client,
-	}, nil
+	store := &DgraphStore{
+		client:  client,
+		address: address,
+		wrapper: client,
+		conn:    conn,
+	}
+
+	// gRPC connects lazily, so check the server actually responds
+	if err := graph.WaitUntilReachable(context.Background(), store, policy); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to connect to Dgraph: %w", err)
+	}
+
+	return store, nil
 }
 
 // NewDgraphStoreWithClient creates a new Dgraph store with a provided client
@@ -47,6 +76,49 @@ func NewDgraphStoreWithClient(client dgraphtest.DgraphClient) *DgraphStore {
 	}
 }
 
+// Reconnect re-dials Dgraph and, once the new connection responds, switches the store over to it and closes
+// the old one. Transactions still using the old connection fail. Only stores created with an address can reconnect.
+func (s *DgraphStore) Reconnect(ctx context.Context) error {
+	if s.wrapper == nil {
+		return fmt.Errorf("store was not created with an address, so cannot reconnect")
+	}
+
+	conn, err := grpc.Dial(s.address, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return fmt.Errorf("failed to reconnect to Dgraph: %w", err)
+	}
+	dgraphClient := dgo.NewDgraphClient(api.NewDgraphClient(conn))
+
+	// gRPC connects lazily, so check the new connection works before switching to it
+	probe := &DgraphStore{client: NewDgraphClientWrapper(dgraphClient)}
+	if err := probe.Ping(ctx); err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to reconnect to Dgraph: %w", err)
+	}
+
+	s.connMu.Lock()
+	oldConn := s.conn
+	s.conn = conn
+	s.wrapper.replace(dgraphClient)
+	s.connMu.Unlock()
+
+	if oldConn != nil {
+		oldConn.Close()
+	}
+	return nil
+}
+
+// Ping checks that Dgraph is reachable by running a trivial read-only query
+func (s *DgraphStore) Ping(ctx context.Context) error {
+	txn := s.client.NewReadOnlyTxn()
+	defer txn.Discard(ctx)
+
+	if _, err := txn.Query(ctx, `{ ping(func: uid(0x1)) { uid } }`); err != nil {
+		return fmt.Errorf("failed to ping Dgraph: %w", err)
+	}
+	return nil
+}
+
 // CreateNode creates a new node in the graph
 func (s *DgraphStore) CreateNode(ctx context.Context, nodeType string, properties map[string]interface{}) (string, error) {
 	txn := s.client.NewTxn()
@@ -85,6 +157,12 @@ func (s *DgraphStore) CreateNode(ctx context.Context, nodeType string, propertie
 	return "", fmt.Errorf("no UID returned from node creation")
 }
 
+// CreateNodeIfNotExists finds the node of the given type whose key property equals value, creating it if there is none.
+func (s *DgraphStore) CreateNodeIfNotExists(ctx context.Context, nodeType, key string, value interface{}, properties map[string]interface{}) (string, bool, error) {
+	// Placeholder implementation - Dgraph upserts require specific query logic
+	return "", false, fmt.Errorf("CreateNodeIfNotExists not implemented for Dgraph")
+}
+
 // GetNode retrieves a node by ID
 func (s *DgraphStore) GetNode(ctx context.Context, id string) (map[string]interface{}, error) {
 	txn := s.client.NewReadOnlyTxn()
@@ -118,7 +196,57 @@ func (s *DgraphStore) GetNode(ctx context.Context, id string) (map[string]interf
 		return nil, fmt.Errorf("node not found: %s", id)
 	}
 
-	return result.Node[0], nil
+	return canonicalNode(result.Node[0]), nil
+}
+
+// GetNodesByIDs retrieves the nodes with the given UIDs in a single query
+func (s *DgraphStore) GetNodesByIDs(ctx context.Context, ids []string) ([]map[string]interface{}, error) {
+	if len(ids) == 0 {
+		return []map[string]interface{}{}, nil
+	}
+
+	txn := s.client.NewReadOnlyTxn()
+
+	// Create query
+	q := fmt.Sprintf(`
+		{
+			nodes(func: uid(%s)) {
+				uid
+				expand(_all_)
+			}
+		}
+	`, strings.Join(ids, ", "))
+
+	// Execute query
+	resp, err := txn.Query(ctx, q)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get nodes: %w", err)
+	}
+
+	// Parse response
+	var result struct {
+		Nodes []map[string]interface{} `json:"nodes"`
+	}
+
+	if err := json.Unmarshal(resp.Json, &result); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	// Dgraph returns nodes in UID order, so put them back in the requested order
+	byID := make(map[string]map[string]interface{}, len(result.Nodes))
+	for _, node := range result.Nodes {
+		if uid, ok := node["uid"].(string); ok {
+			byID[uid] = canonicalNode(node)
+		}
+	}
+	nodes := make([]map[string]interface{}, 0, len(byID))
+	for _, id := range ids {
+		if node, ok := byID[id]; ok {
+			nodes = append(nodes, node)
+			delete(byID, id)
+		}
+	}
+	return nodes, nil
 }
 
 // UpdateNode updates a node's properties
@@ -158,6 +286,12 @@ func (s *DgraphStore) FindOrCreateEntity(ctx context.Context, input graph.Entity
 	return graph.EntityDetails{}, fmt.Errorf("FindOrCreateEntity not implemented for Dgraph")
 }
 
+// FindOrCreateEntityWithChanges finds or creates an entity and reports the properties the merge changed.
+func (s *DgraphStore) FindOrCreateEntityWithChanges(ctx context.Context, input graph.EntityInput) (graph.EntityUpsertResult, error) {
+	// Placeholder implementation
+	return graph.EntityUpsertResult{}, fmt.Errorf("FindOrCreateEntityWithChanges not implemented for Dgraph")
+}
+
 // FindOrCreateRelationship finds a relationship or creates it if not found.
 // Dgraph implementation needs careful handling of upserts.
 func (s *DgraphStore) FindOrCreateRelationship(ctx context.Context, input graph.RelationshipInput) (map[string]interface{}, error) {
@@ -165,9 +299,15 @@ func (s *DgraphStore) FindOrCreateRelationship(ctx context.Context, input graph.
 	return nil, fmt.Errorf("FindOrCreateRelationship not implemented for Dgraph")
 }
 
+// UpsertEntityWithRelationship finds or creates an entity and its relationship to an existing node in one transaction.
+func (s *DgraphStore) UpsertEntityWithRelationship(ctx context.Context, entity graph.EntityInput, rel graph.RelationshipInput) (graph.EntityWithRelationship, error) {
+	// Placeholder implementation
+	return graph.EntityWithRelationship{}, fmt.Errorf("UpsertEntityWithRelationship not implemented for Dgraph")
+}
+
 // GetEntityDetails retrieves the labels and properties of a specific entity.
 // Dgraph doesn't have explicit labels like Neo4j, often relies on a 'type' predicate.
-func (s *DgraphStore) GetEntityDetails(ctx context.Context, labels []string, identifyingProperties map[string]interface{}) (graph.EntityDetails, error) {
+func (s *DgraphStore) GetEntityDetails(ctx context.Context, labels []string, identifyingProperties map[string]interface{}, properties []string) (graph.EntityDetails, error) {
 	// Placeholder implementation
 	return graph.EntityDetails{}, fmt.Errorf("GetEntityDetails not implemented for Dgraph")
 }
@@ -179,7 +319,7 @@ func (s *DgraphStore) FindNeighbors(ctx context.Context, labels []string, identi
 }
 
 // FindDependencies finds entities that the target entity depends on, up to a specified depth.
-func (s *DgraphStore) FindDependencies(ctx context.Context, labels []string, identifyingProperties map[string]interface{}, relationshipTypes []string, maxDepth int) (graph.DependencyResult, error) {
+func (s *DgraphStore) FindDependencies(ctx context.Context, labels []string, identifyingProperties map[string]interface{}, relationshipTypes []string, relationshipPropertyFilters map[string]interface{}, maxDepth int) (graph.DependencyResult, error) {
 	// Placeholder implementation
 	return graph.DependencyResult{}, fmt.Errorf("FindDependencies not implemented for Dgraph")
 }
@@ -191,11 +331,83 @@ func (s *DgraphStore) FindDependents(ctx context.Context, labels []string, ident
 }
 
 // GetEntitySubgraph retrieves nodes and relationships around a central entity, suitable for visualisation.
-func (s *DgraphStore) GetEntitySubgraph(ctx context.Context, labels []string, identifyingProperties map[string]interface{}, maxDepth int) (graph.SubgraphResult, error) {
+func (s *DgraphStore) GetEntitySubgraph(ctx context.Context, labels []string, identifyingProperties map[string]interface{}, maxDepth int, includeLabels []string) (graph.SubgraphResult, error) {
 	// Placeholder implementation
 	return graph.SubgraphResult{}, fmt.Errorf("GetEntitySubgraph not implemented for Dgraph")
 }
 
+// CommonDependencies finds entities that all of the given entities depend on, up to a specified depth.
+func (s *DgraphStore) CommonDependencies(ctx context.Context, locators []graph.EntityLocator, relationshipTypes []string, maxDepth int) (graph.CommonDependenciesResult, error) {
+	// Placeholder implementation
+	return graph.CommonDependenciesResult{}, fmt.Errorf("CommonDependencies not implemented for Dgraph")
+}
+
+// FindNearestByLabel finds the closest entity with the target label and the path to it.
+func (s *DgraphStore) FindNearestByLabel(ctx context.Context, from graph.EntityLocator, targetLabel string, relationshipTypes []string, maxDepth int) (graph.PathResult, error) {
+	// Placeholder implementation
+	return graph.PathResult{}, fmt.Errorf("FindNearestByLabel not implemented for Dgraph")
+}
+
+// FindShortestPath finds the shortest path between two entities, optionally weighted by a relationship property.
+func (s *DgraphStore) FindShortestPath(ctx context.Context, from, to graph.EntityLocator, relationshipTypes []string, weightProperty string, maxDepth int) (graph.ShortestPathResult, error) {
+	// Placeholder implementation
+	return graph.ShortestPathResult{}, fmt.Errorf("FindShortestPath not implemented for Dgraph")
+}
+
+// DependencyPath finds the shortest dependency route from one entity to another.
+func (s *DgraphStore) DependencyPath(ctx context.Context, from, to graph.EntityLocator, relationshipTypes []string, maxDepth int) (graph.PathResult, error) {
+	// Placeholder implementation
+	return graph.PathResult{}, fmt.Errorf("DependencyPath not implemented for Dgraph")
+}
+
+// GetAncestors finds the entities above an entity in a hierarchy
+func (s *DgraphStore) GetAncestors(ctx context.Context, locator graph.EntityLocator, relationshipType string, maxDepth int) ([]graph.HierarchyEntry, error) {
+	// Placeholder implementation
+	return nil, fmt.Errorf("GetAncestors not implemented for Dgraph")
+}
+
+// GetDescendants finds the entities below an entity in a hierarchy
+func (s *DgraphStore) GetDescendants(ctx context.Context, locator graph.EntityLocator, relationshipType string, maxDepth int) ([]graph.HierarchyEntry, error) {
+	// Placeholder implementation
+	return nil, fmt.Errorf("GetDescendants not implemented for Dgraph")
+}
+
+// FindReachable finds the entities reachable from an entity within a number of hops
+func (s *DgraphStore) FindReachable(ctx context.Context, locator graph.EntityLocator, maxDepth int, limit int) ([]graph.ReachableEntity, error) {
+	// Placeholder implementation
+	return nil, fmt.Errorf("FindReachable not implemented for Dgraph")
+}
+
+// GetEntityWithRelationships retrieves an entity together with all of its relationships.
+func (s *DgraphStore) GetEntityWithRelationships(ctx context.Context, locator graph.EntityLocator) (graph.EntityWithRelationships, error) {
+	// Placeholder implementation
+	return graph.EntityWithRelationships{}, fmt.Errorf("GetEntityWithRelationships not implemented for Dgraph")
+}
+
+// GetNodeRelationships retrieves the relationships of a node together with the nodes at their other ends.
+func (s *DgraphStore) GetNodeRelationships(ctx context.Context, id string, direction string) ([]graph.EntityRelationship, error) {
+	// Placeholder implementation
+	return nil, fmt.Errorf("GetNodeRelationships not implemented for Dgraph")
+}
+
+// ListRelationships retrieves an entity's relationships, filtered by type and by the labels of the other node.
+func (s *DgraphStore) ListRelationships(ctx context.Context, locator graph.EntityLocator, direction string, relationshipTypes []string, otherNodeLabels []string) ([]graph.EntityRelationship, error) {
+	// Placeholder implementation
+	return nil, fmt.Errorf("ListRelationships not implemented for Dgraph")
+}
+
+// GetDefinitionLocation finds the file and line range where an entity is defined.
+func (s *DgraphStore) GetDefinitionLocation(ctx context.Context, locator graph.EntityLocator) (graph.DefinitionLocation, error) {
+	// Placeholder implementation
+	return graph.DefinitionLocation{}, fmt.Errorf("GetDefinitionLocation not implemented for Dgraph")
+}
+
+// GetEntitySubgraphPage retrieves one page of the subgraph around a central entity.
+func (s *DgraphStore) GetEntitySubgraphPage(ctx context.Context, labels []string, identifyingProperties map[string]interface{}, maxDepth int, includeLabels []string, offset int, pageSize int) (graph.SubgraphPage, error) {
+	// Placeholder implementation
+	return graph.SubgraphPage{}, fmt.Errorf("GetEntitySubgraphPage not implemented for Dgraph")
+}
+
 // --- Batch Operations ---
 
 // BatchFindOrCreateEntities finds or creates multiple entities in a single operation.
@@ -207,7 +419,7 @@ func (s *DgraphStore) BatchFindOrCreateEntities(ctx context.Context, inputs []gr
 	for i := range individualErrors {
 		individualErrors[i] = fmt.Errorf("BatchFindOrCreateEntities not implemented for Dgraph")
 	}
-	return nil, individualErrors, fmt.Errorf("BatchFindOrCreateEntities not implemented for Dgraph")
+	return make([]graph.EntityDetails, len(inputs)), individualErrors, fmt.Errorf("BatchFindOrCreateEntities not implemented for Dgraph")
 }
 
 // BatchFindOrCreateRelationships finds or creates multiple relationships in a single operation.
@@ -219,22 +431,241 @@ func (s *DgraphStore) BatchFindOrCreateRelationships(ctx context.Context, inputs
 	for i := range individualErrors {
 		individualErrors[i] = fmt.Errorf("BatchFindOrCreateRelationships not implemented for Dgraph")
 	}
-	return nil, individualErrors, fmt.Errorf("BatchFindOrCreateRelationships not implemented for Dgraph")
+	return make([]map[string]interface{}, len(inputs)), individualErrors, fmt.Errorf("BatchFindOrCreateRelationships not implemented for Dgraph")
+}
+
+// BatchCreateNodes creates multiple nodes of the same type in a single operation.
+func (s *DgraphStore) BatchCreateNodes(ctx context.Context, nodeType string, properties []map[string]interface{}) ([]string, error) {
+	// Placeholder implementation
+	return nil, fmt.Errorf("BatchCreateNodes not implemented for Dgraph")
+}
+
+// --- Analysis Operations ---
+
+// Capabilities reports which optional server-side libraries are available.
+// Dgraph has no equivalent of APOC or GDS, so nothing is reported as available.
+func (s *DgraphStore) Capabilities(ctx context.Context) (graph.Capabilities, error) {
+	return graph.Capabilities{}, nil
+}
+
+// Centrality ranks entities by importance within a subgraph.
+func (s *DgraphStore) Centrality(ctx context.Context, labels []string, relationshipTypes []string, topN int) (graph.CentralityResult, error) {
+	// Placeholder implementation
+	return graph.CentralityResult{}, fmt.Errorf("Centrality not implemented for Dgraph")
+}
+
+// RelationshipTypeCounts counts an entity's relationships by type.
+func (s *DgraphStore) RelationshipTypeCounts(ctx context.Context, locator graph.EntityLocator, direction string) (map[string]int64, error) {
+	// Placeholder implementation
+	return nil, fmt.Errorf("RelationshipTypeCounts not implemented for Dgraph")
+}
+
+// LabelHistogram counts nodes by label.
+func (s *DgraphStore) LabelHistogram(ctx context.Context) (map[string]int64, error) {
+	// Placeholder implementation
+	return nil, fmt.Errorf("LabelHistogram not implemented for Dgraph")
+}
+
+// GraphOverview counts nodes by label and relationships by type.
+func (s *DgraphStore) GraphOverview(ctx context.Context) (graph.GraphOverview, error) {
+	// Placeholder implementation
+	return graph.GraphOverview{}, fmt.Errorf("GraphOverview not implemented for Dgraph")
+}
+
+// DescribeEntityModel reports the identifying-property conventions of each label.
+func (s *DgraphStore) DescribeEntityModel(ctx context.Context) (graph.EntityModel, error) {
+	// Placeholder implementation
+	return graph.EntityModel{}, fmt.Errorf("DescribeEntityModel not implemented for Dgraph")
+}
+
+// MaxDependencyDepth finds the longest dependency chain starting from an entity.
+func (s *DgraphStore) MaxDependencyDepth(ctx context.Context, locator graph.EntityLocator, relationshipTypes []string, maxDepth int) (graph.DependencyDepthResult, error) {
+	// Placeholder implementation
+	return graph.DependencyDepthResult{}, fmt.Errorf("MaxDependencyDepth not implemented for Dgraph")
+}
+
+// CouplingMetrics computes the fan-in, fan-out and instability of every entity with the given label.
+func (s *DgraphStore) CouplingMetrics(ctx context.Context, label string, relationshipTypes []string) ([]graph.CouplingMetric, error) {
+	// Placeholder implementation
+	return nil, fmt.Errorf("CouplingMetrics not implemented for Dgraph")
+}
+
+// SimilarEntities ranks other entities by the number of neighbours they share with an entity.
+func (s *DgraphStore) SimilarEntities(ctx context.Context, locator graph.EntityLocator, relationshipTypes []string, topK int) ([]graph.SimilarEntity, error) {
+	// Placeholder implementation
+	return nil, fmt.Errorf("SimilarEntities not implemented for Dgraph")
 }
 
-// DeleteNode deletes a node by ID
+// --- Maintenance Operations ---
+
+// FindDuplicates groups entities with the given label by the key properties.
+func (s *DgraphStore) FindDuplicates(ctx context.Context, label string, keyProperties []string) ([]graph.DuplicateGroup, error) {
+	// Placeholder implementation
+	return nil, fmt.Errorf("FindDuplicates not implemented for Dgraph")
+}
+
+// CopyProperties copies the given properties from one entity to another.
+func (s *DgraphStore) CopyProperties(ctx context.Context, from graph.EntityLocator, to graph.EntityLocator, keys []string, overwrite bool) (graph.EntityDetails, error) {
+	// Placeholder implementation
+	return graph.EntityDetails{}, fmt.Errorf("CopyProperties not implemented for Dgraph")
+}
+
+// DecayConfidence reduces the confidence of relationships based on their age.
+func (s *DgraphStore) DecayConfidence(ctx context.Context, relationshipTypes []string, halfLife time.Duration) (int64, error) {
+	// Placeholder implementation
+	return 0, fmt.Errorf("DecayConfidence not implemented for Dgraph")
+}
+
+// FindMissingRelationships finds entities that have no relationship of the given type.
+func (s *DgraphStore) FindMissingRelationships(ctx context.Context, labels []string, relationshipType string, direction string) ([]graph.EntityDetails, error) {
+	// Placeholder implementation
+	return nil, fmt.Errorf("FindMissingRelationships not implemented for Dgraph")
+}
+
+// SetEntityStatus sets or clears an entity's status.
+func (s *DgraphStore) SetEntityStatus(ctx context.Context, locator graph.EntityLocator, status string) (graph.EntityDetails, error) {
+	// Placeholder implementation
+	return graph.EntityDetails{}, fmt.Errorf("SetEntityStatus not implemented for Dgraph")
+}
+
+// ExportSubgraph copies the nodes with any of the given labels and their relationships.
+func (s *DgraphStore) ExportSubgraph(ctx context.Context, labels []string) (graph.GraphSnapshot, error) {
+	// Placeholder implementation
+	return graph.GraphSnapshot{}, fmt.Errorf("ExportSubgraph not implemented for Dgraph")
+}
+
+// RestoreSubgraph replaces the nodes with any of the snapshot's labels with those in the snapshot.
+func (s *DgraphStore) RestoreSubgraph(ctx context.Context, snapshot graph.GraphSnapshot) (graph.RestoreResult, error) {
+	// Placeholder implementation
+	return graph.RestoreResult{}, fmt.Errorf("RestoreSubgraph not implemented for Dgraph")
+}
+
+// DeleteRelationshipsByType deletes every relationship of the given type.
+func (s *DgraphStore) DeleteRelationshipsByType(ctx context.Context, relType string, dryRun bool) (int, error) {
+	// Placeholder implementation
+	return 0, fmt.Errorf("DeleteRelationshipsByType not implemented for Dgraph")
+}
+
+// PruneLowConfidence deletes the relationships whose confidence is below a threshold.
+func (s *DgraphStore) PruneLowConfidence(ctx context.Context, relTypes []string, threshold float64, dryRun bool) (int, error) {
+	// Placeholder implementation
+	return 0, fmt.Errorf("PruneLowConfidence not implemented for Dgraph")
+}
+
+// BulkUpdateEntities updates the properties of every entity matching the given labels and property filters.
+func (s *DgraphStore) BulkUpdateEntities(ctx context.Context, labels []string, filters []graph.PropertyFilter, setProps map[string]interface{}, removeKeys []string, dryRun bool) (int, error) {
+	// Placeholder implementation
+	return 0, fmt.Errorf("BulkUpdateEntities not implemented for Dgraph")
+}
+
+// MaterializeTransitiveClosure creates direct relationships between entities connected by a path of a type.
+func (s *DgraphStore) MaterializeTransitiveClosure(ctx context.Context, relType, closureType string, maxDepth int) (graph.TransitiveClosureResult, error) {
+	// Placeholder implementation
+	return graph.TransitiveClosureResult{}, fmt.Errorf("MaterializeTransitiveClosure not implemented for Dgraph")
+}
+
+// SweepExpired deletes the nodes whose expiresAt property has passed.
+func (s *DgraphStore) SweepExpired(ctx context.Context, labels []string, dryRun bool) (int, error) {
+	// Placeholder implementation
+	return 0, fmt.Errorf("SweepExpired not implemented for Dgraph")
+}
+
+// CheckIntegrity checks the relationship graph against the configured integrity rules.
+func (s *DgraphStore) CheckIntegrity(ctx context.Context) (graph.IntegrityReport, error) {
+	// Placeholder implementation
+	return graph.IntegrityReport{}, fmt.Errorf("CheckIntegrity not implemented for Dgraph")
+}
+
+// --- Search Operations ---
+
+// FindModifiedSince finds entities modified at or after the given time.
+func (s *DgraphStore) FindModifiedSince(ctx context.Context, labels []string, since time.Time, limit int) ([]graph.EntityDetails, error) {
+	// Placeholder implementation
+	return nil, fmt.Errorf("FindModifiedSince not implemented for Dgraph")
+}
+
+// FindEntities finds entities matching the given labels and property filters.
+func (s *DgraphStore) FindEntities(ctx context.Context, labels []string, filters []graph.PropertyFilter, limit int) ([]graph.EntityDetails, error) {
+	// Placeholder implementation
+	return nil, fmt.Errorf("FindEntities not implemented for Dgraph")
+}
+
+// CountEntities counts entities matching the given labels and property filters.
+func (s *DgraphStore) CountEntities(ctx context.Context, labels []string, filters []graph.PropertyFilter) (int64, error) {
+	// Placeholder implementation
+	return 0, fmt.Errorf("CountEntities not implemented for Dgraph")
+}
+
+// SearchEntities finds entities with a string property containing the given text.
+func (s *DgraphStore) SearchEntities(ctx context.Context, labels []string, text string, limit int) ([]graph.EntityDetails, error) {
+	// Placeholder implementation
+	return nil, fmt.Errorf("SearchEntities not implemented for Dgraph")
+}
+
+// ListRelationshipsByType lists relationships of the given type with their endpoints.
+func (s *DgraphStore) ListRelationshipsByType(ctx context.Context, relType string, skip int, limit int) (graph.RelationshipList, error) {
+	// Placeholder implementation
+	return graph.RelationshipList{}, fmt.Errorf("ListRelationshipsByType not implemented for Dgraph")
+}
+
+// FindBySource finds entities with the given source.
+func (s *DgraphStore) FindBySource(ctx context.Context, labels []string, source string, limit int) ([]graph.EntityDetails, error) {
+	// Placeholder implementation
+	return nil, fmt.Errorf("FindBySource not implemented for Dgraph")
+}
+
+// FindByFilePath finds entities with the given file path, or within the given directory.
+func (s *DgraphStore) FindByFilePath(ctx context.Context, labels []string, filePath string, prefix bool, limit int) ([]graph.EntityDetails, error) {
+	// Placeholder implementation
+	return nil, fmt.Errorf("FindByFilePath not implemented for Dgraph")
+}
+
+// DeleteNode deletes a node by ID, together with the edges from other nodes pointing at it, like Neo4j's
+// DETACH DELETE. Deleting <uid> * * only removes the node's own predicates, so the nodes with an edge to it are
+// found through every uid predicate in the schema, and all the edges are deleted in the same transaction.
 func (s *DgraphStore) DeleteNode(ctx context.Context, id string) error {
 	txn := s.client.NewTxn()
 	defer txn.Discard(ctx)
 
+	// Find the edges pointing at the node
+	predicates, err := uidPredicates(ctx, txn)
+	if err != nil {
+		return fmt.Errorf("failed to delete node: %w", err)
+	}
+	nquads := []string{fmt.Sprintf(`<%s> * * .`, id)}
+	if len(predicates) > 0 {
+		var q strings.Builder
+		q.WriteString("{\n")
+		for i, predicate := range predicates {
+			fmt.Fprintf(&q, "\tp%d(func: has(<%s>)) @filter(uid_in(<%s>, %s)) { uid }\n", i, predicate, predicate, id)
+		}
+		q.WriteString("}")
+
+		resp, err := txn.Query(ctx, q.String())
+		if err != nil {
+			return fmt.Errorf("failed to find edges to node: %w", err)
+		}
+		var result map[string][]struct {
+			UID string `json:"uid"`
+		}
+		if err := json.Unmarshal(resp.Json, &result); err != nil {
+			return fmt.Errorf("failed to unmarshal response: %w", err)
+		}
+		for i, predicate := range predicates {
+			for _, source := range result[fmt.Sprintf("p%d", i)] {
+				nquads = append(nquads, fmt.Sprintf(`<%s> <%s> <%s> .`, source.UID, predicate, id))
+			}
+		}
+	}
+
 	// Create delete mutation
 	mu := &api.Mutation{
-		DelNquads: []byte(fmt.Sprintf(`<%s> * * .`, id)),
+		DelNquads: []byte(strings.Join(nquads, "\n")),
 		CommitNow: true,
 	}
 
 	// Execute mutation
-	_, err := txn.Mutate(ctx, mu)
+	_, err = txn.Mutate(ctx, mu)
 	if err != nil {
 		return fmt.Errorf("failed to delete node: %w", err)
 	}
@@ -242,6 +673,31 @@ func (s *DgraphStore) DeleteNode(ctx context.Context, id string) error {
 	return nil
 }
 
+// uidPredicates returns the predicates in the schema that hold edges to other nodes, apart from Dgraph's own
+func uidPredicates(ctx context.Context, txn dgraphtest.DgraphTxn) ([]string, error) {
+	resp, err := txn.Query(ctx, "schema {}")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query schema: %w", err)
+	}
+	var result struct {
+		Schema []struct {
+			Predicate string `json:"predicate"`
+			Type      string `json:"type"`
+		} `json:"schema"`
+	}
+	if err := json.Unmarshal(resp.Json, &result); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal schema: %w", err)
+	}
+
+	var predicates []string
+	for _, p := range result.Schema {
+		if p.Type == "uid" && !strings.HasPrefix(p.Predicate, "dgraph.") {
+			predicates = append(predicates, p.Predicate)
+		}
+	}
+	return predicates, nil
+}
+
 // CreateEdge creates a new edge between two nodes
 func (s *DgraphStore) CreateEdge(ctx context.Context, fromID, toID, relationshipType string, properties map[string]interface{}) (string, error) {
 	txn := s.client.NewTxn()
@@ -255,12 +711,10 @@ func (s *DgraphStore) CreateEdge(ctx context.Context, fromID, toID, relationship
 		},
 	}
 
-	// Add properties to the edge if provided
-	if len(properties) > 0 {
-		// In Dgraph, edge properties are represented as facets
-		// This is a simplified implementation
-		edgeData[relationshipType+"_props"] = properties
-	}
+	// Add properties to the edge, annotated with timestamps and provenance like edges created by any other path
+	// In Dgraph, edge properties are represented as facets
+	// This is a simplified implementation
+	edgeData[relationshipType+"_props"] = graph.NewRelationshipProperties(properties, time.Now().UTC(), graph.DefaultRelationshipSource)
 
 	// Create mutation
 	pb, err := json.Marshal(edgeData)
@@ -305,6 +759,11 @@ func (s *DgraphStore) DeleteEdge(ctx context.Context, id string) error {
 	return fmt.Errorf("not implemented: edges in Dgraph don't have their own IDs")
 }
 
+// QueryLanguage returns the language custom queries are written in
+func (s *DgraphStore) QueryLanguage() graph.QueryLanguage {
+	return graph.QueryLanguageDQL
+}
+
 // Query executes a custom query against the graph
 func (s *DgraphStore) Query(ctx context.Context, query string, params map[string]interface{}) ([]map[string]interface{}, error) {
 	txn := s.client.NewReadOnlyTxn()
@@ -340,15 +799,63 @@ func (s *DgraphStore) Query(ctx context.Context, query string, params map[string
 		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
 	}
 
-	// Extract results
+	// Extract results, giving any nodes in them the canonical shape
 	var results []map[string]interface{}
 	for _, v := range result {
-		results = append(results, v...)
+		for _, row := range v {
+			results = append(results, canonicalNode(row))
+		}
 	}
 
 	return results, nil
 }
 
+// canonicalNode adds the canonical node keys to a map returned by Dgraph, and to any maps nested in it, that
+// has a uid: the uid as its ID and its dgraph.type values, or otherwise its type property, as its labels. The
+// Dgraph-specific keys are kept. Maps without a uid (e.g. aggregates) are left as they are.
+func canonicalNode(m map[string]interface{}) map[string]interface{} {
+	for k, v := range m {
+		m[k] = canonicalValue(v)
+	}
+	uid, ok := m["uid"].(string)
+	if !ok {
+		return m
+	}
+
+	var labels []string
+	if types, ok := m["dgraph.type"].([]interface{}); ok {
+		for _, t := range types {
+			if label, ok := t.(string); ok {
+				labels = append(labels, label)
+			}
+		}
+	} else if nodeType, ok := m["type"].(string); ok && nodeType != "" {
+		labels = []string{nodeType}
+	}
+	return graph.NewNodeMap(uid, labels, m)
+}
+
+// canonicalValue applies canonicalNode to the maps within a value from a Dgraph response
+func canonicalValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return canonicalNode(v)
+	case []interface{}:
+		for i, item := range v {
+			v[i] = canonicalValue(item)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// QueryWithTypes executes a query and returns the results with column type metadata
+func (s *DgraphStore) QueryWithTypes(ctx context.Context, query string, params map[string]interface{}) (graph.TypedQueryResult, error) {
+	// Placeholder implementation
+	return graph.TypedQueryResult{}, fmt.Errorf("QueryWithTypes not implemented for Dgraph")
+}
+
 // UpsertSchema updates or creates the schema
 func (s *DgraphStore) UpsertSchema(ctx context.Context, schema string) error {
 	op := &api.Operation{
@@ -10,6 +10,7 @@ import (
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/sammcj/mcp-graph/internal/graph"
 	"github.com/sammcj/mcp-graph/internal/graph/dgraph/mocks"
 )
 
@@ -156,6 +157,10 @@ func TestGetNode(t *testing.T) {
 	assert.Equal(t, "Document", node["type"])
 	assert.Equal(t, "Test Document", node["title"])
 	assert.Equal(t, "Test content", node["content"])
+
+	// The node also has the canonical ID and labels shared with the other backends
+	assert.Equal(t, "0x1", node[graph.NodeIDKey])
+	assert.Equal(t, []string{"Document"}, node[graph.NodeLabelsKey])
 }
 
 func TestGetNode_NotFound(t *testing.T) {
@@ -257,6 +262,11 @@ func TestDeleteNode(t *testing.T) {
 	mockClient.EXPECT().NewTxn().Return(mockTxn)
 	mockTxn.EXPECT().Discard(gomock.Any()).Return(nil)
 
+	// No predicates can point at the node
+	mockTxn.EXPECT().Query(gomock.Any(), "schema {}").Return(&api.Response{
+		Json: []byte(`{"schema":[{"predicate":"dgraph.type","type":"string"},{"predicate":"title","type":"string"}]}`),
+	}, nil)
+
 	// Set up the mutation expectation
 	expectedMutation := &api.Mutation{
 		DelNquads: []byte(`<0x1> * * .`),
@@ -277,7 +287,7 @@ func TestDeleteNode(t *testing.T) {
 	assert.NoError(t, err)
 }
 
-func TestCreateEdge(t *testing.T) {
+func TestDeleteNode_IncomingEdges(t *testing.T) {
 	// Create a new mock controller
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -290,25 +300,73 @@ func TestCreateEdge(t *testing.T) {
 	mockClient.EXPECT().NewTxn().Return(mockTxn)
 	mockTxn.EXPECT().Discard(gomock.Any()).Return(nil)
 
-	// Set up the mutation expectation
-	expectedEdgeData := map[string]interface{}{
-		"uid": "0x1",
-		"RELATED_TO": map[string]interface{}{
-			"uid": "0x2",
-		},
-		"RELATED_TO_props": map[string]interface{}{
-			"strength": 0.8,
-		},
-	}
-	expectedJSON, _ := json.Marshal(expectedEdgeData)
+	// Two predicates can point at other nodes
+	gomock.InOrder(
+		mockTxn.EXPECT().Query(gomock.Any(), "schema {}").Return(&api.Response{
+			Json: []byte(`{"schema":[{"predicate":"DEPENDS_ON","type":"uid","list":true},{"predicate":"dgraph.user.group","type":"uid"},{"predicate":"CALLS","type":"uid","list":true},{"predicate":"name","type":"string"}]}`),
+		}, nil),
+		mockTxn.EXPECT().Query(gomock.Any(), "{\n\tp0(func: has(<DEPENDS_ON>)) @filter(uid_in(<DEPENDS_ON>, 0x1)) { uid }\n\tp1(func: has(<CALLS>)) @filter(uid_in(<CALLS>, 0x1)) { uid }\n}").Return(&api.Response{
+			Json: []byte(`{"p0":[{"uid":"0x2"},{"uid":"0x3"}],"p1":[{"uid":"0x4"}]}`),
+		}, nil),
+	)
+
+	// The node's own predicates and every edge pointing at it are deleted together, leaving none dangling
 	expectedMutation := &api.Mutation{
-		SetJson:   expectedJSON,
+		DelNquads: []byte("<0x1> * * .\n<0x2> <DEPENDS_ON> <0x1> .\n<0x3> <DEPENDS_ON> <0x1> .\n<0x4> <CALLS> <0x1> ."),
 		CommitNow: true,
 	}
+	mockTxn.EXPECT().Mutate(gomock.Any(), gomock.Eq(expectedMutation)).Return(&api.Response{}, nil)
 
-	// Mock the response
+	// Create a store with the mock client
+	store := NewDgraphStoreWithClient(mockClient)
+
+	// Call the method being tested
+	err := store.DeleteNode(context.Background(), "0x1")
+
+	// Assert the results
+	assert.NoError(t, err)
+}
+
+func TestDeleteNode_SchemaError(t *testing.T) {
+	// Create a new mock controller
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Create a mock client and transaction
+	mockClient := mocks.NewMockDgraphClient(ctrl)
+	mockTxn := mocks.NewMockDgraphTxn(ctrl)
+
+	// Nothing is deleted if the incoming edges can't be found
+	mockClient.EXPECT().NewTxn().Return(mockTxn)
+	mockTxn.EXPECT().Discard(gomock.Any()).Return(nil)
+	mockTxn.EXPECT().Query(gomock.Any(), "schema {}").Return(nil, errors.New("unavailable"))
+
+	store := NewDgraphStoreWithClient(mockClient)
+	err := store.DeleteNode(context.Background(), "0x1")
+	assert.Error(t, err)
+}
+
+func TestCreateEdge(t *testing.T) {
+	// Create a new mock controller
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Create a mock client and transaction
+	mockClient := mocks.NewMockDgraphClient(ctrl)
+	mockTxn := mocks.NewMockDgraphTxn(ctrl)
+
+	// Set up expectations
+	mockClient.EXPECT().NewTxn().Return(mockTxn)
+	mockTxn.EXPECT().Discard(gomock.Any()).Return(nil)
+
+	// Set up the mutation expectation, capturing the edge data to check its annotations
+	var edgeData map[string]interface{}
 	mockResponse := &api.Response{}
-	mockTxn.EXPECT().Mutate(gomock.Any(), gomock.Eq(expectedMutation)).Return(mockResponse, nil)
+	mockTxn.EXPECT().Mutate(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, mu *api.Mutation) (*api.Response, error) {
+		assert.True(t, mu.CommitNow)
+		assert.NoError(t, json.Unmarshal(mu.SetJson, &edgeData))
+		return mockResponse, nil
+	})
 
 	// Create a store with the mock client
 	store := NewDgraphStoreWithClient(mockClient)
@@ -322,6 +380,15 @@ func TestCreateEdge(t *testing.T) {
 	// Assert the results
 	assert.NoError(t, err)
 	assert.Equal(t, "0x1-RELATED_TO-0x2", id)
+	assert.Equal(t, "0x1", edgeData["uid"])
+	assert.Equal(t, map[string]interface{}{"uid": "0x2"}, edgeData["RELATED_TO"])
+
+	// The edge properties are annotated with timestamps and the default source
+	props := edgeData["RELATED_TO_props"].(map[string]interface{})
+	assert.Equal(t, 0.8, props["strength"])
+	assert.Equal(t, graph.DefaultRelationshipSource, props["source"])
+	assert.NotEmpty(t, props["createdAt"])
+	assert.Equal(t, props["createdAt"], props["lastModifiedAt"])
 }
 
 func TestQuery(t *testing.T) {
@@ -383,6 +450,38 @@ func TestQuery(t *testing.T) {
 	assert.Equal(t, "0x2", results[1]["uid"])
 	assert.Equal(t, "Document 2", results[1]["title"])
 	assert.Equal(t, "Content 2", results[1]["content"])
+	assert.Equal(t, "0x2", results[1][graph.NodeIDKey])
+}
+
+func TestCanonicalNode(t *testing.T) {
+	node := canonicalNode(map[string]interface{}{
+		"uid":         "0x1",
+		"dgraph.type": []interface{}{"Document"},
+		"title":       "Design",
+		"mentions": []interface{}{
+			map[string]interface{}{"uid": "0x2", "type": "Concept", "name": "Graphs"},
+		},
+	})
+
+	assert.Equal(t, map[string]interface{}{
+		graph.NodeIDKey:     "0x1",
+		graph.NodeLabelsKey: []string{"Document"},
+		"uid":               "0x1",
+		"dgraph.type":       []interface{}{"Document"},
+		"title":             "Design",
+		"mentions": []interface{}{
+			map[string]interface{}{
+				graph.NodeIDKey:     "0x2",
+				graph.NodeLabelsKey: []string{"Concept"},
+				"uid":               "0x2",
+				"type":              "Concept",
+				"name":              "Graphs",
+			},
+		},
+	}, node)
+
+	// Rows that aren't nodes are left alone
+	assert.Equal(t, map[string]interface{}{"count": float64(3)}, canonicalNode(map[string]interface{}{"count": float64(3)}))
 }
 
 func TestUpsertSchema(t *testing.T) {
@@ -416,3 +515,63 @@ func TestUpsertSchema(t *testing.T) {
 	// Assert the results
 	assert.NoError(t, err)
 }
+
+func TestPing(t *testing.T) {
+	// Create a new mock controller
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Create a mock client and transaction
+	mockClient := mocks.NewMockDgraphClient(ctrl)
+	mockTxn := mocks.NewMockDgraphTxn(ctrl)
+
+	// Set up expectations
+	mockClient.EXPECT().NewReadOnlyTxn().Return(mockTxn)
+	mockTxn.EXPECT().Discard(gomock.Any()).Return(nil)
+	mockTxn.EXPECT().Query(gomock.Any(), gomock.Any()).Return(&api.Response{}, nil)
+
+	// Create store with mock client
+	store := NewDgraphStoreWithClient(mockClient)
+
+	// Call the method
+	err := store.Ping(context.Background())
+
+	// Assert the results
+	assert.NoError(t, err)
+}
+
+func TestPingError(t *testing.T) {
+	// Create a new mock controller
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Create a mock client and transaction
+	mockClient := mocks.NewMockDgraphClient(ctrl)
+	mockTxn := mocks.NewMockDgraphTxn(ctrl)
+
+	// Set up expectations
+	mockClient.EXPECT().NewReadOnlyTxn().Return(mockTxn)
+	mockTxn.EXPECT().Discard(gomock.Any()).Return(nil)
+	mockTxn.EXPECT().Query(gomock.Any(), gomock.Any()).Return(nil, errors.New("connection refused"))
+
+	// Create store with mock client
+	store := NewDgraphStoreWithClient(mockClient)
+
+	// Call the method
+	err := store.Ping(context.Background())
+
+	// Assert the error
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to ping Dgraph")
+}
+
+func TestReconnect_WithoutAddress(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Stores given a client have no address to re-dial
+	store := NewDgraphStoreWithClient(mocks.NewMockDgraphClient(ctrl))
+	err := store.Reconnect(context.Background())
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot reconnect")
+}
@@ -0,0 +1,71 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Errors wrapped by Store.Ping to explain why the backend is unreachable.
+var (
+	ErrConnectionRefused = errors.New("connection refused")
+	ErrAuthFailed        = errors.New("authentication failed")
+	ErrTimeout           = errors.New("timed out")
+)
+
+// ErrPropertyTooLarge is returned when a string property value exceeds the store's configured size limit.
+var ErrPropertyTooLarge = errors.New("property value too large")
+
+// ErrRelationshipTypeNotAllowed is returned when a relationship type isn't on the store's configured allow-list.
+var ErrRelationshipTypeNotAllowed = errors.New("relationship type not allowed")
+
+// ErrPropertyNotAllowed is returned when a node would get a property that isn't on the store's configured
+// whitelist for its label.
+var ErrPropertyNotAllowed = errors.New("property not allowed")
+
+// Reasons reported by UnavailableReason.
+const (
+	ReasonConnectionRefused = "connection_refused"
+	ReasonAuthFailed        = "auth_failed"
+	ReasonTimeout           = "timeout"
+	ReasonUnknown           = "unknown"
+)
+
+// UnavailableReason classifies an error returned by Store.Ping as one of the Reason constants.
+func UnavailableReason(err error) string {
+	switch {
+	case errors.Is(err, ErrConnectionRefused):
+		return ReasonConnectionRefused
+	case errors.Is(err, ErrAuthFailed):
+		return ReasonAuthFailed
+	case errors.Is(err, ErrTimeout), errors.Is(err, context.DeadlineExceeded):
+		return ReasonTimeout
+	default:
+		return ReasonUnknown
+	}
+}
+
+// EndpointNotFoundError is returned when creating a relationship whose start and/or end node does not exist.
+// Callers can use errors.As to find out which endpoint is missing and create it before retrying.
+type EndpointNotFoundError struct {
+	StartMissing bool     `json:"startMissing"`
+	EndMissing   bool     `json:"endMissing"`
+	StartLabels  []string `json:"startLabels,omitempty"`
+	EndLabels    []string `json:"endLabels,omitempty"`
+}
+
+// Error implements the error interface.
+func (e *EndpointNotFoundError) Error() string {
+	var missing []string
+	if e.StartMissing {
+		missing = append(missing, fmt.Sprintf("start node (:%s)", strings.Join(e.StartLabels, ":")))
+	}
+	if e.EndMissing {
+		missing = append(missing, fmt.Sprintf("end node (:%s)", strings.Join(e.EndLabels, ":")))
+	}
+	if len(missing) > 1 {
+		return strings.Join(missing, " and ") + " not found; create them before creating the relationship"
+	}
+	return strings.Join(missing, " and ") + " not found; create it before creating the relationship"
+}
@@ -0,0 +1,18 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnavailableReason(t *testing.T) {
+	assert.Equal(t, ReasonConnectionRefused, UnavailableReason(fmt.Errorf("%w: dial tcp", ErrConnectionRefused)))
+	assert.Equal(t, ReasonAuthFailed, UnavailableReason(fmt.Errorf("%w: unauthorized", ErrAuthFailed)))
+	assert.Equal(t, ReasonTimeout, UnavailableReason(fmt.Errorf("%w: i/o timeout", ErrTimeout)))
+	assert.Equal(t, ReasonTimeout, UnavailableReason(fmt.Errorf("ping: %w", context.DeadlineExceeded)))
+	assert.Equal(t, ReasonUnknown, UnavailableReason(errors.New("something else")))
+}
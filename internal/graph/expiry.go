@@ -0,0 +1,31 @@
+package graph
+
+import (
+	"context"
+	"time"
+)
+
+// ExpirySweeper is implemented by stores that can delete nodes whose expiresAt property has passed.
+type ExpirySweeper interface {
+	SweepExpired(ctx context.Context, labels []string, dryRun bool) (int, error)
+}
+
+// RunExpirySweeps deletes expired nodes with any of the given labels (all nodes if empty) every interval until ctx is
+// cancelled. Each sweep is limited to the interval. onSweep, if set, is called after each sweep with its result.
+func RunExpirySweeps(ctx context.Context, sweeper ExpirySweeper, labels []string, interval time.Duration, onSweep func(deleted int, err error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			sweepCtx, cancel := context.WithTimeout(ctx, interval)
+			deleted, err := sweeper.SweepExpired(sweepCtx, labels, false)
+			cancel()
+			if onSweep != nil {
+				onSweep(deleted, err)
+			}
+		}
+	}
+}
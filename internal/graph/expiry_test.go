@@ -0,0 +1,60 @@
+package graph
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// countingSweeper records the sweeps it is asked to run
+type countingSweeper struct {
+	sweeps chan []string
+}
+
+func (s *countingSweeper) SweepExpired(ctx context.Context, labels []string, dryRun bool) (int, error) {
+	if dryRun {
+		return 0, nil
+	}
+	select {
+	case s.sweeps <- labels:
+	default: // Later sweeps aren't checked
+	}
+	return 2, nil
+}
+
+func TestRunExpirySweeps(t *testing.T) {
+	sweeper := &countingSweeper{sweeps: make(chan []string, 10)}
+	deleted := make(chan int, 10)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		RunExpirySweeps(ctx, sweeper, []string{"Annotation"}, time.Millisecond, func(n int, err error) {
+			assert.NoError(t, err)
+			select {
+			case deleted <- n:
+			default:
+			}
+		})
+		close(done)
+	}()
+
+	// Sweeps run on the schedule with the configured labels
+	select {
+	case labels := <-sweeper.sweeps:
+		assert.Equal(t, []string{"Annotation"}, labels)
+		assert.Equal(t, 2, <-deleted)
+	case <-time.After(time.Second):
+		t.Fatal("no sweep was run")
+	}
+
+	// And stop with the context
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("RunExpirySweeps did not return after the context was cancelled")
+	}
+}
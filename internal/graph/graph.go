@@ -1,12 +1,30 @@
 package graph
 
-import "context"
+import (
+	"context"
+	"time"
+)
+
+// MaxNearestByLabelDepth caps the depth searched by FindNearestByLabel, as without APOC the search enumerates
+// every path up to that length
+const MaxNearestByLabelDepth = 10
 
 // Store defines the core knowledge graph operations
 type Store interface {
-	// Node operations
+	// Ping checks that the underlying database is reachable. Failures wrap ErrConnectionRefused, ErrAuthFailed
+	// or ErrTimeout where the cause is known.
+	Ping(ctx context.Context) error
+
+	// Node operations. Nodes are returned in the canonical shape built by NewNodeMap.
 	CreateNode(ctx context.Context, nodeType string, properties map[string]interface{}) (string, error)
+	// CreateNodeIfNotExists finds the node of the given type whose key property equals value, creating it with the
+	// given properties if there is none, in a single MERGE. Existing nodes are left unchanged. Returns the node's ID
+	// and whether it was created.
+	CreateNodeIfNotExists(ctx context.Context, nodeType, key string, value interface{}, properties map[string]interface{}) (string, bool, error)
 	GetNode(ctx context.Context, id string) (map[string]interface{}, error)
+	// GetNodesByIDs retrieves the nodes with the given IDs in a single query, in the order of ids. IDs of nodes
+	// that don't exist are skipped.
+	GetNodesByIDs(ctx context.Context, ids []string) ([]map[string]interface{}, error)
 	UpdateNode(ctx context.Context, id string, properties map[string]interface{}) error
 	DeleteNode(ctx context.Context, id string) error
 
@@ -17,7 +35,11 @@ type Store interface {
 	DeleteEdge(ctx context.Context, id string) error
 
 	// Query operations
+
+	// QueryLanguage returns the language Query and QueryWithTypes accept.
+	QueryLanguage() QueryLanguage
 	Query(ctx context.Context, query string, params map[string]interface{}) ([]map[string]interface{}, error)
+	QueryWithTypes(ctx context.Context, query string, params map[string]interface{}) (TypedQueryResult, error)
 
 	// Schema operations
 	UpsertSchema(ctx context.Context, schema string) error
@@ -29,37 +51,233 @@ type Store interface {
 	// Returns the details of the found or created entity.
 	FindOrCreateEntity(ctx context.Context, input EntityInput) (EntityDetails, error)
 
+	// FindOrCreateEntityWithChanges finds or creates an entity like FindOrCreateEntity, and also reports whether it
+	// was created and the old and new values of each property the merge changed.
+	FindOrCreateEntityWithChanges(ctx context.Context, input EntityInput) (EntityUpsertResult, error)
+
 	// FindOrCreateRelationship finds a relationship or creates it if not found.
 	// It merges the provided properties with any existing ones.
 	// Returns the properties of the found or created relationship.
 	FindOrCreateRelationship(ctx context.Context, input RelationshipInput) (map[string]interface{}, error)
 
+	// UpsertEntityWithRelationship finds or creates an entity and a relationship between it and an existing target
+	// node in a single transaction, so the entity never exists without the relationship. The entity's end of rel
+	// (start or end) is left empty and the other end identifies the target. Nothing is written if the target
+	// doesn't exist.
+	UpsertEntityWithRelationship(ctx context.Context, entity EntityInput, rel RelationshipInput) (EntityWithRelationship, error)
+
 	// GetEntityDetails retrieves the labels and properties of a specific entity.
-	GetEntityDetails(ctx context.Context, labels []string, identifyingProperties map[string]interface{}) (EntityDetails, error)
+	// If properties is non-empty, only those properties (plus id) are returned.
+	GetEntityDetails(ctx context.Context, labels []string, identifyingProperties map[string]interface{}, properties []string) (EntityDetails, error)
 
 	// FindNeighbors finds the direct neighbors of a given entity up to a specified depth (depth 1 for direct neighbors).
 	FindNeighbors(ctx context.Context, labels []string, identifyingProperties map[string]interface{}, maxDepth int) (NeighborsResult, error)
 
 	// FindDependencies finds entities that the target entity depends on, up to a specified depth.
-	FindDependencies(ctx context.Context, labels []string, identifyingProperties map[string]interface{}, relationshipTypes []string, maxDepth int) (DependencyResult, error)
+	// If relationshipPropertyFilters is non-empty, only relationships whose properties match every filter are followed.
+	FindDependencies(ctx context.Context, labels []string, identifyingProperties map[string]interface{}, relationshipTypes []string, relationshipPropertyFilters map[string]interface{}, maxDepth int) (DependencyResult, error)
 
 	// FindDependents finds entities that depend on the target entity, up to a specified depth.
 	FindDependents(ctx context.Context, labels []string, identifyingProperties map[string]interface{}, relationshipTypes []string, maxDepth int) (DependencyResult, error)
 
-	// GetEntitySubgraph retrieves nodes and relationships around a central entity, suitable for visualisation.
-	GetEntitySubgraph(ctx context.Context, labels []string, identifyingProperties map[string]interface{}, maxDepth int) (SubgraphResult, error)
+	// GetEntitySubgraph retrieves nodes and relationships around a central entity, suitable for visualisation. If
+	// includeLabels is non-empty, only the central entity and the nodes with any of those labels are returned, with
+	// the relationships among them.
+	GetEntitySubgraph(ctx context.Context, labels []string, identifyingProperties map[string]interface{}, maxDepth int, includeLabels []string) (SubgraphResult, error)
+
+	// GetEntitySubgraphPage retrieves one page of the subgraph around a central entity: up to pageSize nodes starting
+	// at offset (in a stable order), along with the subgraph relationships that start at those nodes. includeLabels
+	// filters the nodes as in GetEntitySubgraph.
+	GetEntitySubgraphPage(ctx context.Context, labels []string, identifyingProperties map[string]interface{}, maxDepth int, includeLabels []string, offset int, pageSize int) (SubgraphPage, error)
+
+	// CommonDependencies finds entities that all of the given entities depend on, up to a specified depth.
+	CommonDependencies(ctx context.Context, locators []EntityLocator, relationshipTypes []string, maxDepth int) (CommonDependenciesResult, error)
+
+	// FindNearestByLabel finds the closest entity with the target label to the given entity, following relationships
+	// in either direction up to a specified depth (at most MaxNearestByLabelDepth), and returns the path to it. Found
+	// is false if there is none.
+	FindNearestByLabel(ctx context.Context, from EntityLocator, targetLabel string, relationshipTypes []string, maxDepth int) (PathResult, error)
+
+	// FindShortestPath finds the shortest path between two entities, following relationships in either direction.
+	// If weightProperty is set and APOC is installed, the path with the lowest total of that relationship property is
+	// found with Dijkstra's algorithm; otherwise the path with the fewest relationships, up to maxDepth, is found.
+	FindShortestPath(ctx context.Context, from, to EntityLocator, relationshipTypes []string, weightProperty string, maxDepth int) (ShortestPathResult, error)
+
+	// DependencyPath finds the shortest dependency route from one entity to another, following outgoing relationships
+	// only, up to a specified depth. It explains how the first entity ends up depending on the second. Found is false
+	// if there is none.
+	DependencyPath(ctx context.Context, from, to EntityLocator, relationshipTypes []string, maxDepth int) (PathResult, error)
+
+	// GetAncestors finds the entities above an entity in the hierarchy formed by a relationship type that points from
+	// parent to child (e.g. CONTAINS), up to a specified depth, ordered by depth: parents first, then grandparents.
+	GetAncestors(ctx context.Context, locator EntityLocator, relationshipType string, maxDepth int) ([]HierarchyEntry, error)
+
+	// GetDescendants finds the entities below an entity in the hierarchy formed by a relationship type that points
+	// from parent to child (e.g. CONTAINS), up to a specified depth, ordered by depth: children first.
+	GetDescendants(ctx context.Context, locator EntityLocator, relationshipType string, maxDepth int) ([]HierarchyEntry, error)
+
+	// FindReachable finds the distinct entities reachable from an entity within maxDepth relationships of any type,
+	// followed in either direction, each with its shortest distance. Results are ordered by distance, nearest first,
+	// and limited to limit entities.
+	FindReachable(ctx context.Context, locator EntityLocator, maxDepth int, limit int) ([]ReachableEntity, error)
+
+	// GetEntityWithRelationships retrieves an entity together with all of its relationships (in both directions)
+	// and the nodes at their other ends.
+	GetEntityWithRelationships(ctx context.Context, locator EntityLocator) (EntityWithRelationships, error)
+
+	// GetNodeRelationships retrieves the relationships of the node with the given ID in the given direction
+	// (outgoing, incoming or both), together with the nodes at their other ends.
+	GetNodeRelationships(ctx context.Context, id string, direction string) ([]EntityRelationship, error)
+
+	// ListRelationships retrieves an entity's relationships in the given direction (outgoing, incoming or both),
+	// together with the nodes at their other ends. Empty relationshipTypes matches any type, and non-empty
+	// otherNodeLabels keeps only relationships whose other node has at least one of those labels.
+	ListRelationships(ctx context.Context, locator EntityLocator, direction string, relationshipTypes []string, otherNodeLabels []string) ([]EntityRelationship, error)
+
+	// GetDefinitionLocation finds the File an entity is DEFINED_IN and the line range of its definition.
+	GetDefinitionLocation(ctx context.Context, locator EntityLocator) (DefinitionLocation, error)
 
 	// --- Batch Operations ---
 
 	// BatchFindOrCreateEntities finds or creates multiple entities in a single operation.
 	// This is more efficient than making multiple individual calls.
-	// Returns details for all entities in the same order as the input array.
+	// Returns details for all entities in the same order as the input array, along with an error per input. Both
+	// slices always have one entry per input, even when the overall error is set; results[i] is zero-valued when
+	// the input at index i failed.
 	BatchFindOrCreateEntities(ctx context.Context, inputs []EntityInput) ([]EntityDetails, []error, error)
 
 	// BatchFindOrCreateRelationships finds or creates multiple relationships in a single operation.
 	// This is more efficient than making multiple individual calls.
-	// Returns properties for all relationships in the same order as the input array.
+	// Returns properties for all relationships in the same order as the input array, along with an error per input.
+	// Both slices always have one entry per input, even when the overall error is set; results[i] is nil when the
+	// input at index i failed.
 	BatchFindOrCreateRelationships(ctx context.Context, inputs []RelationshipInput) ([]map[string]interface{}, []error, error)
+
+	// BatchCreateNodes creates a node of the given type for each properties map in a single operation.
+	// Returns the IDs of the created nodes in the same order as the input array.
+	BatchCreateNodes(ctx context.Context, nodeType string, properties []map[string]interface{}) ([]string, error)
+
+	// --- Analysis Operations ---
+
+	// Capabilities reports which optional server-side libraries (e.g. APOC, GDS) are available to the store.
+	Capabilities(ctx context.Context) (Capabilities, error)
+
+	// Centrality ranks entities by importance within the subgraph formed by the given labels and relationship types.
+	// Uses PageRank when a graph algorithms library is available, falling back to degree centrality otherwise.
+	Centrality(ctx context.Context, labels []string, relationshipTypes []string, topN int) (CentralityResult, error)
+
+	// RelationshipTypeCounts counts an entity's relationships by type in the given direction (outgoing, incoming or both).
+	RelationshipTypeCounts(ctx context.Context, locator EntityLocator, direction string) (map[string]int64, error)
+
+	// LabelHistogram counts nodes by label. A node with several labels is counted under each of them.
+	LabelHistogram(ctx context.Context) (map[string]int64, error)
+
+	// GraphOverview counts the nodes by label and relationships by type, and the totals and density of the graph.
+	GraphOverview(ctx context.Context) (GraphOverview, error)
+
+	// DescribeEntityModel reports, for each label in use, the property keys its entities are identified by, inferred
+	// from constraints, indexes and the properties of a sample of the entities.
+	DescribeEntityModel(ctx context.Context) (EntityModel, error)
+
+	// MaxDependencyDepth finds the longest chain of outgoing relationships (optionally restricted to the given types)
+	// starting from an entity, i.e. how many layers deep its dependencies go, exploring chains up to maxDepth long.
+	// Cycles are not followed.
+	MaxDependencyDepth(ctx context.Context, locator EntityLocator, relationshipTypes []string, maxDepth int) (DependencyDepthResult, error)
+
+	// CouplingMetrics computes the fan-in, fan-out and instability of every entity with the given label, counting
+	// relationships of the given types (all types if empty), most coupled entities first.
+	CouplingMetrics(ctx context.Context, label string, relationshipTypes []string) ([]CouplingMetric, error)
+
+	// SimilarEntities ranks the other entities by how many neighbours they share with an entity, counting
+	// relationships of the given types (all types if empty) in either direction. The topK most similar by Jaccard
+	// similarity of their neighbour sets are returned, most similar first.
+	SimilarEntities(ctx context.Context, locator EntityLocator, relationshipTypes []string, topK int) ([]SimilarEntity, error)
+
+	// --- Maintenance Operations ---
+
+	// FindDuplicates groups entities with the given label by the key properties and returns the groups containing
+	// more than one entity, largest first.
+	FindDuplicates(ctx context.Context, label string, keyProperties []string) ([]DuplicateGroup, error)
+
+	// CopyProperties copies the given properties from one entity to another and returns the updated target.
+	// Unless overwrite is set, properties already present on the target are left unchanged.
+	CopyProperties(ctx context.Context, from EntityLocator, to EntityLocator, keys []string, overwrite bool) (EntityDetails, error)
+
+	// DecayConfidence reduces the confidence of relationships (optionally restricted to the given types) based on
+	// their age, halving it for every halfLife since they were last modified. Returns the number updated.
+	DecayConfidence(ctx context.Context, relationshipTypes []string, halfLife time.Duration) (int64, error)
+
+	// FindMissingRelationships finds entities with all of the given labels that have no relationship of the given
+	// type in the given direction (outgoing, incoming or both), e.g. Functions with no outgoing DEFINED_IN.
+	FindMissingRelationships(ctx context.Context, labels []string, relationshipType string, direction string) ([]EntityDetails, error)
+
+	// SetEntityStatus sets an entity's status to one of EntityStatuses, or clears it if status is empty,
+	// and returns the updated entity.
+	SetEntityStatus(ctx context.Context, locator EntityLocator, status string) (EntityDetails, error)
+
+	// ExportSubgraph copies every node with any of the given labels, with all of its labels and properties, along with
+	// every relationship touching those nodes.
+	ExportSubgraph(ctx context.Context, labels []string) (GraphSnapshot, error)
+
+	// RestoreSubgraph replaces the nodes with any of the snapshot's labels with those in the snapshot, recreating
+	// their relationships, in a single transaction. Node IDs change.
+	RestoreSubgraph(ctx context.Context, snapshot GraphSnapshot) (RestoreResult, error)
+
+	// DeleteRelationshipsByType deletes every relationship of the given type, in batches, and returns the number
+	// deleted. With dryRun set nothing is deleted and the number that would be deleted is returned.
+	DeleteRelationshipsByType(ctx context.Context, relType string, dryRun bool) (int, error)
+
+	// PruneLowConfidence deletes the relationships (optionally restricted to the given types) whose confidence is
+	// below threshold, in batches, and returns the number deleted. Relationships without a confidence are kept. With
+	// dryRun set nothing is deleted and the number that would be deleted is returned.
+	PruneLowConfidence(ctx context.Context, relTypes []string, threshold float64, dryRun bool) (int, error)
+
+	// BulkUpdateEntities sets setProps on, and removes removeKeys from, every entity with any of the given labels (all
+	// entities if empty) matching every property filter, in batches, and returns the number updated. With dryRun set
+	// nothing is changed and the number that would be updated is returned.
+	BulkUpdateEntities(ctx context.Context, labels []string, filters []PropertyFilter, setProps map[string]interface{}, removeKeys []string, dryRun bool) (int, error)
+
+	// MaterializeTransitiveClosure creates a direct closureType relationship from each entity to every entity it
+	// reaches by a path of up to maxDepth relationships of relType, in batches. Existing closure relationships are
+	// kept, so running it again only adds the missing ones.
+	MaterializeTransitiveClosure(ctx context.Context, relType, closureType string, maxDepth int) (TransitiveClosureResult, error)
+
+	// SweepExpired deletes the nodes with any of the given labels (all nodes if empty) whose expiresAt property has
+	// passed, with their relationships, in batches, and returns the number deleted. With dryRun set nothing is
+	// deleted and the number that would be deleted is returned.
+	SweepExpired(ctx context.Context, labels []string, dryRun bool) (int, error)
+
+	// CheckIntegrity checks the relationship graph against the store's configured integrity rules and reports the
+	// entities missing required relationships and the relationships whose endpoints have unexpected labels.
+	CheckIntegrity(ctx context.Context) (IntegrityReport, error)
+
+	// --- Search Operations ---
+
+	// FindModifiedSince finds entities (optionally restricted to the given labels) modified at or after the given time,
+	// most recently modified first.
+	FindModifiedSince(ctx context.Context, labels []string, since time.Time, limit int) ([]EntityDetails, error)
+
+	// FindEntities finds entities with any of the given labels (all entities if empty) matching every property filter.
+	FindEntities(ctx context.Context, labels []string, filters []PropertyFilter, limit int) ([]EntityDetails, error)
+
+	// CountEntities counts entities with any of the given labels (all entities if empty) matching every property filter.
+	CountEntities(ctx context.Context, labels []string, filters []PropertyFilter) (int64, error)
+
+	// SearchEntities finds entities with any of the given labels (all entities if empty) that have a string property
+	// containing text, ignoring case, e.g. to locate a Function by part of its name or a File by part of its path.
+	SearchEntities(ctx context.Context, labels []string, text string, limit int) ([]EntityDetails, error)
+
+	// ListRelationshipsByType lists relationships of the given type with their endpoints, skipping the first skip
+	// relationships and returning at most limit, along with the total number of relationships of that type.
+	ListRelationshipsByType(ctx context.Context, relType string, skip int, limit int) (RelationshipList, error)
+
+	// FindBySource finds entities with any of the given labels (all entities if empty) whose source property (their
+	// provenance, e.g. 'manual', 'static-analysis' or 'agent-inference') equals source, most recently created first.
+	FindBySource(ctx context.Context, labels []string, source string, limit int) ([]EntityDetails, error)
+
+	// FindByFilePath finds entities with any of the given labels (all entities if empty) whose filePath property
+	// equals filePath or, with prefix set, names a file within the directory filePath, ordered by filePath.
+	FindByFilePath(ctx context.Context, labels []string, filePath string, prefix bool, limit int) ([]EntityDetails, error)
 }
 
 // NodeType represents common node types in the knowledge graph
@@ -83,6 +301,7 @@ const (
 	EdgeTypeReferencesTo EdgeType = "REFERENCES_TO"
 	EdgeTypeCreatedBy    EdgeType = "CREATED_BY"
 	EdgeTypeHasProperty  EdgeType = "HAS_PROPERTY"
+	EdgeTypeDescribes    EdgeType = "DESCRIBES"
 )
 
 // Node represents a node in the knowledge graph
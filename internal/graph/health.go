@@ -0,0 +1,106 @@
+package graph
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Reconnecter is implemented by stores that can re-establish their connection to the database, such as
+// Dgraph re-dialling its gRPC connection. Stores whose drivers reconnect by themselves don't implement it.
+type Reconnecter interface {
+	Reconnect(ctx context.Context) error
+}
+
+// HealthMonitor pings a store in the background and marks it unhealthy after a number of consecutive failures,
+// so that dropped connections are noticed (and, where the store supports it, re-established) before the next
+// request fails. The zero value is not usable; create one with NewHealthMonitor.
+type HealthMonitor struct {
+	pinger           Pinger
+	interval         time.Duration
+	failureThreshold int
+
+	// OnChange, if set, is called when the store becomes unhealthy or recovers, with the latest ping error.
+	OnChange func(healthy bool, err error)
+	// OnReconnect, if set, is called after each reconnection attempt with its result.
+	OnReconnect func(err error)
+
+	mu       sync.RWMutex
+	healthy  bool
+	failures int
+	lastErr  error
+}
+
+// NewHealthMonitor creates a monitor that pings p every interval and marks it unhealthy after failureThreshold
+// consecutive failures (at least 1). The store is assumed healthy until then.
+func NewHealthMonitor(p Pinger, interval time.Duration, failureThreshold int) *HealthMonitor {
+	if failureThreshold < 1 {
+		failureThreshold = 1
+	}
+	return &HealthMonitor{
+		pinger:           p,
+		interval:         interval,
+		failureThreshold: failureThreshold,
+		healthy:          true,
+	}
+}
+
+// Run pings the store every interval until ctx is cancelled. Each ping is limited to the interval.
+func (m *HealthMonitor) Run(ctx context.Context) {
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.check(ctx)
+		}
+	}
+}
+
+// Healthy reports whether the store is healthy and, if not, the ping error that made it unhealthy.
+func (m *HealthMonitor) Healthy() (bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.healthy, m.lastErr
+}
+
+// check pings the store once, updating its health and reconnecting if it has failed too many times in a row
+func (m *HealthMonitor) check(ctx context.Context) {
+	pingCtx, cancel := context.WithTimeout(ctx, m.interval)
+	err := m.pinger.Ping(pingCtx)
+	cancel()
+
+	m.mu.Lock()
+	wasHealthy := m.healthy
+	if err == nil {
+		m.failures = 0
+		m.healthy = true
+		m.lastErr = nil
+	} else {
+		m.failures++
+		m.lastErr = err
+		if m.failures >= m.failureThreshold {
+			m.healthy = false
+		}
+	}
+	healthy := m.healthy
+	m.mu.Unlock()
+
+	if healthy != wasHealthy && m.OnChange != nil {
+		m.OnChange(healthy, err)
+	}
+
+	// Keep trying to reconnect on every failed check once unhealthy; the next ping reports whether it worked
+	if !healthy {
+		if reconnecter, ok := m.pinger.(Reconnecter); ok {
+			reconnectCtx, cancel := context.WithTimeout(ctx, m.interval)
+			err := reconnecter.Reconnect(reconnectCtx)
+			cancel()
+			if m.OnReconnect != nil {
+				m.OnReconnect(err)
+			}
+		}
+	}
+}
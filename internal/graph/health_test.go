@@ -0,0 +1,79 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// scriptedPinger returns the scripted ping results in order and counts reconnection attempts
+type scriptedPinger struct {
+	results    []error
+	reconnects int
+}
+
+func (p *scriptedPinger) Ping(ctx context.Context) error {
+	err := p.results[0]
+	p.results = p.results[1:]
+	return err
+}
+
+func (p *scriptedPinger) Reconnect(ctx context.Context) error {
+	p.reconnects++
+	return nil
+}
+
+func TestHealthMonitor_UnhealthyAfterThreshold(t *testing.T) {
+	dropped := errors.New("connection refused")
+	pinger := &scriptedPinger{results: []error{dropped, dropped, dropped, nil}}
+	monitor := NewHealthMonitor(pinger, time.Second, 2)
+
+	var changes []bool
+	monitor.OnChange = func(healthy bool, err error) {
+		changes = append(changes, healthy)
+	}
+
+	// A single failure isn't enough to mark the store unhealthy
+	monitor.check(context.Background())
+	healthy, _ := monitor.Healthy()
+	assert.True(t, healthy)
+	assert.Equal(t, 0, pinger.reconnects)
+
+	// Sustained failures mark it unhealthy and reconnect on every check
+	monitor.check(context.Background())
+	healthy, err := monitor.Healthy()
+	assert.False(t, healthy)
+	assert.ErrorIs(t, err, dropped)
+	assert.Equal(t, 1, pinger.reconnects)
+
+	monitor.check(context.Background())
+	assert.Equal(t, 2, pinger.reconnects)
+
+	// A successful ping recovers
+	monitor.check(context.Background())
+	healthy, err = monitor.Healthy()
+	assert.True(t, healthy)
+	assert.NoError(t, err)
+	assert.Equal(t, []bool{false, true}, changes)
+}
+
+func TestHealthMonitor_RunStopsWithContext(t *testing.T) {
+	monitor := NewHealthMonitor(&countingPinger{}, time.Millisecond, 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		monitor.Run(ctx)
+		close(done)
+	}()
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Run did not return after the context was cancelled")
+	}
+}
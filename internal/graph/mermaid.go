@@ -0,0 +1,69 @@
+package graph
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MermaidAliases maps the ID of each node in a subgraph, and of any relationship endpoint missing from its nodes,
+// to a Mermaid-safe node ID. Element IDs such as "4:abc-123:5" contain characters Mermaid doesn't allow in node IDs,
+// so nodes are numbered n0, n1, ... in the order they are listed, followed by the missing endpoints in the order
+// they are first referred to. The same subgraph always gets the same aliases.
+func MermaidAliases(subgraph SubgraphResult) map[string]string {
+	aliases := make(map[string]string, len(subgraph.Nodes))
+	add := func(id string) {
+		if _, ok := aliases[id]; !ok {
+			aliases[id] = fmt.Sprintf("n%d", len(aliases))
+		}
+	}
+	for _, node := range subgraph.Nodes {
+		add(node.ID)
+	}
+	for _, rel := range subgraph.Relationships {
+		add(rel.StartNode)
+		add(rel.EndNode)
+	}
+	return aliases
+}
+
+// RenderMermaid renders a subgraph as a Mermaid flowchart. Nodes are shown by name (or ID if they have none) and
+// labels, and relationships by type, each referring to nodes by their alias from MermaidAliases. Relationship
+// endpoints missing from the subgraph's nodes, e.g. on a page of a larger subgraph, are shown by ID.
+func RenderMermaid(subgraph SubgraphResult) string {
+	aliases := MermaidAliases(subgraph)
+
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+	declared := make(map[string]bool, len(aliases))
+	for _, node := range subgraph.Nodes {
+		if declared[node.ID] {
+			continue
+		}
+		declared[node.ID] = true
+		text := node.Name
+		if text == "" {
+			text = node.ID
+		}
+		if len(node.Labels) > 0 {
+			text += " (" + strings.Join(node.Labels, ", ") + ")"
+		}
+		fmt.Fprintf(&b, "    %s[\"%s\"]\n", aliases[node.ID], mermaidText(text))
+	}
+	for _, rel := range subgraph.Relationships {
+		for _, id := range []string{rel.StartNode, rel.EndNode} {
+			if !declared[id] {
+				declared[id] = true
+				fmt.Fprintf(&b, "    %s[\"%s\"]\n", aliases[id], mermaidText(id))
+			}
+		}
+	}
+	for _, rel := range subgraph.Relationships {
+		fmt.Fprintf(&b, "    %s -->|\"%s\"| %s\n", aliases[rel.StartNode], mermaidText(rel.Type), aliases[rel.EndNode])
+	}
+	return b.String()
+}
+
+// mermaidText escapes text for use inside a quoted Mermaid node or edge label
+func mermaidText(text string) string {
+	return strings.NewReplacer(`"`, "#quot;", "\n", " ").Replace(text)
+}
@@ -0,0 +1,49 @@
+package graph
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderMermaid(t *testing.T) {
+	subgraph := SubgraphResult{
+		Nodes: []SubgraphNode{
+			{ID: "4:abc-123:5", Labels: []string{"Service"}, Name: "orders"},
+			{ID: "4:abc-123:7", Labels: []string{"DataStore"}, Name: `"main" db`},
+			{ID: "4:abc-123:9", Labels: []string{"Queue"}},
+		},
+		Relationships: []SubgraphRelationship{
+			{ID: "5:abc-123:1", StartNode: "4:abc-123:5", EndNode: "4:abc-123:7", Type: "WRITES_TO"},
+			{ID: "5:abc-123:2", StartNode: "4:abc-123:9", EndNode: "4:abc-123:5", Type: "TRIGGERS"},
+			{ID: "5:abc-123:3", StartNode: "4:abc-123:5", EndNode: "4:def-456:1", Type: "CALLS"},
+		},
+	}
+
+	// Nodes are aliased in order, followed by endpoints that aren't in the subgraph
+	assert.Equal(t, map[string]string{
+		"4:abc-123:5": "n0",
+		"4:abc-123:7": "n1",
+		"4:abc-123:9": "n2",
+		"4:def-456:1": "n3",
+	}, MermaidAliases(subgraph))
+
+	// Edges refer to the same aliases as the nodes, and no element ID is used as a node ID
+	expected := `flowchart LR
+    n0["orders (Service)"]
+    n1["#quot;main#quot; db (DataStore)"]
+    n2["4:abc-123:9 (Queue)"]
+    n3["4:def-456:1"]
+    n0 -->|"WRITES_TO"| n1
+    n2 -->|"TRIGGERS"| n0
+    n0 -->|"CALLS"| n3
+`
+	assert.Equal(t, expected, RenderMermaid(subgraph))
+
+	// Rendering is deterministic
+	assert.Equal(t, RenderMermaid(subgraph), RenderMermaid(subgraph))
+}
+
+func TestRenderMermaid_Empty(t *testing.T) {
+	assert.Equal(t, "flowchart LR\n", RenderMermaid(SubgraphResult{}))
+}
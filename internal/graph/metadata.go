@@ -0,0 +1,26 @@
+package graph
+
+import "context"
+
+// queryMetadataKey is the context key for query metadata.
+type queryMetadataKey struct{}
+
+// WithQueryMetadata returns a context carrying metadata (e.g. the tool name, request ID and client ID) to attach
+// to the database transactions run with it, so that operators can attribute running queries to their cause.
+// The metadata is merged with any already present in ctx, with the new values taking precedence.
+func WithQueryMetadata(ctx context.Context, metadata map[string]interface{}) context.Context {
+	merged := make(map[string]interface{}, len(metadata))
+	for k, v := range QueryMetadataFromContext(ctx) {
+		merged[k] = v
+	}
+	for k, v := range metadata {
+		merged[k] = v
+	}
+	return context.WithValue(ctx, queryMetadataKey{}, merged)
+}
+
+// QueryMetadataFromContext returns the query metadata carried by ctx, or nil if there is none.
+func QueryMetadataFromContext(ctx context.Context) map[string]interface{} {
+	metadata, _ := ctx.Value(queryMetadataKey{}).(map[string]interface{})
+	return metadata
+}
@@ -0,0 +1,17 @@
+package graph
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithQueryMetadata(t *testing.T) {
+	assert.Nil(t, QueryMetadataFromContext(context.Background()))
+
+	ctx := WithQueryMetadata(context.Background(), map[string]interface{}{"clientId": "abc", "tool": "query_knowledge_graph"})
+	ctx = WithQueryMetadata(ctx, map[string]interface{}{"tool": "create_node"})
+
+	assert.Equal(t, map[string]interface{}{"clientId": "abc", "tool": "create_node"}, QueryMetadataFromContext(ctx))
+}
@@ -0,0 +1,713 @@
+package neo4j
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/sammcj/mcp-graph/internal/graph"
+)
+
+// Capabilities reports which optional server-side libraries are installed by inspecting the
+// registered procedures. Requires Neo4j 4.3+ for SHOW PROCEDURES.
+func (s *Neo4jStore) Capabilities(ctx context.Context) (graph.Capabilities, error) {
+	query := "SHOW PROCEDURES YIELD name RETURN collect(name) AS names"
+
+	result, err := neo4j.ExecuteQuery(ctx, s.driver, query, nil, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase(""), txMetadata(ctx))
+	if err != nil {
+		return graph.Capabilities{}, fmt.Errorf("failed to list procedures: %w", err)
+	}
+
+	var caps graph.Capabilities
+	if len(result.Records) == 0 {
+		return caps, nil
+	}
+
+	namesVal, _ := result.Records[0].Get("names")
+	names, _ := namesVal.([]interface{})
+	for _, n := range names {
+		name, _ := n.(string)
+		switch {
+		case strings.HasPrefix(name, "apoc."):
+			caps.APOC = true
+		case strings.HasPrefix(name, "gds."):
+			caps.GDS = true
+		}
+	}
+
+	return caps, nil
+}
+
+// Centrality ranks entities by importance within the subgraph formed by the given labels and
+// relationship types. When the GDS library is installed PageRank is used, otherwise it falls
+// back to degree centrality computed in plain Cypher.
+func (s *Neo4jStore) Centrality(ctx context.Context, labels []string, relationshipTypes []string, topN int) (graph.CentralityResult, error) {
+	if topN <= 0 {
+		topN = 10 // Default to the top 10 entities
+	}
+
+	caps, err := s.Capabilities(ctx)
+	if err == nil && caps.GDS {
+		result, err := s.pageRankCentrality(ctx, labels, relationshipTypes, topN)
+		if err == nil {
+			return result, nil
+		}
+		// GDS projections fail for labels or types that don't exist yet, so fall back rather than erroring
+	}
+
+	return s.degreeCentrality(ctx, labels, relationshipTypes, topN)
+}
+
+// pageRankCentrality runs GDS PageRank over a temporary in-memory projection of the subgraph.
+func (s *Neo4jStore) pageRankCentrality(ctx context.Context, labels []string, relationshipTypes []string, topN int) (graph.CentralityResult, error) {
+	// Native projections accept '*' to include all labels or relationship types
+	var nodeProjection interface{} = "*"
+	if len(labels) > 0 {
+		nodeProjection = labels
+	}
+	var relProjection interface{} = "*"
+	if len(relationshipTypes) > 0 {
+		relProjection = relationshipTypes
+	}
+
+	graphName := fmt.Sprintf("mcp-graph-centrality-%d", time.Now().UnixNano())
+
+	projectQuery := "CALL gds.graph.project($graphName, $nodeProjection, $relProjection) YIELD graphName RETURN graphName"
+	projectParams := map[string]interface{}{
+		"graphName":      graphName,
+		"nodeProjection": nodeProjection,
+		"relProjection":  relProjection,
+	}
+	_, err := neo4j.ExecuteQuery(ctx, s.driver, projectQuery, projectParams, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase(""), txMetadata(ctx))
+	if err != nil {
+		return graph.CentralityResult{}, fmt.Errorf("failed to project graph for PageRank: %w", err)
+	}
+
+	// Always drop the projection so it doesn't linger in GDS memory
+	defer func() {
+		dropQuery := "CALL gds.graph.drop($graphName, false) YIELD graphName RETURN graphName"
+		_, _ = neo4j.ExecuteQuery(context.Background(), s.driver, dropQuery, map[string]interface{}{"graphName": graphName}, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase(""), txMetadata(ctx))
+	}()
+
+	query := `
+        CALL gds.pageRank.stream($graphName)
+        YIELD nodeId, score
+        WITH gds.util.asNode(nodeId) AS n, score
+        ORDER BY score DESC
+        LIMIT $topN
+        RETURN labels(n) as labels, properties(n) as props, elementId(n) as id, score
+    `
+	params := map[string]interface{}{
+		"graphName": graphName,
+		"topN":      topN,
+	}
+
+	result, err := neo4j.ExecuteQuery(ctx, s.driver, query, params, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase(""), txMetadata(ctx))
+	if err != nil {
+		return graph.CentralityResult{}, fmt.Errorf("failed to execute PageRank query: %w", err)
+	}
+
+	return graph.CentralityResult{
+		Algorithm: "pagerank",
+		Results:   centralityScoresFromRecords(result.Records),
+	}, nil
+}
+
+// degreeCentrality approximates importance by counting each node's relationships within the subgraph.
+func (s *Neo4jStore) degreeCentrality(ctx context.Context, labels []string, relationshipTypes []string, topN int) (graph.CentralityResult, error) {
+	if labels == nil {
+		labels = []string{} // size() on a null parameter returns null, so pass an empty list instead
+	}
+
+	relTypeFilter := buildRelationshipTypeFilter(relationshipTypes)
+
+	query := fmt.Sprintf(`
+        MATCH (n)
+        WHERE size($labels) = 0 OR any(l IN labels(n) WHERE l IN $labels)
+        OPTIONAL MATCH (n)-[r%s]-(m)
+        WHERE size($labels) = 0 OR any(l IN labels(m) WHERE l IN $labels)
+        WITH n, count(r) AS score
+        ORDER BY score DESC
+        LIMIT $topN
+        RETURN labels(n) as labels, properties(n) as props, elementId(n) as id, score
+    `, relTypeFilter)
+
+	params := map[string]interface{}{
+		"labels": labels,
+		"topN":   topN,
+	}
+
+	result, err := neo4j.ExecuteQuery(ctx, s.driver, query, params, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase(""), txMetadata(ctx))
+	if err != nil {
+		return graph.CentralityResult{}, fmt.Errorf("failed to execute degree centrality query: %w", err)
+	}
+
+	return graph.CentralityResult{
+		Algorithm: "degree",
+		Results:   centralityScoresFromRecords(result.Records),
+	}, nil
+}
+
+// centralityScoresFromRecords converts centrality query records into scored entities.
+func centralityScoresFromRecords(records []*neo4j.Record) []graph.CentralityScore {
+	scores := make([]graph.CentralityScore, 0, len(records))
+	for _, record := range records {
+		scoreVal, _ := record.Get("score")
+
+		var score float64
+		switch v := scoreVal.(type) {
+		case float64:
+			score = v
+		case int64:
+			score = float64(v)
+		}
+
+		scores = append(scores, graph.CentralityScore{
+			Entity: entityDetailsFromRecord(record, "labels", "props", "id"),
+			Score:  score,
+		})
+	}
+	return scores
+}
+
+// RelationshipTypeCounts counts an entity's relationships by type in the given direction
+// (outgoing, incoming or both), e.g. {"CALLS": 12, "DEFINED_IN": 1}.
+func (s *Neo4jStore) RelationshipTypeCounts(ctx context.Context, locator graph.EntityLocator, direction string) (map[string]int64, error) {
+	if len(locator.Labels) == 0 {
+		return nil, fmt.Errorf("at least one label is required")
+	}
+	if len(locator.IdentifyingProperties) == 0 {
+		return nil, fmt.Errorf("at least one identifying property is required")
+	}
+	relPattern, err := buildDirectedRelationshipPattern(direction, "r")
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf(`
+        MATCH (n%s %s)
+        MATCH (n)%s()
+        RETURN type(r) as type, count(*) as count
+    `, buildLabelString(locator.Labels), buildPropsMatchString("idProps", locator.IdentifyingProperties), relPattern)
+
+	params := map[string]interface{}{
+		"idProps": locator.IdentifyingProperties,
+	}
+
+	result, err := neo4j.ExecuteQuery(ctx, s.driver, query, params, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase(""), txMetadata(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute RelationshipTypeCounts query: %w", err)
+	}
+
+	counts := make(map[string]int64, len(result.Records))
+	for _, record := range result.Records {
+		typeVal, _ := record.Get("type")
+		countVal, _ := record.Get("count")
+		relType, _ := typeVal.(string)
+		count, _ := countVal.(int64)
+		counts[relType] = count
+	}
+
+	return counts, nil
+}
+
+// LabelHistogram counts nodes by label, e.g. {"Service": 12, "Function": 340}. A node with several labels
+// is counted under each of them. When APOC is installed the counts are read from the database's count
+// store via apoc.meta.stats, otherwise every node is scanned.
+func (s *Neo4jStore) LabelHistogram(ctx context.Context) (map[string]int64, error) {
+	caps, err := s.Capabilities(ctx)
+	if err == nil && caps.APOC {
+		counts, err := s.labelHistogramFromMetaStats(ctx)
+		if err == nil {
+			return counts, nil
+		}
+		// Fall back to scanning if the procedure isn't permitted for this user
+	}
+
+	query := `
+        MATCH (n)
+        UNWIND labels(n) AS label
+        RETURN label, count(*) AS count
+    `
+
+	result, err := neo4j.ExecuteQuery(ctx, s.driver, query, nil, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase(""), txMetadata(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute LabelHistogram query: %w", err)
+	}
+
+	counts := make(map[string]int64, len(result.Records))
+	for _, record := range result.Records {
+		labelVal, _ := record.Get("label")
+		countVal, _ := record.Get("count")
+		label, _ := labelVal.(string)
+		count, _ := countVal.(int64)
+		counts[label] = count
+	}
+
+	return counts, nil
+}
+
+// GraphOverview counts nodes by label and relationships by type, together with the total numbers of nodes and
+// relationships and the density of the graph. When APOC is installed everything is read from the database's count
+// store via apoc.meta.stats, otherwise the counts are queried separately, scanning every node and relationship.
+func (s *Neo4jStore) GraphOverview(ctx context.Context) (graph.GraphOverview, error) {
+	caps, err := s.Capabilities(ctx)
+	if err == nil && caps.APOC {
+		overview, err := s.graphOverviewFromMetaStats(ctx)
+		if err == nil {
+			return overview, nil
+		}
+		// Fall back to scanning if the procedure isn't permitted for this user
+	}
+
+	labels, err := s.LabelHistogram(ctx)
+	if err != nil {
+		return graph.GraphOverview{}, err
+	}
+
+	// count(n) on its own is answered from the count store
+	result, err := neo4j.ExecuteQuery(ctx, s.driver, "MATCH (n) RETURN count(n) AS count", nil, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase(""), txMetadata(ctx))
+	if err != nil {
+		return graph.GraphOverview{}, fmt.Errorf("failed to count nodes: %w", err)
+	}
+	var nodeCount int64
+	if len(result.Records) > 0 {
+		countVal, _ := result.Records[0].Get("count")
+		nodeCount, _ = countVal.(int64)
+	}
+
+	query := `
+        MATCH ()-[r]->()
+        RETURN type(r) AS type, count(*) AS count
+    `
+	result, err = neo4j.ExecuteQuery(ctx, s.driver, query, nil, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase(""), txMetadata(ctx))
+	if err != nil {
+		return graph.GraphOverview{}, fmt.Errorf("failed to count relationships by type: %w", err)
+	}
+	relTypes := make(map[string]int64, len(result.Records))
+	var relCount int64
+	for _, record := range result.Records {
+		typeVal, _ := record.Get("type")
+		countVal, _ := record.Get("count")
+		relType, _ := typeVal.(string)
+		count, _ := countVal.(int64)
+		relTypes[relType] = count
+		relCount += count
+	}
+
+	return graph.GraphOverview{
+		NodeCount:         nodeCount,
+		RelationshipCount: relCount,
+		Density:           graphDensity(nodeCount, relCount),
+		Labels:            labels,
+		RelationshipTypes: relTypes,
+	}, nil
+}
+
+// graphDensity returns the fraction of the possible directed relationships between distinct nodes that exist.
+// Self-loops and parallel relationships are counted too, so it can exceed 1.
+func graphDensity(nodeCount, relCount int64) float64 {
+	if nodeCount < 2 {
+		return 0
+	}
+	return float64(relCount) / (float64(nodeCount) * float64(nodeCount-1))
+}
+
+// maxDependencyDepthLimit caps the length of dependency chains considered by MaxDependencyDepth, as the number
+// of paths to compare grows exponentially with their length
+const maxDependencyDepthLimit = 25
+
+// MaxDependencyDepth finds the longest chain of outgoing relationships from an entity with a variable-length
+// path, keeping only paths that visit each node once so that cycles don't inflate the depth. Every such path up
+// to maxDepth is compared, so the cost grows exponentially with maxDepth on densely connected graphs. Chains
+// longer than maxDepth (at most maxDependencyDepthLimit) aren't explored; the result is marked as capped if
+// that limit is reached.
+func (s *Neo4jStore) MaxDependencyDepth(ctx context.Context, locator graph.EntityLocator, relationshipTypes []string, maxDepth int) (graph.DependencyDepthResult, error) {
+	if len(locator.Labels) == 0 {
+		return graph.DependencyDepthResult{}, fmt.Errorf("at least one label is required")
+	}
+	if len(locator.IdentifyingProperties) == 0 {
+		return graph.DependencyDepthResult{}, fmt.Errorf("at least one identifying property is required")
+	}
+	if maxDepth <= 0 {
+		maxDepth = 10 // Default to depth 10 if invalid
+	}
+	if maxDepth > maxDependencyDepthLimit {
+		maxDepth = maxDependencyDepthLimit
+	}
+
+	query := fmt.Sprintf(`
+        MATCH (e%s %s)
+        WITH e LIMIT 1
+        OPTIONAL MATCH path = (e)-[%s*1..%d]->(dep)
+        WHERE all(n IN nodes(path) WHERE single(m IN nodes(path) WHERE m = n))
+        WITH e, path ORDER BY length(path) DESC LIMIT 1
+        RETURN labels(e) as labels, properties(e) as props, elementId(e) as id,
+               coalesce(length(path), 0) AS depth,
+               CASE WHEN path IS NULL THEN [] ELSE
+                   [n IN tail(nodes(path)) | {labels: labels(n), props: properties(n), id: elementId(n)}]
+               END AS chain
+    `, buildLabelString(locator.Labels), buildPropsMatchString("idProps", locator.IdentifyingProperties),
+		buildRelationshipTypeFilter(relationshipTypes), maxDepth)
+
+	params := map[string]interface{}{
+		"idProps": locator.IdentifyingProperties,
+	}
+
+	// Execute query
+	result, err := neo4j.ExecuteQuery(ctx, s.driver, query, params, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase(""), txMetadata(ctx))
+	if err != nil {
+		return graph.DependencyDepthResult{}, fmt.Errorf("failed to execute MaxDependencyDepth query: %w", err)
+	}
+	if len(result.Records) == 0 {
+		return graph.DependencyDepthResult{}, fmt.Errorf("entity not found")
+	}
+
+	record := result.Records[0]
+	depthVal, _ := record.Get("depth")
+	chainVal, _ := record.Get("chain")
+	depth, _ := depthVal.(int64)
+	chainInterface, _ := chainVal.([]interface{})
+
+	chain := make([]graph.EntityDetails, 0, len(chainInterface))
+	for _, nodeInterface := range chainInterface {
+		if m, ok := nodeInterface.(map[string]interface{}); ok {
+			chain = append(chain, entityDetailsFromMap(m, "labels", "props", "id"))
+		}
+	}
+
+	return graph.DependencyDepthResult{
+		Entity: entityDetailsFromRecord(record, "labels", "props", "id"),
+		Depth:  int(depth),
+		Chain:  chain,
+		Capped: depth >= int64(maxDepth),
+	}, nil
+}
+
+// maxCouplingMetrics caps the number of entities CouplingMetrics returns
+const maxCouplingMetrics = 1000
+
+// CouplingMetrics computes the fan-in (incoming relationships), fan-out (outgoing relationships) and instability
+// (fan-out / (fan-in + fan-out)) of every entity with the given label in a single query, counting relationships of
+// the given types. Entities are ordered by total coupling, highest first, and at most maxCouplingMetrics are returned.
+func (s *Neo4jStore) CouplingMetrics(ctx context.Context, label string, relationshipTypes []string) ([]graph.CouplingMetric, error) {
+	if label == "" {
+		return nil, fmt.Errorf("label is required")
+	}
+
+	relTypeFilter := buildRelationshipTypeFilter(relationshipTypes)
+	query := fmt.Sprintf(`
+        MATCH (n%s)
+        WITH n, size([(n)<-[r%s]-() | r]) AS fanIn, size([(n)-[r%s]->() | r]) AS fanOut
+        RETURN labels(n) as labels, properties(n) as props, elementId(n) as id, fanIn, fanOut
+        ORDER BY fanIn + fanOut DESC, id
+        LIMIT $limit
+    `, buildLabelString([]string{label}), relTypeFilter, relTypeFilter)
+
+	params := map[string]interface{}{
+		"limit": maxCouplingMetrics,
+	}
+
+	result, err := neo4j.ExecuteQuery(ctx, s.driver, query, params, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase(""), txMetadata(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute CouplingMetrics query: %w", err)
+	}
+
+	metrics := make([]graph.CouplingMetric, 0, len(result.Records))
+	for _, record := range result.Records {
+		fanInVal, _ := record.Get("fanIn")
+		fanOutVal, _ := record.Get("fanOut")
+		fanIn, _ := fanInVal.(int64)
+		fanOut, _ := fanOutVal.(int64)
+
+		var instability float64
+		if fanIn+fanOut > 0 {
+			instability = float64(fanOut) / float64(fanIn+fanOut)
+		}
+		metrics = append(metrics, graph.CouplingMetric{
+			Entity:      entityDetailsFromRecord(record, "labels", "props", "id"),
+			FanIn:       fanIn,
+			FanOut:      fanOut,
+			Instability: instability,
+		})
+	}
+
+	return metrics, nil
+}
+
+// SimilarEntities ranks the other entities by the neighbours they share with an entity, following relationships
+// of the given types in either direction. Similarity is the Jaccard index of the two neighbour sets (shared
+// neighbours divided by all distinct neighbours of either), with ties broken by the number of shared neighbours.
+func (s *Neo4jStore) SimilarEntities(ctx context.Context, locator graph.EntityLocator, relationshipTypes []string, topK int) ([]graph.SimilarEntity, error) {
+	if len(locator.Labels) == 0 {
+		return nil, fmt.Errorf("at least one label is required")
+	}
+	if len(locator.IdentifyingProperties) == 0 {
+		return nil, fmt.Errorf("at least one identifying property is required")
+	}
+	if topK <= 0 {
+		topK = 10 // Default limit
+	}
+
+	relTypeFilter := buildRelationshipTypeFilter(relationshipTypes)
+	query := fmt.Sprintf(`
+        MATCH (n%s %s)
+        WITH n LIMIT 1
+        MATCH (n)-[%s]-(neighbour)
+        WITH n, collect(DISTINCT neighbour) AS neighbours
+        UNWIND neighbours AS neighbour
+        MATCH (neighbour)-[%s]-(other)
+        WHERE other <> n
+        WITH other, size(neighbours) AS degree, count(DISTINCT neighbour) AS shared
+        CALL {
+            WITH other
+            MATCH (other)-[%s]-(m)
+            RETURN count(DISTINCT m) AS otherDegree
+        }
+        WITH other, shared, toFloat(shared) / (degree + otherDegree - shared) AS similarity
+        RETURN labels(other) as labels, properties(other) as props, elementId(other) as id, shared, similarity
+        ORDER BY similarity DESC, shared DESC, id
+        LIMIT $topK
+    `, buildLabelString(locator.Labels), buildPropsMatchString("idProps", locator.IdentifyingProperties), relTypeFilter, relTypeFilter, relTypeFilter)
+
+	params := map[string]interface{}{
+		"idProps": locator.IdentifyingProperties,
+		"topK":    topK,
+	}
+
+	result, err := neo4j.ExecuteQuery(ctx, s.driver, query, params, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase(""), txMetadata(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute SimilarEntities query: %w", err)
+	}
+
+	similar := make([]graph.SimilarEntity, 0, len(result.Records))
+	for _, record := range result.Records {
+		sharedVal, _ := record.Get("shared")
+		similarityVal, _ := record.Get("similarity")
+		shared, _ := sharedVal.(int64)
+		similarity, _ := similarityVal.(float64)
+		similar = append(similar, graph.SimilarEntity{
+			Entity:          entityDetailsFromRecord(record, "labels", "props", "id"),
+			SharedNeighbors: shared,
+			Similarity:      similarity,
+		})
+	}
+
+	return similar, nil
+}
+
+// labelHistogramFromMetaStats reads node counts per label from apoc.meta.stats
+func (s *Neo4jStore) labelHistogramFromMetaStats(ctx context.Context) (map[string]int64, error) {
+	query := "CALL apoc.meta.stats() YIELD labels RETURN labels"
+
+	result, err := neo4j.ExecuteQuery(ctx, s.driver, query, nil, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase(""), txMetadata(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute apoc.meta.stats: %w", err)
+	}
+	if len(result.Records) == 0 {
+		return nil, fmt.Errorf("apoc.meta.stats returned no results")
+	}
+
+	labelsVal, _ := result.Records[0].Get("labels")
+	return metaStatsCounts(labelsVal), nil
+}
+
+// graphOverviewFromMetaStats reads node and relationship counts from apoc.meta.stats
+func (s *Neo4jStore) graphOverviewFromMetaStats(ctx context.Context) (graph.GraphOverview, error) {
+	query := "CALL apoc.meta.stats() YIELD labels, relTypesCount, nodeCount, relCount RETURN labels, relTypesCount, nodeCount, relCount"
+
+	result, err := neo4j.ExecuteQuery(ctx, s.driver, query, nil, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase(""), txMetadata(ctx))
+	if err != nil {
+		return graph.GraphOverview{}, fmt.Errorf("failed to execute apoc.meta.stats: %w", err)
+	}
+	if len(result.Records) == 0 {
+		return graph.GraphOverview{}, fmt.Errorf("apoc.meta.stats returned no results")
+	}
+
+	record := result.Records[0]
+	labelsVal, _ := record.Get("labels")
+	relTypesVal, _ := record.Get("relTypesCount")
+	nodeCountVal, _ := record.Get("nodeCount")
+	relCountVal, _ := record.Get("relCount")
+	nodeCount, _ := nodeCountVal.(int64)
+	relCount, _ := relCountVal.(int64)
+	return graph.GraphOverview{
+		NodeCount:         nodeCount,
+		RelationshipCount: relCount,
+		Density:           graphDensity(nodeCount, relCount),
+		Labels:            metaStatsCounts(labelsVal),
+		RelationshipTypes: metaStatsCounts(relTypesVal),
+	}, nil
+}
+
+// metaStatsCounts converts a map of counts returned by apoc.meta.stats, leaving out zero counts: labels and
+// relationship types that are no longer used can linger in the count store with a count of zero
+func metaStatsCounts(value interface{}) map[string]int64 {
+	m, _ := value.(map[string]interface{})
+	counts := make(map[string]int64, len(m))
+	for key, countVal := range m {
+		if count, _ := countVal.(int64); count > 0 {
+			counts[key] = count
+		}
+	}
+	return counts
+}
+
+// entityModelSampleSize is the number of entities per label whose properties DescribeEntityModel examines
+const entityModelSampleSize = 1000
+
+// minObservedIdentifyingCoverage is the fraction of sampled entities that must have a unique property for
+// DescribeEntityModel to suggest it as the identifying property of a label without a constraint or index
+const minObservedIdentifyingCoverage = 0.9
+
+// timestampProperties are set on every entity by the tools, so are never suggested as identifying properties
+var timestampProperties = map[string]bool{"createdAt": true, "lastModifiedAt": true}
+
+// DescribeEntityModel reports, for each label in use, its uniqueness constraints and indexes and the properties of
+// a sample of its entities, suggesting identifying properties from the first of these that gives an answer.
+func (s *Neo4jStore) DescribeEntityModel(ctx context.Context) (graph.EntityModel, error) {
+	counts, err := s.LabelHistogram(ctx)
+	if err != nil {
+		return graph.EntityModel{}, err
+	}
+	constraints, indexes, err := s.nodePropertyIndexes(ctx)
+	if err != nil {
+		return graph.EntityModel{}, err
+	}
+
+	labels := make([]string, 0, len(counts))
+	for label := range counts {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+
+	model := graph.EntityModel{
+		Labels:     make([]graph.LabelModel, 0, len(labels)),
+		SampleSize: entityModelSampleSize,
+	}
+	for _, label := range labels {
+		usage, err := s.propertyUsage(ctx, label)
+		if err != nil {
+			return graph.EntityModel{}, err
+		}
+		labelModel := graph.LabelModel{
+			Label:       label,
+			Count:       counts[label],
+			Constraints: constraints[label],
+			Indexes:     indexes[label],
+			Properties:  usage,
+		}
+		if labelModel.Constraints == nil {
+			labelModel.Constraints = [][]string{}
+		}
+		if labelModel.Indexes == nil {
+			labelModel.Indexes = [][]string{}
+		}
+		labelModel.IdentifyingProperties, labelModel.Source = suggestIdentifyingProperties(labelModel)
+		model.Labels = append(model.Labels, labelModel)
+	}
+
+	return model, nil
+}
+
+// nodePropertyIndexes lists the property keys of the range indexes on each label, split into those backing a
+// uniqueness or key constraint and the rest. Full-text, text, point and vector indexes don't identify entities, so
+// they are left out.
+func (s *Neo4jStore) nodePropertyIndexes(ctx context.Context) (constraints, indexes map[string][][]string, err error) {
+	query := `
+        SHOW INDEXES YIELD entityType, type, labelsOrTypes, properties, owningConstraint
+        WHERE entityType = 'NODE' AND type IN ['RANGE', 'BTREE']
+        RETURN labelsOrTypes, properties, owningConstraint IS NOT NULL AS constrained
+        ORDER BY size(properties), properties
+    `
+
+	result, err := neo4j.ExecuteQuery(ctx, s.driver, query, nil, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase(""), txMetadata(ctx))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list indexes: %w", err)
+	}
+
+	constraints = make(map[string][][]string)
+	indexes = make(map[string][][]string)
+	for _, record := range result.Records {
+		labelsVal, _ := record.Get("labelsOrTypes")
+		propsVal, _ := record.Get("properties")
+		constrainedVal, _ := record.Get("constrained")
+		constrained, _ := constrainedVal.(bool)
+
+		propsInterface, _ := propsVal.([]interface{})
+		keys := make([]string, len(propsInterface))
+		for i, p := range propsInterface {
+			keys[i], _ = p.(string)
+		}
+		labelsInterface, _ := labelsVal.([]interface{})
+		for _, l := range labelsInterface {
+			label, _ := l.(string)
+			if constrained {
+				constraints[label] = append(constraints[label], keys)
+			} else {
+				indexes[label] = append(indexes[label], keys)
+			}
+		}
+	}
+
+	return constraints, indexes, nil
+}
+
+// propertyUsage reports how often each property key is used by a sample of the entities with a label, and whether
+// its values are unique within the sample
+func (s *Neo4jStore) propertyUsage(ctx context.Context, label string) ([]graph.PropertyUsage, error) {
+	query := fmt.Sprintf(`
+        MATCH (n:%s)
+        WITH n LIMIT $sampleSize
+        WITH collect(n) AS nodes
+        UNWIND nodes AS n
+        UNWIND keys(n) AS key
+        WITH size(nodes) AS sampled, key, count(*) AS present, count(DISTINCT n[key]) AS distinctValues
+        RETURN key, toFloat(present) / sampled AS coverage, distinctValues = present AS unique
+        ORDER BY coverage DESC, key
+    `, graph.QuoteCypherIdentifier(label))
+
+	params := map[string]interface{}{
+		"sampleSize": entityModelSampleSize,
+	}
+
+	result, err := neo4j.ExecuteQuery(ctx, s.driver, query, params, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase(""), txMetadata(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to sample properties of %s entities: %w", label, err)
+	}
+
+	usage := make([]graph.PropertyUsage, 0, len(result.Records))
+	for _, record := range result.Records {
+		keyVal, _ := record.Get("key")
+		coverageVal, _ := record.Get("coverage")
+		uniqueVal, _ := record.Get("unique")
+		key, _ := keyVal.(string)
+		coverage, _ := coverageVal.(float64)
+		unique, _ := uniqueVal.(bool)
+		usage = append(usage, graph.PropertyUsage{Key: key, Coverage: coverage, Unique: unique})
+	}
+
+	return usage, nil
+}
+
+// suggestIdentifyingProperties suggests the property keys to identify a label's entities by, and where the
+// suggestion came from: the smallest uniqueness constraint, otherwise the smallest index, otherwise the most common
+// property whose sampled values are unique, provided nearly every entity has it. Properties are assumed to be ordered
+// most common first, and constraints and indexes smallest first.
+func suggestIdentifyingProperties(model graph.LabelModel) ([]string, string) {
+	if len(model.Constraints) > 0 {
+		return model.Constraints[0], "constraint"
+	}
+	if len(model.Indexes) > 0 {
+		return model.Indexes[0], "index"
+	}
+	for _, usage := range model.Properties {
+		if usage.Unique && usage.Coverage >= minObservedIdentifyingCoverage && !timestampProperties[usage.Key] {
+			return []string{usage.Key}, "observed"
+		}
+	}
+	return []string{}, ""
+}
@@ -0,0 +1,104 @@
+package neo4j
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+// nodeDeleteBatchSize is the number of nodes SweepExpired deletes per transaction
+const nodeDeleteBatchSize = 1000
+
+// SetLabelTTLs sets how long new nodes with each label live. CreateNode, BatchCreateNodes and FindOrCreateEntity
+// give new nodes with one of the labels an expiresAt property that long after their creation, unless they are
+// created with one; SweepExpired deletes them once it has passed. Nodes with several such labels get the shortest.
+func (s *Neo4jStore) SetLabelTTLs(ttls map[string]time.Duration) {
+	s.labelTTLs = ttls
+}
+
+// nodeExpiry returns when a node with the labels created at now expires, or nil if none of its labels has a TTL
+func (s *Neo4jStore) nodeExpiry(labels []string, now time.Time) interface{} {
+	var shortest time.Duration
+	for _, label := range labels {
+		if ttl, ok := s.labelTTLs[label]; ok && ttl > 0 && (shortest == 0 || ttl < shortest) {
+			shortest = ttl
+		}
+	}
+	if shortest == 0 {
+		return nil
+	}
+	return now.Add(shortest)
+}
+
+// setDefaultExpiry gives the properties of a new node with the labels an expiresAt from its labels' TTLs, in
+// place, unless they already have one
+func (s *Neo4jStore) setDefaultExpiry(labels []string, properties map[string]interface{}, now time.Time) {
+	if _, ok := properties["expiresAt"]; ok {
+		return
+	}
+	if expiry := s.nodeExpiry(labels, now); expiry != nil {
+		properties["expiresAt"] = expiry
+	}
+}
+
+// SweepExpired deletes the nodes with any of the given labels (all nodes if empty) whose expiresAt has passed,
+// together with their relationships, and returns the number deleted. expiresAt may be a datetime or an ISO-8601
+// string in UTC (e.g. "2024-05-01T09:30:00Z"). Deletion is split into transactions of nodeDeleteBatchSize nodes; if
+// a batch fails, the earlier batches stay deleted. With dryRun set the expired nodes are only counted.
+func (s *Neo4jStore) SweepExpired(ctx context.Context, labels []string, dryRun bool) (int, error) {
+	if labels == nil {
+		labels = []string{} // size() on a null parameter returns null, so pass an empty list instead
+	}
+	now := time.Now().UTC()
+
+	// Comparing a string with a datetime gives null, so each comparison only applies to one form of expiresAt
+	where := `
+        WHERE n.expiresAt IS NOT NULL
+          AND (size($labels) = 0 OR any(l IN labels(n) WHERE l IN $labels))
+          AND (n.expiresAt < $now OR n.expiresAt < $nowString)`
+	params := map[string]interface{}{
+		"labels":    labels,
+		"now":       now,
+		"nowString": now.Format(time.RFC3339),
+		"batchSize": nodeDeleteBatchSize,
+	}
+
+	if dryRun {
+		query := "MATCH (n)" + where + "\n        RETURN count(n) AS count"
+		result, err := neo4j.ExecuteQuery(ctx, s.driver, query, params, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase(""), txMetadata(ctx))
+		if err != nil {
+			return 0, fmt.Errorf("failed to count expired nodes: %w", err)
+		}
+		if len(result.Records) == 0 {
+			return 0, nil
+		}
+		countVal, _ := result.Records[0].Get("count")
+		count, _ := countVal.(int64)
+		return int(count), nil
+	}
+
+	query := "MATCH (n)" + where + `
+        WITH n LIMIT $batchSize
+        DETACH DELETE n
+        RETURN count(n) AS deleted
+    `
+
+	total := 0
+	for {
+		result, err := neo4j.ExecuteQuery(ctx, s.driver, query, params, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase(""), txMetadata(ctx))
+		if err != nil {
+			return total, fmt.Errorf("failed to delete expired nodes after deleting %d: %w", total, err)
+		}
+		var deleted int64
+		if len(result.Records) > 0 {
+			deletedVal, _ := result.Records[0].Get("deleted")
+			deleted, _ = deletedVal.(int64)
+		}
+		total += int(deleted)
+		if deleted < nodeDeleteBatchSize {
+			return total, nil
+		}
+	}
+}
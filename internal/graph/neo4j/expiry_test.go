@@ -0,0 +1,30 @@
+package neo4j
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNodeExpiry(t *testing.T) {
+	now := time.Date(2024, 5, 1, 9, 30, 0, 0, time.UTC)
+	s := &Neo4jStore{}
+	s.SetLabelTTLs(map[string]time.Duration{"Annotation": 24 * time.Hour, "Inference": time.Hour})
+
+	// Nodes without a label with a TTL don't expire
+	assert.Nil(t, s.nodeExpiry([]string{"Service"}, now))
+
+	// Otherwise the shortest TTL of their labels applies
+	assert.Equal(t, now.Add(24*time.Hour), s.nodeExpiry([]string{"Service", "Annotation"}, now))
+	assert.Equal(t, now.Add(time.Hour), s.nodeExpiry([]string{"Annotation", "Inference"}, now))
+
+	// An expiry given on creation is kept
+	props := map[string]interface{}{"expiresAt": "2030-01-01T00:00:00Z"}
+	s.setDefaultExpiry([]string{"Annotation"}, props, now)
+	assert.Equal(t, "2030-01-01T00:00:00Z", props["expiresAt"])
+
+	props = map[string]interface{}{"name": "cache"}
+	s.setDefaultExpiry([]string{"Annotation"}, props, now)
+	assert.Equal(t, now.Add(24*time.Hour), props["expiresAt"])
+}
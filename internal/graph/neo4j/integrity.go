@@ -0,0 +1,155 @@
+package neo4j
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+
+	"github.com/sammcj/mcp-graph/internal/graph"
+)
+
+// maxIntegrityIssueSamples limits the number of entities or relationships CheckIntegrity lists for each broken rule
+const maxIntegrityIssueSamples = 100
+
+// CheckIntegrity checks the relationship graph against the integrity rules set with SetIntegrityRules, running one
+// query per rule. Only the rules that are broken appear in the report, each with the number of entities or
+// relationships breaking it and up to maxIntegrityIssueSamples of them.
+func (s *Neo4jStore) CheckIntegrity(ctx context.Context) (graph.IntegrityReport, error) {
+	rules := s.integrityRules
+	report := graph.IntegrityReport{
+		RulesChecked:         len(rules.RequiredRelationships) + len(rules.RelationshipEndpoints),
+		MissingRelationships: []graph.MissingRelationshipIssue{},
+		UnexpectedEndpoints:  []graph.UnexpectedEndpointIssue{},
+	}
+
+	for i, rule := range rules.RequiredRelationships {
+		issue, err := s.checkRequiredRelationship(ctx, rule)
+		if err != nil {
+			return graph.IntegrityReport{}, fmt.Errorf("required relationship rule %d: %w", i, err)
+		}
+		if issue.Count > 0 {
+			report.MissingRelationships = append(report.MissingRelationships, issue)
+			report.IssueCount += issue.Count
+		}
+	}
+
+	for i, rule := range rules.RelationshipEndpoints {
+		issue, err := s.findEndpointRuleViolations(ctx, rule)
+		if err != nil {
+			return graph.IntegrityReport{}, fmt.Errorf("relationship endpoint rule %d: %w", i, err)
+		}
+		if issue.Count > 0 {
+			report.UnexpectedEndpoints = append(report.UnexpectedEndpoints, issue)
+			report.IssueCount += issue.Count
+		}
+	}
+
+	return report, nil
+}
+
+// checkRequiredRelationship finds the entities breaking a required relationship rule
+func (s *Neo4jStore) checkRequiredRelationship(ctx context.Context, rule graph.RequiredRelationshipRule) (graph.MissingRelationshipIssue, error) {
+	if rule.Label == "" {
+		return graph.MissingRelationshipIssue{}, fmt.Errorf("label is required")
+	}
+	if rule.RelationshipType == "" {
+		return graph.MissingRelationshipIssue{}, fmt.Errorf("relationship type is required")
+	}
+	direction := rule.Direction
+	if direction == "" {
+		direction = graph.DirectionOutgoing
+	}
+	relPattern, err := buildDirectedRelationshipPattern(direction, ":"+graph.QuoteCypherIdentifier(rule.RelationshipType))
+	if err != nil {
+		return graph.MissingRelationshipIssue{}, err
+	}
+
+	query := fmt.Sprintf(`
+        MATCH (n%s)
+        WHERE NOT (n)%s()
+        WITH n ORDER BY elementId(n)
+        WITH count(n) AS total, collect(n)[..$limit] AS sample
+        RETURN total, [x IN sample | {labels: labels(x), props: properties(x), id: elementId(x)}] AS entities
+    `, buildLabelString([]string{rule.Label}), relPattern)
+
+	params := map[string]interface{}{
+		"limit": maxIntegrityIssueSamples,
+	}
+
+	result, err := neo4j.ExecuteQuery(ctx, s.driver, query, params, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase(""), txMetadata(ctx))
+	if err != nil {
+		return graph.MissingRelationshipIssue{}, fmt.Errorf("failed to execute CheckIntegrity query: %w", err)
+	}
+
+	issue := graph.MissingRelationshipIssue{Rule: rule, Entities: []graph.EntityDetails{}}
+	if len(result.Records) == 0 {
+		return issue, nil
+	}
+	totalVal, _ := result.Records[0].Get("total")
+	entitiesVal, _ := result.Records[0].Get("entities")
+	issue.Count, _ = totalVal.(int64)
+	entitiesInterface, _ := entitiesVal.([]interface{})
+	for _, e := range entitiesInterface {
+		if m, ok := e.(map[string]interface{}); ok {
+			issue.Entities = append(issue.Entities, entityDetailsFromMap(m, "labels", "props", "id"))
+		}
+	}
+	return issue, nil
+}
+
+// findEndpointRuleViolations finds the relationships breaking a relationship endpoint rule
+func (s *Neo4jStore) findEndpointRuleViolations(ctx context.Context, rule graph.RelationshipEndpointRule) (graph.UnexpectedEndpointIssue, error) {
+	if rule.RelationshipType == "" {
+		return graph.UnexpectedEndpointIssue{}, fmt.Errorf("relationship type is required")
+	}
+	if len(rule.StartLabels) == 0 && len(rule.EndLabels) == 0 {
+		return graph.UnexpectedEndpointIssue{}, fmt.Errorf("at least one start or end label is required")
+	}
+
+	query := fmt.Sprintf(`
+        MATCH (start)-[r:%s]->(end)
+        WHERE (size($startLabels) > 0 AND NOT any(l IN labels(start) WHERE l IN $startLabels))
+           OR (size($endLabels) > 0 AND NOT any(l IN labels(end) WHERE l IN $endLabels))
+        WITH start, r, end ORDER BY elementId(r)
+        WITH count(r) AS total,
+             collect({id: elementId(r), type: type(r), props: properties(r),
+                      startId: elementId(start), startLabels: labels(start),
+                      endId: elementId(end), endLabels: labels(end)})[..$limit] AS relationships
+        RETURN total, relationships
+    `, graph.QuoteCypherIdentifier(rule.RelationshipType))
+
+	startLabels := rule.StartLabels
+	if startLabels == nil {
+		startLabels = []string{}
+	}
+	endLabels := rule.EndLabels
+	if endLabels == nil {
+		endLabels = []string{}
+	}
+	params := map[string]interface{}{
+		"startLabels": startLabels,
+		"endLabels":   endLabels,
+		"limit":       maxIntegrityIssueSamples,
+	}
+
+	result, err := neo4j.ExecuteQuery(ctx, s.driver, query, params, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase(""), txMetadata(ctx))
+	if err != nil {
+		return graph.UnexpectedEndpointIssue{}, fmt.Errorf("failed to execute CheckIntegrity query: %w", err)
+	}
+
+	issue := graph.UnexpectedEndpointIssue{Rule: rule, Relationships: []graph.RelationshipDetails{}}
+	if len(result.Records) == 0 {
+		return issue, nil
+	}
+	totalVal, _ := result.Records[0].Get("total")
+	relationshipsVal, _ := result.Records[0].Get("relationships")
+	issue.Count, _ = totalVal.(int64)
+	relationshipsInterface, _ := relationshipsVal.([]interface{})
+	for _, r := range relationshipsInterface {
+		if m, ok := r.(map[string]interface{}); ok {
+			issue.Relationships = append(issue.Relationships, relationshipDetailsFromMap(m))
+		}
+	}
+	return issue, nil
+}
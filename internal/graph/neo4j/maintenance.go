@@ -0,0 +1,548 @@
+package neo4j
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+
+	"github.com/sammcj/mcp-graph/internal/graph"
+)
+
+// maxDuplicateGroups limits the number of duplicate groups returned by FindDuplicates
+const maxDuplicateGroups = 100
+
+// maxMissingRelationshipResults limits the number of entities returned by FindMissingRelationships
+const maxMissingRelationshipResults = 1000
+
+// FindDuplicates groups entities with the given label by the key properties and returns the groups
+// containing more than one entity, largest first. Entities missing any key property are ignored.
+func (s *Neo4jStore) FindDuplicates(ctx context.Context, label string, keyProperties []string) ([]graph.DuplicateGroup, error) {
+	if label == "" {
+		return nil, fmt.Errorf("label is required")
+	}
+	if len(keyProperties) == 0 {
+		return nil, fmt.Errorf("at least one key property is required")
+	}
+
+	query := fmt.Sprintf(`
+        MATCH (n%s)
+        WHERE all(k IN $keys WHERE n[k] IS NOT NULL)
+        WITH [k IN $keys | n[k]] AS key, collect(n) AS nodes
+        WHERE size(nodes) > 1
+        RETURN key, [x IN nodes | {labels: labels(x), props: properties(x), id: elementId(x)}] AS entities
+        ORDER BY size(nodes) DESC
+        LIMIT $limit
+    `, buildLabelString([]string{label}))
+
+	params := map[string]interface{}{
+		"keys":  keyProperties,
+		"limit": maxDuplicateGroups,
+	}
+
+	// Execute query
+	result, err := neo4j.ExecuteQuery(ctx, s.driver, query, params, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase(""), txMetadata(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute FindDuplicates query: %w", err)
+	}
+
+	// Process results
+	groups := make([]graph.DuplicateGroup, 0, len(result.Records))
+	for _, record := range result.Records {
+		keyVal, _ := record.Get("key")
+		entitiesVal, _ := record.Get("entities")
+
+		keyValues, _ := keyVal.([]interface{})
+		key := make(map[string]interface{}, len(keyProperties))
+		for i, k := range keyProperties {
+			if i < len(keyValues) {
+				key[k] = convertNeo4jValue(keyValues[i])
+			}
+		}
+
+		entitiesInterface, _ := entitiesVal.([]interface{})
+		entities := make([]graph.EntityDetails, 0, len(entitiesInterface))
+		for _, e := range entitiesInterface {
+			if m, ok := e.(map[string]interface{}); ok {
+				entities = append(entities, entityDetailsFromMap(m, "labels", "props", "id"))
+			}
+		}
+
+		groups = append(groups, graph.DuplicateGroup{Key: key, Entities: entities})
+	}
+
+	return groups, nil
+}
+
+// CopyProperties copies the given properties from one entity to another and returns the updated target.
+// Properties missing on the source are skipped. Unless overwrite is set, properties already present on
+// the target are left unchanged. The target's lastModifiedAt is updated if anything was copied.
+func (s *Neo4jStore) CopyProperties(ctx context.Context, from graph.EntityLocator, to graph.EntityLocator, keys []string, overwrite bool) (graph.EntityDetails, error) {
+	if len(keys) == 0 {
+		return graph.EntityDetails{}, fmt.Errorf("at least one property key is required")
+	}
+	for _, k := range keys {
+		if k == "id" {
+			return graph.EntityDetails{}, fmt.Errorf("the id property cannot be copied")
+		}
+	}
+
+	// Read the requested properties from both entities
+	source, err := s.GetEntityDetails(ctx, from.Labels, from.IdentifyingProperties, keys)
+	if err != nil {
+		return graph.EntityDetails{}, fmt.Errorf("source entity not found or error fetching details: %w", err)
+	}
+	target, err := s.GetEntityDetails(ctx, to.Labels, to.IdentifyingProperties, keys)
+	if err != nil {
+		return graph.EntityDetails{}, fmt.Errorf("target entity not found or error fetching details: %w", err)
+	}
+
+	// Work out which properties to set
+	props := make(map[string]interface{}, len(keys))
+	for _, k := range keys {
+		value := source.Properties[k]
+		if value == nil {
+			continue // Not present on the source
+		}
+		if !overwrite && target.Properties[k] != nil {
+			continue // Keep the target's existing value
+		}
+		props[k] = value
+	}
+	if len(props) == 0 {
+		return s.GetEntityDetails(ctx, to.Labels, to.IdentifyingProperties, nil)
+	}
+
+	query := fmt.Sprintf(`
+        MATCH (n%s %s)
+        WHERE elementId(n) = $targetId
+        SET n += $props, n.lastModifiedAt = $now
+        RETURN labels(n) as labels, properties(n) as props, elementId(n) as id
+    `, buildLabelString(to.Labels), buildPropsMatchString("idProps", to.IdentifyingProperties))
+
+	params := map[string]interface{}{
+		"idProps":  to.IdentifyingProperties,
+		"targetId": target.Properties["id"],
+		"props":    props,
+		"now":      time.Now().UTC(),
+	}
+
+	// Execute query
+	result, err := neo4j.ExecuteQuery(ctx, s.driver, query, params, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase(""), txMetadata(ctx))
+	if err != nil {
+		return graph.EntityDetails{}, fmt.Errorf("failed to execute CopyProperties query: %w", err)
+	}
+	if len(result.Records) == 0 {
+		return graph.EntityDetails{}, fmt.Errorf("target entity no longer exists")
+	}
+
+	return entityDetailsFromRecord(result.Records[0], "labels", "props", "id"), nil
+}
+
+// DecayConfidence halves the confidence of relationships (optionally restricted to the given types) for every
+// halfLife that has passed since they were last modified, and returns the number of relationships updated.
+// The time of each decay is recorded in confidenceDecayedAt, and later runs only apply the decay accrued since
+// then (or since the relationship was last modified, if that is more recent), so running this repeatedly
+// doesn't compound. lastModifiedAt may be a Neo4j datetime or an ISO-8601 string, as in FindModifiedSince.
+func (s *Neo4jStore) DecayConfidence(ctx context.Context, relationshipTypes []string, halfLife time.Duration) (int64, error) {
+	if halfLife <= 0 {
+		return 0, fmt.Errorf("half-life must be positive")
+	}
+
+	// duration.between splits the age into months, days and seconds; months are converted using the
+	// average Gregorian month length, as Neo4j does
+	query := fmt.Sprintf(`
+        MATCH ()-[r%s]->()
+        WHERE r.confidence IS NOT NULL AND r.lastModifiedAt IS NOT NULL
+        WITH r,
+             CASE
+                 WHEN r.lastModifiedAt = toString(r.lastModifiedAt) THEN datetime(r.lastModifiedAt)
+                 ELSE datetime({datetime: r.lastModifiedAt})
+             END AS modifiedAt
+        WITH r,
+             CASE
+                 WHEN r.confidenceDecayedAt IS NOT NULL AND r.confidenceDecayedAt > modifiedAt THEN r.confidenceDecayedAt
+                 ELSE modifiedAt
+             END AS decayFrom
+        WITH r, duration.between(decayFrom, $now) AS age
+        WITH r, age.months * 2629746 + age.days * 86400 + age.seconds AS ageSeconds
+        WHERE ageSeconds > 0
+        SET r.confidence = r.confidence * 0.5 ^ (toFloat(ageSeconds) / $halfLifeSeconds),
+            r.confidenceDecayedAt = $now
+        RETURN count(r) AS updated
+    `, buildRelationshipTypeFilter(relationshipTypes))
+
+	params := map[string]interface{}{
+		"now":             time.Now().UTC(),
+		"halfLifeSeconds": halfLife.Seconds(),
+	}
+
+	// Execute query
+	result, err := neo4j.ExecuteQuery(ctx, s.driver, query, params, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase(""), txMetadata(ctx))
+	if err != nil {
+		return 0, fmt.Errorf("failed to execute DecayConfidence query: %w", err)
+	}
+	if len(result.Records) == 0 {
+		return 0, nil
+	}
+
+	updatedVal, _ := result.Records[0].Get("updated")
+	updated, _ := updatedVal.(int64)
+	return updated, nil
+}
+
+// FindMissingRelationships finds entities with all of the given labels that have no relationship of the given
+// type in the given direction, ordered by element ID. At most maxMissingRelationshipResults entities are returned.
+func (s *Neo4jStore) FindMissingRelationships(ctx context.Context, labels []string, relationshipType string, direction string) ([]graph.EntityDetails, error) {
+	if len(labels) == 0 {
+		return nil, fmt.Errorf("at least one label is required")
+	}
+	if relationshipType == "" {
+		return nil, fmt.Errorf("relationship type is required")
+	}
+	relPattern, err := buildDirectedRelationshipPattern(direction, ":"+graph.QuoteCypherIdentifier(relationshipType))
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf(`
+        MATCH (n%s)
+        WHERE NOT (n)%s()
+        RETURN labels(n) as labels, properties(n) as props, elementId(n) as id
+        ORDER BY id
+        LIMIT $limit
+    `, buildLabelString(labels), relPattern)
+
+	params := map[string]interface{}{
+		"limit": maxMissingRelationshipResults,
+	}
+
+	// Execute query
+	result, err := neo4j.ExecuteQuery(ctx, s.driver, query, params, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase(""), txMetadata(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute FindMissingRelationships query: %w", err)
+	}
+
+	// Process results
+	entities := make([]graph.EntityDetails, 0, len(result.Records))
+	for _, record := range result.Records {
+		entities = append(entities, entityDetailsFromRecord(record, "labels", "props", "id"))
+	}
+
+	return entities, nil
+}
+
+// SetEntityStatus sets an entity's status, or removes it if status is empty, and updates its lastModifiedAt.
+// The status must be one of graph.EntityStatuses.
+func (s *Neo4jStore) SetEntityStatus(ctx context.Context, locator graph.EntityLocator, status string) (graph.EntityDetails, error) {
+	if len(locator.Labels) == 0 {
+		return graph.EntityDetails{}, fmt.Errorf("at least one label is required")
+	}
+	if len(locator.IdentifyingProperties) == 0 {
+		return graph.EntityDetails{}, fmt.Errorf("at least one identifying property is required")
+	}
+	var statusParam interface{} // Setting a property to null removes it
+	if status != "" {
+		if !graph.IsValidEntityStatus(status) {
+			return graph.EntityDetails{}, fmt.Errorf("invalid status %q: must be one of %s", status, strings.Join(graph.EntityStatuses, ", "))
+		}
+		statusParam = status
+	}
+
+	query := fmt.Sprintf(`
+        MATCH (n%s %s)
+        SET n.status = $status, n.lastModifiedAt = $now
+        RETURN labels(n) as labels, properties(n) as props, elementId(n) as id
+    `, buildLabelString(locator.Labels), buildPropsMatchString("idProps", locator.IdentifyingProperties))
+
+	params := map[string]interface{}{
+		"idProps": locator.IdentifyingProperties,
+		"status":  statusParam,
+		"now":     time.Now().UTC(),
+	}
+
+	// Execute query
+	result, err := neo4j.ExecuteQuery(ctx, s.driver, query, params, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase(""), txMetadata(ctx))
+	if err != nil {
+		return graph.EntityDetails{}, fmt.Errorf("failed to execute SetEntityStatus query: %w", err)
+	}
+	if len(result.Records) == 0 {
+		return graph.EntityDetails{}, fmt.Errorf("entity not found")
+	}
+
+	return entityDetailsFromRecord(result.Records[0], "labels", "props", "id"), nil
+}
+
+// relationshipDeleteBatchSize is the number of relationships DeleteRelationshipsByType and PruneLowConfidence delete
+// per transaction
+const relationshipDeleteBatchSize = 10000
+
+// DeleteRelationshipsByType deletes every relationship of the given type and returns the number deleted. Deletion
+// is split into transactions of relationshipDeleteBatchSize relationships so that large deletes don't exhaust the
+// transaction memory limit; if a batch fails, the earlier batches stay deleted. With dryRun set the relationships
+// are only counted.
+func (s *Neo4jStore) DeleteRelationshipsByType(ctx context.Context, relType string, dryRun bool) (int, error) {
+	if relType == "" {
+		return 0, fmt.Errorf("relationship type is required")
+	}
+
+	if dryRun {
+		query := fmt.Sprintf("MATCH ()-[r:%s]->() RETURN count(r) AS count", graph.QuoteCypherIdentifier(relType))
+		result, err := neo4j.ExecuteQuery(ctx, s.driver, query, nil, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase(""), txMetadata(ctx))
+		if err != nil {
+			return 0, fmt.Errorf("failed to count relationships: %w", err)
+		}
+		if len(result.Records) == 0 {
+			return 0, nil
+		}
+		countVal, _ := result.Records[0].Get("count")
+		count, _ := countVal.(int64)
+		return int(count), nil
+	}
+
+	query := fmt.Sprintf(`
+        MATCH ()-[r:%s]->()
+        WITH r LIMIT $batchSize
+        DELETE r
+        RETURN count(r) AS deleted
+    `, graph.QuoteCypherIdentifier(relType))
+	params := map[string]interface{}{"batchSize": relationshipDeleteBatchSize}
+
+	total := 0
+	for {
+		result, err := neo4j.ExecuteQuery(ctx, s.driver, query, params, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase(""), txMetadata(ctx))
+		if err != nil {
+			return total, fmt.Errorf("failed to delete relationships after deleting %d: %w", total, err)
+		}
+		var deleted int64
+		if len(result.Records) > 0 {
+			deletedVal, _ := result.Records[0].Get("deleted")
+			deleted, _ = deletedVal.(int64)
+		}
+		total += int(deleted)
+		if deleted < relationshipDeleteBatchSize {
+			return total, nil
+		}
+	}
+}
+
+// PruneLowConfidence deletes the relationships (optionally restricted to the given types) whose confidence is below
+// threshold and returns the number deleted. Relationships without a confidence are kept. As in
+// DeleteRelationshipsByType, deletion is split into transactions of relationshipDeleteBatchSize relationships; if a
+// batch fails, the earlier batches stay deleted. With dryRun set the relationships are only counted.
+func (s *Neo4jStore) PruneLowConfidence(ctx context.Context, relTypes []string, threshold float64, dryRun bool) (int, error) {
+	match := fmt.Sprintf(`
+        MATCH ()-[r%s]->()
+        WHERE r.confidence < $threshold`, buildRelationshipTypeFilter(relTypes))
+	params := map[string]interface{}{
+		"threshold": threshold,
+		"batchSize": relationshipDeleteBatchSize,
+	}
+
+	if dryRun {
+		query := match + "\n        RETURN count(r) AS count"
+		result, err := neo4j.ExecuteQuery(ctx, s.driver, query, params, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase(""), txMetadata(ctx))
+		if err != nil {
+			return 0, fmt.Errorf("failed to count low-confidence relationships: %w", err)
+		}
+		if len(result.Records) == 0 {
+			return 0, nil
+		}
+		countVal, _ := result.Records[0].Get("count")
+		count, _ := countVal.(int64)
+		return int(count), nil
+	}
+
+	query := match + `
+        WITH r LIMIT $batchSize
+        DELETE r
+        RETURN count(r) AS deleted
+    `
+
+	total := 0
+	for {
+		result, err := neo4j.ExecuteQuery(ctx, s.driver, query, params, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase(""), txMetadata(ctx))
+		if err != nil {
+			return total, fmt.Errorf("failed to delete low-confidence relationships after deleting %d: %w", total, err)
+		}
+		var deleted int64
+		if len(result.Records) > 0 {
+			deletedVal, _ := result.Records[0].Get("deleted")
+			deleted, _ = deletedVal.(int64)
+		}
+		total += int(deleted)
+		if deleted < relationshipDeleteBatchSize {
+			return total, nil
+		}
+	}
+}
+
+// entityUpdateBatchSize is the number of entities BulkUpdateEntities updates per transaction
+const entityUpdateBatchSize = 1000
+
+// BulkUpdateEntities sets setProps on, and removes removeKeys from, every entity with any of the given labels (all
+// entities if empty) matching every property filter, and returns the number updated. lastModifiedAt is set on every
+// updated entity. The matching entities are found first and then updated in transactions of entityUpdateBatchSize,
+// so that updating an entity can't change which entities match; if a batch fails, the earlier batches stay updated.
+// With dryRun set the matching entities are only counted.
+func (s *Neo4jStore) BulkUpdateEntities(ctx context.Context, labels []string, filters []graph.PropertyFilter, setProps map[string]interface{}, removeKeys []string, dryRun bool) (int, error) {
+	if len(setProps) == 0 && len(removeKeys) == 0 {
+		return 0, fmt.Errorf("at least one property to set or remove is required")
+	}
+
+	limited, err := s.limitPropertySizes(setProps)
+	if err != nil {
+		return 0, err
+	}
+
+	// Removing a property is the same as setting it to null
+	updates := make(map[string]interface{}, len(limited)+len(removeKeys))
+	for k, v := range limited {
+		updates[k] = v
+	}
+	for _, key := range removeKeys {
+		if _, ok := updates[key]; ok {
+			return 0, fmt.Errorf("property %q cannot be both set and removed", key)
+		}
+		updates[key] = nil
+	}
+
+	whereClause, params, err := buildEntityFilterClause("n", labels, filters)
+	if err != nil {
+		return 0, err
+	}
+
+	if dryRun {
+		query := fmt.Sprintf("MATCH (n) WHERE %s RETURN count(n) AS count", whereClause)
+		result, err := neo4j.ExecuteQuery(ctx, s.driver, query, params, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase(""), txMetadata(ctx))
+		if err != nil {
+			return 0, fmt.Errorf("failed to count entities: %w", err)
+		}
+		if len(result.Records) == 0 {
+			return 0, nil
+		}
+		countVal, _ := result.Records[0].Get("count")
+		count, _ := countVal.(int64)
+		return int(count), nil
+	}
+
+	// Find the matching entities before changing any of them
+	query := fmt.Sprintf("MATCH (n) WHERE %s RETURN elementId(n) AS id", whereClause)
+	result, err := neo4j.ExecuteQuery(ctx, s.driver, query, params, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase(""), txMetadata(ctx))
+	if err != nil {
+		return 0, fmt.Errorf("failed to find entities to update: %w", err)
+	}
+	ids := make([]string, 0, len(result.Records))
+	for _, record := range result.Records {
+		idVal, _ := record.Get("id")
+		if id, ok := idVal.(string); ok {
+			ids = append(ids, id)
+		}
+	}
+
+	updateQuery := `
+        UNWIND $ids AS id
+        MATCH (n) WHERE elementId(n) = id
+        SET n += $updates, n.lastModifiedAt = $now
+        RETURN count(n) AS updated
+    `
+	now := time.Now().UTC()
+
+	total := 0
+	for start := 0; start < len(ids); start += entityUpdateBatchSize {
+		end := min(start+entityUpdateBatchSize, len(ids))
+		updateParams := map[string]interface{}{
+			"ids":     ids[start:end],
+			"updates": updates,
+			"now":     now,
+		}
+		result, err := neo4j.ExecuteQuery(ctx, s.driver, updateQuery, updateParams, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase(""), txMetadata(ctx))
+		if err != nil {
+			return total, fmt.Errorf("failed to update entities after updating %d: %w", total, err)
+		}
+		if len(result.Records) > 0 {
+			updatedVal, _ := result.Records[0].Get("updated")
+			updated, _ := updatedVal.(int64)
+			total += int(updated)
+		}
+	}
+	return total, nil
+}
+
+// closureBatchSize is the number of start entities MaterializeTransitiveClosure processes per transaction. Each may
+// reach many entities, so it is smaller than entityUpdateBatchSize.
+const closureBatchSize = 100
+
+// MaterializeTransitiveClosure creates a closureType relationship from each entity with an outgoing relType
+// relationship to every other entity it reaches by a path of up to maxDepth (default 10) relType relationships. The
+// start entities are found first and then processed in transactions of closureBatchSize; if a batch fails, the
+// relationships created by earlier batches are kept. A closure relationship is only created if the pair doesn't
+// already have one, so running it again is safe. Closure relationships whose path has since been removed are not
+// deleted; delete the closure type first to rebuild it from scratch.
+func (s *Neo4jStore) MaterializeTransitiveClosure(ctx context.Context, relType, closureType string, maxDepth int) (graph.TransitiveClosureResult, error) {
+	if relType == "" || closureType == "" {
+		return graph.TransitiveClosureResult{}, fmt.Errorf("relationship type and closure type are required")
+	}
+	closureType = s.relationshipType(closureType)
+	if closureType == relType {
+		return graph.TransitiveClosureResult{}, fmt.Errorf("closure type must differ from the relationship type")
+	}
+	if err := s.checkRelationshipTypeAllowed(closureType); err != nil {
+		return graph.TransitiveClosureResult{}, err
+	}
+	if maxDepth <= 0 {
+		maxDepth = 10 // Default to depth 10 if invalid
+	}
+	closure := graph.TransitiveClosureResult{RelationshipType: relType, ClosureType: closureType}
+
+	// Find the start entities before creating any closure relationships
+	query := fmt.Sprintf("MATCH (a)-[:%s]->() RETURN DISTINCT elementId(a) AS id", graph.QuoteCypherIdentifier(relType))
+	result, err := neo4j.ExecuteQuery(ctx, s.driver, query, nil, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase(""), txMetadata(ctx))
+	if err != nil {
+		return closure, fmt.Errorf("failed to find start entities: %w", err)
+	}
+	ids := make([]string, 0, len(result.Records))
+	for _, record := range result.Records {
+		idVal, _ := record.Get("id")
+		if id, ok := idVal.(string); ok {
+			ids = append(ids, id)
+		}
+	}
+
+	closureQuery := fmt.Sprintf(`
+        UNWIND $ids AS id
+        MATCH (a) WHERE elementId(a) = id
+        MATCH (a)-[:%s*1..%d]->(b)
+        WHERE b <> a
+        WITH DISTINCT a, b
+        OPTIONAL MATCH (a)-[existing:%s]->(b)
+        WITH a, b, count(existing) = 0 AS missing
+        FOREACH (_ IN CASE WHEN missing THEN [1] ELSE [] END |
+            CREATE (a)-[:%s $createProps]->(b))
+        RETURN count(*) AS pairs, sum(CASE WHEN missing THEN 1 ELSE 0 END) AS created
+    `, graph.QuoteCypherIdentifier(relType), maxDepth, graph.QuoteCypherIdentifier(closureType), graph.QuoteCypherIdentifier(closureType))
+	createProps := s.newRelationshipProperties(map[string]interface{}{"derivedFrom": relType}, time.Now().UTC())
+
+	for start := 0; start < len(ids); start += closureBatchSize {
+		end := min(start+closureBatchSize, len(ids))
+		params := map[string]interface{}{
+			"ids":         ids[start:end],
+			"createProps": createProps,
+		}
+		result, err := neo4j.ExecuteQuery(ctx, s.driver, closureQuery, params, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase(""), txMetadata(ctx))
+		if err != nil {
+			return closure, fmt.Errorf("failed to create closure relationships after creating %d: %w", closure.Created, err)
+		}
+		if len(result.Records) > 0 {
+			pairsVal, _ := result.Records[0].Get("pairs")
+			createdVal, _ := result.Records[0].Get("created")
+			pairs, _ := pairsVal.(int64)
+			created, _ := createdVal.(int64)
+			closure.Pairs += int(pairs)
+			closure.Created += int(created)
+		}
+	}
+	return closure, nil
+}
@@ -0,0 +1,422 @@
+package neo4j
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+
+	"github.com/sammcj/mcp-graph/internal/graph"
+)
+
+// FindModifiedSince finds entities whose lastModifiedAt is at or after the given time, most recently modified first.
+// lastModifiedAt may be stored either as a Neo4j datetime (as written by this store) or as an ISO-8601 string
+// (as written by external tooling); both are compared as datetimes.
+func (s *Neo4jStore) FindModifiedSince(ctx context.Context, labels []string, since time.Time, limit int) ([]graph.EntityDetails, error) {
+	if limit <= 0 {
+		limit = 100 // Default limit
+	}
+
+	// Only a string equals its own toString(), which distinguishes string timestamps from datetimes
+	query := `
+        MATCH (n)
+        WHERE (size($labels) = 0 OR any(l IN labels(n) WHERE l IN $labels))
+          AND n.lastModifiedAt IS NOT NULL
+        WITH n,
+             CASE
+                 WHEN n.lastModifiedAt = toString(n.lastModifiedAt) THEN datetime(n.lastModifiedAt)
+                 ELSE datetime({datetime: n.lastModifiedAt})
+             END AS modifiedAt
+        WHERE modifiedAt >= $since
+        RETURN labels(n) as labels, properties(n) as props, elementId(n) as id
+        ORDER BY modifiedAt DESC
+        LIMIT $limit
+    `
+
+	if labels == nil {
+		labels = []string{}
+	}
+	params := map[string]interface{}{
+		"labels": labels,
+		"since":  since.UTC(),
+		"limit":  limit,
+	}
+
+	// Execute query
+	result, err := neo4j.ExecuteQuery(ctx, s.driver, query, params, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase(""), txMetadata(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute FindModifiedSince query: %w", err)
+	}
+
+	// Process results
+	entities := make([]graph.EntityDetails, 0, len(result.Records))
+	for _, record := range result.Records {
+		entities = append(entities, entityDetailsFromRecord(record, "labels", "props", "id"))
+	}
+
+	return entities, nil
+}
+
+// FindEntities finds entities with any of the given labels (all entities if empty) matching every property filter.
+func (s *Neo4jStore) FindEntities(ctx context.Context, labels []string, filters []graph.PropertyFilter, limit int) ([]graph.EntityDetails, error) {
+	if limit <= 0 {
+		limit = 100 // Default limit
+	}
+
+	whereClause, params, err := buildEntityFilterClause("n", labels, filters)
+	if err != nil {
+		return nil, err
+	}
+	params["limit"] = limit
+
+	query := fmt.Sprintf(`
+        MATCH (n)
+        WHERE %s
+        RETURN labels(n) as labels, properties(n) as props, elementId(n) as id
+        %s
+    `, whereClause, orderAndLimit(ctx, params, "", "$limit"))
+
+	// Execute query
+	result, err := neo4j.ExecuteQuery(ctx, s.driver, query, params, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase(""), txMetadata(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute FindEntities query: %w", err)
+	}
+
+	// Process results
+	entities := make([]graph.EntityDetails, 0, len(result.Records))
+	for _, record := range result.Records {
+		entities = append(entities, entityDetailsFromRecord(record, "labels", "props", "id"))
+	}
+
+	return entities, nil
+}
+
+// CountEntities counts entities with any of the given labels (all entities if empty) matching every property filter.
+func (s *Neo4jStore) CountEntities(ctx context.Context, labels []string, filters []graph.PropertyFilter) (int64, error) {
+	whereClause, params, err := buildEntityFilterClause("n", labels, filters)
+	if err != nil {
+		return 0, err
+	}
+
+	query := fmt.Sprintf(`
+        MATCH (n)
+        WHERE %s
+        RETURN count(n) as count
+    `, whereClause)
+
+	// Execute query
+	result, err := neo4j.ExecuteQuery(ctx, s.driver, query, params, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase(""), txMetadata(ctx))
+	if err != nil {
+		return 0, fmt.Errorf("failed to execute CountEntities query: %w", err)
+	}
+	if len(result.Records) == 0 {
+		return 0, nil
+	}
+
+	countVal, _ := result.Records[0].Get("count")
+	count, _ := countVal.(int64)
+	return count, nil
+}
+
+// SearchEntities finds entities with any of the given labels (all entities if empty) that have a string property
+// containing text, ignoring case. Every matching node's properties are scanned, so this is slower than FindEntities
+// on large graphs; restricting the labels helps.
+func (s *Neo4jStore) SearchEntities(ctx context.Context, labels []string, text string, limit int) ([]graph.EntityDetails, error) {
+	if text == "" {
+		return nil, fmt.Errorf("search text is required")
+	}
+	if limit <= 0 {
+		limit = 100 // Default limit
+	}
+
+	if labels == nil {
+		labels = []string{}
+	}
+	params := map[string]interface{}{
+		"labels": labels,
+		"text":   strings.ToLower(text),
+		"limit":  limit,
+	}
+
+	// Only a string equals its own toString(), which skips numbers, lists and temporal values
+	query := fmt.Sprintf(`
+        MATCH (n)
+        WHERE (size($labels) = 0 OR any(l IN labels(n) WHERE l IN $labels))
+          AND any(k IN keys(n) WHERE n[k] = toString(n[k]) AND toLower(n[k]) CONTAINS $text)
+        RETURN labels(n) as labels, properties(n) as props, elementId(n) as id
+        %s
+    `, orderAndLimit(ctx, params, "", "$limit"))
+
+	// Execute query
+	result, err := neo4j.ExecuteQuery(ctx, s.driver, query, params, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase(""), txMetadata(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute SearchEntities query: %w", err)
+	}
+
+	// Process results
+	entities := make([]graph.EntityDetails, 0, len(result.Records))
+	for _, record := range result.Records {
+		entities = append(entities, entityDetailsFromRecord(record, "labels", "props", "id"))
+	}
+
+	return entities, nil
+}
+
+// ListRelationshipsByType lists relationships of the given type with their endpoints, ordered by element ID
+// so that pages are stable, along with the total number of relationships of that type.
+func (s *Neo4jStore) ListRelationshipsByType(ctx context.Context, relType string, skip int, limit int) (graph.RelationshipList, error) {
+	if relType == "" {
+		return graph.RelationshipList{}, fmt.Errorf("relationship type is required")
+	}
+	if skip < 0 {
+		skip = 0
+	}
+	if limit <= 0 {
+		limit = 100 // Default limit
+	}
+	relPattern := "[r:" + graph.QuoteCypherIdentifier(relType) + "]"
+
+	// Count every relationship of the type; this is answered from the count store
+	countQuery := fmt.Sprintf(`
+        MATCH ()-%s->()
+        RETURN count(r) as total
+    `, relPattern)
+	countResult, err := neo4j.ExecuteQuery(ctx, s.driver, countQuery, nil, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase(""), txMetadata(ctx))
+	if err != nil {
+		return graph.RelationshipList{}, fmt.Errorf("failed to count relationships: %w", err)
+	}
+	var total int64
+	if len(countResult.Records) > 0 {
+		totalVal, _ := countResult.Records[0].Get("total")
+		total, _ = totalVal.(int64)
+	}
+
+	query := fmt.Sprintf(`
+        MATCH (start)-%s->(end)
+        RETURN elementId(r) as id, type(r) as type, properties(r) as props,
+               elementId(start) as startId, labels(start) as startLabels,
+               elementId(end) as endId, labels(end) as endLabels
+        ORDER BY elementId(r)
+        SKIP $skip
+        LIMIT $limit
+    `, relPattern)
+
+	params := map[string]interface{}{
+		"skip":  skip,
+		"limit": limit,
+	}
+
+	// Execute query
+	result, err := neo4j.ExecuteQuery(ctx, s.driver, query, params, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase(""), txMetadata(ctx))
+	if err != nil {
+		return graph.RelationshipList{}, fmt.Errorf("failed to execute ListRelationshipsByType query: %w", err)
+	}
+
+	// Process results
+	rels := make([]graph.RelationshipDetails, 0, len(result.Records))
+	for _, record := range result.Records {
+		rels = append(rels, relationshipDetailsFromMap(record.AsMap()))
+	}
+
+	return graph.RelationshipList{
+		Relationships: rels,
+		Total:         total,
+		Skip:          skip,
+		Limit:         limit,
+	}, nil
+}
+
+// relationshipDetailsFromMap builds a relationship from a map of raw query values with the keys id, type, props,
+// startId, startLabels, endId and endLabels.
+func relationshipDetailsFromMap(m map[string]interface{}) graph.RelationshipDetails {
+	id, _ := m["id"].(string)
+	rType, _ := m["type"].(string)
+	props, _ := m["props"].(map[string]interface{})
+	if props == nil {
+		props = make(map[string]interface{})
+	}
+	for k, v := range props {
+		props[k] = convertNeo4jValue(v)
+	}
+
+	return graph.RelationshipDetails{
+		ID:         id,
+		Type:       rType,
+		StartNode:  relationshipEndpointFromValues(m["startId"], m["startLabels"]),
+		EndNode:    relationshipEndpointFromValues(m["endId"], m["endLabels"]),
+		Properties: props,
+	}
+}
+
+// relationshipEndpointFromValues builds a relationship endpoint from raw query values for its element ID and labels.
+func relationshipEndpointFromValues(idVal, labelsVal interface{}) graph.RelationshipEndpoint {
+	id, _ := idVal.(string)
+	labelsInterface, _ := labelsVal.([]interface{})
+	labels := make([]string, len(labelsInterface))
+	for i, l := range labelsInterface {
+		labels[i], _ = l.(string)
+	}
+	return graph.RelationshipEndpoint{ID: id, Labels: labels}
+}
+
+// filterOperators maps property filter operators to Cypher comparison operators.
+// "contains" is handled separately since the operands are reversed ($value IN n[$prop]), as are "exists" and
+// "missing", which take no value.
+var filterOperators = map[string]string{
+	graph.FilterOpEquals:      "=",
+	graph.FilterOpNotEquals:   "<>",
+	graph.FilterOpGreaterThan: ">",
+	graph.FilterOpGreaterOrEq: ">=",
+	graph.FilterOpLessThan:    "<",
+	graph.FilterOpLessOrEq:    "<=",
+}
+
+// buildEntityFilterClause builds a WHERE clause body (without the WHERE keyword) restricting nodeVar to
+// any of the given labels and to every property filter, along with its parameters. Property names are
+// passed as parameters and accessed dynamically (n[$prop]), so nothing user-supplied is interpolated.
+// Example: "any(l IN labels(n) WHERE l IN $labels) AND $filterValue0 IN n[$filterProp0]"
+func buildEntityFilterClause(nodeVar string, labels []string, filters []graph.PropertyFilter) (string, map[string]interface{}, error) {
+	var conditions []string
+	params := map[string]interface{}{}
+
+	if len(labels) > 0 {
+		conditions = append(conditions, fmt.Sprintf("any(l IN labels(%s) WHERE l IN $labels)", nodeVar))
+		params["labels"] = labels
+	}
+
+	for i, filter := range filters {
+		if filter.Property == "" {
+			return "", nil, fmt.Errorf("filter at index %d has no property", i)
+		}
+		propParam := fmt.Sprintf("filterProp%d", i)
+		valueParam := fmt.Sprintf("filterValue%d", i)
+		params[propParam] = filter.Property
+
+		op := filter.Operator
+		if op == "" {
+			op = graph.FilterOpEquals
+		}
+		switch op {
+		case graph.FilterOpExists:
+			conditions = append(conditions, fmt.Sprintf("%s[$%s] IS NOT NULL", nodeVar, propParam))
+			continue
+		case graph.FilterOpMissing:
+			conditions = append(conditions, fmt.Sprintf("%s[$%s] IS NULL", nodeVar, propParam))
+			continue
+		}
+		params[valueParam] = filter.Value
+		if op == graph.FilterOpContains {
+			conditions = append(conditions, fmt.Sprintf("$%s IN %s[$%s]", valueParam, nodeVar, propParam))
+			continue
+		}
+		cypherOp, ok := filterOperators[op]
+		if !ok {
+			return "", nil, fmt.Errorf("filter at index %d has unsupported operator %q", i, filter.Operator)
+		}
+		conditions = append(conditions, fmt.Sprintf("%s[$%s] %s $%s", nodeVar, propParam, cypherOp, valueParam))
+	}
+
+	if len(conditions) == 0 {
+		return "true", params, nil // Match all nodes
+	}
+	return strings.Join(conditions, " AND "), params, nil
+}
+
+// FindBySource finds entities with any of the given labels (all entities if empty) whose source property equals
+// source, most recently created first. Entities without a createdAt come last.
+func (s *Neo4jStore) FindBySource(ctx context.Context, labels []string, source string, limit int) ([]graph.EntityDetails, error) {
+	if source == "" {
+		return nil, fmt.Errorf("source is required")
+	}
+	if limit <= 0 {
+		limit = 100 // Default limit
+	}
+
+	query := `
+        MATCH (n)
+        WHERE (size($labels) = 0 OR any(l IN labels(n) WHERE l IN $labels))
+          AND n.source = $source
+        RETURN labels(n) as labels, properties(n) as props, elementId(n) as id
+        ORDER BY n.createdAt IS NULL, n.createdAt DESC
+        LIMIT $limit
+    `
+
+	if labels == nil {
+		labels = []string{}
+	}
+	params := map[string]interface{}{
+		"labels": labels,
+		"source": source,
+		"limit":  limit,
+	}
+
+	// Execute query
+	result, err := neo4j.ExecuteQuery(ctx, s.driver, query, params, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase(""), txMetadata(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute FindBySource query: %w", err)
+	}
+
+	// Process results
+	entities := make([]graph.EntityDetails, 0, len(result.Records))
+	for _, record := range result.Records {
+		entities = append(entities, entityDetailsFromRecord(record, "labels", "props", "id"))
+	}
+
+	return entities, nil
+}
+
+// FindByFilePath finds entities with any of the given labels (all entities if empty) whose filePath property equals
+// filePath or, with prefix set, is a path within the directory filePath: "src/api" matches "src/api" and
+// "src/api/handlers.go" but not "src/api2/main.go". A trailing slash on filePath is ignored. Results are ordered
+// by filePath.
+func (s *Neo4jStore) FindByFilePath(ctx context.Context, labels []string, filePath string, prefix bool, limit int) ([]graph.EntityDetails, error) {
+	if filePath == "" {
+		return nil, fmt.Errorf("file path is required")
+	}
+	if limit <= 0 {
+		limit = 100 // Default limit
+	}
+
+	// Keep the root directory ("/") rather than trimming it to nothing
+	if trimmed := strings.TrimRight(filePath, "/"); trimmed != "" {
+		filePath = trimmed
+	}
+	condition := "n.filePath = $filePath"
+	if prefix {
+		condition = "(n.filePath = $filePath OR n.filePath STARTS WITH $directory)"
+	}
+	query := fmt.Sprintf(`
+        MATCH (n)
+        WHERE (size($labels) = 0 OR any(l IN labels(n) WHERE l IN $labels))
+          AND %s
+        RETURN labels(n) as labels, properties(n) as props, elementId(n) as id
+        ORDER BY n.filePath, id
+        LIMIT $limit
+    `, condition)
+
+	if labels == nil {
+		labels = []string{}
+	}
+	params := map[string]interface{}{
+		"labels":    labels,
+		"filePath":  filePath,
+		"directory": strings.TrimSuffix(filePath, "/") + "/",
+		"limit":     limit,
+	}
+
+	// Execute query
+	result, err := neo4j.ExecuteQuery(ctx, s.driver, query, params, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase(""), txMetadata(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute FindByFilePath query: %w", err)
+	}
+
+	// Process results
+	entities := make([]graph.EntityDetails, 0, len(result.Records))
+	for _, record := range result.Records {
+		entities = append(entities, entityDetailsFromRecord(record, "labels", "props", "id"))
+	}
+
+	return entities, nil
+}
@@ -0,0 +1,52 @@
+package neo4j
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sammcj/mcp-graph/internal/graph"
+)
+
+func TestBuildEntityFilterClause(t *testing.T) {
+	clause, params, err := buildEntityFilterClause("n", []string{"Service"}, []graph.PropertyFilter{
+		{Property: "tags", Operator: graph.FilterOpContains, Value: "public-api"},
+		{Property: "status", Value: "active"},
+		{Property: "confidence", Operator: graph.FilterOpGreaterOrEq, Value: 0.5},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "any(l IN labels(n) WHERE l IN $labels) AND $filterValue0 IN n[$filterProp0] AND n[$filterProp1] = $filterValue1 AND n[$filterProp2] >= $filterValue2", clause)
+	assert.Equal(t, []string{"Service"}, params["labels"])
+	assert.Equal(t, "tags", params["filterProp0"])
+	assert.Equal(t, "public-api", params["filterValue0"])
+	assert.Equal(t, 0.5, params["filterValue2"])
+}
+
+func TestBuildEntityFilterClause_NoConditions(t *testing.T) {
+	clause, params, err := buildEntityFilterClause("n", nil, nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "true", clause)
+	assert.Empty(t, params)
+}
+
+func TestBuildEntityFilterClause_UnsupportedOperator(t *testing.T) {
+	_, _, err := buildEntityFilterClause("n", nil, []graph.PropertyFilter{
+		{Property: "name", Operator: "like", Value: "foo"},
+	})
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "unsupported operator")
+}
+
+func TestBuildEntityFilterClause_ExistsAndMissing(t *testing.T) {
+	clause, params, err := buildEntityFilterClause("n", nil, []graph.PropertyFilter{
+		{Property: "owner", Operator: graph.FilterOpExists},
+		{Property: "schemaVersion", Operator: graph.FilterOpMissing},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "n[$filterProp0] IS NOT NULL AND n[$filterProp1] IS NULL", clause)
+	assert.Equal(t, map[string]interface{}{"filterProp0": "owner", "filterProp1": "schemaVersion"}, params)
+}
@@ -0,0 +1,376 @@
+package neo4j
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/sammcj/mcp-graph/internal/graph"
+)
+
+// maxSnapshotNodes caps the number of nodes ExportSubgraph will copy, as snapshots are held in memory
+const maxSnapshotNodes = 10000
+
+// ExportSubgraph copies every node with any of the given labels, and every relationship touching them, in a single
+// read transaction so that the copy is consistent. Fails if more than maxSnapshotNodes nodes match. Property values
+// that JSON can't represent exactly, such as dates and floats with whole values, are encoded as JSON values and
+// tagged with their type (see encodeSnapshotValue), so that RestoreSubgraph can recreate them.
+func (s *Neo4jStore) ExportSubgraph(ctx context.Context, labels []string) (graph.GraphSnapshot, error) {
+	if len(labels) == 0 {
+		return graph.GraphSnapshot{}, fmt.Errorf("at least one label is required")
+	}
+
+	session := s.driver.NewSession(ctx, neo4j.SessionConfig{})
+	defer session.Close(ctx)
+
+	snapshot, err := neo4j.ExecuteRead(ctx, session, func(tx neo4j.ManagedTransaction) (graph.GraphSnapshot, error) {
+		snapshot := graph.GraphSnapshot{Labels: labels, CreatedAt: time.Now().UTC()}
+
+		nodeResult, err := tx.Run(ctx, `
+            MATCH (n) WHERE any(l IN labels(n) WHERE l IN $labels)
+            RETURN elementId(n) AS id, labels(n) AS labels, properties(n) AS props
+            LIMIT $limit
+        `, map[string]interface{}{"labels": labels, "limit": maxSnapshotNodes + 1})
+		if err != nil {
+			return snapshot, err
+		}
+		nodeRecords, err := nodeResult.Collect(ctx)
+		if err != nil {
+			return snapshot, err
+		}
+		if len(nodeRecords) > maxSnapshotNodes {
+			return snapshot, fmt.Errorf("more than %d nodes have these labels; snapshot a narrower set of labels", maxSnapshotNodes)
+		}
+		for _, record := range nodeRecords {
+			idVal, _ := record.Get("id")
+			labelsVal, _ := record.Get("labels")
+			propsVal, _ := record.Get("props")
+			id, _ := idVal.(string)
+			labelsInterface, _ := labelsVal.([]interface{})
+			labels := make([]string, len(labelsInterface))
+			for i, l := range labelsInterface {
+				labels[i], _ = l.(string)
+			}
+			rawProps, _ := propsVal.(map[string]interface{})
+			props, types := encodeSnapshotProperties(rawProps)
+			snapshot.Nodes = append(snapshot.Nodes, graph.SnapshotNode{ID: id, Labels: labels, Properties: props, Types: types})
+		}
+
+		relResult, err := tx.Run(ctx, `
+            MATCH (n)-[r]-() WHERE any(l IN labels(n) WHERE l IN $labels)
+            WITH DISTINCT r
+            RETURN type(r) AS type, elementId(startNode(r)) AS start, elementId(endNode(r)) AS end, properties(r) AS props
+        `, map[string]interface{}{"labels": labels})
+		if err != nil {
+			return snapshot, err
+		}
+		relRecords, err := relResult.Collect(ctx)
+		if err != nil {
+			return snapshot, err
+		}
+		for _, record := range relRecords {
+			typeVal, _ := record.Get("type")
+			startVal, _ := record.Get("start")
+			endVal, _ := record.Get("end")
+			propsVal, _ := record.Get("props")
+			relType, _ := typeVal.(string)
+			start, _ := startVal.(string)
+			end, _ := endVal.(string)
+			rawProps, _ := propsVal.(map[string]interface{})
+			props, types := encodeSnapshotProperties(rawProps)
+			snapshot.Relationships = append(snapshot.Relationships, graph.SnapshotRelationship{
+				Type:       relType,
+				StartNode:  start,
+				EndNode:    end,
+				Properties: props,
+				Types:      types,
+			})
+		}
+
+		return snapshot, nil
+	}, neo4j.WithTxMetadata(graph.QueryMetadataFromContext(ctx)))
+	if err != nil {
+		return graph.GraphSnapshot{}, fmt.Errorf("failed to export subgraph: %w", err)
+	}
+
+	return snapshot, nil
+}
+
+// RestoreSubgraph deletes every node with any of the snapshot's labels, then recreates the snapshot's nodes and
+// relationships, all in one write transaction. Relationships to nodes outside the snapshot are reconnected if
+// that node still exists (and hasn't since gained one of the snapshot's labels), and skipped otherwise.
+func (s *Neo4jStore) RestoreSubgraph(ctx context.Context, snapshot graph.GraphSnapshot) (graph.RestoreResult, error) {
+	if len(snapshot.Labels) == 0 {
+		return graph.RestoreResult{}, fmt.Errorf("snapshot has no labels")
+	}
+
+	session := s.driver.NewSession(ctx, neo4j.SessionConfig{})
+	defer session.Close(ctx)
+
+	result, err := neo4j.ExecuteWrite(ctx, session, func(tx neo4j.ManagedTransaction) (graph.RestoreResult, error) {
+		var result graph.RestoreResult
+
+		// Remove the current nodes
+		deleted, err := runCount(ctx, tx, `
+            MATCH (n) WHERE any(l IN labels(n) WHERE l IN $labels)
+            DETACH DELETE n
+            RETURN count(*) AS count
+        `, map[string]interface{}{"labels": snapshot.Labels})
+		if err != nil {
+			return result, err
+		}
+		result.NodesDeleted = deleted
+
+		// Recreate the nodes, one query per combination of labels, mapping old IDs to new ones
+		newIDs := make(map[string]string, len(snapshot.Nodes))
+		for labelStr, nodes := range groupSnapshotNodes(snapshot.Nodes) {
+			nodeResult, err := tx.Run(ctx, fmt.Sprintf(`
+                UNWIND $nodes AS node
+                CREATE (n%s)
+                SET n = node.props
+                RETURN node.id AS oldId, elementId(n) AS newId
+            `, labelStr), map[string]interface{}{"nodes": nodes})
+			if err != nil {
+				return result, err
+			}
+			records, err := nodeResult.Collect(ctx)
+			if err != nil {
+				return result, err
+			}
+			for _, record := range records {
+				oldIDVal, _ := record.Get("oldId")
+				newIDVal, _ := record.Get("newId")
+				oldID, _ := oldIDVal.(string)
+				newIDs[oldID], _ = newIDVal.(string)
+			}
+			result.NodesCreated += int64(len(records))
+		}
+
+		// Recreate the relationships, one query per type
+		for relType, rels := range groupSnapshotRelationships(snapshot.Relationships, newIDs) {
+			created, err := runCount(ctx, tx, fmt.Sprintf(`
+                UNWIND $rels AS rel
+                MATCH (a) WHERE elementId(a) = rel.start AND (rel.startRestored OR none(l IN labels(a) WHERE l IN $labels))
+                MATCH (b) WHERE elementId(b) = rel.end AND (rel.endRestored OR none(l IN labels(b) WHERE l IN $labels))
+                CREATE (a)-[r:%s]->(b)
+                SET r = rel.props
+                RETURN count(r) AS count
+            `, graph.QuoteCypherIdentifier(relType)), map[string]interface{}{"rels": rels, "labels": snapshot.Labels})
+			if err != nil {
+				return result, err
+			}
+			result.RelationshipsCreated += created
+			result.RelationshipsSkipped += int64(len(rels)) - created
+		}
+
+		return result, nil
+	}, neo4j.WithTxMetadata(graph.QueryMetadataFromContext(ctx)))
+	if err != nil {
+		return graph.RestoreResult{}, fmt.Errorf("failed to restore subgraph: %w", err)
+	}
+
+	return result, nil
+}
+
+// runCount runs a query returning a single "count" column in a transaction and returns its value
+func runCount(ctx context.Context, tx neo4j.ManagedTransaction, query string, params map[string]interface{}) (int64, error) {
+	res, err := tx.Run(ctx, query, params)
+	if err != nil {
+		return 0, err
+	}
+	record, err := res.Single(ctx)
+	if err != nil {
+		return 0, err
+	}
+	countVal, _ := record.Get("count")
+	count, _ := countVal.(int64)
+	return count, nil
+}
+
+// groupSnapshotNodes groups snapshot nodes by their label string (e.g. ":`Function`:`Go`") as query parameters
+func groupSnapshotNodes(nodes []graph.SnapshotNode) map[string][]map[string]interface{} {
+	groups := make(map[string][]map[string]interface{})
+	for _, node := range nodes {
+		labels := append([]string(nil), node.Labels...)
+		sort.Strings(labels)
+		var labelStr strings.Builder
+		for _, l := range labels {
+			labelStr.WriteString(":" + graph.QuoteCypherIdentifier(l))
+		}
+		groups[labelStr.String()] = append(groups[labelStr.String()], map[string]interface{}{
+			"id":    node.ID,
+			"props": decodeSnapshotProperties(node.Properties, node.Types),
+		})
+	}
+	return groups
+}
+
+// groupSnapshotRelationships groups snapshot relationships by type as query parameters, replacing the IDs of
+// restored nodes with their new IDs. IDs of nodes outside the snapshot are kept as they are.
+func groupSnapshotRelationships(rels []graph.SnapshotRelationship, newIDs map[string]string) map[string][]map[string]interface{} {
+	groups := make(map[string][]map[string]interface{})
+	for _, rel := range rels {
+		start, startRestored := newIDs[rel.StartNode]
+		if !startRestored {
+			start = rel.StartNode
+		}
+		end, endRestored := newIDs[rel.EndNode]
+		if !endRestored {
+			end = rel.EndNode
+		}
+		groups[rel.Type] = append(groups[rel.Type], map[string]interface{}{
+			"start":         start,
+			"end":           end,
+			"startRestored": startRestored,
+			"endRestored":   endRestored,
+			"props":         decodeSnapshotProperties(rel.Properties, rel.Types),
+		})
+	}
+	return groups
+}
+
+// Layouts of the temporal values stored in snapshots
+const (
+	snapshotDateLayout          = "2006-01-02"
+	snapshotLocalDateTimeLayout = "2006-01-02T15:04:05.999999999"
+	snapshotLocalTimeLayout     = "15:04:05.999999999"
+	snapshotTimeLayout          = "15:04:05.999999999Z07:00"
+)
+
+// encodeSnapshotProperties encodes property values as returned by the driver for a snapshot, returning the encoded
+// properties and the type tags of those that need one
+func encodeSnapshotProperties(properties map[string]interface{}) (map[string]interface{}, map[string]string) {
+	encoded := make(map[string]interface{}, len(properties))
+	var types map[string]string
+	for k, v := range properties {
+		value, tag := encodeSnapshotValue(v)
+		encoded[k] = value
+		if tag != "" {
+			if types == nil {
+				types = make(map[string]string)
+			}
+			types[k] = tag
+		}
+	}
+	return encoded, types
+}
+
+// encodeSnapshotValue encodes a property value as one JSON can represent, returning the Neo4j type name to tag it
+// with, or "" if it comes back from JSON unchanged. Temporal values become ISO-8601 strings, durations become a map
+// of their parts, and floats are tagged so that whole ones aren't restored as integers. Lists, which Neo4j keeps
+// homogeneous, are tagged "List<T>" after their elements' type.
+func encodeSnapshotValue(value interface{}) (interface{}, string) {
+	switch v := value.(type) {
+	case float64:
+		return v, "Float"
+	case time.Time:
+		return v.Format(time.RFC3339Nano), "DateTime"
+	case neo4j.LocalDateTime:
+		return v.Time().Format(snapshotLocalDateTimeLayout), "LocalDateTime"
+	case neo4j.Date:
+		return v.Time().Format(snapshotDateLayout), "Date"
+	case neo4j.LocalTime:
+		return v.Time().Format(snapshotLocalTimeLayout), "LocalTime"
+	case neo4j.OffsetTime:
+		return v.Time().Format(snapshotTimeLayout), "Time"
+	case neo4j.Duration:
+		return map[string]interface{}{"months": v.Months, "days": v.Days, "seconds": v.Seconds, "nanos": int64(v.Nanos)}, "Duration"
+	case []interface{}:
+		items := make([]interface{}, len(v))
+		var tag string
+		for i, item := range v {
+			items[i], tag = encodeSnapshotValue(item)
+		}
+		if tag == "" {
+			return items, ""
+		}
+		return items, "List<" + tag + ">"
+	default:
+		return v, ""
+	}
+}
+
+// decodeSnapshotProperties reverses encodeSnapshotProperties, returning properties ready to be written to the graph
+func decodeSnapshotProperties(properties map[string]interface{}, types map[string]string) map[string]interface{} {
+	decoded := make(map[string]interface{}, len(properties))
+	for k, v := range properties {
+		decoded[k] = decodeSnapshotValue(v, types[k])
+	}
+	return decoded
+}
+
+// decodeSnapshotValue converts a value encoded by encodeSnapshotValue back to the Neo4j type it was tagged with.
+// Values that don't parse as their tagged type are returned unchanged.
+func decodeSnapshotValue(value interface{}, tag string) interface{} {
+	if strings.HasPrefix(tag, "List<") && strings.HasSuffix(tag, ">") {
+		items, ok := value.([]interface{})
+		if !ok {
+			return value
+		}
+		elemTag := strings.TrimSuffix(strings.TrimPrefix(tag, "List<"), ">")
+		decoded := make([]interface{}, len(items))
+		for i, item := range items {
+			decoded[i] = decodeSnapshotValue(item, elemTag)
+		}
+		return decoded
+	}
+
+	switch tag {
+	case "Float":
+		switch v := value.(type) {
+		case int64:
+			return float64(v)
+		case int:
+			return float64(v)
+		}
+	case "Duration":
+		if parts, ok := value.(map[string]interface{}); ok {
+			return neo4j.DurationOf(snapshotInt(parts["months"]), snapshotInt(parts["days"]), snapshotInt(parts["seconds"]), int(snapshotInt(parts["nanos"])))
+		}
+	default:
+		str, ok := value.(string)
+		if !ok {
+			return value
+		}
+		switch tag {
+		case "DateTime":
+			if t, err := time.Parse(time.RFC3339Nano, str); err == nil {
+				return t
+			}
+		case "LocalDateTime":
+			if t, err := time.Parse(snapshotLocalDateTimeLayout, str); err == nil {
+				return neo4j.LocalDateTimeOf(t)
+			}
+		case "Date":
+			if t, err := time.Parse(snapshotDateLayout, str); err == nil {
+				return neo4j.DateOf(t)
+			}
+		case "LocalTime":
+			if t, err := time.Parse(snapshotLocalTimeLayout, str); err == nil {
+				return neo4j.LocalTimeOf(t)
+			}
+		case "Time":
+			if t, err := time.Parse(snapshotTimeLayout, str); err == nil {
+				return neo4j.OffsetTimeOf(t)
+			}
+		}
+	}
+	return value
+}
+
+// snapshotInt returns a whole number loaded from a snapshot as an int64
+func snapshotInt(value interface{}) int64 {
+	switch v := value.(type) {
+	case int64:
+		return v
+	case int:
+		return int64(v)
+	case float64:
+		return int64(v)
+	default:
+		return 0
+	}
+}
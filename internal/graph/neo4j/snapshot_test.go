@@ -0,0 +1,71 @@
+package neo4j
+
+import (
+	"testing"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sammcj/mcp-graph/internal/graph"
+	"github.com/sammcj/mcp-graph/internal/snapshot"
+)
+
+func TestSnapshotProperties_RoundTrip(t *testing.T) {
+	modified := time.Date(2024, 5, 1, 9, 30, 0, 123000000, time.UTC)
+	properties := map[string]interface{}{
+		"name":           "api",
+		"port":           int64(8080),
+		"weight":         1.0, // A whole float must not come back as an integer
+		"ratio":          0.25,
+		"createdAt":      time.Date(2024, 4, 1, 8, 0, 0, 0, time.FixedZone("", 10*3600)),
+		"lastModifiedAt": modified,
+		"expiresAt":      neo4j.LocalDateTimeOf(time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)),
+		"releasedOn":     neo4j.DateOf(time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)),
+		"opensAt":        neo4j.LocalTimeOf(time.Date(0, 1, 1, 9, 0, 0, 0, time.UTC)),
+		"timeout":        neo4j.DurationOf(1, 2, 30, 500),
+		"scores":         []interface{}{1.0, 2.5},
+		"tags":           []interface{}{"a", "b"},
+	}
+
+	encoded, types := encodeSnapshotProperties(properties)
+	assert.Equal(t, "DateTime", types["lastModifiedAt"])
+	assert.Equal(t, "Float", types["weight"])
+	assert.Equal(t, "List<Float>", types["scores"])
+	assert.NotContains(t, types, "name")
+	assert.NotContains(t, types, "tags")
+
+	// Save and load the snapshot through JSON, as restore_snapshot does
+	store := snapshot.NewStore(t.TempDir())
+	err := store.Save(graph.GraphSnapshot{
+		Name:   "round-trip",
+		Labels: []string{"Service"},
+		Nodes:  []graph.SnapshotNode{{ID: "4:abc:1", Labels: []string{"Service"}, Properties: encoded, Types: types}},
+	}, false)
+	assert.NoError(t, err)
+	loaded, err := store.Load("round-trip")
+	assert.NoError(t, err)
+
+	decoded := decodeSnapshotProperties(loaded.Nodes[0].Properties, loaded.Nodes[0].Types)
+	for k, want := range properties {
+		got := decoded[k]
+		if wantTime, ok := want.(time.Time); ok {
+			// Time zones are restored as fixed offsets
+			gotTime, ok := got.(time.Time)
+			if assert.True(t, ok, k) {
+				assert.True(t, wantTime.Equal(gotTime), k)
+			}
+			continue
+		}
+		assert.Equal(t, want, got, k)
+	}
+}
+
+func TestDecodeSnapshotValue_Untagged(t *testing.T) {
+	// Snapshots saved before types were recorded are restored as they were loaded
+	assert.Equal(t, "2024-05-01T09:30:00Z", decodeSnapshotValue("2024-05-01T09:30:00Z", ""))
+	assert.Equal(t, int64(1), decodeSnapshotValue(int64(1), ""))
+
+	// Values that don't parse as their tagged type are kept
+	assert.Equal(t, "soon", decodeSnapshotValue("soon", "DateTime"))
+}
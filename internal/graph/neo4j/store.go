@@ -2,10 +2,17 @@ package neo4j
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net"
+	"reflect"
+	"sort"
 	"strings"
 	"sync"
+	"syscall"
 	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
 	"github.com/sammcj/mcp-graph/internal/graph"
@@ -13,30 +20,309 @@ import (
 
 // Neo4jStore implements the graph.Store interface using Neo4j
 type Neo4jStore struct {
-	driver neo4j.DriverWithContext
+	driver                      neo4j.DriverWithContext
+	normalizeRelationshipTypes  bool
+	batchConcurrency            int
+	maxPropertyBytes            int    // Maximum size of a string property value; 0 means unlimited
+	truncateOversizedProperties bool   // Truncate oversized values instead of rejecting them
+	coerceTemporalProperties    bool   // Convert ISO-8601 strings in MERGE inputs to temporal values
+	defaultRelationshipSource   string // Source recorded on new relationships that don't give one; graph.DefaultRelationshipSource if empty
+	integrityRules              graph.IntegrityRules
+	allowedRelationshipTypes    []string                 // Types relationships may be created with; any type if empty
+	propertyWhitelist           map[string][]string      // Property keys allowed on nodes with each label; unrestricted for other labels
+	stripUnknownProperties      bool                     // Drop properties not on the whitelist instead of rejecting the write
+	labelTTLs                   map[string]time.Duration // Lifetime of new nodes with each label; nodes without one don't expire
 }
 
+// defaultBatchConcurrency is the default number of entities or relationships processed at once by batch operations
+const defaultBatchConcurrency = 10
+
+// createdMarkerProperty is set on nodes created by CreateNodeIfNotExists's MERGE, and removed again, to report
+// whether the node was created
+const createdMarkerProperty = "`__createdByMerge`"
+
 // Ensure Neo4jStore implements graph.Store
 var _ graph.Store = (*Neo4jStore)(nil)
 
 // NewNeo4jStore creates a new Neo4j store
 func NewNeo4jStore(uri, username, password string) (*Neo4jStore, error) {
+	return NewNeo4jStoreWithRetry(uri, username, password, graph.RetryPolicy{Attempts: 1})
+}
+
+// NewNeo4jStoreWithRetry creates a new Neo4j store, retrying the connection according to the policy
+// so that the server can wait for a database that is still starting up. Any configurers (e.g.
+// WithMaxConnectionPoolSize) are applied to the driver configuration.
+func NewNeo4jStoreWithRetry(uri, username, password string, policy graph.RetryPolicy, configurers ...func(*neo4j.Config)) (*Neo4jStore, error) {
+	return connect(uri, username, password, policy, configurers...)
+}
+
+// NewNeo4jStoreWithSchemeFallback creates a new Neo4j store like NewNeo4jStoreWithRetry, but if connecting fails
+// because of routing (e.g. a neo4j:// URI for a single instance that only accepts direct connections) it tries
+// again with the alternative scheme: bolt:// for neo4j:// and vice versa, keeping any +s or +ssc suffix.
+// Routing failures aren't retried with the original scheme, as waiting won't fix them.
+// Returns the URI the store connected with.
+func NewNeo4jStoreWithSchemeFallback(uri, username, password string, policy graph.RetryPolicy, configurers ...func(*neo4j.Config)) (*Neo4jStore, string, error) {
+	alternate, ok := alternateScheme(uri)
+	if !ok {
+		store, err := connect(uri, username, password, policy, configurers...)
+		return store, uri, err
+	}
+
+	retryable := policy.Retryable
+	policy.Retryable = func(err error) bool {
+		return !isRoutingError(err) && (retryable == nil || retryable(err))
+	}
+
+	store, err := connect(uri, username, password, policy, configurers...)
+	if err == nil || !isRoutingError(err) {
+		return store, uri, err
+	}
+
+	store, fallbackErr := connect(alternate, username, password, policy, configurers...)
+	if fallbackErr != nil {
+		return nil, uri, fmt.Errorf("%w (falling back to %s also failed: %v)", err, alternate, fallbackErr)
+	}
+	return store, alternate, nil
+}
+
+// alternateScheme swaps a URI's routing scheme for the direct one or vice versa, e.g.
+// "neo4j+s://host" -> "bolt+s://host". Returns false for URIs with any other scheme.
+func alternateScheme(uri string) (string, bool) {
+	scheme, rest, found := strings.Cut(uri, "://")
+	if !found {
+		return "", false
+	}
+	base, suffix, _ := strings.Cut(scheme, "+")
+	if suffix != "" {
+		suffix = "+" + suffix
+	}
+	switch strings.ToLower(base) {
+	case "neo4j":
+		return "bolt" + suffix + "://" + rest, true
+	case "bolt":
+		return "neo4j" + suffix + "://" + rest, true
+	default:
+		return "", false
+	}
+}
+
+// isRoutingError reports whether a connection failure was caused by routing, such as the server being unable
+// to provide a routing table, rather than the server being unreachable or rejecting the credentials.
+func isRoutingError(err error) bool {
+	if errors.Is(err, graph.ErrConnectionRefused) || errors.Is(err, graph.ErrTimeout) || errors.Is(err, graph.ErrAuthFailed) {
+		return false
+	}
+	// The driver reports routing failures with an internal error type, so its message is the only signal.
+	// A routing table request to a server that is down fails the same way, so exclude network errors.
+	msg := strings.ToLower(err.Error())
+	if strings.Contains(msg, "connection refused") || strings.Contains(msg, "timeout") || strings.Contains(msg, "no such host") {
+		return false
+	}
+	return strings.Contains(msg, "routing")
+}
+
+// connect creates a driver for the URI and waits until the database is reachable
+func connect(uri, username, password string, policy graph.RetryPolicy, configurers ...func(*neo4j.Config)) (*Neo4jStore, error) {
 	// Create a Neo4j driver
-	driver, err := neo4j.NewDriverWithContext(uri, neo4j.BasicAuth(username, password, ""))
+	driver, err := neo4j.NewDriverWithContext(uri, neo4j.BasicAuth(username, password, ""), configurers...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Neo4j driver: %w", err)
 	}
 
+	store := &Neo4jStore{
+		driver:           driver,
+		batchConcurrency: defaultBatchConcurrency,
+	}
+
 	// Verify connectivity
 	ctx := context.Background()
-	err = driver.VerifyConnectivity(ctx)
-	if err != nil {
+	if err := graph.WaitUntilReachable(ctx, store, policy); err != nil {
+		driver.Close(ctx)
 		return nil, fmt.Errorf("failed to connect to Neo4j: %w", err)
 	}
 
-	return &Neo4jStore{
-		driver: driver,
-	}, nil
+	return store, nil
+}
+
+// Ping checks that Neo4j is reachable
+func (s *Neo4jStore) Ping(ctx context.Context) error {
+	if err := s.driver.VerifyConnectivity(ctx); err != nil {
+		return classifyConnectivityError(err)
+	}
+	return nil
+}
+
+// classifyConnectivityError wraps a driver error with the graph error describing its cause, if known.
+// The driver's ConnectivityError doesn't support unwrapping, so its inner error is examined directly.
+func classifyConnectivityError(err error) error {
+	inner := err
+	var connErr *neo4j.ConnectivityError
+	if errors.As(err, &connErr) && connErr.Inner != nil {
+		inner = connErr.Inner
+	}
+
+	var neoErr *neo4j.Neo4jError
+	var netErr net.Error
+	switch {
+	case errors.As(err, &neoErr) && strings.HasPrefix(neoErr.Code, "Neo.ClientError.Security."):
+		return fmt.Errorf("%w: %v", graph.ErrAuthFailed, err)
+	case errors.Is(inner, syscall.ECONNREFUSED):
+		return fmt.Errorf("%w: %v", graph.ErrConnectionRefused, err)
+	case errors.Is(inner, context.DeadlineExceeded), errors.As(inner, &netErr) && netErr.Timeout():
+		return fmt.Errorf("%w: %v", graph.ErrTimeout, err)
+	default:
+		return err
+	}
+}
+
+// SetNormalizeRelationshipTypes enables or disables converting relationship types to upper snake case
+// (e.g. "dependsOn" -> "DEPENDS_ON") before they are used to create relationships.
+func (s *Neo4jStore) SetNormalizeRelationshipTypes(enabled bool) {
+	s.normalizeRelationshipTypes = enabled
+}
+
+// SetDefaultRelationshipSource sets the source recorded on new relationships that don't give one (e.g.
+// "agent-inference"). An empty source restores graph.DefaultRelationshipSource.
+func (s *Neo4jStore) SetDefaultRelationshipSource(source string) {
+	s.defaultRelationshipSource = source
+}
+
+// SetAllowedRelationshipTypes restricts the types relationships may be created with to the given ones, checked after
+// normalisation. An empty list allows any type.
+func (s *Neo4jStore) SetAllowedRelationshipTypes(types []string) {
+	s.allowedRelationshipTypes = types
+}
+
+// SetPropertyWhitelist restricts the property keys CreateNode, BatchCreateNodes and FindOrCreateEntity may write on
+// nodes with the given labels. Other properties are dropped if strip is set, otherwise the write is rejected with
+// graph.ErrPropertyNotAllowed. Labels without a whitelist are unrestricted.
+func (s *Neo4jStore) SetPropertyWhitelist(whitelist map[string][]string, strip bool) {
+	s.propertyWhitelist = whitelist
+	s.stripUnknownProperties = strip
+}
+
+// SetIntegrityRules sets the rules CheckIntegrity checks the relationship graph against.
+func (s *Neo4jStore) SetIntegrityRules(rules graph.IntegrityRules) {
+	s.integrityRules = rules
+}
+
+// WithMaxConnectionPoolSize sets the maximum number of connections the driver keeps open to the database.
+// Non-positive sizes leave the driver's default (100) unchanged.
+func WithMaxConnectionPoolSize(size int) func(*neo4j.Config) {
+	return func(c *neo4j.Config) {
+		if size > 0 {
+			c.MaxConnectionPoolSize = size
+		}
+	}
+}
+
+// SetBatchConcurrency sets the number of entities or relationships that batch operations process at once.
+// Each uses a connection, so this should be well below the connection pool size. Non-positive values
+// leave the current setting unchanged.
+func (s *Neo4jStore) SetBatchConcurrency(limit int) {
+	if limit > 0 {
+		s.batchConcurrency = limit
+	}
+}
+
+// SetPropertySizeLimit limits the size in bytes of string property values written by CreateNode, BatchCreateNodes,
+// UpdateNode and FindOrCreateEntity. Oversized values are truncated if truncate is set, otherwise the write is
+// rejected with graph.ErrPropertyTooLarge. A non-positive maxBytes removes the limit.
+func (s *Neo4jStore) SetPropertySizeLimit(maxBytes int, truncate bool) {
+	s.maxPropertyBytes = maxBytes
+	s.truncateOversizedProperties = truncate
+}
+
+// applyPropertyWhitelist applies the property whitelists of a node's labels to its properties. A key is allowed if
+// any of the labels with a whitelist allows it, and timestamps are always allowed. The map is returned unchanged if
+// every key is allowed; otherwise a copy is returned without the other keys if strip is set, or an error naming them.
+func (s *Neo4jStore) applyPropertyWhitelist(labels []string, properties map[string]interface{}, strip bool) (map[string]interface{}, error) {
+	allowed := make(map[string]bool)
+	whitelisted := false
+	for _, label := range labels {
+		if keys, ok := s.propertyWhitelist[label]; ok {
+			whitelisted = true
+			for _, key := range keys {
+				allowed[key] = true
+			}
+		}
+	}
+	if !whitelisted {
+		return properties, nil
+	}
+
+	var unknown []string
+	for key := range properties {
+		if !allowed[key] && !timestampProperties[key] {
+			unknown = append(unknown, key)
+		}
+	}
+	if len(unknown) == 0 {
+		return properties, nil
+	}
+	sort.Strings(unknown)
+	if !strip {
+		allowedKeys := make([]string, 0, len(allowed))
+		for key := range allowed {
+			allowedKeys = append(allowedKeys, key)
+		}
+		sort.Strings(allowedKeys)
+		return nil, fmt.Errorf("%w on %s nodes: %s; allowed properties are %s", graph.ErrPropertyNotAllowed,
+			strings.Join(labels, ":"), strings.Join(unknown, ", "), strings.Join(allowedKeys, ", "))
+	}
+
+	stripped := make(map[string]interface{}, len(properties)-len(unknown))
+	for key, value := range properties {
+		stripped[key] = value
+	}
+	for _, key := range unknown {
+		delete(stripped, key)
+	}
+	return stripped, nil
+}
+
+// limitPropertySizes applies the property size limit to a properties map. The map is returned unchanged if
+// no value is too large; otherwise a copy is returned with oversized values truncated, or an error if
+// truncation is disabled.
+func (s *Neo4jStore) limitPropertySizes(properties map[string]interface{}) (map[string]interface{}, error) {
+	if s.maxPropertyBytes <= 0 {
+		return properties, nil
+	}
+
+	var limited map[string]interface{}
+	for k, v := range properties {
+		str, ok := v.(string)
+		if !ok || len(str) <= s.maxPropertyBytes {
+			continue
+		}
+		if !s.truncateOversizedProperties {
+			return nil, fmt.Errorf("%w: %q is %d bytes, the limit is %d", graph.ErrPropertyTooLarge, k, len(str), s.maxPropertyBytes)
+		}
+		if limited == nil {
+			limited = make(map[string]interface{}, len(properties))
+			for k2, v2 := range properties {
+				limited[k2] = v2
+			}
+		}
+		limited[k] = truncateUTF8(str, s.maxPropertyBytes)
+	}
+
+	if limited == nil {
+		return properties, nil
+	}
+	return limited, nil
+}
+
+// truncateUTF8 shortens a string to at most maxBytes bytes without splitting a multi-byte character
+func truncateUTF8(str string, maxBytes int) string {
+	if len(str) <= maxBytes {
+		return str
+	}
+	cut := maxBytes
+	for cut > 0 && !utf8.RuneStart(str[cut]) {
+		cut--
+	}
+	return str[:cut]
 }
 
 // Close closes the Neo4j driver
@@ -46,10 +332,21 @@ func (s *Neo4jStore) Close(ctx context.Context) error {
 
 // CreateNode creates a new node in the graph
 func (s *Neo4jStore) CreateNode(ctx context.Context, nodeType string, properties map[string]interface{}) (string, error) {
+	properties, err := s.applyPropertyWhitelist([]string{nodeType}, properties, s.stripUnknownProperties)
+	if err != nil {
+		return "", err
+	}
+
 	// Add the type to properties if not already present
 	if _, ok := properties["type"]; !ok {
 		properties["type"] = nodeType
 	}
+	s.setDefaultExpiry([]string{nodeType}, properties, time.Now().UTC())
+
+	properties, err = s.limitPropertySizes(properties)
+	if err != nil {
+		return "", err
+	}
 
 	// Create Cypher query
 	query := fmt.Sprintf("CREATE (n:%s $props) RETURN n", nodeType)
@@ -58,7 +355,7 @@ func (s *Neo4jStore) CreateNode(ctx context.Context, nodeType string, properties
 	}
 
 	// Execute query
-	result, err := neo4j.ExecuteQuery(ctx, s.driver, query, params, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase(""))
+	result, err := neo4j.ExecuteQuery(ctx, s.driver, query, params, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase(""), txMetadata(ctx))
 	if err != nil {
 		return "", fmt.Errorf("failed to create node: %w", err)
 	}
@@ -83,6 +380,60 @@ func (s *Neo4jStore) CreateNode(ctx context.Context, nodeType string, properties
 	return node.ElementId, nil
 }
 
+// CreateNodeIfNotExists finds the node of the given type whose key property equals value, creating it with the
+// given properties if there is none. A marker property set only on creation tells the two cases apart, and is
+// removed in the same query. The MERGE only prevents duplicates under concurrent calls when the key property has a
+// uniqueness constraint.
+func (s *Neo4jStore) CreateNodeIfNotExists(ctx context.Context, nodeType, key string, value interface{}, properties map[string]interface{}) (string, bool, error) {
+	properties, err := s.applyPropertyWhitelist([]string{nodeType}, properties, s.stripUnknownProperties)
+	if err != nil {
+		return "", false, err
+	}
+
+	// Add the type to properties if not already present
+	if _, ok := properties["type"]; !ok {
+		properties["type"] = nodeType
+	}
+	properties[key] = value
+	s.setDefaultExpiry([]string{nodeType}, properties, time.Now().UTC())
+
+	properties, err = s.limitPropertySizes(properties)
+	if err != nil {
+		return "", false, err
+	}
+
+	query := fmt.Sprintf(`
+        MERGE (n:%s {%s: $value})
+        ON CREATE SET n += $props, n.%[3]s = true
+        WITH n, coalesce(n.%[3]s, false) AS created
+        REMOVE n.%[3]s
+        RETURN elementId(n) AS id, created
+    `, graph.QuoteCypherIdentifier(nodeType), graph.QuoteCypherIdentifier(key), createdMarkerProperty)
+	params := map[string]interface{}{
+		"value": value,
+		"props": properties,
+	}
+
+	// Execute query
+	result, err := neo4j.ExecuteQuery(ctx, s.driver, query, params, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase(""), txMetadata(ctx))
+	if err != nil {
+		return "", false, fmt.Errorf("failed to find or create node: %w", err)
+	}
+	if len(result.Records) == 0 {
+		return "", false, fmt.Errorf("no node returned from MERGE operation")
+	}
+
+	record := result.Records[0]
+	idVal, _ := record.Get("id")
+	id, ok := idVal.(string)
+	if !ok {
+		return "", false, fmt.Errorf("elementId is not a string")
+	}
+	createdVal, _ := record.Get("created")
+	created, _ := createdVal.(bool)
+	return id, created, nil
+}
+
 // GetNode retrieves a node by ID
 func (s *Neo4jStore) GetNode(ctx context.Context, id string) (map[string]interface{}, error) {
 	// Create Cypher query - use elementId for more reliable retrieval
@@ -92,7 +443,7 @@ func (s *Neo4jStore) GetNode(ctx context.Context, id string) (map[string]interfa
 	}
 
 	// Execute query
-	result, err := neo4j.ExecuteQuery(ctx, s.driver, query, params, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase(""))
+	result, err := neo4j.ExecuteQuery(ctx, s.driver, query, params, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase(""), txMetadata(ctx))
 	if err != nil {
 		return nil, fmt.Errorf("failed to get node: %w", err)
 	}
@@ -108,22 +459,61 @@ func (s *Neo4jStore) GetNode(ctx context.Context, id string) (map[string]interfa
 		return nil, fmt.Errorf("node not found in record")
 	}
 
-	// Convert node to map
+	// Convert node to its canonical map
 	node, ok := nodeVal.(neo4j.Node)
 	if !ok {
 		return nil, fmt.Errorf("failed to convert result to node")
 	}
 
-	// Convert properties to map
-	props := node.Props
-	props["id"] = node.ElementId
-	props["labels"] = node.Labels
+	return convertNeo4jValue(node).(map[string]interface{}), nil
+}
 
-	return props, nil
+// GetNodesByIDs retrieves the nodes with the given element IDs in a single query, in the order of ids.
+// Missing nodes are skipped.
+func (s *Neo4jStore) GetNodesByIDs(ctx context.Context, ids []string) ([]map[string]interface{}, error) {
+	if len(ids) == 0 {
+		return []map[string]interface{}{}, nil
+	}
+
+	query := "MATCH (n) WHERE elementId(n) IN $ids RETURN n"
+	params := map[string]interface{}{
+		"ids": ids,
+	}
+
+	// Execute query
+	result, err := neo4j.ExecuteQuery(ctx, s.driver, query, params, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase(""), txMetadata(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get nodes: %w", err)
+	}
+
+	// Index the nodes by ID so they can be returned in the requested order
+	byID := make(map[string]map[string]interface{}, len(result.Records))
+	for _, record := range result.Records {
+		nodeVal, _ := record.Get("n")
+		node, ok := nodeVal.(neo4j.Node)
+		if !ok {
+			return nil, fmt.Errorf("failed to convert result to node")
+		}
+		byID[node.ElementId] = convertNeo4jValue(node).(map[string]interface{})
+	}
+
+	nodes := make([]map[string]interface{}, 0, len(byID))
+	for _, id := range ids {
+		if node, ok := byID[id]; ok {
+			nodes = append(nodes, node)
+			delete(byID, id) // Return each node once, even if its ID is repeated
+		}
+	}
+	return nodes, nil
 }
 
 // UpdateNode updates a node's properties
 func (s *Neo4jStore) UpdateNode(ctx context.Context, id string, properties map[string]interface{}) error {
+	properties, err := s.limitPropertySizes(properties)
+	if err != nil {
+		return err
+	}
+
 	// Create Cypher query - use elementId for more reliable retrieval
 	query := "MATCH (n) WHERE elementId(n) = $id SET n += $props"
 	params := map[string]interface{}{
@@ -132,7 +522,7 @@ func (s *Neo4jStore) UpdateNode(ctx context.Context, id string, properties map[s
 	}
 
 	// Execute query
-	_, err := neo4j.ExecuteQuery(ctx, s.driver, query, params, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase(""))
+	_, err = neo4j.ExecuteQuery(ctx, s.driver, query, params, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase(""), txMetadata(ctx))
 	if err != nil {
 		return fmt.Errorf("failed to update node: %w", err)
 	}
@@ -149,7 +539,7 @@ func (s *Neo4jStore) DeleteNode(ctx context.Context, id string) error {
 	}
 
 	// Execute query
-	_, err := neo4j.ExecuteQuery(ctx, s.driver, query, params, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase(""))
+	_, err := neo4j.ExecuteQuery(ctx, s.driver, query, params, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase(""), txMetadata(ctx))
 	if err != nil {
 		return fmt.Errorf("failed to delete node: %w", err)
 	}
@@ -159,16 +549,21 @@ func (s *Neo4jStore) DeleteNode(ctx context.Context, id string) error {
 
 // CreateEdge creates a new edge between two nodes
 func (s *Neo4jStore) CreateEdge(ctx context.Context, fromID, toID, relationshipType string, properties map[string]interface{}) (string, error) {
+	relationshipType = s.relationshipType(relationshipType)
+	if err := s.checkRelationshipTypeAllowed(relationshipType); err != nil {
+		return "", err
+	}
+
 	// Create Cypher query - use elementId for more reliable node lookup
 	query := "MATCH (a), (b) WHERE elementId(a) = $fromID AND elementId(b) = $toID CREATE (a)-[r:" + relationshipType + " $props]->(b) RETURN r"
 	params := map[string]interface{}{
 		"fromID": fromID,
 		"toID":   toID,
-		"props":  properties,
+		"props":  s.newRelationshipProperties(properties, time.Now().UTC()),
 	}
 
 	// Execute query
-	result, err := neo4j.ExecuteQuery(ctx, s.driver, query, params, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase(""))
+	result, err := neo4j.ExecuteQuery(ctx, s.driver, query, params, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase(""), txMetadata(ctx))
 	if err != nil {
 		return "", fmt.Errorf("failed to create edge: %w", err)
 	}
@@ -202,7 +597,7 @@ func (s *Neo4jStore) GetEdge(ctx context.Context, id string) (map[string]interfa
 	}
 
 	// Execute query
-	result, err := neo4j.ExecuteQuery(ctx, s.driver, query, params, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase(""))
+	result, err := neo4j.ExecuteQuery(ctx, s.driver, query, params, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase(""), txMetadata(ctx))
 	if err != nil {
 		return nil, fmt.Errorf("failed to get edge: %w", err)
 	}
@@ -270,7 +665,7 @@ func (s *Neo4jStore) UpdateEdge(ctx context.Context, id string, properties map[s
 	}
 
 	// Execute query
-	_, err := neo4j.ExecuteQuery(ctx, s.driver, query, params, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase(""))
+	_, err := neo4j.ExecuteQuery(ctx, s.driver, query, params, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase(""), txMetadata(ctx))
 	if err != nil {
 		return fmt.Errorf("failed to update edge: %w", err)
 	}
@@ -287,7 +682,7 @@ func (s *Neo4jStore) DeleteEdge(ctx context.Context, id string) error {
 	}
 
 	// Execute query
-	_, err := neo4j.ExecuteQuery(ctx, s.driver, query, params, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase(""))
+	_, err := neo4j.ExecuteQuery(ctx, s.driver, query, params, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase(""), txMetadata(ctx))
 	if err != nil {
 		return fmt.Errorf("failed to delete edge: %w", err)
 	}
@@ -295,10 +690,15 @@ func (s *Neo4jStore) DeleteEdge(ctx context.Context, id string) error {
 	return nil
 }
 
+// QueryLanguage returns the language custom queries are written in
+func (s *Neo4jStore) QueryLanguage() graph.QueryLanguage {
+	return graph.QueryLanguageCypher
+}
+
 // Query executes a custom query against the graph
 func (s *Neo4jStore) Query(ctx context.Context, query string, params map[string]interface{}) ([]map[string]interface{}, error) {
 	// Execute query
-	result, err := neo4j.ExecuteQuery(ctx, s.driver, query, params, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase(""))
+	result, err := neo4j.ExecuteQuery(ctx, s.driver, query, params, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase(""), txMetadata(ctx))
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute query: %w", err)
 	}
@@ -317,6 +717,43 @@ func (s *Neo4jStore) Query(ctx context.Context, query string, params map[string]
 	return results, nil
 }
 
+// QueryWithTypes executes a query and returns the results as rows along with the Neo4j type of each column.
+// Values are converted in the same way as by Query.
+func (s *Neo4jStore) QueryWithTypes(ctx context.Context, query string, params map[string]interface{}) (graph.TypedQueryResult, error) {
+	// Execute query
+	result, err := neo4j.ExecuteQuery(ctx, s.driver, query, params, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase(""), txMetadata(ctx))
+	if err != nil {
+		return graph.TypedQueryResult{}, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	// Work out the type of each column from its non-null values
+	columnTypes := make([]string, len(result.Keys))
+	rows := make([][]interface{}, 0, len(result.Records))
+	for _, record := range result.Records {
+		row := make([]interface{}, len(result.Keys))
+		for i := range result.Keys {
+			var value interface{}
+			if i < len(record.Values) {
+				value = record.Values[i]
+			}
+			columnTypes[i] = mergeColumnType(columnTypes[i], neo4jValueType(value))
+			row[i] = convertNeo4jValue(value)
+		}
+		rows = append(rows, row)
+	}
+
+	columns := make([]graph.QueryColumn, len(result.Keys))
+	for i, key := range result.Keys {
+		columnType := columnTypes[i]
+		if columnType == "" {
+			columnType = "Null"
+		}
+		columns[i] = graph.QueryColumn{Name: key, Type: columnType}
+	}
+
+	return graph.TypedQueryResult{Columns: columns, Rows: rows}, nil
+}
+
 // UpsertSchema updates or creates the schema
 func (s *Neo4jStore) UpsertSchema(ctx context.Context, schema string) error {
 	// Split schema into individual statements
@@ -341,7 +778,7 @@ func (s *Neo4jStore) UpsertSchema(ctx context.Context, schema string) error {
 			stmt := strings.TrimSpace(currentStatement.String())
 			if stmt != "" {
 				// Execute statement
-				_, err := neo4j.ExecuteQuery(ctx, s.driver, stmt, nil, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase(""))
+				_, err := neo4j.ExecuteQuery(ctx, s.driver, stmt, nil, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase(""), txMetadata(ctx))
 				if err != nil {
 					return fmt.Errorf("failed to execute schema statement: %w", err)
 				}
@@ -356,7 +793,7 @@ func (s *Neo4jStore) UpsertSchema(ctx context.Context, schema string) error {
 	remainingStmt := strings.TrimSpace(currentStatement.String())
 	if remainingStmt != "" {
 		// Execute the remaining statement
-		_, err := neo4j.ExecuteQuery(ctx, s.driver, remainingStmt, nil, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase(""))
+		_, err := neo4j.ExecuteQuery(ctx, s.driver, remainingStmt, nil, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase(""), txMetadata(ctx))
 		if err != nil {
 			return fmt.Errorf("failed to execute schema statement: %w", err)
 		}
@@ -369,13 +806,11 @@ func (s *Neo4jStore) UpsertSchema(ctx context.Context, schema string) error {
 func convertNeo4jValue(value interface{}) interface{} {
 	switch v := value.(type) {
 	case neo4j.Node:
-		node := make(map[string]interface{})
-		node["id"] = v.ElementId
-		node["labels"] = v.Labels
+		props := make(map[string]interface{}, len(v.Props))
 		for k, prop := range v.Props {
-			node[k] = convertNeo4jValue(prop)
+			props[k] = convertNeo4jValue(prop)
 		}
-		return node
+		return graph.NewNodeMap(v.ElementId, v.Labels, props)
 	case neo4j.Relationship:
 		rel := make(map[string]interface{})
 		rel["id"] = v.ElementId
@@ -424,16 +859,88 @@ func convertNeo4jValue(value interface{}) interface{} {
 	}
 }
 
+// neo4jValueType returns the Neo4j type name of a value as returned by the driver,
+// e.g. "Integer" for int64 or "DateTime" for time.Time
+func neo4jValueType(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "Null"
+	case bool:
+		return "Boolean"
+	case int64:
+		return "Integer"
+	case float64:
+		return "Float"
+	case string:
+		return "String"
+	case []byte:
+		return "ByteArray"
+	case []interface{}:
+		return "List"
+	case map[string]interface{}:
+		return "Map"
+	case neo4j.Node:
+		return "Node"
+	case neo4j.Relationship:
+		return "Relationship"
+	case neo4j.Path:
+		return "Path"
+	case neo4j.Date:
+		return "Date"
+	case neo4j.OffsetTime:
+		return "Time"
+	case neo4j.LocalTime:
+		return "LocalTime"
+	case time.Time:
+		return "DateTime"
+	case neo4j.LocalDateTime:
+		return "LocalDateTime"
+	case neo4j.Duration:
+		return "Duration"
+	case neo4j.Point2D, neo4j.Point3D:
+		return "Point"
+	default:
+		return "Any"
+	}
+}
+
+// mergeColumnType combines the type seen so far for a column with the type of another of its values.
+// Nulls don't affect the type, and a column whose values differ in type is "Any".
+func mergeColumnType(current, next string) string {
+	switch {
+	case next == "Null" || current == next:
+		return current
+	case current == "":
+		return next
+	default:
+		return "Any"
+	}
+}
+
 // --- Software Architecture Specific Operations ---
 
 // FindOrCreateEntity finds an entity based on identifying properties or creates it if not found.
 // It merges the provided properties with any existing ones.
 func (s *Neo4jStore) FindOrCreateEntity(ctx context.Context, input graph.EntityInput) (graph.EntityDetails, error) {
+	result, err := s.upsertEntity(ctx, input, false)
+	return result.EntityDetails, err
+}
+
+// FindOrCreateEntityWithChanges finds or creates an entity like FindOrCreateEntity, and reports whether it was
+// created and which properties the merge changed. The entity's properties are read in the same query, and so the
+// same transaction, as the MERGE.
+func (s *Neo4jStore) FindOrCreateEntityWithChanges(ctx context.Context, input graph.EntityInput) (graph.EntityUpsertResult, error) {
+	return s.upsertEntity(ctx, input, true)
+}
+
+// upsertEntity finds or creates an entity. If withChanges is set, the entity's properties are also read before the
+// MERGE and compared with those after it.
+func (s *Neo4jStore) upsertEntity(ctx context.Context, input graph.EntityInput, withChanges bool) (graph.EntityUpsertResult, error) {
 	if len(input.Labels) == 0 {
-		return graph.EntityDetails{}, fmt.Errorf("at least one label is required")
+		return graph.EntityUpsertResult{}, fmt.Errorf("at least one label is required")
 	}
 	if len(input.IdentifyingProperties) == 0 {
-		return graph.EntityDetails{}, fmt.Errorf("at least one identifying property is required")
+		return graph.EntityUpsertResult{}, fmt.Errorf("at least one identifying property is required")
 	}
 
 	// Build label string (e.g., :Label1:Label2)
@@ -447,37 +954,61 @@ func (s *Neo4jStore) FindOrCreateEntity(ctx context.Context, input graph.EntityI
 	}
 	idPropsMatchStr := "{" + strings.Join(idPropsParts, ", ") + "}"
 
+	// Identifying properties can't be stripped without changing which entity is matched, so are always checked
+	if _, err := s.applyPropertyWhitelist(input.Labels, input.IdentifyingProperties, false); err != nil {
+		return graph.EntityUpsertResult{}, err
+	}
+	properties, err := s.applyPropertyWhitelist(input.Labels, input.Properties, s.stripUnknownProperties)
+	if err != nil {
+		return graph.EntityUpsertResult{}, err
+	}
+
 	// Prepare all properties, ensuring timestamps are handled correctly
 	allProps := make(map[string]interface{})
-	for k, v := range input.Properties {
+	for k, v := range properties {
 		allProps[k] = v
 	}
 	now := time.Now().UTC() // Use UTC for consistency
 	// Ensure lastModifiedAt is always updated, even if present in input.Properties
 	allProps["lastModifiedAt"] = now
+	s.coerceTemporalValues(allProps, input.IdentifyingProperties)
+	allProps, err = s.limitPropertySizes(allProps)
+	if err != nil {
+		return graph.EntityUpsertResult{}, err
+	}
+
+	// Read the existing properties first if the changes are wanted; before is null if there is no match
+	var beforeClause, beforeReturn string
+	if withChanges {
+		beforeClause = fmt.Sprintf(`
+        OPTIONAL MATCH (existing%s %s)
+        WITH collect(properties(existing))[0] AS before`, labelStr, idPropsMatchStr)
+		beforeReturn = ", before"
+	}
 
 	// Construct the MERGE query
-	query := fmt.Sprintf(`
+	query := fmt.Sprintf(`%s
         MERGE (n%s %s)
-        ON CREATE SET n = $allProps, n.createdAt = $now
+        ON CREATE SET n = $allProps, n.createdAt = $now, n.expiresAt = coalesce(n.expiresAt, $expiresAt)
         ON MATCH SET n += $allProps // Use += to merge properties, lastModifiedAt is updated via $allProps
-        RETURN labels(n) as labels, properties(n) as props, elementId(n) as id
-    `, labelStr, idPropsMatchStr)
+        RETURN labels(n) as labels, properties(n) as props, elementId(n) as id%s
+    `, beforeClause, labelStr, idPropsMatchStr, beforeReturn)
 
 	params := map[string]interface{}{
-		"idProps":  input.IdentifyingProperties,
-		"allProps": allProps,
-		"now":      now,
+		"idProps":   input.IdentifyingProperties,
+		"allProps":  allProps,
+		"now":       now,
+		"expiresAt": s.nodeExpiry(input.Labels, now),
 	}
 
 	// Execute query
-	result, err := neo4j.ExecuteQuery(ctx, s.driver, query, params, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase(""))
+	result, err := neo4j.ExecuteQuery(ctx, s.driver, query, params, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase(""), txMetadata(ctx))
 	if err != nil {
-		return graph.EntityDetails{}, fmt.Errorf("failed to execute FindOrCreateEntity query: %w", err)
+		return graph.EntityUpsertResult{}, fmt.Errorf("failed to execute FindOrCreateEntity query: %w", err)
 	}
 
 	if len(result.Records) == 0 {
-		return graph.EntityDetails{}, fmt.Errorf("no node returned from MERGE operation")
+		return graph.EntityUpsertResult{}, fmt.Errorf("no node returned from MERGE operation")
 	}
 
 	record := result.Records[0]
@@ -485,38 +1016,38 @@ func (s *Neo4jStore) FindOrCreateEntity(ctx context.Context, input graph.EntityI
 	// Extract labels
 	labelsVal, labelsOk := record.Get("labels")
 	if !labelsOk {
-		return graph.EntityDetails{}, fmt.Errorf("failed to get labels from result")
+		return graph.EntityUpsertResult{}, fmt.Errorf("failed to get labels from result")
 	}
 	labelsInterface, ok := labelsVal.([]interface{})
 	if !ok {
-		return graph.EntityDetails{}, fmt.Errorf("labels are not in expected format []interface{}")
+		return graph.EntityUpsertResult{}, fmt.Errorf("labels are not in expected format []interface{}")
 	}
 	labels := make([]string, len(labelsInterface))
 	for i, l := range labelsInterface {
 		labels[i], ok = l.(string)
 		if !ok {
-			return graph.EntityDetails{}, fmt.Errorf("label item is not a string")
+			return graph.EntityUpsertResult{}, fmt.Errorf("label item is not a string")
 		}
 	}
 
 	// Extract properties
 	propsVal, propsOk := record.Get("props")
 	if !propsOk {
-		return graph.EntityDetails{}, fmt.Errorf("failed to get properties from result")
+		return graph.EntityUpsertResult{}, fmt.Errorf("failed to get properties from result")
 	}
 	props, ok := propsVal.(map[string]interface{})
 	if !ok {
-		return graph.EntityDetails{}, fmt.Errorf("properties are not in expected format map[string]interface{}")
+		return graph.EntityUpsertResult{}, fmt.Errorf("properties are not in expected format map[string]interface{}")
 	}
 
 	// Extract element ID
 	idVal, idOk := record.Get("id")
 	if !idOk {
-		return graph.EntityDetails{}, fmt.Errorf("failed to get elementId from result")
+		return graph.EntityUpsertResult{}, fmt.Errorf("failed to get elementId from result")
 	}
 	idStr, ok := idVal.(string)
 	if !ok {
-		return graph.EntityDetails{}, fmt.Errorf("elementId is not a string")
+		return graph.EntityUpsertResult{}, fmt.Errorf("elementId is not a string")
 	}
 	// Add the element ID to the properties map, as Neo4j doesn't include it by default
 	props["id"] = idStr
@@ -526,11 +1057,40 @@ func (s *Neo4jStore) FindOrCreateEntity(ctx context.Context, input graph.EntityI
 		props[k] = convertNeo4jValue(v) // Reuse existing conversion logic
 	}
 
+	upsert := graph.EntityUpsertResult{
+		EntityDetails: graph.EntityDetails{
+			Labels:     labels,
+			Properties: props,
+		},
+	}
+	if withChanges {
+		beforeVal, _ := record.Get("before")
+		before, found := beforeVal.(map[string]interface{})
+		upsert.Created = !found
+		upsert.ChangedProperties = changedProperties(before, props)
+	}
 
-	return graph.EntityDetails{
-		Labels:     labels,
-		Properties: props,
-	}, nil
+	return upsert, nil
+}
+
+// changedProperties compares an entity's properties before and after a merge, ignoring lastModifiedAt, which every
+// merge updates, and the id added to the properties after. Only properties whose values differ are returned. before
+// holds raw Neo4j values and after converted ones.
+func changedProperties(before, after map[string]interface{}) map[string]graph.PropertyChange {
+	changes := make(map[string]graph.PropertyChange)
+	for k, newVal := range after {
+		if k == "lastModifiedAt" || k == "id" {
+			continue
+		}
+		oldVal, existed := before[k]
+		if existed {
+			oldVal = convertNeo4jValue(oldVal)
+		}
+		if !existed || !reflect.DeepEqual(oldVal, newVal) {
+			changes[k] = graph.PropertyChange{Old: oldVal, New: newVal}
+		}
+	}
+	return changes
 }
 
 // FindOrCreateRelationship finds a relationship or creates it if not found.
@@ -545,6 +1105,10 @@ func (s *Neo4jStore) FindOrCreateRelationship(ctx context.Context, input graph.R
 	if input.RelationshipType == "" {
 		return nil, fmt.Errorf("relationship type is required")
 	}
+	input.RelationshipType = s.relationshipType(input.RelationshipType)
+	if err := s.checkRelationshipTypeAllowed(input.RelationshipType); err != nil {
+		return nil, err
+	}
 
 	// Build start node match clause
 	startLabelStr := ":" + strings.Join(input.StartNodeLabels, ":")
@@ -562,14 +1126,38 @@ func (s *Neo4jStore) FindOrCreateRelationship(ctx context.Context, input graph.R
 	}
 	endIdPropsMatchStr := "{" + strings.Join(endIdPropsParts, ", ") + "}"
 
-	// Prepare relationship properties, ensuring timestamps are handled
-	relProps := make(map[string]interface{})
-	for k, v := range input.Properties {
-		relProps[k] = v
+	// Check both endpoints exist first, so a missing node produces an actionable error
+	endpointQuery := fmt.Sprintf(`
+        OPTIONAL MATCH (start%s %s)
+        WITH count(start) > 0 AS startFound
+        OPTIONAL MATCH (end%s %s)
+        RETURN startFound, count(end) > 0 AS endFound
+    `, startLabelStr, startIdPropsMatchStr, endLabelStr, endIdPropsMatchStr)
+	endpointParams := map[string]interface{}{
+		"startIdProps": input.StartNodeIdentifyingProperties,
+		"endIdProps":   input.EndNodeIdentifyingProperties,
+	}
+	endpointResult, err := neo4j.ExecuteQuery(ctx, s.driver, endpointQuery, endpointParams, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase(""), txMetadata(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to check relationship endpoints: %w", err)
+	}
+	if len(endpointResult.Records) > 0 {
+		startFoundVal, _ := endpointResult.Records[0].Get("startFound")
+		endFoundVal, _ := endpointResult.Records[0].Get("endFound")
+		startFound, _ := startFoundVal.(bool)
+		endFound, _ := endFoundVal.(bool)
+		if err := checkRelationshipEndpoints(input, startFound, endFound); err != nil {
+			return nil, err
+		}
 	}
+
+	// Prepare relationship properties: timestamps and provenance for a new relationship, and an updated
+	// lastModifiedAt for an existing one
 	now := time.Now().UTC()
-	// Ensure lastModifiedAt is always updated
-	relProps["lastModifiedAt"] = now
+	createProps := s.newRelationshipProperties(input.Properties, now)
+	relProps := graph.UpdatedRelationshipProperties(input.Properties, now)
+	s.coerceTemporalValues(createProps, nil)
+	s.coerceTemporalValues(relProps, nil)
 
 	// Construct the MERGE query for the relationship
 	// Note: MERGE on relationships requires matching both start and end nodes first.
@@ -577,7 +1165,7 @@ func (s *Neo4jStore) FindOrCreateRelationship(ctx context.Context, input graph.R
         MATCH (start%s %s)
         MATCH (end%s %s)
         MERGE (start)-[r:%s]->(end)
-        ON CREATE SET r = $relProps, r.createdAt = $now
+        ON CREATE SET r = $createProps
         ON MATCH SET r += $relProps // Merge properties on match
         RETURN properties(r) as props, elementId(r) as id
     `, startLabelStr, startIdPropsMatchStr, endLabelStr, endIdPropsMatchStr, input.RelationshipType)
@@ -585,19 +1173,18 @@ func (s *Neo4jStore) FindOrCreateRelationship(ctx context.Context, input graph.R
 	params := map[string]interface{}{
 		"startIdProps": input.StartNodeIdentifyingProperties,
 		"endIdProps":   input.EndNodeIdentifyingProperties,
+		"createProps":  createProps,
 		"relProps":     relProps,
-		"now":          now,
 	}
 
 	// Execute query
-	result, err := neo4j.ExecuteQuery(ctx, s.driver, query, params, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase(""))
+	result, err := neo4j.ExecuteQuery(ctx, s.driver, query, params, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase(""), txMetadata(ctx))
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute FindOrCreateRelationship query: %w", err)
 	}
 
 	if len(result.Records) == 0 {
-		// This might happen if the start or end node doesn't exist.
-		// Consider adding checks or returning a more specific error.
+		// The endpoints were checked above, so this only happens if a node was removed concurrently.
 		return nil, fmt.Errorf("no relationship returned from MERGE operation (start or end node might not exist)")
 	}
 
@@ -633,8 +1220,119 @@ func (s *Neo4jStore) FindOrCreateRelationship(ctx context.Context, input graph.R
 	return props, nil
 }
 
+// UpsertEntityWithRelationship finds or creates an entity and merges a relationship between it and an existing
+// target node in a single query, so either both are written or neither is. Whichever end of rel is left empty is
+// the entity; the other end identifies the target.
+func (s *Neo4jStore) UpsertEntityWithRelationship(ctx context.Context, entity graph.EntityInput, rel graph.RelationshipInput) (graph.EntityWithRelationship, error) {
+	if len(entity.Labels) == 0 {
+		return graph.EntityWithRelationship{}, fmt.Errorf("at least one label is required")
+	}
+	if len(entity.IdentifyingProperties) == 0 {
+		return graph.EntityWithRelationship{}, fmt.Errorf("at least one identifying property is required")
+	}
+	if rel.RelationshipType == "" {
+		return graph.EntityWithRelationship{}, fmt.Errorf("relationship type is required")
+	}
+
+	// Work out which end of the relationship the entity is
+	startEmpty := len(rel.StartNodeLabels) == 0 && len(rel.StartNodeIdentifyingProperties) == 0
+	endEmpty := len(rel.EndNodeLabels) == 0 && len(rel.EndNodeIdentifyingProperties) == 0
+	if startEmpty == endEmpty {
+		return graph.EntityWithRelationship{}, fmt.Errorf("exactly one end of the relationship must be left empty for the entity, and the other must identify the target")
+	}
+	entityIsStart := startEmpty
+	targetLabels, targetIdProps := rel.EndNodeLabels, rel.EndNodeIdentifyingProperties
+	if !entityIsStart {
+		targetLabels, targetIdProps = rel.StartNodeLabels, rel.StartNodeIdentifyingProperties
+	}
+	if len(targetLabels) == 0 || len(targetIdProps) == 0 {
+		return graph.EntityWithRelationship{}, fmt.Errorf("target node labels and identifying properties are required")
+	}
+
+	rel.RelationshipType = s.relationshipType(rel.RelationshipType)
+	if err := s.checkRelationshipTypeAllowed(rel.RelationshipType); err != nil {
+		return graph.EntityWithRelationship{}, err
+	}
+
+	// Prepare the entity's properties as FindOrCreateEntity does
+	if _, err := s.applyPropertyWhitelist(entity.Labels, entity.IdentifyingProperties, false); err != nil {
+		return graph.EntityWithRelationship{}, err
+	}
+	properties, err := s.applyPropertyWhitelist(entity.Labels, entity.Properties, s.stripUnknownProperties)
+	if err != nil {
+		return graph.EntityWithRelationship{}, err
+	}
+	now := time.Now().UTC()
+	allProps := make(map[string]interface{})
+	for k, v := range properties {
+		allProps[k] = v
+	}
+	allProps["lastModifiedAt"] = now
+	s.coerceTemporalValues(allProps, entity.IdentifyingProperties)
+	allProps, err = s.limitPropertySizes(allProps)
+	if err != nil {
+		return graph.EntityWithRelationship{}, err
+	}
+
+	// And the relationship's as FindOrCreateRelationship does
+	createProps := s.newRelationshipProperties(rel.Properties, now)
+	relProps := graph.UpdatedRelationshipProperties(rel.Properties, now)
+	s.coerceTemporalValues(createProps, nil)
+	s.coerceTemporalValues(relProps, nil)
+
+	query := upsertEntityWithRelationshipQuery(entity, rel.RelationshipType, targetLabels, targetIdProps, entityIsStart)
+
+	params := map[string]interface{}{
+		"targetIdProps": targetIdProps,
+		"idProps":       entity.IdentifyingProperties,
+		"allProps":      allProps,
+		"now":           now,
+		"expiresAt":     s.nodeExpiry(entity.Labels, now),
+		"createProps":   createProps,
+		"relProps":      relProps,
+	}
+
+	result, err := neo4j.ExecuteQuery(ctx, s.driver, query, params, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase(""), txMetadata(ctx))
+	if err != nil {
+		return graph.EntityWithRelationship{}, fmt.Errorf("failed to execute UpsertEntityWithRelationship query: %w", err)
+	}
+	if len(result.Records) == 0 {
+		// Nothing was written: find out whether the target is missing or ambiguous
+		countQuery := fmt.Sprintf("MATCH (target%s %s) RETURN count(target) AS count",
+			buildLabelString(targetLabels), buildPropsMatchString("targetIdProps", targetIdProps))
+		countResult, err := neo4j.ExecuteQuery(ctx, s.driver, countQuery, params, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase(""), txMetadata(ctx))
+		if err != nil {
+			return graph.EntityWithRelationship{}, fmt.Errorf("failed to count target nodes: %w", err)
+		}
+		var count int64
+		if len(countResult.Records) > 0 {
+			countVal, _ := countResult.Records[0].Get("count")
+			count, _ = countVal.(int64)
+		}
+		return graph.EntityWithRelationship{}, unwrittenTargetError(rel, entityIsStart, targetLabels, count)
+	}
+
+	record := result.Records[0]
+	relPropsVal, _ := record.Get("relProps")
+	props, ok := relPropsVal.(map[string]interface{})
+	if !ok {
+		return graph.EntityWithRelationship{}, fmt.Errorf("relationship properties are not in expected format map[string]interface{}")
+	}
+	for k, v := range props {
+		props[k] = convertNeo4jValue(v)
+	}
+	if relID, ok := record.Get("relId"); ok {
+		props["id"] = relID
+	}
+
+	return graph.EntityWithRelationship{
+		Entity:       entityDetailsFromRecord(record, "labels", "props", "id"),
+		Relationship: props,
+	}, nil
+}
+
 // GetEntityDetails retrieves the labels and properties of a specific entity identified by its labels and unique properties.
-func (s *Neo4jStore) GetEntityDetails(ctx context.Context, labels []string, identifyingProperties map[string]interface{}) (graph.EntityDetails, error) {
+func (s *Neo4jStore) GetEntityDetails(ctx context.Context, labels []string, identifyingProperties map[string]interface{}, properties []string) (graph.EntityDetails, error) {
 	if len(labels) == 0 {
 		return graph.EntityDetails{}, fmt.Errorf("at least one label is required")
 	}
@@ -652,19 +1350,25 @@ func (s *Neo4jStore) GetEntityDetails(ctx context.Context, labels []string, iden
 	}
 	idPropsMatchStr := "{" + strings.Join(idPropsParts, ", ") + "}"
 
+	// Return all properties, or only the requested ones via a map projection
+	propsExpr := "properties(n)"
+	if len(properties) > 0 {
+		propsExpr = buildMapProjection("n", properties)
+	}
+
 	// Construct the MATCH query
 	query := fmt.Sprintf(`
         MATCH (n%s %s)
-        RETURN labels(n) as labels, properties(n) as props, elementId(n) as id
+        RETURN labels(n) as labels, %s as props, elementId(n) as id
         LIMIT 1 // Ensure only one node is returned
-    `, labelStr, idPropsMatchStr)
+    `, labelStr, idPropsMatchStr, propsExpr)
 
 	params := map[string]interface{}{
 		"idProps": identifyingProperties,
 	}
 
 	// Execute query
-	result, err := neo4j.ExecuteQuery(ctx, s.driver, query, params, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase(""))
+	result, err := neo4j.ExecuteQuery(ctx, s.driver, query, params, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase(""), txMetadata(ctx))
 	if err != nil {
 		return graph.EntityDetails{}, fmt.Errorf("failed to execute GetEntityDetails query: %w", err)
 	}
@@ -775,7 +1479,7 @@ func (s *Neo4jStore) FindNeighbors(ctx context.Context, labels []string, identif
 	}
 
 	// Execute query
-	result, err := neo4j.ExecuteQuery(ctx, s.driver, query, params, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase(""))
+	result, err := neo4j.ExecuteQuery(ctx, s.driver, query, params, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase(""), txMetadata(ctx))
 	if err != nil {
 		return graph.NeighborsResult{}, fmt.Errorf("failed to execute FindNeighbors query: %w", err)
 	}
@@ -783,7 +1487,7 @@ func (s *Neo4jStore) FindNeighbors(ctx context.Context, labels []string, identif
 	if len(result.Records) == 0 {
 		// It's possible the node exists but has no neighbors, or the node doesn't exist.
 		// Try getting just the central node to differentiate.
-		centralNodeDetails, err := s.GetEntityDetails(ctx, labels, identifyingProperties)
+		centralNodeDetails, err := s.GetEntityDetails(ctx, labels, identifyingProperties, nil)
 		if err != nil {
 			// Central node likely doesn't exist or there was another error
 			return graph.NeighborsResult{}, fmt.Errorf("central node not found or error fetching details: %w", err)
@@ -890,6 +1594,115 @@ func (s *Neo4jStore) FindNeighbors(ctx context.Context, labels []string, identif
 	}, nil
 }
 
+// checkRelationshipEndpoints returns a *graph.EndpointNotFoundError naming the missing endpoint(s)
+// of a relationship, or nil if both the start and end nodes were found.
+func checkRelationshipEndpoints(input graph.RelationshipInput, startFound, endFound bool) error {
+	if startFound && endFound {
+		return nil
+	}
+	return &graph.EndpointNotFoundError{
+		StartMissing: !startFound,
+		EndMissing:   !endFound,
+		StartLabels:  input.StartNodeLabels,
+		EndLabels:    input.EndNodeLabels,
+	}
+}
+
+// upsertEntityWithRelationshipQuery builds the UpsertEntityWithRelationship query. Matching the target first means
+// the entity and relationship are only written if exactly one node matches the target's identifying properties: a
+// missing target produces no rows, and an ambiguous one is filtered out rather than linked to every match.
+func upsertEntityWithRelationshipQuery(entity graph.EntityInput, relType string, targetLabels []string, targetIdProps map[string]interface{}, entityIsStart bool) string {
+	relPattern := "(n)-[r:%s]->(target)"
+	if !entityIsStart {
+		relPattern = "(target)-[r:%s]->(n)"
+	}
+	return fmt.Sprintf(`
+        MATCH (target%s %s)
+        WITH collect(target) AS targets
+        WHERE size(targets) = 1
+        WITH targets[0] AS target
+        MERGE (n%s %s)
+        ON CREATE SET n = $allProps, n.createdAt = $now, n.expiresAt = coalesce(n.expiresAt, $expiresAt)
+        ON MATCH SET n += $allProps
+        MERGE %s
+        ON CREATE SET r = $createProps
+        ON MATCH SET r += $relProps
+        RETURN labels(n) as labels, properties(n) as props, elementId(n) as id,
+               properties(r) as relProps, elementId(r) as relId
+    `, buildLabelString(targetLabels), buildPropsMatchString("targetIdProps", targetIdProps),
+		buildLabelString(entity.Labels), buildPropsMatchString("idProps", entity.IdentifyingProperties),
+		fmt.Sprintf(relPattern, relType))
+}
+
+// unwrittenTargetError explains why an UpsertEntityWithRelationship call wrote nothing, given how many nodes
+// match the target. A missing target is reported as a *graph.EndpointNotFoundError for the target's end: the end
+// node if the entity is the start, otherwise the start node.
+func unwrittenTargetError(rel graph.RelationshipInput, entityIsStart bool, targetLabels []string, count int64) error {
+	if count > 1 {
+		return fmt.Errorf("target node (%s) is ambiguous: %d nodes match its identifying properties; identify exactly one", buildLabelString(targetLabels), count)
+	}
+	return checkRelationshipEndpoints(rel, entityIsStart, !entityIsStart)
+}
+
+// relationshipType returns the relationship type to use when creating a relationship,
+// normalised to upper snake case if normalisation is enabled.
+func (s *Neo4jStore) relationshipType(relType string) string {
+	if !s.normalizeRelationshipTypes {
+		return relType
+	}
+	return normalizeRelationshipType(relType)
+}
+
+// checkRelationshipTypeAllowed returns an error listing the allowed types if relationships may not be created with
+// the given type.
+func (s *Neo4jStore) checkRelationshipTypeAllowed(relType string) error {
+	if len(s.allowedRelationshipTypes) == 0 {
+		return nil
+	}
+	for _, allowed := range s.allowedRelationshipTypes {
+		if relType == allowed {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %q; use one of %s", graph.ErrRelationshipTypeNotAllowed, relType, strings.Join(s.allowedRelationshipTypes, ", "))
+}
+
+// newRelationshipProperties annotates the properties of a relationship being created with timestamps and this
+// store's default source; see graph.NewRelationshipProperties.
+func (s *Neo4jStore) newRelationshipProperties(properties map[string]interface{}, now time.Time) map[string]interface{} {
+	source := s.defaultRelationshipSource
+	if source == "" {
+		source = graph.DefaultRelationshipSource
+	}
+	return graph.NewRelationshipProperties(properties, now, source)
+}
+
+// normalizeRelationshipType converts a relationship type to upper snake case.
+// Word boundaries are camelCase transitions and any of ' ', '-' or '.'.
+// Example: "dependsOn" -> "DEPENDS_ON", "calls" -> "CALLS", "HTTPCalls" -> "HTTP_CALLS".
+func normalizeRelationshipType(relType string) string {
+	runes := []rune(relType)
+	var b strings.Builder
+	for i, r := range runes {
+		switch {
+		case r == ' ' || r == '-' || r == '.' || r == '_':
+			b.WriteRune('_')
+			continue
+		case unicode.IsUpper(r) && i > 0:
+			prev := runes[i-1]
+			nextIsLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextIsLower) {
+				b.WriteRune('_')
+			}
+		}
+		b.WriteRune(unicode.ToUpper(r))
+	}
+
+	// Collapse repeated separators and trim them from the ends
+	parts := strings.FieldsFunc(b.String(), func(r rune) bool { return r == '_' })
+	return strings.Join(parts, "_")
+}
+
 // buildRelationshipTypeFilter builds the relationship type part of a Cypher query.
 // Example: ":REL1|:REL2" or "" if types are empty.
 func buildRelationshipTypeFilter(types []string) string {
@@ -904,10 +1717,113 @@ func buildRelationshipTypeFilter(types []string) string {
 	return strings.Join(quotedTypes, "|")
 }
 
+// buildRelationshipPropertyPredicate builds a WHERE clause fragment requiring every relationship
+// in the named path to match all properties in the named parameter map. Property names and values
+// are both taken from the parameter, so nothing user-supplied is interpolated into the query.
+// Example: " AND all(rel IN relationships(path) WHERE all(k IN keys($relProps) WHERE rel[k] = $relProps[k]))"
+// or "" if filters are empty.
+func buildRelationshipPropertyPredicate(pathVar, paramName string, filters map[string]interface{}) string {
+	if len(filters) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" AND all(rel IN relationships(%s) WHERE all(k IN keys($%s) WHERE rel[k] = $%s[k]))", pathVar, paramName, paramName)
+}
+
+// buildDirectedRelationshipPattern wraps a relationship pattern body (e.g. "r" or "r:CALLS*1..3") in the
+// arrows for the given direction. An empty direction means both.
+// Example: "-[r]->" for outgoing, "<-[r]-" for incoming, "-[r]-" for both.
+func buildDirectedRelationshipPattern(direction, body string) (string, error) {
+	switch direction {
+	case graph.DirectionOutgoing:
+		return "-[" + body + "]->", nil
+	case graph.DirectionIncoming:
+		return "<-[" + body + "]-", nil
+	case graph.DirectionBoth, "":
+		return "-[" + body + "]-", nil
+	default:
+		return "", fmt.Errorf("invalid direction %q: must be %q, %q or %q", direction, graph.DirectionOutgoing, graph.DirectionIncoming, graph.DirectionBoth)
+	}
+}
+
+// txMetadata attaches the query metadata carried by ctx (see graph.WithQueryMetadata) to the transaction, so that it
+// is visible to operators in SHOW TRANSACTIONS and the query log.
+func txMetadata(ctx context.Context) neo4j.ExecuteQueryConfigurationOption {
+	return neo4j.ExecuteQueryWithTransactionConfig(neo4j.WithTxMetadata(graph.QueryMetadataFromContext(ctx)))
+}
+
+// buildMapProjection builds a map projection returning only the given properties of a node.
+// Example: "n {.`name`, .`filePath`}"
+func buildMapProjection(nodeVar string, properties []string) string {
+	parts := make([]string, len(properties))
+	for i, p := range properties {
+		parts[i] = "." + graph.QuoteCypherIdentifier(p)
+	}
+	return nodeVar + " {" + strings.Join(parts, ", ") + "}"
+}
+
+// buildLabelString builds the label part of a node pattern.
+// Example: ":Label1:Label2"
+func buildLabelString(labels []string) string {
+	return ":" + strings.Join(labels, ":")
+}
+
+// buildPropsMatchString builds an inline property map for a node pattern, binding each
+// value to the named parameter map.
+// Example: "{key1: $idProps.key1, key2: $idProps.key2}"
+func buildPropsMatchString(paramName string, props map[string]interface{}) string {
+	var parts []string
+	for k := range props {
+		parts = append(parts, fmt.Sprintf("%s: $%s.%s", k, paramName, k))
+	}
+	return "{" + strings.Join(parts, ", ") + "}"
+}
+
+// entityDetailsFromRecord extracts an entity from a record holding its labels, properties and element ID
+// under the given keys. Missing or malformed values result in empty fields rather than an error.
+func entityDetailsFromRecord(record *neo4j.Record, labelsKey, propsKey, idKey string) graph.EntityDetails {
+	labelsVal, _ := record.Get(labelsKey)
+	propsVal, _ := record.Get(propsKey)
+	idVal, _ := record.Get(idKey)
+	return entityDetailsFromValues(labelsVal, propsVal, idVal)
+}
+
+// entityDetailsFromMap extracts an entity from a map (e.g. one element of a collected list)
+// holding its labels, properties and element ID under the given keys.
+func entityDetailsFromMap(m map[string]interface{}, labelsKey, propsKey, idKey string) graph.EntityDetails {
+	return entityDetailsFromValues(m[labelsKey], m[propsKey], m[idKey])
+}
+
+// entityDetailsFromValues builds an entity from raw query values for its labels, properties and element ID.
+func entityDetailsFromValues(labelsVal, propsVal, idVal interface{}) graph.EntityDetails {
+	labelsInterface, _ := labelsVal.([]interface{})
+	labels := make([]string, len(labelsInterface))
+	for i, l := range labelsInterface {
+		labels[i], _ = l.(string)
+	}
+
+	props, _ := propsVal.(map[string]interface{})
+	if props == nil {
+		props = make(map[string]interface{})
+	}
+	id, _ := idVal.(string)
+	props["id"] = id // Add element ID
+
+	// Convert properties
+	for k, v := range props {
+		props[k] = convertNeo4jValue(v)
+	}
+
+	return graph.EntityDetails{
+		Labels:     labels,
+		Properties: props,
+	}
+}
+
 
 // FindDependencies finds entities that the target entity depends on (outgoing relationships),
-// following specified relationship types up to a certain depth.
-func (s *Neo4jStore) FindDependencies(ctx context.Context, labels []string, identifyingProperties map[string]interface{}, relationshipTypes []string, maxDepth int) (graph.DependencyResult, error) {
+// following specified relationship types up to a certain depth. When relationshipPropertyFilters
+// is non-empty, every relationship along the path must match all of the given property values.
+func (s *Neo4jStore) FindDependencies(ctx context.Context, labels []string, identifyingProperties map[string]interface{}, relationshipTypes []string, relationshipPropertyFilters map[string]interface{}, maxDepth int) (graph.DependencyResult, error) {
 	if len(labels) == 0 {
 		return graph.DependencyResult{}, fmt.Errorf("at least one label is required for the target node")
 	}
@@ -919,7 +1835,7 @@ func (s *Neo4jStore) FindDependencies(ctx context.Context, labels []string, iden
 	}
 
 	// First, get the target node details to include in the result
-	targetNodeDetails, err := s.GetEntityDetails(ctx, labels, identifyingProperties)
+	targetNodeDetails, err := s.GetEntityDetails(ctx, labels, identifyingProperties, nil)
 	if err != nil {
 		return graph.DependencyResult{}, fmt.Errorf("target node not found or error fetching details: %w", err)
 	}
@@ -934,58 +1850,26 @@ func (s *Neo4jStore) FindDependencies(ctx context.Context, labels []string, iden
 	}
 	idPropsMatchStr := "{" + strings.Join(idPropsParts, ", ") + "}"
 
-	// Build relationship type filter string
-	relTypeFilter := buildRelationshipTypeFilter(relationshipTypes)
-
-	// Construct the MATCH query for dependencies (outgoing relationships)
-	query := fmt.Sprintf(`
-        MATCH (target%s %s)
-        MATCH (target)-[r%s*1..%d]->(dependency)
-        WHERE target <> dependency
-        RETURN DISTINCT
-            labels(dependency) as depLabels,
-            properties(dependency) as depProps,
-            elementId(dependency) as depId
-        LIMIT 500 // Add a reasonable limit
-    `, labelStr, idPropsMatchStr, relTypeFilter, maxDepth)
-
 	params := map[string]interface{}{
 		"idProps": identifyingProperties,
 	}
+	if len(relationshipPropertyFilters) > 0 {
+		params["relProps"] = relationshipPropertyFilters
+	}
+
+	// Construct the MATCH query for dependencies (outgoing relationships)
+	caps, err := s.Capabilities(ctx)
+	useAPOC := err == nil && caps.APOC
+	query := dependencyQuery(ctx, params, labelStr, idPropsMatchStr, relationshipTypes, relationshipPropertyFilters, maxDepth, graph.DirectionOutgoing, useAPOC)
 
 	// Execute query
-	result, err := neo4j.ExecuteQuery(ctx, s.driver, query, params, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase(""))
+	result, err := s.executeTraversalQuery(ctx, query, params)
 	if err != nil {
 		return graph.DependencyResult{}, fmt.Errorf("failed to execute FindDependencies query: %w", err)
 	}
 
 	// Process results
-	dependencies := make([]graph.EntityDetails, 0, len(result.Records))
-	for _, record := range result.Records {
-		depLabelsVal, _ := record.Get("depLabels")
-		depPropsVal, _ := record.Get("depProps")
-		depIdVal, _ := record.Get("depId")
-
-		depLabelsInterface, _ := depLabelsVal.([]interface{})
-		depLabels := make([]string, len(depLabelsInterface))
-		for i, l := range depLabelsInterface {
-			depLabels[i], _ = l.(string)
-		}
-
-		depProps, _ := depPropsVal.(map[string]interface{})
-		depId, _ := depIdVal.(string)
-		depProps["id"] = depId // Add element ID
-
-		// Convert properties
-		for k, v := range depProps {
-			depProps[k] = convertNeo4jValue(v)
-		}
-
-		dependencies = append(dependencies, graph.EntityDetails{
-			Labels:     depLabels,
-			Properties: depProps,
-		})
-	}
+	dependencies := dependencyEntriesFromRecords(result.Records)
 
 	return graph.DependencyResult{
 		TargetNode: targetNodeDetails,
@@ -995,6 +1879,113 @@ func (s *Neo4jStore) FindDependencies(ctx context.Context, labels []string, iden
 	}, nil
 }
 
+// dependencyQuery builds the FindDependencies (outgoing) or FindDependents (incoming) query. The entities within
+// maxDepth of the target, and the depth of each, are found in a single traversal. With APOC (and no relationship
+// property filters, which APOC can't apply) this is a breadth-first apoc.path.expandConfig that reaches each node
+// once, by a shortest path. Otherwise a variable-length match enumerates the paths and keeps each entity's shortest.
+// An entity's relationship types then come from a depth-1 match against the target and the entities closer to it.
+func dependencyQuery(ctx context.Context, params map[string]interface{}, labelStr, idPropsMatchStr string, relationshipTypes []string, relationshipPropertyFilters map[string]interface{}, maxDepth int, direction string, useAPOC bool) string {
+	relTypeFilter := buildRelationshipTypeFilter(relationshipTypes)
+
+	var traversal string
+	if useAPOC && len(relationshipPropertyFilters) == 0 {
+		params["relationshipFilter"] = apocRelationshipFilter(relationshipTypes, direction)
+		traversal = fmt.Sprintf(`CALL apoc.path.expandConfig(target, {
+            relationshipFilter: $relationshipFilter, uniqueness: 'NODE_GLOBAL', bfs: true, minLevel: 1, maxLevel: %d
+        }) YIELD path
+        WITH target, last(nodes(path)) AS entity, length(path) AS depth`, maxDepth)
+	} else {
+		pathPattern, _ := buildDirectedRelationshipPattern(direction, fmt.Sprintf("%s*1..%d", relTypeFilter, maxDepth))
+		traversal = fmt.Sprintf(`MATCH path = (target)%s(entity)
+        WHERE target <> entity%s
+        WITH target, entity, min(length(path)) AS depth`, pathPattern, buildRelationshipPropertyPredicate("path", "relProps", relationshipPropertyFilters))
+	}
+
+	// The relationships connecting an entity to those closer to the target run the same way as the paths, so point
+	// back towards the target from the entity
+	viaDirection := graph.DirectionIncoming
+	if direction == graph.DirectionIncoming {
+		viaDirection = graph.DirectionOutgoing
+	}
+	viaPattern, _ := buildDirectedRelationshipPattern(viaDirection, "via"+relTypeFilter)
+	var viaPropsPredicate string
+	if len(relationshipPropertyFilters) > 0 {
+		viaPropsPredicate = " AND all(k IN keys($relProps) WHERE via[k] = $relProps[k])"
+	}
+
+	return fmt.Sprintf(`
+        MATCH (target%[1]s %[2]s)
+        %[3]s
+        WITH target, collect({entity: entity, depth: depth}) AS found
+        WITH target, found, [target] + [f IN found WHERE f.depth < %[4]d | f.entity] AS closer
+        UNWIND found AS f
+        WITH target, closer, f.entity AS entity, f.depth AS depth
+        %[7]s
+        RETURN
+            labels(entity) as depLabels,
+            properties(entity) as depProps,
+            elementId(entity) as depId,
+            depth,
+            reduce(types = [], t IN [(entity)%[5]s(other) WHERE other IN closer%[6]s | type(via)] |
+                CASE WHEN t IN types THEN types ELSE types + t END) AS relTypes,
+            [(entity)%[5]s(target) WHERE true%[6]s | {type: type(via), props: properties(via)}] AS direct
+    `, labelStr, idPropsMatchStr, traversal, maxDepth, viaPattern, viaPropsPredicate, orderAndLimit(ctx, params, "depth", "500"))
+}
+
+// apocRelationshipFilter builds an APOC relationshipFilter following the given relationship types (or any type if
+// there are none) in one direction, e.g. "CALLS>|USES>".
+func apocRelationshipFilter(relationshipTypes []string, direction string) string {
+	suffix := ">"
+	if direction == graph.DirectionIncoming {
+		suffix = "<"
+	}
+	if len(relationshipTypes) == 0 {
+		return suffix
+	}
+	filters := make([]string, len(relationshipTypes))
+	for i, t := range relationshipTypes {
+		filters[i] = t + suffix
+	}
+	return strings.Join(filters, "|")
+}
+
+// dependencyEntriesFromRecords converts FindDependencies and FindDependents records into dependency entries.
+func dependencyEntriesFromRecords(records []*neo4j.Record) []graph.DependencyEntry {
+	entries := make([]graph.DependencyEntry, 0, len(records))
+	for _, record := range records {
+		depthVal, _ := record.Get("depth")
+		relTypesVal, _ := record.Get("relTypes")
+		directVal, _ := record.Get("direct")
+
+		depth, _ := depthVal.(int64)
+		relTypesInterface, _ := relTypesVal.([]interface{})
+		relTypes := make([]string, len(relTypesInterface))
+		for i, t := range relTypesInterface {
+			relTypes[i], _ = t.(string)
+		}
+
+		directInterface, _ := directVal.([]interface{})
+		var direct []graph.ConnectingRelationship
+		for _, d := range directInterface {
+			m, ok := d.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			relType, _ := m["type"].(string)
+			props, _ := convertNeo4jValue(m["props"]).(map[string]interface{})
+			direct = append(direct, graph.ConnectingRelationship{Type: relType, Properties: props})
+		}
+
+		entries = append(entries, graph.DependencyEntry{
+			EntityDetails:     entityDetailsFromRecord(record, "depLabels", "depProps", "depId"),
+			Depth:             int(depth),
+			RelationshipTypes: relTypes,
+			Relationships:     direct,
+		})
+	}
+	return entries
+}
+
 // FindDependents finds entities that depend on the target entity (incoming relationships),
 // following specified relationship types up to a certain depth.
 func (s *Neo4jStore) FindDependents(ctx context.Context, labels []string, identifyingProperties map[string]interface{}, relationshipTypes []string, maxDepth int) (graph.DependencyResult, error) {
@@ -1009,7 +2000,7 @@ func (s *Neo4jStore) FindDependents(ctx context.Context, labels []string, identi
 	}
 
 	// First, get the target node details
-	targetNodeDetails, err := s.GetEntityDetails(ctx, labels, identifyingProperties)
+	targetNodeDetails, err := s.GetEntityDetails(ctx, labels, identifyingProperties, nil)
 	if err != nil {
 		return graph.DependencyResult{}, fmt.Errorf("target node not found or error fetching details: %w", err)
 	}
@@ -1024,58 +2015,23 @@ func (s *Neo4jStore) FindDependents(ctx context.Context, labels []string, identi
 	}
 	idPropsMatchStr := "{" + strings.Join(idPropsParts, ", ") + "}"
 
-	// Build relationship type filter string
-	relTypeFilter := buildRelationshipTypeFilter(relationshipTypes)
-
-	// Construct the MATCH query for dependents (incoming relationships)
-	query := fmt.Sprintf(`
-        MATCH (target%s %s)
-        MATCH (dependent)-[r%s*1..%d]->(target)
-        WHERE target <> dependent
-        RETURN DISTINCT
-            labels(dependent) as depLabels,
-            properties(dependent) as depProps,
-            elementId(dependent) as depId
-        LIMIT 500 // Add a reasonable limit
-    `, labelStr, idPropsMatchStr, relTypeFilter, maxDepth)
-
 	params := map[string]interface{}{
 		"idProps": identifyingProperties,
 	}
 
+	// Construct the MATCH query for dependents (incoming relationships)
+	caps, err := s.Capabilities(ctx)
+	useAPOC := err == nil && caps.APOC
+	query := dependencyQuery(ctx, params, labelStr, idPropsMatchStr, relationshipTypes, nil, maxDepth, graph.DirectionIncoming, useAPOC)
+
 	// Execute query
-	result, err := neo4j.ExecuteQuery(ctx, s.driver, query, params, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase(""))
+	result, err := s.executeTraversalQuery(ctx, query, params)
 	if err != nil {
 		return graph.DependencyResult{}, fmt.Errorf("failed to execute FindDependents query: %w", err)
 	}
 
 	// Process results
-	dependents := make([]graph.EntityDetails, 0, len(result.Records))
-	for _, record := range result.Records {
-		depLabelsVal, _ := record.Get("depLabels")
-		depPropsVal, _ := record.Get("depProps")
-		depIdVal, _ := record.Get("depId")
-
-		depLabelsInterface, _ := depLabelsVal.([]interface{})
-		depLabels := make([]string, len(depLabelsInterface))
-		for i, l := range depLabelsInterface {
-			depLabels[i], _ = l.(string)
-		}
-
-		depProps, _ := depPropsVal.(map[string]interface{})
-		depId, _ := depIdVal.(string)
-		depProps["id"] = depId // Add element ID
-
-		// Convert properties
-		for k, v := range depProps {
-			depProps[k] = convertNeo4jValue(v)
-		}
-
-		dependents = append(dependents, graph.EntityDetails{
-			Labels:     depLabels,
-			Properties: depProps,
-		})
-	}
+	dependents := dependencyEntriesFromRecords(result.Records)
 
 	return graph.DependencyResult{
 		TargetNode: targetNodeDetails,
@@ -1098,7 +2054,10 @@ func (s *Neo4jStore) BatchFindOrCreateEntities(ctx context.Context, inputs []gra
 	individualErrors := make([]error, len(inputs))
 
 	// Process entities in parallel with a reasonable concurrency limit
-	concurrencyLimit := 10
+	concurrencyLimit := s.batchConcurrency
+	if concurrencyLimit <= 0 {
+		concurrencyLimit = defaultBatchConcurrency
+	}
 	if len(inputs) < concurrencyLimit {
 		concurrencyLimit = len(inputs)
 	}
@@ -1146,6 +2105,69 @@ func (s *Neo4jStore) BatchFindOrCreateEntities(ctx context.Context, inputs []gra
 	return results, individualErrors, nil
 }
 
+// BatchCreateNodes creates a node of the given type for each properties map with a single UNWIND query.
+// Returns the element IDs of the created nodes in the same order as the input array.
+func (s *Neo4jStore) BatchCreateNodes(ctx context.Context, nodeType string, properties []map[string]interface{}) ([]string, error) {
+	if nodeType == "" {
+		return nil, fmt.Errorf("node type is required")
+	}
+	if len(properties) == 0 {
+		return nil, fmt.Errorf("at least one node is required")
+	}
+
+	// Add the type to each node's properties if not already present, as CreateNode does
+	props := make([]map[string]interface{}, len(properties))
+	now := time.Now().UTC()
+	for i, p := range properties {
+		p, err := s.applyPropertyWhitelist([]string{nodeType}, p, s.stripUnknownProperties)
+		if err != nil {
+			return nil, fmt.Errorf("node at index %d: %w", i, err)
+		}
+		props[i] = make(map[string]interface{}, len(p)+1)
+		for k, v := range p {
+			props[i][k] = v
+		}
+		if _, ok := props[i]["type"]; !ok {
+			props[i]["type"] = nodeType
+		}
+		s.setDefaultExpiry([]string{nodeType}, props[i], now)
+		limited, err := s.limitPropertySizes(props[i])
+		if err != nil {
+			return nil, fmt.Errorf("node at index %d: %w", i, err)
+		}
+		props[i] = limited
+	}
+
+	query := fmt.Sprintf(`
+        UNWIND range(0, size($props) - 1) AS i
+        CREATE (n:%s)
+        SET n = $props[i]
+        RETURN i, elementId(n) as id
+    `, graph.QuoteCypherIdentifier(nodeType))
+	params := map[string]interface{}{
+		"props": props,
+	}
+
+	// Execute query
+	result, err := neo4j.ExecuteQuery(ctx, s.driver, query, params, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase(""), txMetadata(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create nodes: %w", err)
+	}
+
+	// Place each ID at its input's index
+	ids := make([]string, len(properties))
+	for _, record := range result.Records {
+		indexVal, _ := record.Get("i")
+		idVal, _ := record.Get("id")
+		index, _ := indexVal.(int64)
+		if index >= 0 && int(index) < len(ids) {
+			ids[index], _ = idVal.(string)
+		}
+	}
+
+	return ids, nil
+}
+
 // BatchFindOrCreateRelationships finds or creates multiple relationships in a single operation.
 // This is more efficient than making multiple individual calls.
 // Returns properties for all relationships in the same order as the input array, along with any individual errors.
@@ -1159,7 +2181,10 @@ func (s *Neo4jStore) BatchFindOrCreateRelationships(ctx context.Context, inputs
 	individualErrors := make([]error, len(inputs))
 
 	// Process relationships in parallel with a reasonable concurrency limit
-	concurrencyLimit := 10
+	concurrencyLimit := s.batchConcurrency
+	if concurrencyLimit <= 0 {
+		concurrencyLimit = defaultBatchConcurrency
+	}
 	if len(inputs) < concurrencyLimit {
 		concurrencyLimit = len(inputs)
 	}
@@ -1208,8 +2233,10 @@ func (s *Neo4jStore) BatchFindOrCreateRelationships(ctx context.Context, inputs
 }
 
 // GetEntitySubgraph retrieves nodes and relationships around a central entity up to a specified depth,
-// formatted suitably for visualisation tools like Mermaid.
-func (s *Neo4jStore) GetEntitySubgraph(ctx context.Context, labels []string, identifyingProperties map[string]interface{}, maxDepth int) (graph.SubgraphResult, error) {
+// formatted suitably for visualisation tools like Mermaid. If includeLabels is non-empty, the subgraph is
+// filtered afterwards to the central entity and the nodes with any of those labels, and the relationships
+// between the remaining nodes; nodes are still reached through nodes that are filtered out.
+func (s *Neo4jStore) GetEntitySubgraph(ctx context.Context, labels []string, identifyingProperties map[string]interface{}, maxDepth int, includeLabels []string) (graph.SubgraphResult, error) {
 	if len(labels) == 0 {
 		return graph.SubgraphResult{}, fmt.Errorf("at least one label is required for the target node")
 	}
@@ -1237,17 +2264,23 @@ func (s *Neo4jStore) GetEntitySubgraph(ctx context.Context, labels []string, ide
         MATCH (target%s %s)
         CALL apoc.path.subgraphAll(target, {maxLevel: %d})
         YIELD nodes, relationships
+        WITH [node IN nodes WHERE node = target OR size($includeLabels) = 0 OR any(l IN labels(node) WHERE l IN $includeLabels)] AS nodes, relationships
+        WITH nodes, [rel IN relationships WHERE startNode(rel) IN nodes AND endNode(rel) IN nodes] AS relationships
         RETURN
             [node IN nodes | { id: elementId(node), labels: labels(node), name: node.name, props: properties(node) }] AS subgraphNodes,
             [rel IN relationships | { id: elementId(rel), startNode: elementId(startNode(rel)), endNode: elementId(endNode(rel)), type: type(rel), props: properties(rel) }] AS subgraphRels
     `, labelStr, idPropsMatchStr, maxDepth)
 
+	if includeLabels == nil {
+		includeLabels = []string{}
+	}
 	params := map[string]interface{}{
-		"idProps": identifyingProperties,
+		"idProps":       identifyingProperties,
+		"includeLabels": includeLabels,
 	}
 
 	// Execute query
-	result, err := neo4j.ExecuteQuery(ctx, s.driver, query, params, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase(""))
+	result, err := neo4j.ExecuteQuery(ctx, s.driver, query, params, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase(""), txMetadata(ctx))
 	if err != nil {
 		// Check if the error is due to APOC procedure not found
 		if strings.Contains(err.Error(), "Unknown function 'apoc.path.subgraphAll'") {
@@ -1259,7 +2292,7 @@ func (s *Neo4jStore) GetEntitySubgraph(ctx context.Context, labels []string, ide
 	if len(result.Records) == 0 {
 		// This could mean the target node wasn't found, or APOC didn't return results
 		// Check if the target node exists first
-		_, err := s.GetEntityDetails(ctx, labels, identifyingProperties)
+		_, err := s.GetEntityDetails(ctx, labels, identifyingProperties, nil)
 		if err != nil {
 			return graph.SubgraphResult{}, fmt.Errorf("target node not found or error fetching details: %w", err)
 		}
@@ -1269,8 +2302,21 @@ func (s *Neo4jStore) GetEntitySubgraph(ctx context.Context, labels []string, ide
 
 	record := result.Records[0]
 
-	// Process nodes
+	// Process nodes and relationships
 	nodesVal, _ := record.Get("subgraphNodes")
+	relsVal, _ := record.Get("subgraphRels")
+	subgraphNodes := subgraphNodesFromValue(nodesVal)
+	subgraphRels := subgraphRelationshipsFromValue(relsVal)
+
+	return graph.SubgraphResult{
+		Nodes:         subgraphNodes,
+		Relationships: subgraphRels,
+	}, nil
+}
+
+// subgraphNodesFromValue converts a list of node maps ({id, labels, name, props}) into subgraph nodes,
+// leaving out common bookkeeping properties.
+func subgraphNodesFromValue(nodesVal interface{}) []graph.SubgraphNode {
 	nodesInterface, _ := nodesVal.([]interface{})
 	subgraphNodes := make([]graph.SubgraphNode, 0, len(nodesInterface))
 	for _, nodeIntf := range nodesInterface {
@@ -1308,9 +2354,12 @@ func (s *Neo4jStore) GetEntitySubgraph(ctx context.Context, labels []string, ide
 			Props:  convertedProps,
 		})
 	}
+	return subgraphNodes
+}
 
-	// Process relationships
-	relsVal, _ := record.Get("subgraphRels")
+// subgraphRelationshipsFromValue converts a list of relationship maps ({id, startNode, endNode, type, props})
+// into subgraph relationships, leaving out common bookkeeping properties.
+func subgraphRelationshipsFromValue(relsVal interface{}) []graph.SubgraphRelationship {
 	relsInterface, _ := relsVal.([]interface{})
 	subgraphRels := make([]graph.SubgraphRelationship, 0, len(relsInterface))
 	for _, relIntf := range relsInterface {
@@ -1343,9 +2392,6 @@ func (s *Neo4jStore) GetEntitySubgraph(ctx context.Context, labels []string, ide
 			Props:     convertedProps,
 		})
 	}
-
-	return graph.SubgraphResult{
-		Nodes:         subgraphNodes,
-		Relationships: subgraphRels,
-	}, nil
+	return subgraphRels
 }
+
@@ -0,0 +1,477 @@
+package neo4j
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sammcj/mcp-graph/internal/graph"
+)
+
+func TestCheckRelationshipEndpoints(t *testing.T) {
+	input := graph.RelationshipInput{
+		StartNodeLabels:  []string{"Service"},
+		EndNodeLabels:    []string{"Library"},
+		RelationshipType: "DEPENDS_ON",
+	}
+
+	tests := []struct {
+		name         string
+		startFound   bool
+		endFound     bool
+		startMissing bool
+		endMissing   bool
+		message      string
+	}{
+		{
+			name:         "missing start",
+			startFound:   false,
+			endFound:     true,
+			startMissing: true,
+			message:      "start node (:Service) not found; create it before creating the relationship",
+		},
+		{
+			name:       "missing end",
+			startFound: true,
+			endFound:   false,
+			endMissing: true,
+			message:    "end node (:Library) not found; create it before creating the relationship",
+		},
+		{
+			name:         "both missing",
+			startFound:   false,
+			endFound:     false,
+			startMissing: true,
+			endMissing:   true,
+			message:      "start node (:Service) and end node (:Library) not found; create them before creating the relationship",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkRelationshipEndpoints(input, tt.startFound, tt.endFound)
+
+			var endpointErr *graph.EndpointNotFoundError
+			assert.True(t, errors.As(err, &endpointErr))
+			assert.Equal(t, tt.startMissing, endpointErr.StartMissing)
+			assert.Equal(t, tt.endMissing, endpointErr.EndMissing)
+			assert.Equal(t, tt.message, err.Error())
+		})
+	}
+
+	// Both endpoints present
+	assert.NoError(t, checkRelationshipEndpoints(input, true, true))
+}
+
+func TestUnwrittenTargetError(t *testing.T) {
+	// The entity is the start of an outgoing relationship, so the missing target is the end
+	err := unwrittenTargetError(graph.RelationshipInput{EndNodeLabels: []string{"Library"}, RelationshipType: "DEPENDS_ON"}, true, []string{"Library"}, 0)
+	var endpointErr *graph.EndpointNotFoundError
+	assert.True(t, errors.As(err, &endpointErr))
+	assert.False(t, endpointErr.StartMissing)
+	assert.True(t, endpointErr.EndMissing)
+	assert.Equal(t, "end node (:Library) not found; create it before creating the relationship", err.Error())
+
+	// The entity is the end of an incoming relationship, so the missing target is the start
+	err = unwrittenTargetError(graph.RelationshipInput{StartNodeLabels: []string{"Service"}, RelationshipType: "DEPENDS_ON"}, false, []string{"Service"}, 0)
+	assert.True(t, errors.As(err, &endpointErr))
+	assert.True(t, endpointErr.StartMissing)
+	assert.False(t, endpointErr.EndMissing)
+	assert.Equal(t, "start node (:Service) not found; create it before creating the relationship", err.Error())
+
+	// Targets matching several nodes are ambiguous
+	err = unwrittenTargetError(graph.RelationshipInput{EndNodeLabels: []string{"Library"}, RelationshipType: "DEPENDS_ON"}, true, []string{"Library"}, 2)
+	assert.False(t, errors.As(err, &endpointErr))
+	assert.Equal(t, "target node (:Library) is ambiguous: 2 nodes match its identifying properties; identify exactly one", err.Error())
+}
+
+func TestUpsertEntityWithRelationshipQuery(t *testing.T) {
+	entity := graph.EntityInput{Labels: []string{"Function"}, IdentifyingProperties: map[string]interface{}{"name": "parse"}}
+
+	// Nothing is written unless exactly one node matches the target
+	query := upsertEntityWithRelationshipQuery(entity, "CALLS", []string{"Function"}, map[string]interface{}{"name": "lex"}, true)
+	assert.Contains(t, query, "WITH collect(target) AS targets\n        WHERE size(targets) = 1\n        WITH targets[0] AS target\n        MERGE (n:Function")
+	assert.Contains(t, query, "MERGE (n)-[r:CALLS]->(target)")
+
+	query = upsertEntityWithRelationshipQuery(entity, "CALLS", []string{"Function"}, map[string]interface{}{"name": "main"}, false)
+	assert.Contains(t, query, "MERGE (target)-[r:CALLS]->(n)")
+}
+
+func TestNormalizeRelationshipType(t *testing.T) {
+	tests := map[string]string{
+		"calls":        "CALLS",
+		"CALLS":        "CALLS",
+		"dependsOn":    "DEPENDS_ON",
+		"DependsOn":    "DEPENDS_ON",
+		"DEPENDS_ON":   "DEPENDS_ON",
+		"depends_on":   "DEPENDS_ON",
+		"depends-on":   "DEPENDS_ON",
+		"depends on":   "DEPENDS_ON",
+		"HTTPCalls":    "HTTP_CALLS",
+		"usesV2Api":    "USES_V2_API",
+		"_leading__x_": "LEADING_X",
+	}
+
+	for input, expected := range tests {
+		assert.Equal(t, expected, normalizeRelationshipType(input), "input %q", input)
+	}
+}
+
+func TestBuildDirectedRelationshipPattern(t *testing.T) {
+	tests := map[string]string{
+		graph.DirectionOutgoing: "-[r:CALLS]->",
+		graph.DirectionIncoming: "<-[r:CALLS]-",
+		graph.DirectionBoth:     "-[r:CALLS]-",
+		"":                      "-[r:CALLS]-",
+	}
+	for direction, expected := range tests {
+		pattern, err := buildDirectedRelationshipPattern(direction, "r:CALLS")
+		assert.NoError(t, err)
+		assert.Equal(t, expected, pattern, "direction %q", direction)
+	}
+
+	_, err := buildDirectedRelationshipPattern("sideways", "r")
+	assert.Error(t, err)
+}
+
+func TestBuildMapProjection(t *testing.T) {
+	assert.Equal(t, "n {.`name`, .`filePath`}", buildMapProjection("n", []string{"name", "filePath"}))
+	assert.Equal(t, "n {.`we``ird`}", buildMapProjection("n", []string{"we`ird"}))
+}
+
+func TestNeo4jValueType(t *testing.T) {
+	tests := []struct {
+		value    interface{}
+		expected string
+	}{
+		{nil, "Null"},
+		{true, "Boolean"},
+		{int64(42), "Integer"},
+		{3.14, "Float"},
+		{"text", "String"},
+		{[]interface{}{"a"}, "List"},
+		{map[string]interface{}{"a": 1}, "Map"},
+		{neo4j.Node{ElementId: "4:abc:1"}, "Node"},
+		{neo4j.Relationship{ElementId: "5:abc:1"}, "Relationship"},
+		{time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), "DateTime"},
+		{neo4j.LocalDateTime(time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)), "LocalDateTime"},
+		{neo4j.Date(time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)), "Date"},
+		{neo4j.Duration{Days: 1}, "Duration"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, neo4jValueType(tt.value), "value %#v", tt.value)
+	}
+}
+
+func TestConvertNeo4jValue_CanonicalNode(t *testing.T) {
+	node := neo4j.Node{
+		ElementId: "4:abc:1",
+		Labels:    []string{"Document"},
+		Props:     map[string]any{"title": "Design", "published": neo4j.Date(time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC))},
+	}
+	expected := map[string]interface{}{
+		graph.NodeIDKey:     "4:abc:1",
+		graph.NodeLabelsKey: []string{"Document"},
+		"title":             "Design",
+		"published":         time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	// Nodes have the same shape at the top level of a row and nested inside other values
+	assert.Equal(t, expected, convertNeo4jValue(node))
+	assert.Equal(t, []interface{}{expected}, convertNeo4jValue([]interface{}{node}))
+
+	// The driver's properties aren't modified
+	assert.NotContains(t, node.Props, graph.NodeIDKey)
+}
+
+func TestMergeColumnType(t *testing.T) {
+	assert.Equal(t, "String", mergeColumnType("", "String"))
+	assert.Equal(t, "String", mergeColumnType("String", "String"))
+	assert.Equal(t, "String", mergeColumnType("String", "Null"))
+	assert.Equal(t, "", mergeColumnType("", "Null"))
+	assert.Equal(t, "Any", mergeColumnType("String", "DateTime"))
+	assert.Equal(t, "Any", mergeColumnType("Any", "Integer"))
+}
+
+func TestSetBatchConcurrency(t *testing.T) {
+	s := &Neo4jStore{batchConcurrency: defaultBatchConcurrency}
+
+	s.SetBatchConcurrency(4)
+	assert.Equal(t, 4, s.batchConcurrency)
+
+	// Non-positive values are ignored
+	s.SetBatchConcurrency(0)
+	assert.Equal(t, 4, s.batchConcurrency)
+}
+
+func TestLimitPropertySizes(t *testing.T) {
+	props := map[string]interface{}{"title": "short", "content": "héllo world", "size": 12}
+
+	// No limit leaves the properties untouched
+	s := &Neo4jStore{}
+	limited, err := s.limitPropertySizes(props)
+	assert.NoError(t, err)
+	assert.Equal(t, props, limited)
+
+	// Oversized values are rejected
+	s.SetPropertySizeLimit(5, false)
+	_, err = s.limitPropertySizes(props)
+	assert.ErrorIs(t, err, graph.ErrPropertyTooLarge)
+	assert.Contains(t, err.Error(), `"content"`)
+
+	// Or truncated without splitting a character, leaving the input map unchanged
+	s.SetPropertySizeLimit(2, true)
+	limited, err = s.limitPropertySizes(map[string]interface{}{"content": "héllo", "size": 12})
+	assert.NoError(t, err)
+	assert.Equal(t, "h", limited["content"])
+	assert.Equal(t, 12, limited["size"])
+
+	s.SetPropertySizeLimit(5, true)
+	limited, err = s.limitPropertySizes(props)
+	assert.NoError(t, err)
+	assert.Equal(t, "héll", limited["content"])
+	assert.Equal(t, "short", limited["title"])
+	assert.Equal(t, "héllo world", props["content"])
+}
+
+func TestApplyPropertyWhitelist(t *testing.T) {
+	props := map[string]interface{}{"name": "parse", "filePath": "parse.go", "file_path": "parse.go", "lastModifiedAt": "now"}
+
+	// Labels without a whitelist are unrestricted
+	s := &Neo4jStore{}
+	s.SetPropertyWhitelist(map[string][]string{"Function": {"name", "filePath"}}, false)
+	allowed, err := s.applyPropertyWhitelist([]string{"File"}, props, false)
+	assert.NoError(t, err)
+	assert.Equal(t, props, allowed)
+
+	// Unknown keys are rejected, naming them and the allowed ones
+	_, err = s.applyPropertyWhitelist([]string{"Function", "Go"}, props, false)
+	assert.ErrorIs(t, err, graph.ErrPropertyNotAllowed)
+	assert.Contains(t, err.Error(), "file_path; allowed properties are filePath, name")
+
+	// Or stripped, leaving the input map unchanged
+	allowed, err = s.applyPropertyWhitelist([]string{"Function"}, props, true)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"name": "parse", "filePath": "parse.go", "lastModifiedAt": "now"}, allowed)
+	assert.Contains(t, props, "file_path")
+
+	// A node with several whitelisted labels may have the properties of any of them
+	s.SetPropertyWhitelist(map[string][]string{"Function": {"name", "filePath"}, "Legacy": {"file_path"}}, false)
+	allowed, err = s.applyPropertyWhitelist([]string{"Function", "Legacy"}, props, false)
+	assert.NoError(t, err)
+	assert.Equal(t, props, allowed)
+}
+
+func TestAlternateScheme(t *testing.T) {
+	tests := map[string]string{
+		"neo4j://localhost:7687":       "bolt://localhost:7687",
+		"bolt://localhost:7687":        "neo4j://localhost:7687",
+		"neo4j+s://db.example.com":     "bolt+s://db.example.com",
+		"bolt+ssc://db.example.com:76": "neo4j+ssc://db.example.com:76",
+	}
+	for uri, want := range tests {
+		got, ok := alternateScheme(uri)
+		assert.True(t, ok, uri)
+		assert.Equal(t, want, got)
+	}
+
+	_, ok := alternateScheme("http://localhost:7474")
+	assert.False(t, ok)
+	_, ok = alternateScheme("localhost:7687")
+	assert.False(t, ok)
+}
+
+func TestIsRoutingError(t *testing.T) {
+	assert.True(t, isRoutingError(&neo4j.ConnectivityError{Inner: errors.New("Unable to retrieve routing table from localhost:7687: no routing procedure")}))
+	assert.False(t, isRoutingError(&neo4j.ConnectivityError{Inner: errors.New("Unable to retrieve routing table from localhost:7687: dial tcp 127.0.0.1:7687: connect: connection refused")}))
+	assert.False(t, isRoutingError(fmt.Errorf("%w: routing", graph.ErrAuthFailed)))
+	assert.False(t, isRoutingError(errors.New("something else")))
+}
+
+func TestWithMaxConnectionPoolSize(t *testing.T) {
+	c := &neo4j.Config{MaxConnectionPoolSize: 100}
+
+	WithMaxConnectionPoolSize(0)(c)
+	assert.Equal(t, 100, c.MaxConnectionPoolSize)
+
+	WithMaxConnectionPoolSize(20)(c)
+	assert.Equal(t, 20, c.MaxConnectionPoolSize)
+}
+
+func TestClassifyConnectivityError(t *testing.T) {
+	refused := &neo4j.ConnectivityError{Inner: &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}}
+	assert.ErrorIs(t, classifyConnectivityError(refused), graph.ErrConnectionRefused)
+
+	timeout := &neo4j.ConnectivityError{Inner: context.DeadlineExceeded}
+	assert.ErrorIs(t, classifyConnectivityError(timeout), graph.ErrTimeout)
+
+	unauthorized := &neo4j.Neo4jError{Code: "Neo.ClientError.Security.Unauthorized", Msg: "The client is unauthorized"}
+	assert.ErrorIs(t, classifyConnectivityError(unauthorized), graph.ErrAuthFailed)
+
+	other := errors.New("something else")
+	assert.Equal(t, other, classifyConnectivityError(other))
+}
+
+func TestChangedProperties(t *testing.T) {
+	before := map[string]interface{}{
+		"name":           "parse",
+		"signature":      "func parse()",
+		"lines":          int64(10),
+		"lastModifiedAt": "2025-01-01T00:00:00Z",
+	}
+	after := map[string]interface{}{
+		"id":             "4:abc:1",
+		"name":           "parse",
+		"signature":      "func parse(s string)",
+		"lines":          int64(10),
+		"language":       "go",
+		"lastModifiedAt": "2025-02-01T00:00:00Z",
+	}
+
+	// Only changed and added properties are reported, ignoring the id and lastModifiedAt
+	changes := changedProperties(before, after)
+	assert.Equal(t, map[string]graph.PropertyChange{
+		"signature": {Old: "func parse()", New: "func parse(s string)"},
+		"language":  {Old: nil, New: "go"},
+	}, changes)
+
+	// A merge that changes nothing reports no changes
+	assert.Empty(t, changedProperties(after, after))
+
+	// Every property of a new entity is a change
+	assert.Len(t, changedProperties(nil, after), 4)
+}
+
+func TestCheckRelationshipTypeAllowed(t *testing.T) {
+	s := &Neo4jStore{}
+
+	// Any type is allowed without an allow-list
+	assert.NoError(t, s.checkRelationshipTypeAllowed("Calls"))
+
+	s.SetAllowedRelationshipTypes([]string{"CALLS", "DEPENDS_ON"})
+	assert.NoError(t, s.checkRelationshipTypeAllowed("CALLS"))
+
+	err := s.checkRelationshipTypeAllowed("CALL")
+	assert.ErrorIs(t, err, graph.ErrRelationshipTypeNotAllowed)
+	assert.ErrorContains(t, err, `"CALL"; use one of CALLS, DEPENDS_ON`)
+}
+
+func TestSuggestIdentifyingProperties(t *testing.T) {
+	properties := []graph.PropertyUsage{
+		{Key: "lastModifiedAt", Coverage: 1, Unique: true},
+		{Key: "name", Coverage: 1, Unique: false},
+		{Key: "path", Coverage: 0.95, Unique: true},
+		{Key: "checksum", Coverage: 0.5, Unique: true},
+	}
+
+	// A constraint is preferred to an index, and an index to observed usage
+	keys, source := suggestIdentifyingProperties(graph.LabelModel{
+		Constraints: [][]string{{"path"}},
+		Indexes:     [][]string{{"name"}},
+		Properties:  properties,
+	})
+	assert.Equal(t, []string{"path"}, keys)
+	assert.Equal(t, "constraint", source)
+
+	keys, source = suggestIdentifyingProperties(graph.LabelModel{Indexes: [][]string{{"name"}}, Properties: properties})
+	assert.Equal(t, []string{"name"}, keys)
+	assert.Equal(t, "index", source)
+
+	// Timestamps and properties that aren't unique or that most entities lack are never suggested
+	keys, source = suggestIdentifyingProperties(graph.LabelModel{Properties: properties})
+	assert.Equal(t, []string{"path"}, keys)
+	assert.Equal(t, "observed", source)
+
+	keys, source = suggestIdentifyingProperties(graph.LabelModel{Properties: properties[3:]})
+	assert.Empty(t, keys)
+	assert.Empty(t, source)
+}
+
+func TestGraphDensity(t *testing.T) {
+	assert.Equal(t, 0.0, graphDensity(0, 0))
+	assert.Equal(t, 0.0, graphDensity(1, 1)) // A single node with a self-loop
+	assert.Equal(t, 0.5, graphDensity(3, 3))
+	assert.Equal(t, 1.0, graphDensity(2, 2))
+}
+
+func TestDependencyQuery(t *testing.T) {
+	params := map[string]interface{}{}
+
+	// Dependencies are reached by outgoing paths and connected by relationships into them. APOC can't filter on
+	// relationship properties, so a variable-length match is used even though it's installed
+	query := dependencyQuery(context.Background(), params, ":Function", "{name: $idProps.name}", []string{"CALLS"}, map[string]interface{}{"weak": false}, 3, graph.DirectionOutgoing, true)
+	assert.Contains(t, query, "MATCH path = (target)-[:CALLS*1..3]->(entity)")
+	assert.Contains(t, query, "WITH target, entity, min(length(path)) AS depth")
+	assert.Contains(t, query, "[(entity)<-[via:CALLS]-(other) WHERE other IN closer AND all(k IN keys($relProps) WHERE via[k] = $relProps[k]) | type(via)]")
+	assert.Contains(t, query, "f.depth < 3")
+	assert.NotContains(t, query, "shortestPath")
+	assert.NotContains(t, query, "apoc.path.expandConfig")
+
+	// With APOC, each entity is reached once by a breadth-first traversal
+	params = map[string]interface{}{}
+	query = dependencyQuery(context.Background(), params, ":Function", "{name: $idProps.name}", []string{"CALLS", "USES"}, nil, 3, graph.DirectionOutgoing, true)
+	assert.Contains(t, query, "CALL apoc.path.expandConfig(target, {")
+	assert.Contains(t, query, "uniqueness: 'NODE_GLOBAL', bfs: true, minLevel: 1, maxLevel: 3")
+	assert.Contains(t, query, "WITH target, last(nodes(path)) AS entity, length(path) AS depth")
+	assert.NotContains(t, query, "MATCH path =")
+	assert.Equal(t, "CALLS>|USES>", params["relationshipFilter"])
+
+	// Dependents are reached by incoming paths and connected by relationships out of them
+	params = map[string]interface{}{}
+	query = dependencyQuery(context.Background(), params, ":Function", "{name: $idProps.name}", nil, nil, 2, graph.DirectionIncoming, false)
+	assert.Contains(t, query, "MATCH path = (target)<-[*1..2]-(entity)")
+	assert.Contains(t, query, "[(entity)-[via]->(other) WHERE other IN closer | type(via)]")
+	assert.Contains(t, query, "[(entity)-[via]->(target) WHERE true | {type: type(via), props: properties(via)}]")
+}
+
+func TestAPOCRelationshipFilter(t *testing.T) {
+	assert.Equal(t, "CALLS>|USES>", apocRelationshipFilter([]string{"CALLS", "USES"}, graph.DirectionOutgoing))
+	assert.Equal(t, "CALLS<", apocRelationshipFilter([]string{"CALLS"}, graph.DirectionIncoming))
+	assert.Equal(t, ">", apocRelationshipFilter(nil, graph.DirectionOutgoing))
+	assert.Equal(t, "<", apocRelationshipFilter(nil, graph.DirectionIncoming))
+}
+
+func TestDependencyEntriesFromRecords(t *testing.T) {
+	keys := []string{"depLabels", "depProps", "depId", "depth", "relTypes", "direct"}
+	records := []*neo4j.Record{
+		{Keys: keys, Values: []any{
+			[]any{"Function"}, map[string]any{"name": "parse"}, "4:abc:1", int64(1),
+			[]any{"CALLS"},
+			[]any{map[string]any{"type": "CALLS", "props": map[string]any{"line": int64(12)}}},
+		}},
+		{Keys: keys, Values: []any{
+			[]any{"Function"}, map[string]any{"name": "lex"}, "4:abc:2", int64(2),
+			[]any{"CALLS", "USES"},
+			[]any{},
+		}},
+	}
+
+	entries := dependencyEntriesFromRecords(records)
+	assert.Len(t, entries, 2)
+
+	assert.Equal(t, []string{"Function"}, entries[0].Labels)
+	assert.Equal(t, "parse", entries[0].Properties["name"])
+	assert.Equal(t, "4:abc:1", entries[0].Properties["id"])
+	assert.Equal(t, 1, entries[0].Depth)
+	assert.Equal(t, []string{"CALLS"}, entries[0].RelationshipTypes)
+	assert.Equal(t, []graph.ConnectingRelationship{{Type: "CALLS", Properties: map[string]interface{}{"line": int64(12)}}}, entries[0].Relationships)
+
+	// Entities further away have no direct relationships
+	assert.Equal(t, 2, entries[1].Depth)
+	assert.Equal(t, []string{"CALLS", "USES"}, entries[1].RelationshipTypes)
+	assert.Nil(t, entries[1].Relationships)
+
+	// Entries embed the entity, and leave out the direct relationships of entities further away
+	encoded, err := json.Marshal(entries[1])
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"labels":["Function"],"properties":{"name":"lex","id":"4:abc:2"},"depth":2,"relationshipTypes":["CALLS","USES"]}`, string(encoded))
+}
@@ -0,0 +1,64 @@
+package neo4j
+
+import (
+	"regexp"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+// isoTemporalPattern matches the ISO-8601 forms coerceTemporalValue understands: a date, optionally followed by
+// a time with optional fractional seconds and an optional "Z" or ±hh:mm offset
+var isoTemporalPattern = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}(T\d{2}:\d{2}:\d{2}(\.\d{1,9})?(Z|[+-]\d{2}:\d{2})?)?$`)
+
+// SetCoerceTemporalProperties enables or disables converting ISO-8601 strings in the properties passed to
+// FindOrCreateEntity and FindOrCreateRelationship into Neo4j temporal values, so that they can be compared
+// and used in range queries. See coerceTemporalValue for the rules.
+func (s *Neo4jStore) SetCoerceTemporalProperties(enabled bool) {
+	s.coerceTemporalProperties = enabled
+}
+
+// coerceTemporalValues replaces ISO-8601 string values in properties with Neo4j temporal values, in place,
+// if coercion is enabled. Keys in skip are left alone, so that identifying properties keep matching the
+// values existing nodes were created with.
+func (s *Neo4jStore) coerceTemporalValues(properties map[string]interface{}, skip map[string]interface{}) {
+	if !s.coerceTemporalProperties {
+		return
+	}
+	for k, v := range properties {
+		if _, ok := skip[k]; ok {
+			continue
+		}
+		properties[k] = coerceTemporalValue(v)
+	}
+}
+
+// coerceTemporalValue converts a string in one of these ISO-8601 forms to the matching Neo4j type:
+//
+//	2024-05-01                      -> Date
+//	2024-05-01T09:30:00             -> LocalDateTime (no offset)
+//	2024-05-01T09:30:00.123Z        -> DateTime
+//	2024-05-01T09:30:00+10:00       -> DateTime
+//
+// Any other value, including strings that match the pattern but aren't valid dates (e.g. 2024-13-01),
+// is returned unchanged.
+func coerceTemporalValue(value interface{}) interface{} {
+	str, ok := value.(string)
+	if !ok || !isoTemporalPattern.MatchString(str) {
+		return value
+	}
+
+	if len(str) == len("2006-01-02") {
+		if t, err := time.Parse("2006-01-02", str); err == nil {
+			return neo4j.DateOf(t)
+		}
+		return value
+	}
+	if t, err := time.Parse(time.RFC3339Nano, str); err == nil {
+		return t
+	}
+	if t, err := time.Parse("2006-01-02T15:04:05.999999999", str); err == nil {
+		return neo4j.LocalDateTimeOf(t)
+	}
+	return value
+}
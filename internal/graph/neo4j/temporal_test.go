@@ -0,0 +1,45 @@
+package neo4j
+
+import (
+	"testing"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCoerceTemporalValue(t *testing.T) {
+	assert.Equal(t, neo4j.DateOf(time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)), coerceTemporalValue("2024-05-01"))
+	assert.Equal(t, neo4j.LocalDateTimeOf(time.Date(2024, 5, 1, 9, 30, 0, 0, time.UTC)), coerceTemporalValue("2024-05-01T09:30:00"))
+
+	dateTime, ok := coerceTemporalValue("2024-05-01T09:30:00.123Z").(time.Time)
+	if assert.True(t, ok) {
+		assert.True(t, dateTime.Equal(time.Date(2024, 5, 1, 9, 30, 0, 123000000, time.UTC)))
+	}
+	offset, ok := coerceTemporalValue("2024-05-01T09:30:00+10:00").(time.Time)
+	if assert.True(t, ok) {
+		_, seconds := offset.Zone()
+		assert.Equal(t, 10*60*60, seconds)
+	}
+
+	// Anything else is left alone
+	assert.Equal(t, "2024-13-01", coerceTemporalValue("2024-13-01"))
+	assert.Equal(t, "May 1st 2024", coerceTemporalValue("May 1st 2024"))
+	assert.Equal(t, "2024-05-01 09:30:00", coerceTemporalValue("2024-05-01 09:30:00"))
+	assert.Equal(t, int64(2024), coerceTemporalValue(int64(2024)))
+}
+
+func TestCoerceTemporalValues(t *testing.T) {
+	props := map[string]interface{}{"releasedOn": "2024-05-01", "version": "2024-05-01"}
+
+	// Disabled by default
+	s := &Neo4jStore{}
+	s.coerceTemporalValues(props, nil)
+	assert.Equal(t, "2024-05-01", props["releasedOn"])
+
+	// Identifying properties are skipped so they keep matching existing nodes
+	s.SetCoerceTemporalProperties(true)
+	s.coerceTemporalValues(props, map[string]interface{}{"version": "2024-05-01"})
+	assert.IsType(t, neo4j.Date{}, props["releasedOn"])
+	assert.Equal(t, "2024-05-01", props["version"])
+}
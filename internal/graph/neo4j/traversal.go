@@ -0,0 +1,831 @@
+package neo4j
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+
+	"github.com/sammcj/mcp-graph/internal/graph"
+)
+
+// CommonDependencies finds entities that every one of the given entities depends on (outgoing relationships),
+// following specified relationship types up to a certain depth. The intersection is computed from the
+// per-entity dependency sets, preserving the order in which the first entity's dependencies were returned.
+func (s *Neo4jStore) CommonDependencies(ctx context.Context, locators []graph.EntityLocator, relationshipTypes []string, maxDepth int) (graph.CommonDependenciesResult, error) {
+	if len(locators) == 0 {
+		return graph.CommonDependenciesResult{}, fmt.Errorf("at least one entity is required")
+	}
+	if maxDepth <= 0 {
+		maxDepth = 1 // Default to depth 1 if invalid
+	}
+
+	targetNodes := make([]graph.EntityDetails, 0, len(locators))
+	var common []graph.EntityDetails
+
+	for i, locator := range locators {
+		depResult, err := s.FindDependencies(ctx, locator.Labels, locator.IdentifyingProperties, relationshipTypes, nil, maxDepth)
+		if err != nil {
+			return graph.CommonDependenciesResult{}, fmt.Errorf("failed to find dependencies for entity at index %d: %w", i, err)
+		}
+		targetNodes = append(targetNodes, depResult.TargetNode)
+
+		if i == 0 {
+			common = make([]graph.EntityDetails, len(depResult.Results))
+			for j, dep := range depResult.Results {
+				common[j] = dep.EntityDetails
+			}
+			continue
+		}
+
+		// Keep only the dependencies also found for this entity
+		seen := make(map[string]bool, len(depResult.Results))
+		for _, dep := range depResult.Results {
+			if id, ok := dep.Properties["id"].(string); ok {
+				seen[id] = true
+			}
+		}
+		filtered := common[:0]
+		for _, dep := range common {
+			if id, ok := dep.Properties["id"].(string); ok && seen[id] {
+				filtered = append(filtered, dep)
+			}
+		}
+		common = filtered
+	}
+
+	if common == nil {
+		common = []graph.EntityDetails{}
+	}
+
+	return graph.CommonDependenciesResult{
+		TargetNodes: targetNodes,
+		Results:     common,
+		Depth:       maxDepth,
+	}, nil
+}
+
+// FindNearestByLabel finds the closest entity with the target label to the given entity, following the
+// specified relationship types in either direction up to a certain depth, and returns the shortest path to it.
+// With APOC this is a breadth-first search that visits each node at most once and stops at the first match.
+// Without it, every path from the start node up to maxDepth is enumerated and the shortest kept, which grows
+// exponentially with the depth, so maxDepth is capped at graph.MaxNearestByLabelDepth either way.
+func (s *Neo4jStore) FindNearestByLabel(ctx context.Context, from graph.EntityLocator, targetLabel string, relationshipTypes []string, maxDepth int) (graph.PathResult, error) {
+	if len(from.Labels) == 0 {
+		return graph.PathResult{}, fmt.Errorf("at least one label is required for the start node")
+	}
+	if len(from.IdentifyingProperties) == 0 {
+		return graph.PathResult{}, fmt.Errorf("at least one identifying property is required for the start node")
+	}
+	if targetLabel == "" {
+		return graph.PathResult{}, fmt.Errorf("target label is required")
+	}
+	if maxDepth <= 0 {
+		maxDepth = 1 // Default to depth 1 if invalid
+	}
+	if maxDepth > graph.MaxNearestByLabelDepth {
+		maxDepth = graph.MaxNearestByLabelDepth
+	}
+
+	caps, err := s.Capabilities(ctx)
+	useAPOC := err == nil && caps.APOC
+	query := nearestByLabelQuery(from, targetLabel, relationshipTypes, maxDepth, useAPOC)
+
+	params := map[string]interface{}{
+		"idProps":           from.IdentifyingProperties,
+		"maxDepth":          maxDepth,
+		"labelFilter":       "/" + targetLabel,
+		"relationshipTypes": strings.Join(relationshipTypes, "|"),
+	}
+
+	// Execute query
+	result, err := s.executeTraversalQuery(ctx, query, params)
+	if err != nil {
+		return graph.PathResult{}, fmt.Errorf("failed to execute FindNearestByLabel query: %w", err)
+	}
+
+	if len(result.Records) == 0 {
+		return emptyPathResult(), nil
+	}
+	pathVal, _ := result.Records[0].Get("path")
+	path, ok := pathVal.(neo4j.Path)
+	if !ok {
+		return graph.PathResult{}, fmt.Errorf("path is not in expected format")
+	}
+
+	return pathResultFromNeo4jPath(path), nil
+}
+
+// nearestByLabelQuery builds the FindNearestByLabel query. With APOC, apoc.path.expandConfig searches breadth-first
+// and ends paths at the first node with the target label; limit 1 stops the search once it is found. Otherwise a
+// variable-length match is ordered by length instead.
+func nearestByLabelQuery(from graph.EntityLocator, targetLabel string, relationshipTypes []string, maxDepth int, useAPOC bool) string {
+	if useAPOC {
+		return fmt.Sprintf(`
+        MATCH (start%s %s)
+        WITH start LIMIT 1
+        CALL apoc.path.expandConfig(start, {
+            relationshipFilter: $relationshipTypes, labelFilter: $labelFilter, uniqueness: 'NODE_GLOBAL',
+            bfs: true, minLevel: 1, maxLevel: $maxDepth, limit: 1
+        }) YIELD path
+        RETURN path
+    `, buildLabelString(from.Labels), buildPropsMatchString("idProps", from.IdentifyingProperties))
+	}
+
+	return fmt.Sprintf(`
+        MATCH (start%s %s)
+        WITH start LIMIT 1
+        MATCH path = (start)-[%s*1..%d]-(target:%s)
+        WHERE target <> start
+        RETURN path
+        ORDER BY length(path) ASC
+        LIMIT 1
+    `, buildLabelString(from.Labels), buildPropsMatchString("idProps", from.IdentifyingProperties),
+		buildRelationshipTypeFilter(relationshipTypes), maxDepth, graph.QuoteCypherIdentifier(targetLabel))
+}
+
+// FindShortestPath finds the shortest path between two entities, following relationships in either direction.
+// With a weight property the lowest-weight path is found using APOC's Dijkstra implementation, where relationships
+// missing the property count as a weight of 1. Without one, or if APOC isn't installed, the path with the fewest
+// relationships (up to maxDepth) is found with shortestPath. maxDepth does not limit weighted searches.
+func (s *Neo4jStore) FindShortestPath(ctx context.Context, from, to graph.EntityLocator, relationshipTypes []string, weightProperty string, maxDepth int) (graph.ShortestPathResult, error) {
+	if len(from.Labels) == 0 || len(to.Labels) == 0 {
+		return graph.ShortestPathResult{}, fmt.Errorf("at least one label is required for the start and end nodes")
+	}
+	if len(from.IdentifyingProperties) == 0 || len(to.IdentifyingProperties) == 0 {
+		return graph.ShortestPathResult{}, fmt.Errorf("at least one identifying property is required for the start and end nodes")
+	}
+	if maxDepth <= 0 {
+		maxDepth = 5 // Default to depth 5 if invalid
+	}
+
+	if weightProperty != "" {
+		caps, err := s.Capabilities(ctx)
+		if err == nil && caps.APOC {
+			return s.dijkstraShortestPath(ctx, from, to, relationshipTypes, weightProperty)
+		}
+	}
+
+	query := fmt.Sprintf(`
+        MATCH (start%s %s)
+        MATCH (end%s %s)
+        WHERE end <> start
+        MATCH path = shortestPath((start)-[%s*1..%d]-(end))
+        RETURN path
+        LIMIT 1
+    `, buildLabelString(from.Labels), buildPropsMatchString("fromProps", from.IdentifyingProperties),
+		buildLabelString(to.Labels), buildPropsMatchString("toProps", to.IdentifyingProperties),
+		buildRelationshipTypeFilter(relationshipTypes), maxDepth)
+
+	params := map[string]interface{}{
+		"fromProps": from.IdentifyingProperties,
+		"toProps":   to.IdentifyingProperties,
+	}
+
+	result, err := s.executeTraversalQuery(ctx, query, params)
+	if err != nil {
+		return graph.ShortestPathResult{}, fmt.Errorf("failed to execute FindShortestPath query: %w", err)
+	}
+
+	shortest := graph.ShortestPathResult{PathResult: emptyPathResult(), Algorithm: "shortestPath"}
+	if len(result.Records) == 0 {
+		return shortest, nil
+	}
+	pathVal, _ := result.Records[0].Get("path")
+	path, ok := pathVal.(neo4j.Path)
+	if !ok {
+		return graph.ShortestPathResult{}, fmt.Errorf("path is not in expected format")
+	}
+	shortest.PathResult = pathResultFromNeo4jPath(path)
+	return shortest, nil
+}
+
+// DependencyPath finds the shortest dependency route from one entity to another, following only outgoing
+// relationships of the specified types up to a certain depth, so that each entity on the path depends on the next.
+func (s *Neo4jStore) DependencyPath(ctx context.Context, from, to graph.EntityLocator, relationshipTypes []string, maxDepth int) (graph.PathResult, error) {
+	if len(from.Labels) == 0 || len(to.Labels) == 0 {
+		return graph.PathResult{}, fmt.Errorf("at least one label is required for the start and end nodes")
+	}
+	if len(from.IdentifyingProperties) == 0 || len(to.IdentifyingProperties) == 0 {
+		return graph.PathResult{}, fmt.Errorf("at least one identifying property is required for the start and end nodes")
+	}
+	if maxDepth <= 0 {
+		maxDepth = 5 // Default to depth 5 if invalid
+	}
+
+	query := fmt.Sprintf(`
+        MATCH (start%s %s)
+        MATCH (end%s %s)
+        WHERE end <> start
+        MATCH path = shortestPath((start)-[%s*1..%d]->(end))
+        RETURN path
+        LIMIT 1
+    `, buildLabelString(from.Labels), buildPropsMatchString("fromProps", from.IdentifyingProperties),
+		buildLabelString(to.Labels), buildPropsMatchString("toProps", to.IdentifyingProperties),
+		buildRelationshipTypeFilter(relationshipTypes), maxDepth)
+
+	params := map[string]interface{}{
+		"fromProps": from.IdentifyingProperties,
+		"toProps":   to.IdentifyingProperties,
+	}
+
+	result, err := s.executeTraversalQuery(ctx, query, params)
+	if err != nil {
+		return graph.PathResult{}, fmt.Errorf("failed to execute DependencyPath query: %w", err)
+	}
+
+	if len(result.Records) == 0 {
+		return emptyPathResult(), nil
+	}
+	pathVal, _ := result.Records[0].Get("path")
+	path, ok := pathVal.(neo4j.Path)
+	if !ok {
+		return graph.PathResult{}, fmt.Errorf("path is not in expected format")
+	}
+
+	return pathResultFromNeo4jPath(path), nil
+}
+
+// maxHierarchyResults limits the number of entities returned by GetAncestors and GetDescendants
+const maxHierarchyResults = 1000
+
+// GetAncestors finds the entities from which the given entity can be reached by following relationships of the
+// given type forwards, up to a certain depth. Each is returned once, at its shortest distance.
+func (s *Neo4jStore) GetAncestors(ctx context.Context, locator graph.EntityLocator, relationshipType string, maxDepth int) ([]graph.HierarchyEntry, error) {
+	return s.hierarchy(ctx, locator, relationshipType, maxDepth, "(related)-[:%s*1..%d]->(start)")
+}
+
+// GetDescendants finds the entities that can be reached from the given entity by following relationships of the
+// given type forwards, up to a certain depth. Each is returned once, at its shortest distance.
+func (s *Neo4jStore) GetDescendants(ctx context.Context, locator graph.EntityLocator, relationshipType string, maxDepth int) ([]graph.HierarchyEntry, error) {
+	return s.hierarchy(ctx, locator, relationshipType, maxDepth, "(start)-[:%s*1..%d]->(related)")
+}
+
+// hierarchy finds the entities related to an entity by pathPattern, a format string for a path between start and
+// related taking the relationship type and maximum depth, ordered by depth and then element ID.
+func (s *Neo4jStore) hierarchy(ctx context.Context, locator graph.EntityLocator, relationshipType string, maxDepth int, pathPattern string) ([]graph.HierarchyEntry, error) {
+	if len(locator.Labels) == 0 {
+		return nil, fmt.Errorf("at least one label is required")
+	}
+	if len(locator.IdentifyingProperties) == 0 {
+		return nil, fmt.Errorf("at least one identifying property is required")
+	}
+	if relationshipType == "" {
+		return nil, fmt.Errorf("relationship type is required")
+	}
+	if maxDepth <= 0 {
+		maxDepth = 10 // Default to depth 10 if invalid
+	}
+
+	params := map[string]interface{}{
+		"idProps": locator.IdentifyingProperties,
+		"limit":   maxHierarchyResults,
+	}
+
+	query := fmt.Sprintf(`
+        MATCH (start%s %s)
+        MATCH path = %s
+        WHERE related <> start
+        WITH related, min(length(path)) AS depth
+        RETURN labels(related) as labels, properties(related) as props, elementId(related) as id, depth
+        %s
+    `, buildLabelString(locator.Labels), buildPropsMatchString("idProps", locator.IdentifyingProperties),
+		fmt.Sprintf(pathPattern, graph.QuoteCypherIdentifier(relationshipType), maxDepth), orderAndLimit(ctx, params, "depth, id", "$limit"))
+
+	result, err := s.executeTraversalQuery(ctx, query, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute hierarchy query: %w", err)
+	}
+
+	entries := make([]graph.HierarchyEntry, 0, len(result.Records))
+	for _, record := range result.Records {
+		depthVal, _ := record.Get("depth")
+		depth, _ := depthVal.(int64)
+		entries = append(entries, graph.HierarchyEntry{
+			Depth:  int(depth),
+			Entity: entityDetailsFromRecord(record, "labels", "props", "id"),
+		})
+	}
+
+	return entries, nil
+}
+
+// FindReachable finds the distinct entities reachable from the given entity within maxDepth relationships of any
+// type in either direction, using the shortest path to each to find its distance.
+func (s *Neo4jStore) FindReachable(ctx context.Context, locator graph.EntityLocator, maxDepth int, limit int) ([]graph.ReachableEntity, error) {
+	if len(locator.Labels) == 0 {
+		return nil, fmt.Errorf("at least one label is required")
+	}
+	if len(locator.IdentifyingProperties) == 0 {
+		return nil, fmt.Errorf("at least one identifying property is required")
+	}
+	if maxDepth <= 0 {
+		maxDepth = 2 // Default to depth 2 if invalid
+	}
+	if limit <= 0 {
+		limit = 100 // Default limit
+	}
+
+	params := map[string]interface{}{
+		"idProps": locator.IdentifyingProperties,
+		"limit":   limit,
+	}
+
+	query := fmt.Sprintf(`
+        MATCH (center%s %s)
+        MATCH path = shortestPath((center)-[*1..%d]-(m))
+        WHERE m <> center
+        WITH m, min(length(path)) AS distance
+        RETURN labels(m) as labels, properties(m) as props, elementId(m) as id, distance
+        %s
+    `, buildLabelString(locator.Labels), buildPropsMatchString("idProps", locator.IdentifyingProperties), maxDepth,
+		orderAndLimit(ctx, params, "distance, id", "$limit"))
+
+	result, err := s.executeTraversalQuery(ctx, query, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute FindReachable query: %w", err)
+	}
+
+	reachable := make([]graph.ReachableEntity, 0, len(result.Records))
+	for _, record := range result.Records {
+		distanceVal, _ := record.Get("distance")
+		distance, _ := distanceVal.(int64)
+		reachable = append(reachable, graph.ReachableEntity{
+			Distance: int(distance),
+			Entity:   entityDetailsFromRecord(record, "labels", "props", "id"),
+		})
+	}
+
+	return reachable, nil
+}
+
+// dijkstraShortestPath finds the path between two entities with the lowest total weight using apoc.algo.dijkstra
+func (s *Neo4jStore) dijkstraShortestPath(ctx context.Context, from, to graph.EntityLocator, relationshipTypes []string, weightProperty string) (graph.ShortestPathResult, error) {
+	query := fmt.Sprintf(`
+        MATCH (start%s %s)
+        MATCH (end%s %s)
+        WHERE end <> start
+        CALL apoc.algo.dijkstra(start, end, $relFilter, $weightProperty, 1.0) YIELD path, weight
+        RETURN path, weight
+        ORDER BY weight ASC
+        LIMIT 1
+    `, buildLabelString(from.Labels), buildPropsMatchString("fromProps", from.IdentifyingProperties),
+		buildLabelString(to.Labels), buildPropsMatchString("toProps", to.IdentifyingProperties))
+
+	params := map[string]interface{}{
+		"fromProps":      from.IdentifyingProperties,
+		"toProps":        to.IdentifyingProperties,
+		"relFilter":      strings.Join(relationshipTypes, "|"), // APOC's format, where "" matches any type in either direction
+		"weightProperty": weightProperty,
+	}
+
+	result, err := s.executeTraversalQuery(ctx, query, params)
+	if err != nil {
+		return graph.ShortestPathResult{}, fmt.Errorf("failed to execute weighted FindShortestPath query: %w", err)
+	}
+
+	shortest := graph.ShortestPathResult{PathResult: emptyPathResult(), Algorithm: "dijkstra"}
+	if len(result.Records) == 0 {
+		return shortest, nil
+	}
+	pathVal, _ := result.Records[0].Get("path")
+	path, ok := pathVal.(neo4j.Path)
+	if !ok {
+		return graph.ShortestPathResult{}, fmt.Errorf("path is not in expected format")
+	}
+	weightVal, _ := result.Records[0].Get("weight")
+	weight, _ := weightVal.(float64)
+	shortest.PathResult = pathResultFromNeo4jPath(path)
+	shortest.TotalWeight = &weight
+	return shortest, nil
+}
+
+// emptyPathResult returns the result for when no path was found
+func emptyPathResult() graph.PathResult {
+	return graph.PathResult{
+		Found:         false,
+		Nodes:         []graph.EntityDetails{},
+		Relationships: []graph.SubgraphRelationship{},
+	}
+}
+
+// pathResultFromNeo4jPath converts a Neo4j path into a PathResult
+func pathResultFromNeo4jPath(path neo4j.Path) graph.PathResult {
+	nodes := make([]graph.EntityDetails, len(path.Nodes))
+	for i, node := range path.Nodes {
+		props := make(map[string]interface{}, len(node.Props)+1)
+		for k, v := range node.Props {
+			props[k] = convertNeo4jValue(v)
+		}
+		props["id"] = node.ElementId
+		nodes[i] = graph.EntityDetails{Labels: node.Labels, Properties: props}
+	}
+
+	rels := make([]graph.SubgraphRelationship, len(path.Relationships))
+	for i, rel := range path.Relationships {
+		props := make(map[string]interface{}, len(rel.Props))
+		for k, v := range rel.Props {
+			props[k] = convertNeo4jValue(v)
+		}
+		rels[i] = graph.SubgraphRelationship{
+			ID:        rel.ElementId,
+			StartNode: rel.StartElementId,
+			EndNode:   rel.EndElementId,
+			Type:      rel.Type,
+			Props:     props,
+		}
+	}
+
+	return graph.PathResult{
+		Found:         true,
+		Nodes:         nodes,
+		Relationships: rels,
+		Length:        len(rels),
+	}
+}
+
+// GetEntitySubgraphPage retrieves one page of the subgraph around a central entity. Subgraph nodes are
+// ordered by element ID, and each page holds up to pageSize of them starting at offset, along with the
+// subgraph relationships starting at those nodes, so every relationship appears on exactly one page.
+// includeLabels filters the nodes before they are paged, as in GetEntitySubgraph. Like GetEntitySubgraph,
+// this requires APOC.
+func (s *Neo4jStore) GetEntitySubgraphPage(ctx context.Context, labels []string, identifyingProperties map[string]interface{}, maxDepth int, includeLabels []string, offset int, pageSize int) (graph.SubgraphPage, error) {
+	if len(labels) == 0 {
+		return graph.SubgraphPage{}, fmt.Errorf("at least one label is required for the target node")
+	}
+	if len(identifyingProperties) == 0 {
+		return graph.SubgraphPage{}, fmt.Errorf("at least one identifying property is required for the target node")
+	}
+	if maxDepth <= 0 {
+		maxDepth = 1 // Default to depth 1 if invalid
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	if pageSize <= 0 {
+		return graph.SubgraphPage{}, fmt.Errorf("page size must be positive")
+	}
+
+	query := fmt.Sprintf(`
+        MATCH (target%s %s)
+        CALL apoc.path.subgraphNodes(target, {maxLevel: $maxDepth}) YIELD node
+        WHERE node = target OR size($includeLabels) = 0 OR any(l IN labels(node) WHERE l IN $includeLabels)
+        WITH node ORDER BY elementId(node)
+        WITH collect(node) AS allNodes
+        WITH allNodes, allNodes[$offset..($offset + $pageSize)] AS pageNodes
+        UNWIND CASE WHEN size(pageNodes) = 0 THEN [null] ELSE pageNodes END AS n
+        OPTIONAL MATCH (n)-[r]->(m)
+        WHERE m IN allNodes
+        WITH allNodes, pageNodes, collect(DISTINCT r) AS pageRels
+        RETURN
+            size(allNodes) AS totalNodes,
+            [node IN pageNodes | { id: elementId(node), labels: labels(node), name: node.name, props: properties(node) }] AS subgraphNodes,
+            [rel IN pageRels | { id: elementId(rel), startNode: elementId(startNode(rel)), endNode: elementId(endNode(rel)), type: type(rel), props: properties(rel) }] AS subgraphRels
+    `, buildLabelString(labels), buildPropsMatchString("idProps", identifyingProperties))
+
+	if includeLabels == nil {
+		includeLabels = []string{}
+	}
+	params := map[string]interface{}{
+		"idProps":       identifyingProperties,
+		"maxDepth":      maxDepth,
+		"includeLabels": includeLabels,
+		"offset":        offset,
+		"pageSize":      pageSize,
+	}
+
+	// Execute query
+	result, err := neo4j.ExecuteQuery(ctx, s.driver, query, params, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase(""), txMetadata(ctx))
+	if err != nil {
+		return graph.SubgraphPage{}, fmt.Errorf("failed to execute GetEntitySubgraphPage query: %w", err)
+	}
+
+	if len(result.Records) == 0 {
+		return graph.SubgraphPage{}, fmt.Errorf("target node not found with labels %v and properties %v", labels, identifyingProperties)
+	}
+
+	record := result.Records[0]
+	totalVal, _ := record.Get("totalNodes")
+	total, _ := totalVal.(int64)
+	if total == 0 {
+		// The subgraph always contains the target node, so an empty one means it wasn't found
+		return graph.SubgraphPage{}, fmt.Errorf("target node not found with labels %v and properties %v", labels, identifyingProperties)
+	}
+	nodesVal, _ := record.Get("subgraphNodes")
+	relsVal, _ := record.Get("subgraphRels")
+
+	page := graph.SubgraphPage{
+		Nodes:         subgraphNodesFromValue(nodesVal),
+		Relationships: subgraphRelationshipsFromValue(relsVal),
+		TotalNodes:    int(total),
+	}
+	if next := offset + pageSize; next < page.TotalNodes {
+		page.HasMore = true
+		page.NextOffset = next
+	}
+
+	return page, nil
+}
+
+// maxEntityRelationships limits the number of relationships returned by GetEntityWithRelationships
+const maxEntityRelationships = 1000
+
+// GetEntityWithRelationships retrieves an entity together with its relationships in both directions and the
+// nodes at their other ends, in a single query. At most maxEntityRelationships relationships are returned.
+func (s *Neo4jStore) GetEntityWithRelationships(ctx context.Context, locator graph.EntityLocator) (graph.EntityWithRelationships, error) {
+	if len(locator.Labels) == 0 {
+		return graph.EntityWithRelationships{}, fmt.Errorf("at least one label is required")
+	}
+	if len(locator.IdentifyingProperties) == 0 {
+		return graph.EntityWithRelationships{}, fmt.Errorf("at least one identifying property is required")
+	}
+
+	query := fmt.Sprintf(`
+        MATCH (n%s %s)
+        WITH n LIMIT 1
+        OPTIONAL MATCH (n)-[r]-(m)
+        WITH n, collect(CASE WHEN r IS NULL THEN null ELSE {
+            id: elementId(r), type: type(r), outgoing: startNode(r) = n, props: properties(r),
+            nodeLabels: labels(m), nodeProps: properties(m), nodeId: elementId(m)
+        } END)[..$limit] AS rels
+        RETURN labels(n) as labels, properties(n) as props, elementId(n) as id, rels
+    `, buildLabelString(locator.Labels), buildPropsMatchString("idProps", locator.IdentifyingProperties))
+
+	params := map[string]interface{}{
+		"idProps": locator.IdentifyingProperties,
+		"limit":   maxEntityRelationships,
+	}
+
+	// Execute query
+	result, err := neo4j.ExecuteQuery(ctx, s.driver, query, params, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase(""), txMetadata(ctx))
+	if err != nil {
+		return graph.EntityWithRelationships{}, fmt.Errorf("failed to execute GetEntityWithRelationships query: %w", err)
+	}
+	if len(result.Records) == 0 {
+		return graph.EntityWithRelationships{}, fmt.Errorf("entity not found")
+	}
+
+	// Process the result
+	record := result.Records[0]
+	relsVal, _ := record.Get("rels")
+
+	return graph.EntityWithRelationships{
+		Entity:        entityDetailsFromRecord(record, "labels", "props", "id"),
+		Relationships: entityRelationshipsFromValue(relsVal),
+	}, nil
+}
+
+// GetNodeRelationships retrieves the relationships of the node with the given element ID in the given direction,
+// together with the nodes at their other ends. At most maxEntityRelationships relationships are returned.
+func (s *Neo4jStore) GetNodeRelationships(ctx context.Context, id string, direction string) ([]graph.EntityRelationship, error) {
+	relPattern, err := buildDirectedRelationshipPattern(direction, "r")
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf(`
+        MATCH (n)
+        WHERE elementId(n) = $id
+        OPTIONAL MATCH (n)%s(m)
+        WITH n, collect(CASE WHEN r IS NULL THEN null ELSE {
+            id: elementId(r), type: type(r), outgoing: startNode(r) = n, props: properties(r),
+            nodeLabels: labels(m), nodeProps: properties(m), nodeId: elementId(m)
+        } END)[..$limit] AS rels
+        RETURN rels
+    `, relPattern)
+
+	params := map[string]interface{}{
+		"id":    id,
+		"limit": maxEntityRelationships,
+	}
+
+	// Execute query
+	result, err := neo4j.ExecuteQuery(ctx, s.driver, query, params, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase(""), txMetadata(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute GetNodeRelationships query: %w", err)
+	}
+	if len(result.Records) == 0 {
+		return nil, fmt.Errorf("node not found: %s", id)
+	}
+
+	relsVal, _ := result.Records[0].Get("rels")
+	return entityRelationshipsFromValue(relsVal), nil
+}
+
+// ListRelationships retrieves an entity's relationships in the given direction, together with the nodes at their
+// other ends. Empty relationshipTypes matches any type; non-empty otherNodeLabels keeps only relationships whose
+// other node has at least one of the labels. At most maxEntityRelationships relationships are returned.
+func (s *Neo4jStore) ListRelationships(ctx context.Context, locator graph.EntityLocator, direction string, relationshipTypes []string, otherNodeLabels []string) ([]graph.EntityRelationship, error) {
+	if len(locator.Labels) == 0 {
+		return nil, fmt.Errorf("at least one label is required")
+	}
+	if len(locator.IdentifyingProperties) == 0 {
+		return nil, fmt.Errorf("at least one identifying property is required")
+	}
+	relPattern, err := buildDirectedRelationshipPattern(direction, "r"+buildRelationshipTypeFilter(relationshipTypes))
+	if err != nil {
+		return nil, err
+	}
+	if otherNodeLabels == nil {
+		otherNodeLabels = []string{}
+	}
+
+	query := fmt.Sprintf(`
+        MATCH (n%s %s)
+        WITH n LIMIT 1
+        OPTIONAL MATCH (n)%s(m)
+        WHERE size($otherNodeLabels) = 0 OR any(l IN labels(m) WHERE l IN $otherNodeLabels)
+        WITH n, collect(CASE WHEN r IS NULL THEN null ELSE {
+            id: elementId(r), type: type(r), outgoing: startNode(r) = n, props: properties(r),
+            nodeLabels: labels(m), nodeProps: properties(m), nodeId: elementId(m)
+        } END)[..$limit] AS rels
+        RETURN rels
+    `, buildLabelString(locator.Labels), buildPropsMatchString("idProps", locator.IdentifyingProperties), relPattern)
+
+	params := map[string]interface{}{
+		"idProps":         locator.IdentifyingProperties,
+		"otherNodeLabels": otherNodeLabels,
+		"limit":           maxEntityRelationships,
+	}
+
+	// Execute query
+	result, err := neo4j.ExecuteQuery(ctx, s.driver, query, params, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase(""), txMetadata(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute ListRelationships query: %w", err)
+	}
+	if len(result.Records) == 0 {
+		return nil, fmt.Errorf("entity not found")
+	}
+
+	relsVal, _ := result.Records[0].Get("rels")
+	return entityRelationshipsFromValue(relsVal), nil
+}
+
+// GetDefinitionLocation finds the File an entity is DEFINED_IN and the startLine/endLine recorded on that
+// relationship. If the entity is defined in more than one file, the first by filePath is returned.
+func (s *Neo4jStore) GetDefinitionLocation(ctx context.Context, locator graph.EntityLocator) (graph.DefinitionLocation, error) {
+	if len(locator.Labels) == 0 {
+		return graph.DefinitionLocation{}, fmt.Errorf("at least one label is required")
+	}
+	if len(locator.IdentifyingProperties) == 0 {
+		return graph.DefinitionLocation{}, fmt.Errorf("at least one identifying property is required")
+	}
+
+	query := fmt.Sprintf(`
+        MATCH (e%s %s)
+        WITH e LIMIT 1
+        OPTIONAL MATCH (e)-[d:DEFINED_IN]->(f:File)
+        WITH e, d, f ORDER BY f.filePath LIMIT 1
+        RETURN labels(e) as labels, properties(e) as props, elementId(e) as id,
+               labels(f) as fileLabels, properties(f) as fileProps, elementId(f) as fileId,
+               d.startLine as startLine, d.endLine as endLine
+    `, buildLabelString(locator.Labels), buildPropsMatchString("idProps", locator.IdentifyingProperties))
+
+	params := map[string]interface{}{
+		"idProps": locator.IdentifyingProperties,
+	}
+
+	// Execute query
+	result, err := neo4j.ExecuteQuery(ctx, s.driver, query, params, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase(""), txMetadata(ctx))
+	if err != nil {
+		return graph.DefinitionLocation{}, fmt.Errorf("failed to execute GetDefinitionLocation query: %w", err)
+	}
+	if len(result.Records) == 0 {
+		return graph.DefinitionLocation{}, fmt.Errorf("entity not found")
+	}
+
+	record := result.Records[0]
+	if fileID, _ := record.Get("fileId"); fileID == nil {
+		return graph.DefinitionLocation{}, fmt.Errorf("entity has no DEFINED_IN relationship to a File")
+	}
+
+	startLine, _ := record.Get("startLine")
+	endLine, _ := record.Get("endLine")
+
+	return graph.DefinitionLocation{
+		Entity:    entityDetailsFromRecord(record, "labels", "props", "id"),
+		File:      entityDetailsFromRecord(record, "fileLabels", "fileProps", "fileId"),
+		StartLine: lineNumber(startLine),
+		EndLine:   lineNumber(endLine),
+	}, nil
+}
+
+// lineNumber converts a stored line number to an int. Lines written through MCP tools arrive as JSON numbers
+// and may be stored as floats rather than integers.
+func lineNumber(v interface{}) int {
+	switch n := v.(type) {
+	case int64:
+		return int(n)
+	case float64:
+		return int(n)
+	default:
+		return 0
+	}
+}
+
+// entityRelationshipsFromValue converts a list of relationship maps, as built by GetEntityWithRelationships and
+// GetNodeRelationships, into entity relationships.
+func entityRelationshipsFromValue(relsVal interface{}) []graph.EntityRelationship {
+	relsInterface, _ := relsVal.([]interface{})
+	rels := make([]graph.EntityRelationship, 0, len(relsInterface))
+	for _, relInterface := range relsInterface {
+		m, ok := relInterface.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		id, _ := m["id"].(string)
+		relType, _ := m["type"].(string)
+		props, _ := convertNeo4jValue(m["props"]).(map[string]interface{})
+		direction := graph.DirectionIncoming
+		if outgoing, _ := m["outgoing"].(bool); outgoing {
+			direction = graph.DirectionOutgoing
+		}
+		rels = append(rels, graph.EntityRelationship{
+			ID:         id,
+			Type:       relType,
+			Direction:  direction,
+			Properties: props,
+			Node:       entityDetailsFromMap(m, "nodeLabels", "nodeProps", "nodeId"),
+		})
+	}
+	return rels
+}
+
+// orderAndLimit returns the ORDER BY and LIMIT clauses ending a traversal or search query: orderBy (if not empty)
+// and limit as given, or a random order if ctx asks for a sample (see graph.WithSample). Samples are limited to the
+// smaller of the sample size and limit, which is added to params. limit is a literal or a parameter in params.
+func orderAndLimit(ctx context.Context, params map[string]interface{}, orderBy, limit string) string {
+	if size := graph.SampleSizeFromContext(ctx); size > 0 {
+		if max, ok := limitValue(params, limit); ok && max < size {
+			size = max
+		}
+		params["sampleSize"] = size
+		return "ORDER BY rand()\n        LIMIT $sampleSize"
+	}
+	if orderBy == "" {
+		return "LIMIT " + limit
+	}
+	return "ORDER BY " + orderBy + "\n        LIMIT " + limit
+}
+
+// limitValue returns the value of a LIMIT expression that is an integer literal or a parameter in params
+func limitValue(params map[string]interface{}, limit string) (int, bool) {
+	if name, ok := strings.CutPrefix(limit, "$"); ok {
+		value, ok := params[name].(int)
+		return value, ok
+	}
+	value, err := strconv.Atoi(limit)
+	return value, err == nil
+}
+
+// executeTraversalQuery runs a traversal query. If ctx is collecting a cost estimate (see graph.WithCostEstimate),
+// the query is EXPLAINed instead of run: its plan's estimates are recorded and no records are returned.
+func (s *Neo4jStore) executeTraversalQuery(ctx context.Context, query string, params map[string]interface{}) (*neo4j.EagerResult, error) {
+	estimate := graph.CostEstimateFromContext(ctx)
+	if estimate == nil {
+		return neo4j.ExecuteQuery(ctx, s.driver, query, params, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase(""), txMetadata(ctx))
+	}
+
+	result, err := neo4j.ExecuteQuery(ctx, s.driver, "EXPLAIN "+query, params, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase(""), txMetadata(ctx))
+	if err != nil {
+		return nil, err
+	}
+	if result.Summary == nil || result.Summary.Plan() == nil {
+		return nil, fmt.Errorf("no query plan was returned")
+	}
+	estimate.Add(queryCostFromPlan(result.Summary.Plan()))
+	return &neo4j.EagerResult{Keys: result.Keys, Summary: result.Summary}, nil
+}
+
+// queryCostFromPlan summarises the planner's row estimates for a query plan. Database hits are only known once a
+// query has run, so the largest operator estimate stands in for the work involved.
+func queryCostFromPlan(plan neo4j.Plan) graph.QueryCost {
+	cost := graph.QueryCost{
+		Operator:      plan.Operator(),
+		EstimatedRows: estimatedRows(plan),
+	}
+	pending := []neo4j.Plan{plan}
+	for len(pending) > 0 {
+		p := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		if rows := estimatedRows(p); rows > cost.MaxOperatorRows {
+			cost.MaxOperatorRows = rows
+		}
+		pending = append(pending, p.Children()...)
+	}
+	return cost
+}
+
+// estimatedRows returns the EstimatedRows argument of a plan operator, or 0 if it has none
+func estimatedRows(plan neo4j.Plan) float64 {
+	switch rows := plan.Arguments()["EstimatedRows"].(type) {
+	case float64:
+		return rows
+	case int64:
+		return float64(rows)
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,108 @@
+package neo4j
+
+import (
+	"context"
+	"testing"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sammcj/mcp-graph/internal/graph"
+)
+
+func TestPathResultFromNeo4jPath(t *testing.T) {
+	path := neo4j.Path{
+		Nodes: []neo4j.Node{
+			{ElementId: "4:abc:1", Labels: []string{"Function"}, Props: map[string]any{"name": "main"}},
+			{ElementId: "4:abc:2", Labels: []string{"File"}, Props: map[string]any{"path": "main.go"}},
+		},
+		Relationships: []neo4j.Relationship{
+			{ElementId: "5:abc:1", StartElementId: "4:abc:1", EndElementId: "4:abc:2", Type: "DEFINED_IN", Props: map[string]any{}},
+		},
+	}
+
+	result := pathResultFromNeo4jPath(path)
+
+	assert.True(t, result.Found)
+	assert.Equal(t, 1, result.Length)
+	assert.Len(t, result.Nodes, 2)
+	assert.Equal(t, "4:abc:2", result.Nodes[1].Properties["id"])
+	assert.Equal(t, "main.go", result.Nodes[1].Properties["path"])
+	assert.Equal(t, "DEFINED_IN", result.Relationships[0].Type)
+	assert.Equal(t, "4:abc:1", result.Relationships[0].StartNode)
+}
+
+func TestEmptyPathResult(t *testing.T) {
+	result := emptyPathResult()
+
+	assert.False(t, result.Found)
+	assert.NotNil(t, result.Nodes)
+	assert.NotNil(t, result.Relationships)
+}
+
+func TestNearestByLabelQuery(t *testing.T) {
+	from := graph.EntityLocator{Labels: []string{"Function"}, IdentifyingProperties: map[string]interface{}{"name": "main"}}
+
+	// With APOC the search is breadth-first and stops at the first match
+	query := nearestByLabelQuery(from, "File", []string{"DEFINED_IN"}, 3, true)
+	assert.Contains(t, query, "WITH start LIMIT 1")
+	assert.Contains(t, query, "CALL apoc.path.expandConfig(start, {")
+	assert.Contains(t, query, "labelFilter: $labelFilter")
+	assert.Contains(t, query, "bfs: true, minLevel: 1, maxLevel: $maxDepth, limit: 1")
+	assert.NotContains(t, query, "ORDER BY")
+
+	// Without it, the variable-length match is bounded by maxDepth
+	query = nearestByLabelQuery(from, "File", []string{"DEFINED_IN"}, 3, false)
+	assert.Contains(t, query, "WITH start LIMIT 1")
+	assert.Contains(t, query, "MATCH path = (start)-[:DEFINED_IN*1..3]-(target:`File`)")
+}
+
+func TestOrderAndLimit(t *testing.T) {
+	params := map[string]interface{}{}
+	assert.Equal(t, "ORDER BY depth\n        LIMIT 500", orderAndLimit(context.Background(), params, "depth", "500"))
+	assert.Equal(t, "LIMIT $limit", orderAndLimit(context.Background(), params, "", "$limit"))
+	assert.NotContains(t, params, "sampleSize")
+
+	// Sampling replaces the order and limit
+	ctx := graph.WithSample(context.Background(), 25)
+	assert.Equal(t, "ORDER BY rand()\n        LIMIT $sampleSize", orderAndLimit(ctx, params, "depth", "500"))
+	assert.Equal(t, 25, params["sampleSize"])
+
+	// Samples larger than the limit are clamped to it, whether it's a literal or a parameter
+	ctx = graph.WithSample(context.Background(), 1000)
+	orderAndLimit(ctx, params, "depth", "500")
+	assert.Equal(t, 500, params["sampleSize"])
+	params["limit"] = 10
+	orderAndLimit(ctx, params, "", "$limit")
+	assert.Equal(t, 10, params["sampleSize"])
+}
+
+// fakePlan is a query plan operator with a row estimate
+type fakePlan struct {
+	operator string
+	rows     float64
+	children []neo4j.Plan
+}
+
+func (p fakePlan) Operator() string { return p.operator }
+func (p fakePlan) Arguments() map[string]any {
+	return map[string]any{"EstimatedRows": p.rows}
+}
+func (p fakePlan) Identifiers() []string  { return nil }
+func (p fakePlan) Children() []neo4j.Plan { return p.children }
+
+func TestQueryCostFromPlan(t *testing.T) {
+	plan := fakePlan{operator: "ProduceResults", rows: 12, children: []neo4j.Plan{
+		fakePlan{operator: "Top", rows: 12, children: []neo4j.Plan{
+			fakePlan{operator: "VarLengthExpand(All)", rows: 4800, children: []neo4j.Plan{
+				fakePlan{operator: "NodeIndexSeek", rows: 1},
+			}},
+		}},
+	}}
+
+	cost := queryCostFromPlan(plan)
+
+	assert.Equal(t, "ProduceResults", cost.Operator)
+	assert.Equal(t, 12.0, cost.EstimatedRows)
+	assert.Equal(t, 4800.0, cost.MaxOperatorRows)
+}
@@ -0,0 +1,24 @@
+package graph
+
+// Keys of the canonical node map. Every node a Store returns as a map (from GetNode and GetNodesByIDs, and
+// nodes within Query results) holds its ID under NodeIDKey and its labels under NodeLabelsKey, alongside its
+// properties, whichever backend produced it. Dgraph nodes are labelled with their type.
+const (
+	NodeIDKey     = "id"
+	NodeLabelsKey = "labels"
+)
+
+// NewNodeMap builds the canonical map for a node from its ID, labels and properties. The ID and labels take
+// precedence over properties with the same keys. Labels are never nil, so they always encode as a JSON array.
+func NewNodeMap(id string, labels []string, properties map[string]interface{}) map[string]interface{} {
+	node := make(map[string]interface{}, len(properties)+2)
+	for k, v := range properties {
+		node[k] = v
+	}
+	if labels == nil {
+		labels = []string{}
+	}
+	node[NodeIDKey] = id
+	node[NodeLabelsKey] = labels
+	return node
+}
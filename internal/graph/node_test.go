@@ -0,0 +1,22 @@
+package graph
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewNodeMap(t *testing.T) {
+	node := NewNodeMap("4:abc:1", []string{"Document"}, map[string]interface{}{"title": "Design", "id": "stale"})
+	assert.Equal(t, map[string]interface{}{
+		"id":     "4:abc:1",
+		"labels": []string{"Document"},
+		"title":  "Design",
+	}, node)
+
+	// Nodes without labels still encode labels as an array
+	encoded, err := json.Marshal(NewNodeMap("0x1", nil, nil))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"id": "0x1", "labels": []}`, string(encoded))
+}
@@ -0,0 +1,30 @@
+package graph
+
+import "time"
+
+// DefaultRelationshipSource is the source recorded on new relationships that don't give one, unless a store is
+// configured with another.
+const DefaultRelationshipSource = "manual"
+
+// NewRelationshipProperties returns a copy of the properties of a relationship being created, annotated with
+// timestamps and provenance: createdAt and lastModifiedAt are set to now, and source to defaultSource unless the
+// properties already include one. Every edge-creation path uses it, so relationships are annotated uniformly.
+func NewRelationshipProperties(properties map[string]interface{}, now time.Time, defaultSource string) map[string]interface{} {
+	props := UpdatedRelationshipProperties(properties, now)
+	props["createdAt"] = now
+	if source, ok := props["source"].(string); !ok || source == "" {
+		props["source"] = defaultSource
+	}
+	return props
+}
+
+// UpdatedRelationshipProperties returns a copy of the properties to merge into an existing relationship, with
+// lastModifiedAt set to now.
+func UpdatedRelationshipProperties(properties map[string]interface{}, now time.Time) map[string]interface{} {
+	props := make(map[string]interface{}, len(properties)+3)
+	for k, v := range properties {
+		props[k] = v
+	}
+	props["lastModifiedAt"] = now
+	return props
+}
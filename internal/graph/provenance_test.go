@@ -0,0 +1,40 @@
+package graph
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewRelationshipProperties(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	input := map[string]interface{}{"weight": 2, "createdAt": "2020-01-01"}
+
+	props := NewRelationshipProperties(input, now, DefaultRelationshipSource)
+	assert.Equal(t, map[string]interface{}{
+		"weight":         2,
+		"createdAt":      now,
+		"lastModifiedAt": now,
+		"source":         "manual",
+	}, props)
+
+	// The input is left unchanged
+	assert.Equal(t, "2020-01-01", input["createdAt"])
+	assert.NotContains(t, input, "source")
+
+	// A given source is kept
+	props = NewRelationshipProperties(map[string]interface{}{"source": "static-analysis"}, now, DefaultRelationshipSource)
+	assert.Equal(t, "static-analysis", props["source"])
+
+	// Nil properties are allowed
+	props = NewRelationshipProperties(nil, now, "agent-inference")
+	assert.Equal(t, "agent-inference", props["source"])
+}
+
+func TestUpdatedRelationshipProperties(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	props := UpdatedRelationshipProperties(map[string]interface{}{"weight": 2}, now)
+	assert.Equal(t, map[string]interface{}{"weight": 2, "lastModifiedAt": now}, props)
+}
@@ -0,0 +1,79 @@
+package graph
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// QueryLanguage identifies the query language a store accepts.
+type QueryLanguage string
+
+// Query languages accepted by the supported backends
+const (
+	QueryLanguageCypher QueryLanguage = "Cypher"
+	QueryLanguageDQL    QueryLanguage = "DQL"
+)
+
+// queryLanguageBackends names the backend that expects each query language, for error messages
+var queryLanguageBackends = map[QueryLanguage]string{
+	QueryLanguageCypher: "Neo4j",
+	QueryLanguageDQL:    "Dgraph",
+}
+
+var (
+	// cypherClausePattern matches the clauses a Cypher query normally starts with
+	cypherClausePattern = regexp.MustCompile(`(?i)^(OPTIONAL\s+MATCH|MATCH|MERGE|CREATE|RETURN|WITH|UNWIND|CALL|SHOW|EXPLAIN|PROFILE|USE|LOAD\s+CSV|DETACH\s+DELETE|DELETE|FOREACH)\b`)
+
+	// dqlBlockPattern matches the start of a DQL query or schema block, e.g. "{", "query q($a: string) {" or "schema {"
+	dqlBlockPattern = regexp.MustCompile(`(?i)^(\{|(query|schema)\b[^{]*\{)`)
+
+	// dqlMarkerPattern matches constructs that only appear in DQL
+	dqlMarkerPattern = regexp.MustCompile(`\bfunc\s*:|@filter\b|@recurse\b|@cascade\b|@facets\b|\buid\s*\(`)
+)
+
+// QuoteCypherIdentifier quotes a label, relationship type, property key or other identifier with backticks so it
+// can be safely interpolated into a Cypher query. Example: "name" -> "`name`"
+func QuoteCypherIdentifier(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+// DetectQueryLanguage makes a best guess at the language of a query from its leading clause and any
+// language-specific syntax. Returns an empty string if the language can't be determined.
+func DetectQueryLanguage(query string) QueryLanguage {
+	trimmed := strings.TrimSpace(stripLeadingComments(query))
+	switch {
+	case cypherClausePattern.MatchString(trimmed):
+		return QueryLanguageCypher
+	case dqlBlockPattern.MatchString(trimmed), dqlMarkerPattern.MatchString(trimmed):
+		return QueryLanguageDQL
+	default:
+		return ""
+	}
+}
+
+// stripLeadingComments removes whole-line comments ("//" in Cypher, "#" in DQL) from the start of a query
+func stripLeadingComments(query string) string {
+	for {
+		trimmed := strings.TrimLeft(query, " \t\r\n")
+		if !strings.HasPrefix(trimmed, "//") && !strings.HasPrefix(trimmed, "#") {
+			return trimmed
+		}
+		newline := strings.IndexByte(trimmed, '\n')
+		if newline < 0 {
+			return ""
+		}
+		query = trimmed[newline+1:]
+	}
+}
+
+// CheckQueryLanguage returns an error explaining the mismatch if the query looks like it is written in a
+// different language from the one the store expects, or nil if it matches or its language is unknown.
+func CheckQueryLanguage(expected QueryLanguage, query string) error {
+	detected := DetectQueryLanguage(query)
+	if detected == "" || detected == expected {
+		return nil
+	}
+	return fmt.Errorf("this server is running the %s backend which expects %s; your query looks like %s",
+		queryLanguageBackends[expected], expected, detected)
+}
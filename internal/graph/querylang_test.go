@@ -0,0 +1,53 @@
+package graph
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectQueryLanguage(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  QueryLanguage
+	}{
+		{"cypher match", "MATCH (n:Service) RETURN n", QueryLanguageCypher},
+		{"cypher lower case", "  optional match (n) return n", QueryLanguageCypher},
+		{"cypher after comment", "// all services\nMATCH (n:Service) RETURN n", QueryLanguageCypher},
+		{"cypher map literal", "RETURN {name: 'a'} AS m", QueryLanguageCypher},
+		{"dql block", "{ documents(func: type(Document)) { uid title } }", QueryLanguageDQL},
+		{"dql named query", "query docs($t: string) { documents(func: eq(title, $t)) { uid } }", QueryLanguageDQL},
+		{"dql after comment", "# find docs\n{ q(func: has(title)) { uid } }", QueryLanguageDQL},
+		{"dql schema", "schema {}", QueryLanguageDQL},
+		{"unknown", "SELECT * FROM nodes", ""},
+		{"empty", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, DetectQueryLanguage(tt.query))
+		})
+	}
+}
+
+func TestCheckQueryLanguage(t *testing.T) {
+	err := CheckQueryLanguage(QueryLanguageCypher, "{ q(func: has(title)) { uid } }")
+	if assert.Error(t, err) {
+		assert.Equal(t, "this server is running the Neo4j backend which expects Cypher; your query looks like DQL", err.Error())
+	}
+
+	err = CheckQueryLanguage(QueryLanguageDQL, "MATCH (n) RETURN n")
+	if assert.Error(t, err) {
+		assert.Equal(t, "this server is running the Dgraph backend which expects DQL; your query looks like Cypher", err.Error())
+	}
+
+	assert.NoError(t, CheckQueryLanguage(QueryLanguageCypher, "MATCH (n) RETURN n"))
+	assert.NoError(t, CheckQueryLanguage(QueryLanguageCypher, "SELECT 1"))
+}
+
+func TestQuoteCypherIdentifier(t *testing.T) {
+	assert.Equal(t, "`name`", QuoteCypherIdentifier("name"))
+	assert.Equal(t, "`file path`", QuoteCypherIdentifier("file path"))
+	assert.Equal(t, "`a``b`", QuoteCypherIdentifier("a`b"))
+}
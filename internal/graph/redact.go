@@ -0,0 +1,96 @@
+package graph
+
+import (
+	"bytes"
+	"encoding/json"
+	"path"
+	"strings"
+)
+
+// RedactedValue replaces the values of redacted properties.
+const RedactedValue = "***REDACTED***"
+
+// Redactor replaces the values of sensitive properties (e.g. credentials, connection strings)
+// before results are returned to clients.
+type Redactor struct {
+	patterns []string
+}
+
+// NewRedactor creates a redactor for property keys matching any of the given patterns.
+// Patterns use path.Match syntax (e.g. "password", "*secret*", "connection*") and match case-insensitively.
+func NewRedactor(patterns []string) *Redactor {
+	lowered := make([]string, 0, len(patterns))
+	for _, p := range patterns {
+		if p = strings.TrimSpace(p); p != "" {
+			lowered = append(lowered, strings.ToLower(p))
+		}
+	}
+	return &Redactor{patterns: lowered}
+}
+
+// Enabled reports whether the redactor has any patterns.
+func (r *Redactor) Enabled() bool {
+	return r != nil && len(r.patterns) > 0
+}
+
+// Matches reports whether a property key should be redacted.
+func (r *Redactor) Matches(key string) bool {
+	if !r.Enabled() {
+		return false
+	}
+	key = strings.ToLower(key)
+	for _, p := range r.patterns {
+		if matched, _ := path.Match(p, key); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// Redact returns a copy of value with the values of matching keys replaced, at any depth of nested maps and slices.
+func (r *Redactor) Redact(value interface{}) interface{} {
+	if !r.Enabled() {
+		return value
+	}
+	switch v := value.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for k, item := range v {
+			if r.Matches(k) {
+				result[k] = RedactedValue
+			} else {
+				result[k] = r.Redact(item)
+			}
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, item := range v {
+			result[i] = r.Redact(item)
+		}
+		return result
+	default:
+		return v
+	}
+}
+
+// RedactJSON redacts a JSON document. Data that isn't valid JSON is returned unchanged with ok set to false.
+func (r *Redactor) RedactJSON(data []byte) (redacted []byte, ok bool) {
+	if !r.Enabled() {
+		return data, true
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber() // Preserve numbers exactly
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return data, false
+	}
+	// Encode without escaping HTML, so that text such as "<b>" or "a & b" is returned as it was given
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(r.Redact(value)); err != nil {
+		return data, false
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), true
+}
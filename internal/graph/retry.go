@@ -0,0 +1,71 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// maxRetryBackoff caps the wait between connection attempts as the backoff doubles.
+const maxRetryBackoff = 30 * time.Second
+
+// minRetryBackoff is the shortest wait between connection attempts, so that a zero or tiny backoff doesn't retry in
+// a tight loop.
+const minRetryBackoff = 100 * time.Millisecond
+
+// Pinger is implemented by stores that can check their database is reachable.
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// RetryPolicy controls how connecting to a database is retried at startup.
+type RetryPolicy struct {
+	Attempts int           // Total number of attempts; values below 1 mean a single attempt
+	Backoff  time.Duration // Wait before the second attempt, at least 100ms, doubling after each failure up to 30s
+
+	// OnRetry, if set, is called after each failed attempt that will be retried.
+	OnRetry func(attempt int, err error, wait time.Duration)
+
+	// Retryable, if set, reports whether an error is worth retrying. Attempts stop at the first error
+	// it rejects, e.g. a misconfiguration that waiting won't fix.
+	Retryable func(err error) bool
+}
+
+// WaitUntilReachable pings until it succeeds, the attempts are exhausted or the context is cancelled.
+// It returns the last ping error if the database never became reachable.
+func WaitUntilReachable(ctx context.Context, p Pinger, policy RetryPolicy) error {
+	attempts := policy.Attempts
+	if attempts < 1 {
+		attempts = 1
+	}
+	wait := policy.Backoff
+	if wait < minRetryBackoff {
+		wait = minRetryBackoff
+	}
+
+	var err error
+	attempt := 1
+	for ; attempt <= attempts; attempt++ {
+		if err = p.Ping(ctx); err == nil {
+			return nil
+		}
+		if attempt == attempts || (policy.Retryable != nil && !policy.Retryable(err)) {
+			break
+		}
+
+		if policy.OnRetry != nil {
+			policy.OnRetry(attempt, err, wait)
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("gave up waiting for database: %w", ctx.Err())
+		case <-time.After(wait):
+		}
+
+		wait *= 2
+		if wait > maxRetryBackoff {
+			wait = maxRetryBackoff
+		}
+	}
+	return fmt.Errorf("database not reachable after %d attempt(s): %w", attempt, err)
+}
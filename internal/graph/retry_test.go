@@ -0,0 +1,90 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// countingPinger fails until it has been pinged failures+1 times
+type countingPinger struct {
+	failures int
+	calls    int
+}
+
+func (p *countingPinger) Ping(ctx context.Context) error {
+	p.calls++
+	if p.calls <= p.failures {
+		return errors.New("connection refused")
+	}
+	return nil
+}
+
+func TestWaitUntilReachable_SucceedsAfterRetries(t *testing.T) {
+	pinger := &countingPinger{failures: 2}
+	var retries []int
+
+	err := WaitUntilReachable(context.Background(), pinger, RetryPolicy{
+		Attempts: 5,
+		Backoff:  time.Millisecond,
+		OnRetry: func(attempt int, err error, wait time.Duration) {
+			retries = append(retries, attempt)
+		},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, pinger.calls)
+	assert.Equal(t, []int{1, 2}, retries)
+}
+
+func TestWaitUntilReachable_GivesUp(t *testing.T) {
+	pinger := &countingPinger{failures: 10}
+
+	err := WaitUntilReachable(context.Background(), pinger, RetryPolicy{Attempts: 3, Backoff: time.Millisecond})
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "after 3 attempt(s)")
+	assert.Contains(t, err.Error(), "connection refused")
+	assert.Equal(t, 3, pinger.calls)
+}
+
+func TestWaitUntilReachable_SingleAttemptByDefault(t *testing.T) {
+	pinger := &countingPinger{failures: 1}
+
+	err := WaitUntilReachable(context.Background(), pinger, RetryPolicy{})
+
+	assert.Error(t, err)
+	assert.Equal(t, 1, pinger.calls)
+}
+
+func TestWaitUntilReachable_StopsOnNonRetryableError(t *testing.T) {
+	pinger := &countingPinger{failures: 10}
+
+	err := WaitUntilReachable(context.Background(), pinger, RetryPolicy{
+		Attempts:  5,
+		Backoff:   time.Millisecond,
+		Retryable: func(err error) bool { return false },
+	})
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "after 1 attempt(s)")
+	assert.Equal(t, 1, pinger.calls)
+}
+
+func TestWaitUntilReachable_MinimumBackoff(t *testing.T) {
+	pinger := &countingPinger{failures: 1}
+	var waits []time.Duration
+
+	err := WaitUntilReachable(context.Background(), pinger, RetryPolicy{
+		Attempts: 2,
+		OnRetry: func(attempt int, err error, wait time.Duration) {
+			waits = append(waits, wait)
+		},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []time.Duration{minRetryBackoff}, waits)
+}
@@ -0,0 +1,22 @@
+package graph
+
+import "context"
+
+// sampleSizeKey is the context key for result sampling.
+type sampleSizeKey struct{}
+
+// WithSample returns a context asking stores to return a pseudo-random sample of up to size results from their
+// traversal and search queries, instead of the first results in their usual order. Samples are non-deterministic, so
+// repeating a request may return different results. Stores that can't sample ignore it.
+func WithSample(ctx context.Context, size int) context.Context {
+	return context.WithValue(ctx, sampleSizeKey{}, size)
+}
+
+// SampleSizeFromContext returns the sample size requested for ctx, or 0 if results aren't being sampled.
+func SampleSizeFromContext(ctx context.Context) int {
+	size, _ := ctx.Value(sampleSizeKey{}).(int)
+	if size < 0 {
+		return 0
+	}
+	return size
+}
@@ -169,6 +169,60 @@ type RelationshipInput struct {
 	Properties                    map[string]interface{} `json:"properties"`       // Properties to set/update on the relationship
 }
 
+// EntityLocator identifies a single entity by its labels and identifying properties.
+type EntityLocator struct {
+	Labels                []string               `json:"labels"`
+	IdentifyingProperties map[string]interface{} `json:"identifyingProperties"`
+}
+
+// Relationship directions relative to an entity.
+const (
+	DirectionOutgoing = "outgoing" // (entity)-[r]->()
+	DirectionIncoming = "incoming" // (entity)<-[r]-()
+	DirectionBoth     = "both"     // (entity)-[r]-()
+)
+
+// Entity statuses, tracking how far an entity has been analysed (see BaseNode.Status).
+const (
+	StatusStub              = "stub"
+	StatusPartiallyAnalysed = "partially_analysed"
+	StatusFullyAnalysed     = "fully_analysed"
+	StatusDeprecated        = "deprecated"
+)
+
+// EntityStatuses lists the valid entity statuses.
+var EntityStatuses = []string{StatusStub, StatusPartiallyAnalysed, StatusFullyAnalysed, StatusDeprecated}
+
+// IsValidEntityStatus reports whether status is one of EntityStatuses.
+func IsValidEntityStatus(status string) bool {
+	for _, s := range EntityStatuses {
+		if status == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Property filter operators supported by PropertyFilter.
+const (
+	FilterOpEquals      = "eq"       // Property equals the value (default)
+	FilterOpNotEquals   = "neq"      // Property does not equal the value
+	FilterOpGreaterThan = "gt"       // Property is greater than the value
+	FilterOpGreaterOrEq = "gte"      // Property is greater than or equal to the value
+	FilterOpLessThan    = "lt"       // Property is less than the value
+	FilterOpLessOrEq    = "lte"      // Property is less than or equal to the value
+	FilterOpContains    = "contains" // List property contains the value (e.g. tags)
+	FilterOpExists      = "exists"   // Property is set; the value is ignored
+	FilterOpMissing     = "missing"  // Property is not set; the value is ignored
+)
+
+// PropertyFilter represents a single condition on an entity property, used by find_entities and count_entities.
+type PropertyFilter struct {
+	Property string      `json:"property"`
+	Operator string      `json:"operator,omitempty"` // One of the FilterOp constants; defaults to "eq"
+	Value    interface{} `json:"value"`
+}
+
 // EntityDetails represents the output for get_entity_details.
 type EntityDetails struct {
 	Labels     []string               `json:"labels"`
@@ -189,12 +243,69 @@ type NeighborsResult struct {
 	Neighbors   []Neighbor    `json:"neighbors"`
 }
 
+// EntityRelationship represents one of an entity's relationships together with the node at its other end.
+type EntityRelationship struct {
+	ID         string                 `json:"id"` // Unique ID (e.g., elementId)
+	Type       string                 `json:"type"`
+	Direction  string                 `json:"direction"` // "incoming" or "outgoing", relative to the entity
+	Properties map[string]interface{} `json:"properties"`
+	Node       EntityDetails          `json:"node"` // The adjacent node
+}
+
+// EntityWithRelationships represents the output for get_entity_with_relationships.
+type EntityWithRelationships struct {
+	Entity        EntityDetails        `json:"entity"`
+	Relationships []EntityRelationship `json:"relationships"`
+}
+
+// DefinitionLocation represents the output for get_definition_location: the File an entity is DEFINED_IN
+// and the lines it spans there. Lines are omitted if the relationship doesn't record them.
+type DefinitionLocation struct {
+	Entity    EntityDetails `json:"entity"`
+	File      EntityDetails `json:"file"`
+	StartLine int           `json:"startLine,omitempty"`
+	EndLine   int           `json:"endLine,omitempty"`
+}
+
 // DependencyResult represents the output for find_dependencies/find_dependents.
 type DependencyResult struct {
-	TargetNode EntityDetails   `json:"targetNode"` // The node for which dependencies/dependents were found
-	Results    []EntityDetails `json:"results"`    // List of dependencies or dependents
-	Depth      int             `json:"depth"`      // The depth searched
-	Direction  string          `json:"direction"`  // "dependencies" or "dependents"
+	TargetNode EntityDetails     `json:"targetNode"` // The node for which dependencies/dependents were found
+	Results    []DependencyEntry `json:"results"`    // List of dependencies or dependents
+	Depth      int               `json:"depth"`      // The depth searched
+	Direction  string            `json:"direction"`  // "dependencies" or "dependents"
+}
+
+// HierarchyEntry represents an ancestor or descendant of an entity in a hierarchy.
+type HierarchyEntry struct {
+	Depth  int           `json:"depth"` // 1 for parents or children, 2 for grandparents or grandchildren, and so on
+	Entity EntityDetails `json:"entity"`
+}
+
+// ReachableEntity represents an entity reachable from another, as returned by find_reachable.
+type ReachableEntity struct {
+	Distance int           `json:"distance"` // Fewest relationships between the entities, in either direction
+	Entity   EntityDetails `json:"entity"`
+}
+
+// DependencyEntry represents a dependency or dependent together with how it is connected to the target node.
+type DependencyEntry struct {
+	EntityDetails
+	Depth             int                      `json:"depth"`                   // Length of the shortest path to the target node
+	RelationshipTypes []string                 `json:"relationshipTypes"`       // Types of the relationships connecting this entity to the paths found (e.g. its incoming CALLS for a dependency)
+	Relationships     []ConnectingRelationship `json:"relationships,omitempty"` // Relationships directly connecting it to the target node (depth 1 only)
+}
+
+// ConnectingRelationship represents a relationship directly connecting a dependency or dependent to the target node.
+type ConnectingRelationship struct {
+	Type       string                 `json:"type"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+// CommonDependenciesResult represents the output for common_dependencies.
+type CommonDependenciesResult struct {
+	TargetNodes []EntityDetails `json:"targetNodes"` // The nodes whose dependencies were intersected
+	Results     []EntityDetails `json:"results"`     // Dependencies shared by every target node
+	Depth       int             `json:"depth"`       // The depth searched
 }
 
 // SubgraphNode represents a node within a subgraph result, simplified for visualisation.
@@ -219,3 +330,257 @@ type SubgraphResult struct {
 	Nodes         []SubgraphNode         `json:"nodes"`
 	Relationships []SubgraphRelationship `json:"relationships"`
 }
+
+// DuplicateGroup represents a set of entities sharing the same values for the key properties.
+type DuplicateGroup struct {
+	Key      map[string]interface{} `json:"key"`      // The shared key property values
+	Entities []EntityDetails        `json:"entities"` // The entities sharing the key (at least two)
+}
+
+// RequiredRelationshipRule requires every entity with a label to have at least one relationship of a type in a
+// direction (DirectionOutgoing if empty), e.g. every Function to be DEFINED_IN a File.
+type RequiredRelationshipRule struct {
+	Label            string `json:"label"`
+	RelationshipType string `json:"relationshipType"`
+	Direction        string `json:"direction,omitempty"`
+}
+
+// RelationshipEndpointRule restricts the labels of the nodes at either end of relationships of a type, e.g.
+// DEFINED_IN relationships to end at a File. A node satisfies a side if it has any of that side's labels; an empty
+// side is unrestricted.
+type RelationshipEndpointRule struct {
+	RelationshipType string   `json:"relationshipType"`
+	StartLabels      []string `json:"startLabels,omitempty"`
+	EndLabels        []string `json:"endLabels,omitempty"`
+}
+
+// IntegrityRules are the rules CheckIntegrity checks the relationship graph against.
+type IntegrityRules struct {
+	RequiredRelationships []RequiredRelationshipRule `json:"requiredRelationships"`
+	RelationshipEndpoints []RelationshipEndpointRule `json:"relationshipEndpoints"`
+}
+
+// MissingRelationshipIssue lists the entities breaking a RequiredRelationshipRule.
+type MissingRelationshipIssue struct {
+	Rule     RequiredRelationshipRule `json:"rule"`
+	Count    int64                    `json:"count"`    // Number of entities breaking the rule
+	Entities []EntityDetails          `json:"entities"` // The first of them, ordered by ID
+}
+
+// UnexpectedEndpointIssue lists the relationships breaking a RelationshipEndpointRule.
+type UnexpectedEndpointIssue struct {
+	Rule          RelationshipEndpointRule `json:"rule"`
+	Count         int64                    `json:"count"`         // Number of relationships breaking the rule
+	Relationships []RelationshipDetails    `json:"relationships"` // The first of them, ordered by ID
+}
+
+// TransitiveClosureResult reports the closure relationships written by MaterializeTransitiveClosure.
+type TransitiveClosureResult struct {
+	RelationshipType string `json:"relationshipType"` // Relationship type whose paths were followed
+	ClosureType      string `json:"closureType"`      // Type of the closure relationships
+	Pairs            int    `json:"pairs"`            // Pairs of entities connected by a path, each of which now has a closure relationship
+	Created          int    `json:"created"`          // Closure relationships created; the rest already existed
+}
+
+// IntegrityReport lists the rules the relationship graph breaks, as returned by check_integrity. Rules that hold
+// are left out.
+type IntegrityReport struct {
+	RulesChecked         int                        `json:"rulesChecked"`
+	IssueCount           int64                      `json:"issueCount"` // Total number of entities and relationships breaking a rule
+	MissingRelationships []MissingRelationshipIssue `json:"missingRelationships"`
+	UnexpectedEndpoints  []UnexpectedEndpointIssue  `json:"unexpectedEndpoints"`
+}
+
+// PathResult represents a path between two entities.
+type PathResult struct {
+	Found         bool                   `json:"found"`         // Whether a path was found
+	Nodes         []EntityDetails        `json:"nodes"`         // Nodes along the path, from start to end
+	Relationships []SubgraphRelationship `json:"relationships"` // Relationships along the path, in order
+	Length        int                    `json:"length"`        // Number of relationships in the path
+}
+
+// ShortestPathResult represents the shortest path between two entities, either by hop count or by total weight.
+type ShortestPathResult struct {
+	PathResult
+	Algorithm   string   `json:"algorithm"`             // "dijkstra" (APOC, weighted) or "shortestPath" (unweighted)
+	TotalWeight *float64 `json:"totalWeight,omitempty"` // Sum of the weight property along the path, for weighted searches
+}
+
+// SubgraphPage represents one page of a subgraph, for retrieving large subgraphs in bounded chunks.
+// Each page holds a slice of the subgraph's nodes and the relationships starting at those nodes.
+type SubgraphPage struct {
+	Nodes         []SubgraphNode         `json:"nodes"`
+	Relationships []SubgraphRelationship `json:"relationships"`
+	TotalNodes    int                    `json:"totalNodes"` // Number of nodes in the whole subgraph
+	HasMore       bool                   `json:"hasMore"`    // Whether further pages follow
+	NextOffset    int                    `json:"-"`          // Offset of the next page, if HasMore
+}
+
+// PropertyChange is the value of a property before and after an update. Old is null for properties that were added.
+type PropertyChange struct {
+	Old interface{} `json:"old"`
+	New interface{} `json:"new"`
+}
+
+// EntityUpsertResult is an entity found or created by FindOrCreateEntityWithChanges, along with whether it was
+// created and the properties the merge changed.
+type EntityUpsertResult struct {
+	EntityDetails
+	Created           bool                      `json:"created"`
+	ChangedProperties map[string]PropertyChange `json:"changedProperties"` // Empty for no-op merges; every property for new entities
+}
+
+// EntityWithRelationship is an entity upserted by UpsertEntityWithRelationship, along with the properties of the
+// relationship connecting it to the existing target node.
+type EntityWithRelationship struct {
+	Entity       EntityDetails          `json:"entity"`
+	Relationship map[string]interface{} `json:"relationship"`
+}
+
+// RelationshipEndpoint identifies the node at one end of a relationship.
+type RelationshipEndpoint struct {
+	ID     string   `json:"id"`     // Unique ID (e.g., elementId)
+	Labels []string `json:"labels"` // Node labels
+}
+
+// RelationshipDetails represents a relationship together with its endpoints.
+type RelationshipDetails struct {
+	ID         string                 `json:"id"` // Unique ID (e.g., elementId)
+	Type       string                 `json:"type"`
+	StartNode  RelationshipEndpoint   `json:"startNode"`
+	EndNode    RelationshipEndpoint   `json:"endNode"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+// RelationshipList represents one page of relationships, as returned by list_relationships_by_type.
+type RelationshipList struct {
+	Relationships []RelationshipDetails `json:"relationships"`
+	Total         int64                 `json:"total"` // Number of matching relationships across all pages
+	Skip          int                   `json:"skip"`
+	Limit         int                   `json:"limit"`
+}
+
+// QueryColumn describes a column of a query result and the kind of value it holds, using Neo4j type names
+// (e.g. "String", "Integer", "DateTime", "Node"). "Null" means every value was null and "Any" means the
+// non-null values were of more than one kind.
+type QueryColumn struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// TypedQueryResult represents query results along with the type of each column, so that clients can
+// tell, for example, a string from a datetime after both have been converted to JSON.
+type TypedQueryResult struct {
+	Columns []QueryColumn   `json:"columns"`
+	Rows    [][]interface{} `json:"rows"` // Values in the same order as Columns
+}
+
+// Capabilities describes the optional server-side libraries available to a store.
+type Capabilities struct {
+	APOC bool `json:"apoc"` // APOC procedures (e.g. apoc.path.subgraphAll)
+	GDS  bool `json:"gds"`  // Graph Data Science library (e.g. gds.pageRank)
+}
+
+// PropertyUsage describes how often a property key is used by the entities with a label, from a sample of them.
+type PropertyUsage struct {
+	Key      string  `json:"key"`
+	Coverage float64 `json:"coverage"` // Fraction of the sampled entities that have the property
+	Unique   bool    `json:"unique"`   // Whether every sampled entity with the property has a different value
+}
+
+// LabelModel describes the entities with a label and how they are keyed.
+type LabelModel struct {
+	Label                 string          `json:"label"`
+	Count                 int64           `json:"count"`                 // Number of entities with the label
+	IdentifyingProperties []string        `json:"identifyingProperties"` // Suggested identifying property keys; empty if none could be inferred
+	Source                string          `json:"source,omitempty"`      // Where the suggestion came from: "constraint", "index" or "observed"
+	Constraints           [][]string      `json:"constraints"`           // Property keys of each uniqueness or key constraint on the label
+	Indexes               [][]string      `json:"indexes"`               // Property keys of each other index on the label
+	Properties            []PropertyUsage `json:"properties"`            // Property keys seen on the sampled entities, most common first
+}
+
+// EntityModel describes every label in use and its identifying-property conventions.
+type EntityModel struct {
+	Labels     []LabelModel `json:"labels"`     // Ordered by label
+	SampleSize int          `json:"sampleSize"` // Maximum number of entities per label whose properties were examined
+}
+
+// CentralityScore represents an entity and its centrality score.
+type CentralityScore struct {
+	Entity EntityDetails `json:"entity"`
+	Score  float64       `json:"score"`
+}
+
+// DependencyDepthResult represents the output for max_dependency_depth.
+type DependencyDepthResult struct {
+	Entity EntityDetails   `json:"entity"`
+	Depth  int             `json:"depth"`  // Length of the longest dependency chain; 0 if the entity has no dependencies
+	Chain  []EntityDetails `json:"chain"`  // Entities along one longest chain, in order, excluding the entity itself
+	Capped bool            `json:"capped"` // True if the depth reached the search limit, so the chain may go deeper
+}
+
+// CouplingMetric represents the fan-in, fan-out and instability of an entity, for coupling_metrics.
+type CouplingMetric struct {
+	Entity      EntityDetails `json:"entity"`
+	FanIn       int64         `json:"fanIn"`       // Number of incoming relationships
+	FanOut      int64         `json:"fanOut"`      // Number of outgoing relationships
+	Instability float64       `json:"instability"` // fanOut / (fanIn + fanOut); 0 for entities with no relationships
+}
+
+// SimilarEntity is an entity ranked by how many neighbours it shares with another.
+type SimilarEntity struct {
+	Entity          EntityDetails `json:"entity"`
+	SharedNeighbors int64         `json:"sharedNeighbors"` // Number of neighbours the entities have in common
+	Similarity      float64       `json:"similarity"`      // Jaccard similarity of the neighbour sets, from 0 to 1
+}
+
+// GraphOverview summarises the size and shape of the whole graph.
+type GraphOverview struct {
+	NodeCount         int64            `json:"nodeCount"`
+	RelationshipCount int64            `json:"relationshipCount"`
+	Density           float64          `json:"density"`           // relationshipCount / (nodeCount * (nodeCount - 1)); 0 with fewer than two nodes
+	Labels            map[string]int64 `json:"labels"`            // Node counts by label; a node with several labels is counted under each
+	RelationshipTypes map[string]int64 `json:"relationshipTypes"` // Relationship counts by type
+}
+
+// CentralityResult represents the output for centrality.
+type CentralityResult struct {
+	Algorithm string            `json:"algorithm"` // "pagerank" (GDS) or "degree" (Cypher fallback)
+	Results   []CentralityScore `json:"results"`
+}
+
+// GraphSnapshot is a self-contained copy of part of the graph: every node with any of Labels and every
+// relationship touching them, including those to nodes outside the snapshot.
+type GraphSnapshot struct {
+	Name          string                 `json:"name"`
+	CreatedAt     time.Time              `json:"createdAt"`
+	Labels        []string               `json:"labels"`
+	Nodes         []SnapshotNode         `json:"nodes"`
+	Relationships []SnapshotRelationship `json:"relationships"`
+}
+
+// SnapshotNode is a node within a snapshot. ID is its element ID when the snapshot was taken.
+type SnapshotNode struct {
+	ID         string                 `json:"id"`
+	Labels     []string               `json:"labels"`
+	Properties map[string]interface{} `json:"properties"`
+	Types      map[string]string      `json:"types,omitempty"` // Database type of properties JSON can't represent, e.g. "DateTime" or "List<Float>"
+}
+
+// SnapshotRelationship is a relationship within a snapshot. StartNode and EndNode are element IDs when the snapshot
+// was taken; at least one of them is a node in the snapshot.
+type SnapshotRelationship struct {
+	Type       string                 `json:"type"`
+	StartNode  string                 `json:"startNode"`
+	EndNode    string                 `json:"endNode"`
+	Properties map[string]interface{} `json:"properties"`
+	Types      map[string]string      `json:"types,omitempty"` // As in SnapshotNode
+}
+
+// RestoreResult represents the output for restore_snapshot.
+type RestoreResult struct {
+	NodesDeleted         int64 `json:"nodesDeleted"`         // Nodes with the snapshot's labels removed before restoring
+	NodesCreated         int64 `json:"nodesCreated"`         // Nodes recreated from the snapshot
+	RelationshipsCreated int64 `json:"relationshipsCreated"` // Relationships recreated from the snapshot
+	RelationshipsSkipped int64 `json:"relationshipsSkipped"` // Relationships to nodes outside the snapshot that no longer exist
+}
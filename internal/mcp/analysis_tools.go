@@ -0,0 +1,308 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/sammcj/mcp-graph/internal/graph"
+)
+
+// setupAnalysisTools configures the graph analysis tools
+func (s *Server) setupAnalysisTools() {
+	centralityTool := mcp.NewTool("centrality",
+		mcp.WithDescription("Ranks entities by importance (e.g. 'what are the most important modules?'). Runs PageRank via the Neo4j Graph Data Science (GDS) library when it is installed, otherwise falls back to degree centrality (relationship count) computed in Cypher. The 'algorithm' field of the result reports which was used."),
+		mcp.WithArray("labels",
+			mcp.Description("Optional list of labels restricting the nodes considered (e.g. ['Module', 'Service']). If omitted or empty, all nodes are considered."),
+			mcp.Items(map[string]interface{}{"type": "string"}),
+		),
+		mcp.WithArray("relationshipTypes",
+			mcp.Description("Optional list of relationship types to consider (e.g. ['DEPENDS_ON', 'CALLS']). If omitted or empty, all relationship types are considered."),
+			mcp.Items(map[string]interface{}{"type": "string"}),
+		),
+		mcp.WithNumber("topN",
+			mcp.Description("Number of highest-ranked entities to return. Defaults to 10 if not provided or invalid."),
+		),
+	)
+	s.addTool(centralityTool, s.handleCentralityTool)
+
+	relationshipTypeCountsTool := mcp.NewTool("relationship_type_counts",
+		mcp.WithDescription("Counts an entity's relationships by type (e.g. {\"CALLS\": 12, \"DEFINED_IN\": 1}), giving a compact connectivity profile for ranking or summarising the entity."),
+		mcp.WithArray("labels",
+			mcp.Required(),
+			mcp.Description("List of labels for the entity."),
+			mcp.Items(map[string]interface{}{"type": "string"}),
+		),
+		mcp.WithObject("identifyingProperties",
+			mcp.Required(),
+			mcp.Description("Map of properties to uniquely identify the entity."),
+		),
+		mcp.WithString("direction",
+			mcp.Description("Which relationships to count: 'outgoing', 'incoming' or 'both'. Defaults to 'both'."),
+			mcp.Enum(graph.DirectionOutgoing, graph.DirectionIncoming, graph.DirectionBoth),
+		),
+	)
+	s.addTool(relationshipTypeCountsTool, s.handleRelationshipTypeCountsTool)
+
+	labelHistogramTool := mcp.NewTool("label_histogram",
+		mcp.WithDescription("Counts nodes by label (e.g. {\"Service\": 12, \"Function\": 340}), answering 'how many of each type are there?' for a quick overview of the graph. A node with several labels is counted under each of them. Uses the APOC count store when available, so it is fast even on large graphs."),
+	)
+	s.addTool(labelHistogramTool, s.handleLabelHistogramTool)
+
+	graphOverviewTool := mcp.NewTool("graph_overview",
+		mcp.WithDescription("Summarises the whole graph in one call: node counts per label, relationship counts per type, the total numbers of nodes and relationships, and the density (relationships divided by the number of possible directed relationships between distinct nodes). Uses the APOC count store when available, so it is fast even on large graphs."),
+	)
+	s.addTool(graphOverviewTool, s.handleGraphOverviewTool)
+
+	describeEntityModelTool := mcp.NewTool("describe_entity_model",
+		mcp.WithDescription("Describes how the entities of each label are keyed, so that find_or_create_entity calls can use the same identifying properties as the rest of the graph and don't create duplicates. For each label it returns the entity count, the suggested identifyingProperties and their source ('constraint', 'index' or 'observed' from unique, near-universal property values), the label's uniqueness constraints and indexes, and the property keys seen on a sample of up to 1000 entities with how many have each and whether its values are unique. Call this before adding entities to an existing graph."),
+	)
+	s.addTool(describeEntityModelTool, s.handleDescribeEntityModelTool)
+
+	maxDependencyDepthTool := mcp.NewTool("max_dependency_depth",
+		mcp.WithDescription("Computes the length of the longest dependency chain starting from an entity, i.e. how many layers deep its dependencies go, following outgoing relationships. Returns the depth and the entities along one longest chain. Cycles are not followed, and chains are explored up to maxDepth relationships long ('capped' is true if that limit was reached). Every chain up to maxDepth is compared, so the cost grows exponentially with maxDepth on densely connected graphs: start with the default and only raise it if the result is capped. Useful for layering analysis and assessing architectural complexity."),
+		mcp.WithArray("labels",
+			mcp.Required(),
+			mcp.Description("List of labels for the entity."),
+			mcp.Items(map[string]interface{}{"type": "string"}),
+		),
+		mcp.WithObject("identifyingProperties",
+			mcp.Required(),
+			mcp.Description("Map of properties to uniquely identify the entity."),
+		),
+		mcp.WithArray("relationshipTypes",
+			mcp.Description("Optional list of relationship types to follow (e.g. ['DEPENDS_ON', 'IMPORTS']). If omitted or empty, all outgoing relationship types are followed."),
+			mcp.Items(map[string]interface{}{"type": "string"}),
+		),
+		mcp.WithNumber("maxDepth",
+			mcp.Description("Maximum chain length to explore, at most 25. Defaults to 10 if not provided or invalid."),
+		),
+	)
+	s.addTool(maxDependencyDepthTool, s.handleMaxDependencyDepthTool)
+
+	couplingMetricsTool := mcp.NewTool("coupling_metrics",
+		mcp.WithDescription("Computes coupling metrics for every entity with a label (e.g. every Service): fan-in (incoming relationships, i.e. how many things depend on it), fan-out (outgoing relationships, i.e. how many things it depends on) and instability, fanOut / (fanIn + fanOut), from 0 (stable, only depended on) to 1 (unstable, only depends on others). Entities are returned most coupled first, at most 1000. Useful for architecture dashboards and spotting components that are risky to change."),
+		mcp.WithString("label",
+			mcp.Required(),
+			mcp.Description("Label of the entities to measure (e.g. 'Service')."),
+		),
+		mcp.WithArray("relationshipTypes",
+			mcp.Description("Optional list of relationship types to count (e.g. ['DEPENDS_ON', 'CALLS']). If omitted or empty, relationships of all types are counted."),
+			mcp.Items(map[string]interface{}{"type": "string"}),
+		),
+	)
+	s.addTool(couplingMetricsTool, s.handleCouplingMetricsTool)
+
+	similarEntitiesTool := mcp.NewTool("similar_entities",
+		mcp.WithDescription("Finds the entities most similar to an entity by the neighbours they share (e.g. 'which modules are most like this one?'): two modules that import the same packages and are called by the same services are similar. Relationships are followed in either direction. Each result has the number of shared neighbours and a similarity score from 0 to 1, the Jaccard similarity of the two neighbour sets (shared neighbours divided by all distinct neighbours of either). Results are ordered most similar first. This is based on graph structure, not on the entities' properties or content."),
+		mcp.WithArray("labels",
+			mcp.Required(),
+			mcp.Description("List of labels for the entity."),
+			mcp.Items(map[string]interface{}{"type": "string"}),
+		),
+		mcp.WithObject("identifyingProperties",
+			mcp.Required(),
+			mcp.Description("Map of properties to uniquely identify the entity."),
+		),
+		mcp.WithArray("relationshipTypes",
+			mcp.Description("Optional list of relationship types connecting entities to their neighbours (e.g. ['IMPORTS', 'CALLS']). If omitted or empty, relationships of all types are followed."),
+			mcp.Items(map[string]interface{}{"type": "string"}),
+		),
+		mcp.WithNumber("topK",
+			mcp.Description("Number of most similar entities to return. Defaults to 10 if not provided or invalid."),
+		),
+	)
+	s.addTool(similarEntitiesTool, s.handleSimilarEntitiesTool)
+}
+
+// handleCentralityTool handles the centrality tool
+func (s *Server) handleCentralityTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	labels, err := parseOptionalStringArray(request, "labels")
+	if err != nil {
+		return nil, err
+	}
+	relTypes, err := parseOptionalRelationshipTypes(request)
+	if err != nil {
+		return nil, err
+	}
+	topN, err := parseOptionalInt(request, "topN", 10)
+	if err != nil {
+		return nil, err
+	}
+
+	// Call graph store method
+	centralityResult, err := s.graph.Centrality(ctx, labels, relTypes, topN)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute centrality: %w", err)
+	}
+
+	// Return the result
+	resultJSON, err := json.Marshal(centralityResult)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal centrality result: %w", err)
+	}
+	return mcp.NewToolResultText(string(resultJSON)), nil
+}
+
+// handleRelationshipTypeCountsTool handles the relationship_type_counts tool
+func (s *Server) handleRelationshipTypeCountsTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	locator, err := parseEntityLocator(request.Params.Arguments)
+	if err != nil {
+		return nil, err
+	}
+	direction := graph.DirectionBoth
+	if directionArg, exists := request.Params.Arguments["direction"]; exists && directionArg != nil {
+		var ok bool
+		direction, ok = directionArg.(string)
+		if !ok {
+			return nil, errors.New("direction must be a string")
+		}
+	}
+
+	// Call graph store method
+	counts, err := s.graph.RelationshipTypeCounts(ctx, locator, direction)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count relationship types: %w", err)
+	}
+
+	// Return the result
+	resultJSON, err := json.Marshal(counts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal relationship type counts: %w", err)
+	}
+	return mcp.NewToolResultText(string(resultJSON)), nil
+}
+
+// handleLabelHistogramTool handles the label_histogram tool
+func (s *Server) handleLabelHistogramTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	// Call graph store method
+	counts, err := s.graph.LabelHistogram(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count nodes by label: %w", err)
+	}
+
+	// Return the result
+	resultJSON, err := json.Marshal(counts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal label counts: %w", err)
+	}
+	return mcp.NewToolResultText(string(resultJSON)), nil
+}
+
+// handleGraphOverviewTool handles the graph_overview tool
+func (s *Server) handleGraphOverviewTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	// Call graph store method
+	overview, err := s.graph.GraphOverview(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get graph overview: %w", err)
+	}
+
+	// Return the result
+	resultJSON, err := json.Marshal(overview)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal graph overview: %w", err)
+	}
+	return mcp.NewToolResultText(string(resultJSON)), nil
+}
+
+// handleDescribeEntityModelTool handles the describe_entity_model tool
+func (s *Server) handleDescribeEntityModelTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	// Call graph store method
+	model, err := s.graph.DescribeEntityModel(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe entity model: %w", err)
+	}
+
+	// Return the result
+	resultJSON, err := json.Marshal(model)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal entity model: %w", err)
+	}
+	return mcp.NewToolResultText(string(resultJSON)), nil
+}
+
+// handleMaxDependencyDepthTool handles the max_dependency_depth tool
+func (s *Server) handleMaxDependencyDepthTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	locator, err := parseEntityLocator(request.Params.Arguments)
+	if err != nil {
+		return nil, err
+	}
+	relTypes, err := parseOptionalRelationshipTypes(request)
+	if err != nil {
+		return nil, err
+	}
+	maxDepth, err := parseOptionalInt(request, "maxDepth", 10)
+	if err != nil {
+		return nil, err
+	}
+
+	// Call graph store method
+	depthResult, err := s.graph.MaxDependencyDepth(ctx, locator, relTypes, maxDepth)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute dependency depth: %w", err)
+	}
+
+	// Return the result
+	resultJSON, err := json.Marshal(depthResult)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal dependency depth: %w", err)
+	}
+	return mcp.NewToolResultText(string(resultJSON)), nil
+}
+
+// handleCouplingMetricsTool handles the coupling_metrics tool
+func (s *Server) handleCouplingMetricsTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	label, ok := request.Params.Arguments["label"].(string)
+	if !ok || label == "" {
+		return nil, errors.New("label must be a non-empty string")
+	}
+	relTypes, err := parseOptionalRelationshipTypes(request)
+	if err != nil {
+		return nil, err
+	}
+
+	// Call graph store method
+	metrics, err := s.graph.CouplingMetrics(ctx, label, relTypes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute coupling metrics: %w", err)
+	}
+
+	// Return the result
+	resultJSON, err := json.Marshal(metrics)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal coupling metrics: %w", err)
+	}
+	return mcp.NewToolResultText(string(resultJSON)), nil
+}
+
+// handleSimilarEntitiesTool handles the similar_entities tool
+func (s *Server) handleSimilarEntitiesTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	locator, err := parseEntityLocator(request.Params.Arguments)
+	if err != nil {
+		return nil, err
+	}
+	relTypes, err := parseOptionalRelationshipTypes(request)
+	if err != nil {
+		return nil, err
+	}
+	topK, err := parseOptionalInt(request, "topK", 10)
+	if err != nil {
+		return nil, err
+	}
+
+	// Call graph store method
+	similar, err := s.graph.SimilarEntities(ctx, locator, relTypes, topK)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find similar entities: %w", err)
+	}
+
+	// Return the result
+	resultJSON, err := json.Marshal(similar)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal similar entities: %w", err)
+	}
+	return mcp.NewToolResultText(string(resultJSON)), nil
+}
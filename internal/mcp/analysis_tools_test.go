@@ -0,0 +1,420 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sammcj/mcp-graph/internal/graph"
+	"github.com/sammcj/mcp-graph/internal/mcp/mocks"
+)
+
+// TestHandleCentralityTool tests the centrality tool handler
+func TestHandleCentralityTool(t *testing.T) {
+	// Create a new mock controller
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Create mock graph store and service
+	mockGraph := mocks.NewMockStore(ctrl)
+	mockService := mocks.NewMockKnowledgeManager(ctrl)
+
+	// Create MCP server with mocks
+	server := &Server{
+		graph:   mockGraph,
+		service: mockService,
+	}
+
+	// Mock centrality result
+	centralityResult := graph.CentralityResult{
+		Algorithm: "degree",
+		Results: []graph.CentralityScore{
+			{
+				Entity: graph.EntityDetails{
+					Labels:     []string{"Module"},
+					Properties: map[string]interface{}{"id": "4:abc:1", "name": "core"},
+				},
+				Score: 12,
+			},
+		},
+	}
+
+	// Set up expectations
+	mockGraph.EXPECT().Centrality(
+		gomock.Any(),
+		gomock.Eq([]string{"Module"}),
+		gomock.Eq([]string{"DEPENDS_ON"}),
+		gomock.Eq(5),
+	).Return(centralityResult, nil)
+
+	// Create tool request
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{
+		"labels":            []interface{}{"Module"},
+		"relationshipTypes": []interface{}{"DEPENDS_ON"},
+		"topN":              float64(5),
+	}
+
+	// Call the handler
+	result, err := server.handleCentralityTool(context.Background(), request)
+
+	// Assert the results
+	assert.NoError(t, err)
+	assert.NotNil(t, result)
+
+	// Verify the result content
+	var resultData map[string]interface{}
+	err = json.Unmarshal([]byte(getResultText(result)), &resultData)
+	assert.NoError(t, err)
+	assert.Equal(t, "degree", resultData["algorithm"])
+	assert.Len(t, resultData["results"], 1)
+}
+
+// TestHandleCentralityTool_Defaults tests the centrality tool handler with no arguments
+func TestHandleCentralityTool_Defaults(t *testing.T) {
+	// Create a new mock controller
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Create mock graph store and service
+	mockGraph := mocks.NewMockStore(ctrl)
+	mockService := mocks.NewMockKnowledgeManager(ctrl)
+
+	// Create MCP server with mocks
+	server := &Server{
+		graph:   mockGraph,
+		service: mockService,
+	}
+
+	// Set up expectations - the default topN is 10
+	mockGraph.EXPECT().Centrality(gomock.Any(), gomock.Nil(), gomock.Nil(), gomock.Eq(10)).Return(graph.CentralityResult{Algorithm: "pagerank"}, nil)
+
+	// Create tool request
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{}
+
+	// Call the handler
+	result, err := server.handleCentralityTool(context.Background(), request)
+
+	// Assert the results
+	assert.NoError(t, err)
+	assert.NotNil(t, result)
+}
+
+// TestHandleRelationshipTypeCountsTool tests the relationship_type_counts tool handler
+func TestHandleRelationshipTypeCountsTool(t *testing.T) {
+	// Create a new mock controller
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Create mock graph store and service
+	mockGraph := mocks.NewMockStore(ctrl)
+	mockService := mocks.NewMockKnowledgeManager(ctrl)
+
+	// Create MCP server with mocks
+	server := &Server{
+		graph:   mockGraph,
+		service: mockService,
+	}
+
+	// Set up expectations - direction defaults to both
+	mockGraph.EXPECT().RelationshipTypeCounts(
+		gomock.Any(),
+		gomock.Eq(graph.EntityLocator{Labels: []string{"Function"}, IdentifyingProperties: map[string]interface{}{"name": "main"}}),
+		gomock.Eq(graph.DirectionBoth),
+	).Return(map[string]int64{"CALLS": 12, "DEFINED_IN": 1}, nil)
+
+	// Create tool request
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{
+		"labels":                []interface{}{"Function"},
+		"identifyingProperties": map[string]interface{}{"name": "main"},
+	}
+
+	// Call the handler
+	result, err := server.handleRelationshipTypeCountsTool(context.Background(), request)
+
+	// Assert the results
+	assert.NoError(t, err)
+
+	// Verify the result content
+	var resultData map[string]interface{}
+	err = json.Unmarshal([]byte(getResultText(result)), &resultData)
+	assert.NoError(t, err)
+	assert.Equal(t, float64(12), resultData["CALLS"])
+	assert.Equal(t, float64(1), resultData["DEFINED_IN"])
+}
+
+// TestHandleLabelHistogramTool tests the label_histogram tool handler
+func TestHandleLabelHistogramTool(t *testing.T) {
+	// Create a new mock controller
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Create mock graph store and service
+	mockGraph := mocks.NewMockStore(ctrl)
+	mockService := mocks.NewMockKnowledgeManager(ctrl)
+
+	// Create MCP server with mocks
+	server := &Server{
+		graph:   mockGraph,
+		service: mockService,
+	}
+
+	// Set up expectations
+	mockGraph.EXPECT().LabelHistogram(gomock.Any()).Return(map[string]int64{"Service": 12, "Function": 340}, nil)
+
+	// Call the handler
+	result, err := server.handleLabelHistogramTool(context.Background(), mcp.CallToolRequest{})
+
+	// Assert the results
+	assert.NoError(t, err)
+
+	// Verify the result content
+	var resultData map[string]interface{}
+	err = json.Unmarshal([]byte(getResultText(result)), &resultData)
+	assert.NoError(t, err)
+	assert.Equal(t, float64(12), resultData["Service"])
+	assert.Equal(t, float64(340), resultData["Function"])
+}
+
+// TestHandleGraphOverviewTool tests the graph_overview tool handler
+func TestHandleGraphOverviewTool(t *testing.T) {
+	// Create a new mock controller
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Create mock graph store
+	mockGraph := mocks.NewMockStore(ctrl)
+
+	// Create MCP server with mocks
+	server := &Server{graph: mockGraph}
+
+	// Set up expectations
+	mockGraph.EXPECT().GraphOverview(gomock.Any()).Return(graph.GraphOverview{
+		NodeCount:         3,
+		RelationshipCount: 3,
+		Density:           0.5,
+		Labels:            map[string]int64{"Service": 2, "DataStore": 1},
+		RelationshipTypes: map[string]int64{"CALLS": 1, "WRITES_TO": 2},
+	}, nil)
+
+	// Call the handler
+	result, err := server.handleGraphOverviewTool(context.Background(), mcp.CallToolRequest{})
+
+	// Assert the results
+	assert.NoError(t, err)
+
+	// Verify the result content
+	var resultData graph.GraphOverview
+	err = json.Unmarshal([]byte(getResultText(result)), &resultData)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), resultData.NodeCount)
+	assert.Equal(t, int64(3), resultData.RelationshipCount)
+	assert.Equal(t, 0.5, resultData.Density)
+	assert.Equal(t, int64(2), resultData.Labels["Service"])
+	assert.Equal(t, int64(2), resultData.RelationshipTypes["WRITES_TO"])
+}
+
+// TestHandleDescribeEntityModelTool tests the describe_entity_model tool handler
+func TestHandleDescribeEntityModelTool(t *testing.T) {
+	// Create a new mock controller
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Create mock graph store
+	mockGraph := mocks.NewMockStore(ctrl)
+
+	// Create MCP server with mocks
+	server := &Server{graph: mockGraph}
+
+	// Set up expectations
+	mockGraph.EXPECT().DescribeEntityModel(gomock.Any()).Return(graph.EntityModel{
+		Labels: []graph.LabelModel{{
+			Label:                 "Function",
+			Count:                 340,
+			IdentifyingProperties: []string{"filePath", "name"},
+			Source:                "constraint",
+			Constraints:           [][]string{{"filePath", "name"}},
+			Indexes:               [][]string{},
+			Properties:            []graph.PropertyUsage{{Key: "name", Coverage: 1, Unique: false}},
+		}},
+		SampleSize: 1000,
+	}, nil)
+
+	// Call the handler
+	result, err := server.handleDescribeEntityModelTool(context.Background(), mcp.CallToolRequest{})
+
+	// Assert the results
+	assert.NoError(t, err)
+
+	// Verify the result content
+	var resultData graph.EntityModel
+	err = json.Unmarshal([]byte(getResultText(result)), &resultData)
+	assert.NoError(t, err)
+	assert.Len(t, resultData.Labels, 1)
+	assert.Equal(t, []string{"filePath", "name"}, resultData.Labels[0].IdentifyingProperties)
+	assert.Equal(t, "constraint", resultData.Labels[0].Source)
+}
+
+// TestHandleMaxDependencyDepthTool tests the max_dependency_depth tool handler
+func TestHandleMaxDependencyDepthTool(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockGraph := mocks.NewMockStore(ctrl)
+	server := &Server{graph: mockGraph}
+
+	depthResult := graph.DependencyDepthResult{
+		Entity: graph.EntityDetails{Labels: []string{"Service"}, Properties: map[string]interface{}{"name": "api"}},
+		Depth:  2,
+		Chain: []graph.EntityDetails{
+			{Labels: []string{"Library"}, Properties: map[string]interface{}{"name": "client"}},
+			{Labels: []string{"Library"}, Properties: map[string]interface{}{"name": "transport"}},
+		},
+	}
+	mockGraph.EXPECT().MaxDependencyDepth(
+		gomock.Any(),
+		graph.EntityLocator{Labels: []string{"Service"}, IdentifyingProperties: map[string]interface{}{"name": "api"}},
+		[]string{"DEPENDS_ON"},
+		4,
+	).Return(depthResult, nil)
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{
+		"labels":                []interface{}{"Service"},
+		"identifyingProperties": map[string]interface{}{"name": "api"},
+		"relationshipTypes":     []interface{}{"DEPENDS_ON"},
+		"maxDepth":              float64(4),
+	}
+
+	result, err := server.handleMaxDependencyDepthTool(context.Background(), request)
+	assert.NoError(t, err)
+
+	var resultData graph.DependencyDepthResult
+	assert.NoError(t, json.Unmarshal([]byte(getResultText(result)), &resultData))
+	assert.Equal(t, 2, resultData.Depth)
+	assert.Len(t, resultData.Chain, 2)
+	assert.False(t, resultData.Capped)
+}
+
+// TestHandleMaxDependencyDepthToolDefaultDepth tests that max_dependency_depth explores 10 levels by default
+func TestHandleMaxDependencyDepthToolDefaultDepth(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockGraph := mocks.NewMockStore(ctrl)
+	server := &Server{graph: mockGraph}
+
+	mockGraph.EXPECT().MaxDependencyDepth(gomock.Any(), gomock.Any(), gomock.Nil(), 10).Return(graph.DependencyDepthResult{}, nil)
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{
+		"labels":                []interface{}{"Service"},
+		"identifyingProperties": map[string]interface{}{"name": "api"},
+	}
+
+	_, err := server.handleMaxDependencyDepthTool(context.Background(), request)
+	assert.NoError(t, err)
+}
+
+// TestHandleCouplingMetricsTool tests the coupling_metrics tool handler
+func TestHandleCouplingMetricsTool(t *testing.T) {
+	// Create a new mock controller
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Create mock graph store
+	mockGraph := mocks.NewMockStore(ctrl)
+
+	// Create MCP server with mocks
+	server := &Server{graph: mockGraph}
+
+	// Set up expectations
+	mockGraph.EXPECT().CouplingMetrics(gomock.Any(), "Service", []string{"DEPENDS_ON"}).Return([]graph.CouplingMetric{
+		{
+			Entity:      graph.EntityDetails{Labels: []string{"Service"}, Properties: map[string]interface{}{"id": "4:abc:1", "name": "billing"}},
+			FanIn:       3,
+			FanOut:      1,
+			Instability: 0.25,
+		},
+	}, nil)
+
+	// Create tool request
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{
+		"label":             "Service",
+		"relationshipTypes": []interface{}{"DEPENDS_ON"},
+	}
+
+	// Call the handler
+	result, err := server.handleCouplingMetricsTool(context.Background(), request)
+
+	// Assert the results
+	assert.NoError(t, err)
+
+	// Verify the result content
+	var resultData []graph.CouplingMetric
+	err = json.Unmarshal([]byte(getResultText(result)), &resultData)
+	assert.NoError(t, err)
+	assert.Len(t, resultData, 1)
+	assert.Equal(t, int64(3), resultData[0].FanIn)
+	assert.Equal(t, 0.25, resultData[0].Instability)
+
+	// A label is required
+	request.Params.Arguments = map[string]interface{}{}
+	_, err = server.handleCouplingMetricsTool(context.Background(), request)
+	assert.Error(t, err)
+}
+
+// TestHandleSimilarEntitiesTool tests the similar_entities tool handler
+func TestHandleSimilarEntitiesTool(t *testing.T) {
+	// Create a new mock controller
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Create mock graph store
+	mockGraph := mocks.NewMockStore(ctrl)
+
+	// Create MCP server with mocks
+	server := &Server{graph: mockGraph}
+
+	// Set up expectations
+	locator := graph.EntityLocator{Labels: []string{"Module"}, IdentifyingProperties: map[string]interface{}{"name": "billing"}}
+	mockGraph.EXPECT().SimilarEntities(gomock.Any(), locator, []string{"IMPORTS"}, 5).Return([]graph.SimilarEntity{
+		{
+			Entity:          graph.EntityDetails{Labels: []string{"Module"}, Properties: map[string]interface{}{"id": "4:abc:2", "name": "invoicing"}},
+			SharedNeighbors: 3,
+			Similarity:      0.75,
+		},
+	}, nil)
+
+	// Create tool request
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{
+		"labels":                []interface{}{"Module"},
+		"identifyingProperties": map[string]interface{}{"name": "billing"},
+		"relationshipTypes":     []interface{}{"IMPORTS"},
+		"topK":                  float64(5),
+	}
+
+	// Call the handler
+	result, err := server.handleSimilarEntitiesTool(context.Background(), request)
+
+	// Assert the results
+	assert.NoError(t, err)
+
+	// Verify the result content
+	var resultData []graph.SimilarEntity
+	err = json.Unmarshal([]byte(getResultText(result)), &resultData)
+	assert.NoError(t, err)
+	if assert.Len(t, resultData, 1) {
+		assert.Equal(t, int64(3), resultData[0].SharedNeighbors)
+		assert.Equal(t, 0.75, resultData[0].Similarity)
+		assert.Equal(t, "invoicing", resultData[0].Entity.Properties["name"])
+	}
+}
@@ -0,0 +1,96 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/sammcj/mcp-graph/internal/audit"
+)
+
+// mutatingTools lists the tools that change the graph and are recorded in the audit log.
+// query_knowledge_graph is included since Cypher queries can write.
+var mutatingTools = map[string]bool{
+	"query_knowledge_graph":              true,
+	"create_document":                    true,
+	"find_or_create_document":            true,
+	"batch_create_documents":             true,
+	"create_concept":                     true,
+	"link_concepts":                      true,
+	"link_document":                      true,
+	"create_node":                        true,
+	"create_edge":                        true,
+	"upsert_schema":                      true,
+	"find_or_create_entity":              true,
+	"find_or_create_relationship":        true,
+	"upsert_entity_with_relationship":    true,
+	"batch_find_or_create_entities":      true,
+	"batch_find_or_create_relationships": true,
+	"copy_properties":                    true,
+	"decay_confidence":                   true,
+	"set_entity_status":                  true,
+	"restore_snapshot":                   true,
+	"delete_relationships_by_type":       true,
+	"prune_low_confidence":               true,
+	"bulk_update_entities":               true,
+	"materialize_transitive_closure":     true,
+	"sweep_expired":                      true,
+}
+
+// SetAuditLogger sets the audit log that calls to mutating tools are recorded in
+func (s *Server) SetAuditLogger(auditLog *audit.Logger) {
+	s.auditLog = auditLog
+}
+
+// auditToolCallStart records a mutating tool call before it runs
+func (s *Server) auditToolCallStart(ctx context.Context, id any, request *mcp.CallToolRequest) {
+	if !s.auditLog.Enabled() || !mutatingTools[request.Params.Name] {
+		return
+	}
+	s.auditLog.Record(audit.Entry{
+		Source:    "mcp",
+		RequestID: fmt.Sprint(id),
+		Operation: request.Params.Name,
+		Arguments: request.Params.Arguments,
+		Outcome:   audit.OutcomeStarted,
+	})
+}
+
+// auditToolCallResult records the outcome of a mutating tool call that returned a result
+func (s *Server) auditToolCallResult(ctx context.Context, id any, request *mcp.CallToolRequest, result *mcp.CallToolResult) {
+	if !s.auditLog.Enabled() || !mutatingTools[request.Params.Name] {
+		return
+	}
+	entry := audit.Entry{
+		Source:    "mcp",
+		RequestID: fmt.Sprint(id),
+		Operation: request.Params.Name,
+		Outcome:   audit.OutcomeSucceeded,
+	}
+	if result != nil && result.IsError {
+		entry.Outcome = audit.OutcomeFailed
+		for _, content := range result.Content {
+			if textContent, ok := content.(mcp.TextContent); ok {
+				entry.Error = textContent.Text
+				break
+			}
+		}
+	}
+	s.auditLog.Record(entry)
+}
+
+// auditToolCallError records the outcome of a mutating tool call that failed
+func (s *Server) auditToolCallError(ctx context.Context, id any, method mcp.MCPMethod, message any, err error) {
+	request, ok := message.(*mcp.CallToolRequest)
+	if !s.auditLog.Enabled() || method != mcp.MethodToolsCall || !ok || !mutatingTools[request.Params.Name] {
+		return
+	}
+	s.auditLog.Record(audit.Entry{
+		Source:    "mcp",
+		RequestID: fmt.Sprint(id),
+		Operation: request.Params.Name,
+		Outcome:   audit.OutcomeFailed,
+		Error:     err.Error(),
+	})
+}
@@ -0,0 +1,55 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sammcj/mcp-graph/internal/audit"
+)
+
+// TestAuditToolCall tests that mutating tool calls are recorded with their outcome and read-only calls are not
+func TestAuditToolCall(t *testing.T) {
+	var buf bytes.Buffer
+	server := &Server{auditLog: audit.NewLogger(&buf, nil)}
+
+	createNode := &mcp.CallToolRequest{}
+	createNode.Params.Name = "create_node"
+	createNode.Params.Arguments = map[string]interface{}{"labels": []interface{}{"Service"}}
+	server.auditToolCallStart(context.Background(), 1, createNode)
+	server.auditToolCallResult(context.Background(), 1, createNode, mcp.NewToolResultText(`{"id":"1"}`))
+
+	createEdge := &mcp.CallToolRequest{}
+	createEdge.Params.Name = "create_edge"
+	server.auditToolCallStart(context.Background(), 2, createEdge)
+	server.auditToolCallError(context.Background(), 2, mcp.MethodToolsCall, createEdge, errors.New("missing node"))
+
+	getNode := &mcp.CallToolRequest{}
+	getNode.Params.Name = "get_node"
+	server.auditToolCallStart(context.Background(), 3, getNode)
+	server.auditToolCallResult(context.Background(), 3, getNode, mcp.NewToolResultText(`{}`))
+
+	var entries []audit.Entry
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var entry audit.Entry
+		assert.NoError(t, json.Unmarshal([]byte(line), &entry))
+		entries = append(entries, entry)
+	}
+
+	if assert.Len(t, entries, 4) {
+		assert.Equal(t, "create_node", entries[0].Operation)
+		assert.Equal(t, audit.OutcomeStarted, entries[0].Outcome)
+		assert.Equal(t, "1", entries[0].RequestID)
+		assert.NotNil(t, entries[0].Arguments)
+		assert.Equal(t, audit.OutcomeSucceeded, entries[1].Outcome)
+		assert.Equal(t, "create_edge", entries[3].Operation)
+		assert.Equal(t, audit.OutcomeFailed, entries[3].Outcome)
+		assert.Equal(t, "missing node", entries[3].Error)
+	}
+}
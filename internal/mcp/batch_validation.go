@@ -0,0 +1,172 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/sammcj/mcp-graph/internal/graph"
+	"github.com/sammcj/mcp-graph/internal/service"
+)
+
+// batchValidationError identifies an invalid field of one input to a batch tool, so that clients can correct it
+// programmatically
+type batchValidationError struct {
+	Index  int    `json:"index"`           // Index of the input in the batch
+	Field  string `json:"field,omitempty"` // Field of the input, e.g. "labels[2]"; empty if the input isn't an object
+	Reason string `json:"reason"`          // Why the field is invalid, e.g. "must be a string"
+}
+
+// batchValidator collects the validation errors for every input of a batch, rather than stopping at the first
+type batchValidator struct {
+	errors []batchValidationError
+}
+
+// fail records that field of the input at index is invalid
+func (v *batchValidator) fail(index int, field, reason string) {
+	v.errors = append(v.errors, batchValidationError{Index: index, Field: field, Reason: reason})
+}
+
+// result returns an error result listing the validation errors as JSON, or nil if there are none. Nothing in the
+// batch is processed if any input is invalid.
+func (v *batchValidator) result(inputCount int) (*mcp.CallToolResult, error) {
+	if len(v.errors) == 0 {
+		return nil, nil
+	}
+
+	invalid := make(map[int]bool)
+	for _, e := range v.errors {
+		invalid[e.Index] = true
+	}
+	response := struct {
+		Error            string                 `json:"error"`
+		ValidationErrors []batchValidationError `json:"validationErrors"`
+	}{
+		Error:            fmt.Sprintf("%d out of %d inputs are invalid; nothing was processed", len(invalid), inputCount),
+		ValidationErrors: v.errors,
+	}
+	responseJSON, err := json.Marshal(response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal validation errors: %w", err)
+	}
+	return mcp.NewToolResultError(string(responseJSON)), nil
+}
+
+// object returns the batch input at index as an object
+func (v *batchValidator) object(index int, arg interface{}) (map[string]interface{}, bool) {
+	m, ok := arg.(map[string]interface{})
+	if !ok {
+		v.fail(index, "", "must be an object")
+	}
+	return m, ok
+}
+
+// labels parses a required, non-empty array of strings field of a batch input
+func (v *batchValidator) labels(index int, input map[string]interface{}, field string) []string {
+	arg, exists := input[field]
+	if !exists || arg == nil {
+		v.fail(index, field, "is required")
+		return nil
+	}
+	items, ok := arg.([]interface{})
+	if !ok {
+		v.fail(index, field, "must be an array of strings")
+		return nil
+	}
+	if len(items) == 0 {
+		v.fail(index, field, "must contain at least one label")
+		return nil
+	}
+	labels := make([]string, len(items))
+	for j, item := range items {
+		if labels[j], ok = item.(string); !ok {
+			v.fail(index, fmt.Sprintf("%s[%d]", field, j), "must be a string")
+		}
+	}
+	return labels
+}
+
+// properties parses an object field of a batch input. Required fields must be present, and nonEmpty ones must have
+// at least one property. Optional fields that are missing are returned as an empty map.
+func (v *batchValidator) properties(index int, input map[string]interface{}, field string, required, nonEmpty bool) map[string]interface{} {
+	arg, exists := input[field]
+	if !exists || arg == nil {
+		if required {
+			v.fail(index, field, "is required")
+		}
+		return make(map[string]interface{})
+	}
+	props, ok := arg.(map[string]interface{})
+	if !ok {
+		v.fail(index, field, "must be an object")
+		return nil
+	}
+	if nonEmpty && len(props) == 0 {
+		v.fail(index, field, "must contain at least one property")
+	}
+	return props
+}
+
+// str parses a string field of a batch input, which must be non-empty if nonEmpty is set
+func (v *batchValidator) str(index int, input map[string]interface{}, field string, nonEmpty bool) string {
+	arg, exists := input[field]
+	if !exists || arg == nil {
+		v.fail(index, field, "is required")
+		return ""
+	}
+	s, ok := arg.(string)
+	if !ok {
+		v.fail(index, field, "must be a string")
+		return ""
+	}
+	if nonEmpty && s == "" {
+		v.fail(index, field, "must not be empty")
+	}
+	return s
+}
+
+// entityInput parses the input at index of batch_find_or_create_entities
+func (v *batchValidator) entityInput(index int, arg interface{}) graph.EntityInput {
+	entityMap, ok := v.object(index, arg)
+	if !ok {
+		return graph.EntityInput{}
+	}
+	return graph.EntityInput{
+		Labels:                v.labels(index, entityMap, "labels"),
+		IdentifyingProperties: v.properties(index, entityMap, "identifyingProperties", true, true),
+		Properties:            v.properties(index, entityMap, "properties", true, false),
+	}
+}
+
+// relationshipInput parses the input at index of batch_find_or_create_relationships
+func (v *batchValidator) relationshipInput(index int, arg interface{}) graph.RelationshipInput {
+	relMap, ok := v.object(index, arg)
+	if !ok {
+		return graph.RelationshipInput{}
+	}
+	return graph.RelationshipInput{
+		StartNodeLabels:                v.labels(index, relMap, "startNodeLabels"),
+		StartNodeIdentifyingProperties: v.properties(index, relMap, "startNodeIdentifyingProperties", true, true),
+		EndNodeLabels:                  v.labels(index, relMap, "endNodeLabels"),
+		EndNodeIdentifyingProperties:   v.properties(index, relMap, "endNodeIdentifyingProperties", true, true),
+		RelationshipType:               v.str(index, relMap, "relationshipType", true),
+		Properties:                     v.properties(index, relMap, "properties", false, false),
+	}
+}
+
+// documentInput parses the input at index of batch_create_documents
+func (v *batchValidator) documentInput(index int, arg interface{}) service.DocumentInput {
+	documentMap, ok := v.object(index, arg)
+	if !ok {
+		return service.DocumentInput{}
+	}
+	doc := service.DocumentInput{
+		Title:   v.str(index, documentMap, "title", false),
+		Content: v.str(index, documentMap, "content", false),
+	}
+	if metadataArg, exists := documentMap["metadata"]; exists && metadataArg != nil {
+		doc.Metadata = v.properties(index, documentMap, "metadata", false, false)
+	}
+	return doc
+}
@@ -0,0 +1,96 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sammcj/mcp-graph/internal/mcp/mocks"
+)
+
+// TestHandleBatchFindOrCreateEntitiesTool_ValidationErrors tests that every invalid field is reported by index and
+// field, and that nothing is created
+func TestHandleBatchFindOrCreateEntitiesTool_ValidationErrors(t *testing.T) {
+	// Create a new mock controller
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Create mock graph store - no expectations, as the batch must not reach the store
+	mockGraph := mocks.NewMockStore(ctrl)
+
+	// Create MCP server with mock
+	server := &Server{graph: mockGraph}
+
+	// Create tool request
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{
+		"entities": []interface{}{
+			map[string]interface{}{
+				"labels":                []interface{}{"Function"},
+				"identifyingProperties": map[string]interface{}{"name": "Charge"},
+				"properties":            map[string]interface{}{},
+			},
+			"not an entity",
+			map[string]interface{}{
+				"labels":                []interface{}{"Function", 42},
+				"identifyingProperties": map[string]interface{}{},
+			},
+		},
+	}
+
+	// Call the handler
+	result, err := server.handleBatchFindOrCreateEntitiesToolTool(context.Background(), request)
+
+	// Assert the results
+	assert.NoError(t, err)
+	assert.True(t, result.IsError)
+
+	var resultData struct {
+		Error            string                 `json:"error"`
+		ValidationErrors []batchValidationError `json:"validationErrors"`
+	}
+	err = json.Unmarshal([]byte(getResultText(result)), &resultData)
+	assert.NoError(t, err)
+	assert.Equal(t, "2 out of 3 inputs are invalid; nothing was processed", resultData.Error)
+	assert.Equal(t, []batchValidationError{
+		{Index: 1, Reason: "must be an object"},
+		{Index: 2, Field: "labels[1]", Reason: "must be a string"},
+		{Index: 2, Field: "identifyingProperties", Reason: "must contain at least one property"},
+		{Index: 2, Field: "properties", Reason: "is required"},
+	}, resultData.ValidationErrors)
+}
+
+// TestBatchValidator_RelationshipInput tests parsing a valid relationship, with its optional properties defaulted
+func TestBatchValidator_RelationshipInput(t *testing.T) {
+	var validator batchValidator
+	input := validator.relationshipInput(0, map[string]interface{}{
+		"startNodeLabels":                []interface{}{"Function"},
+		"startNodeIdentifyingProperties": map[string]interface{}{"name": "Charge"},
+		"endNodeLabels":                  []interface{}{"Function"},
+		"endNodeIdentifyingProperties":   map[string]interface{}{"name": "Refund"},
+		"relationshipType":               "CALLS",
+	})
+
+	assert.Empty(t, validator.errors)
+	assert.Equal(t, "CALLS", input.RelationshipType)
+	assert.Equal(t, []string{"Function"}, input.EndNodeLabels)
+	assert.NotNil(t, input.Properties)
+
+	result, err := validator.result(1)
+	assert.NoError(t, err)
+	assert.Nil(t, result)
+
+	// A missing relationship type is reported by field
+	validator.relationshipInput(1, map[string]interface{}{
+		"startNodeLabels":                []interface{}{"Function"},
+		"startNodeIdentifyingProperties": map[string]interface{}{"name": "Charge"},
+		"endNodeLabels":                  []interface{}{"Function"},
+		"endNodeIdentifyingProperties":   map[string]interface{}{"name": "Refund"},
+		"relationshipType":               "",
+	})
+	assert.Equal(t, []batchValidationError{{Index: 1, Field: "relationshipType", Reason: "must not be empty"}}, validator.errors)
+}
@@ -0,0 +1,144 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/sammcj/mcp-graph/internal/graph"
+)
+
+// relationshipCSVColumns are the leading columns of export_relationships_csv, before one column per relationship
+// property
+var relationshipCSVColumns = []string{"direction", "relationshipType", "otherNodeLabels", "otherNodeName", "otherNodeId"}
+
+// setupExportTools configures the tools that export parts of the graph for use outside it
+func (s *Server) setupExportTools() {
+	exportRelationshipsCSVTool := mcp.NewTool("export_relationships_csv",
+		mcp.WithDescription("Exports every relationship of an entity as CSV, for reviewing its connections in a spreadsheet. Each row is one relationship, with its direction relative to the entity, its type, the labels (separated by ';'), name (or title) and ID of the node at the other end, followed by one column per relationship property found on any of them. Rows are ordered by direction, type and name. Returns at most 1000 relationships."),
+		mcp.WithArray("labels",
+			mcp.Required(),
+			mcp.Description("List of labels for the entity."),
+			mcp.Items(map[string]interface{}{"type": "string"}),
+		),
+		mcp.WithObject("identifyingProperties",
+			mcp.Required(),
+			mcp.Description("Map of properties that uniquely identify the entity."),
+		),
+	)
+	s.addTool(exportRelationshipsCSVTool, s.handleExportRelationshipsCSVTool)
+}
+
+// handleExportRelationshipsCSVTool handles the export_relationships_csv tool
+func (s *Server) handleExportRelationshipsCSVTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	locator, err := parseEntityLocator(request.Params.Arguments)
+	if err != nil {
+		return nil, err
+	}
+
+	// Call graph store method
+	result, err := s.graph.GetEntityWithRelationships(ctx, locator)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get entity with relationships: %w", err)
+	}
+
+	// Redact before formatting, as redactToolResult only redacts JSON results
+	rels := make([]graph.EntityRelationship, len(result.Relationships))
+	for i, rel := range result.Relationships {
+		rel.Properties, _ = s.redactor.Redact(rel.Properties).(map[string]interface{})
+		rel.Node.Properties, _ = s.redactor.Redact(rel.Node.Properties).(map[string]interface{})
+		rels[i] = rel
+	}
+
+	// Return the result
+	csvText, err := relationshipsCSV(rels)
+	if err != nil {
+		return nil, fmt.Errorf("failed to write relationships CSV: %w", err)
+	}
+	return mcp.NewToolResultText(csvText), nil
+}
+
+// relationshipsCSV writes relationships as CSV with a header row, ordered by direction, type and the other node's
+// name. Property values that aren't strings are written as JSON.
+func relationshipsCSV(relationships []graph.EntityRelationship) (string, error) {
+	rels := append([]graph.EntityRelationship(nil), relationships...)
+	sort.SliceStable(rels, func(i, j int) bool {
+		if rels[i].Direction != rels[j].Direction {
+			return rels[i].Direction < rels[j].Direction
+		}
+		if rels[i].Type != rels[j].Type {
+			return rels[i].Type < rels[j].Type
+		}
+		return nodeName(rels[i].Node) < nodeName(rels[j].Node)
+	})
+
+	// One column per relationship property, in name order
+	keySet := make(map[string]bool)
+	for _, rel := range rels {
+		for k := range rel.Properties {
+			keySet[k] = true
+		}
+	}
+	keys := make([]string, 0, len(keySet))
+	for k := range keySet {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(append(append([]string(nil), relationshipCSVColumns...), keys...)); err != nil {
+		return "", err
+	}
+	for _, rel := range rels {
+		otherID, _ := rel.Node.Properties["id"].(string)
+		row := []string{rel.Direction, rel.Type, strings.Join(rel.Node.Labels, ";"), nodeName(rel.Node), otherID}
+		for _, k := range keys {
+			value, err := csvValue(rel.Properties[k])
+			if err != nil {
+				return "", err
+			}
+			row = append(row, value)
+		}
+		if err := w.Write(row); err != nil {
+			return "", err
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// nodeName returns a node's name property, or its title if it has no name (as documents don't)
+func nodeName(node graph.EntityDetails) string {
+	if name, ok := node.Properties["name"].(string); ok {
+		return name
+	}
+	title, _ := node.Properties["title"].(string)
+	return title
+}
+
+// csvValue formats a property value for a CSV cell: strings as they are, missing values as empty cells and anything
+// else as JSON
+func csvValue(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return "", err
+		}
+		return string(data), nil
+	}
+}
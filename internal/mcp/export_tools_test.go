@@ -0,0 +1,107 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sammcj/mcp-graph/internal/graph"
+	"github.com/sammcj/mcp-graph/internal/mcp/mocks"
+)
+
+// TestHandleExportRelationshipsCSVTool tests the export_relationships_csv tool handler
+func TestHandleExportRelationshipsCSVTool(t *testing.T) {
+	// Create a new mock controller
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Create mock graph store
+	mockGraph := mocks.NewMockStore(ctrl)
+
+	// Create MCP server with mocks
+	server := &Server{graph: mockGraph}
+
+	// Set up expectations
+	locator := graph.EntityLocator{Labels: []string{"Service"}, IdentifyingProperties: map[string]interface{}{"name": "api"}}
+	mockGraph.EXPECT().GetEntityWithRelationships(gomock.Any(), gomock.Eq(locator)).Return(graph.EntityWithRelationships{
+		Entity: graph.EntityDetails{Labels: []string{"Service"}, Properties: map[string]interface{}{"id": "4:abc:1", "name": "api"}},
+		Relationships: []graph.EntityRelationship{
+			{
+				ID:         "5:abc:2",
+				Type:       "DEPENDS_ON",
+				Direction:  "outgoing",
+				Properties: map[string]interface{}{"confidence": 0.9, "source": "manual"},
+				Node:       graph.EntityDetails{Labels: []string{"Library", "Go"}, Properties: map[string]interface{}{"id": "4:abc:3", "name": "auth"}},
+			},
+			{
+				ID:         "5:abc:1",
+				Type:       "CALLS",
+				Direction:  "incoming",
+				Properties: map[string]interface{}{"note": "via gateway, with retries"},
+				Node:       graph.EntityDetails{Labels: []string{"Service"}, Properties: map[string]interface{}{"id": "4:abc:2", "name": "web"}},
+			},
+		},
+	}, nil)
+
+	// Create tool request
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{
+		"labels":                []interface{}{"Service"},
+		"identifyingProperties": map[string]interface{}{"name": "api"},
+	}
+
+	// Call the handler
+	result, err := server.handleExportRelationshipsCSVTool(context.Background(), request)
+
+	// Assert the results: one column per property, rows ordered by direction, and values quoted where needed
+	assert.NoError(t, err)
+	assert.Equal(t, "direction,relationshipType,otherNodeLabels,otherNodeName,otherNodeId,confidence,note,source\n"+
+		"incoming,CALLS,Service,web,4:abc:2,,\"via gateway, with retries\",\n"+
+		"outgoing,DEPENDS_ON,Library;Go,auth,4:abc:3,0.9,,manual\n", getResultText(result))
+}
+
+// TestHandleExportRelationshipsCSVTool_Redacted tests that redacted relationship properties are masked in the CSV
+func TestHandleExportRelationshipsCSVTool_Redacted(t *testing.T) {
+	// Create a new mock controller
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Create mock graph store
+	mockGraph := mocks.NewMockStore(ctrl)
+
+	// Create MCP server with mocks and a redactor
+	server := &Server{graph: mockGraph, redactor: graph.NewRedactor([]string{"*secret*"})}
+
+	// Set up expectations
+	mockGraph.EXPECT().GetEntityWithRelationships(gomock.Any(), gomock.Any()).Return(graph.EntityWithRelationships{
+		Entity: graph.EntityDetails{Labels: []string{"Service"}, Properties: map[string]interface{}{"id": "4:abc:1", "name": "api"}},
+		Relationships: []graph.EntityRelationship{
+			{
+				ID:         "5:abc:1",
+				Type:       "CONNECTS_TO",
+				Direction:  "outgoing",
+				Properties: map[string]interface{}{"clientSecret": "hunter2", "protocol": "tcp"},
+				Node:       graph.EntityDetails{Labels: []string{"DataStore"}, Properties: map[string]interface{}{"id": "4:abc:2", "name": "db"}},
+			},
+		},
+	}, nil)
+
+	// Create tool request
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{
+		"labels":                []interface{}{"Service"},
+		"identifyingProperties": map[string]interface{}{"name": "api"},
+	}
+
+	// Call the handler
+	result, err := server.handleExportRelationshipsCSVTool(context.Background(), request)
+
+	// Assert the results
+	assert.NoError(t, err)
+	assert.Equal(t, "direction,relationshipType,otherNodeLabels,otherNodeName,otherNodeId,clientSecret,protocol\n"+
+		"outgoing,CONNECTS_TO,DataStore,db,4:abc:2,"+graph.RedactedValue+",tcp\n", getResultText(result))
+	assert.NotContains(t, getResultText(result), "hunter2")
+}
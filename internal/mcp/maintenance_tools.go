@@ -0,0 +1,522 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/sammcj/mcp-graph/internal/graph"
+)
+
+// setupMaintenanceTools configures the data quality and maintenance tools
+func (s *Server) setupMaintenanceTools() {
+	findDuplicatesTool := mcp.NewTool("find_duplicates",
+		mcp.WithDescription("Finds groups of entities with the same label that share the same values for the given key properties (e.g. Services with the same name), which usually indicates repeated imports. Returns at most 100 groups, largest first, so you can decide which entities to merge or delete."),
+		mcp.WithString("label",
+			mcp.Required(),
+			mcp.Description("Label of the entities to check (e.g. 'Service')."),
+		),
+		mcp.WithArray("keyProperties",
+			mcp.Required(),
+			mcp.Description("Property keys that together should uniquely identify an entity (e.g. ['name'] or ['groupId', 'artifactId']). Entities missing any of these properties are ignored."),
+			mcp.Items(map[string]interface{}{"type": "string"}),
+		),
+	)
+	s.addTool(findDuplicatesTool, s.handleFindDuplicatesTool)
+
+	copyPropertiesTool := mcp.NewTool("copy_properties",
+		mcp.WithDescription("Copies selected properties from one entity to another without merging the entities (e.g. when consolidating duplicates). Properties missing on the source are skipped. Returns the updated target entity."),
+		mcp.WithObject("from",
+			mcp.Required(),
+			mcp.Description("The source entity, as {labels, identifyingProperties}."),
+		),
+		mcp.WithObject("to",
+			mcp.Required(),
+			mcp.Description("The target entity, as {labels, identifyingProperties}."),
+		),
+		mcp.WithArray("keys",
+			mcp.Required(),
+			mcp.Description("Property keys to copy (e.g. ['description', 'ownerTeam'])."),
+			mcp.Items(map[string]interface{}{"type": "string"}),
+		),
+		mcp.WithBoolean("overwrite",
+			mcp.Description("Whether to overwrite properties already present on the target. Defaults to false."),
+		),
+	)
+	s.addTool(copyPropertiesTool, s.handleCopyPropertiesTool)
+
+	decayConfidenceTool := mcp.NewTool("decay_confidence",
+		mcp.WithDescription("Reduces the confidence property of relationships according to their age since lastModifiedAt, halving it every half-life, so that stale inferred relationships lose weight. Relationships without a confidence are left alone. Safe to run repeatedly: each run only applies the decay accrued since the previous run or the last modification. Returns the number of relationships updated."),
+		mcp.WithString("halfLife",
+			mcp.Required(),
+			mcp.Description("Time after which confidence halves, as a duration such as '720h' (30 days) or '168h' (7 days)."),
+		),
+		mcp.WithArray("relationshipTypes",
+			mcp.Description("Optional list of relationship types to decay (e.g., ['INFERRED_DEPENDS_ON']). If omitted or empty, all relationship types are decayed."),
+			mcp.Items(map[string]interface{}{"type": "string"}),
+		),
+	)
+	s.addTool(decayConfidenceTool, s.handleDecayConfidenceTool)
+
+	findMissingRelationshipsTool := mcp.NewTool("find_missing_relationships",
+		mcp.WithDescription("Finds entities with the given labels that lack a relationship of a given type in a given direction (e.g. Functions with no outgoing DEFINED_IN), as a targeted completeness check while building the graph incrementally. Returns at most 1000 entities."),
+		mcp.WithArray("labels",
+			mcp.Required(),
+			mcp.Description("Labels the entities must have (e.g. ['Function'])."),
+			mcp.Items(map[string]interface{}{"type": "string"}),
+		),
+		mcp.WithString("relationshipType",
+			mcp.Required(),
+			mcp.Description("The relationship type the entities should have (e.g. 'DEFINED_IN')."),
+		),
+		mcp.WithString("direction",
+			mcp.Description("Direction of the missing relationship relative to the entity: 'outgoing', 'incoming' or 'both' (either direction). Defaults to 'outgoing'."),
+			mcp.Enum(graph.DirectionOutgoing, graph.DirectionIncoming, graph.DirectionBoth),
+		),
+	)
+	s.addTool(findMissingRelationshipsTool, s.handleFindMissingRelationshipsTool)
+
+	setEntityStatusTool := mcp.NewTool("set_entity_status",
+		mcp.WithDescription("Sets an entity's analysis status, or clears it, and updates its lastModifiedAt. Use this to track progress when building the graph incrementally (e.g. mark a Function 'stub' when first referenced and 'fully_analysed' once its body has been processed). Returns the updated entity."),
+		mcp.WithArray("labels",
+			mcp.Required(),
+			mcp.Description("List of labels for the entity."),
+			mcp.Items(map[string]interface{}{"type": "string"}),
+		),
+		mcp.WithObject("identifyingProperties",
+			mcp.Required(),
+			mcp.Description("Map of properties to uniquely identify the entity."),
+		),
+		mcp.WithString("status",
+			mcp.Description("The new status. If omitted or empty, the entity's status is cleared."),
+			mcp.Enum(graph.EntityStatuses...),
+		),
+	)
+	s.addTool(setEntityStatusTool, s.handleSetEntityStatusTool)
+
+	deleteRelationshipsByTypeTool := mcp.NewTool("delete_relationships_by_type",
+		mcp.WithDescription("Deletes every relationship of the given type, e.g. to remove all stale CALLS relationships after a bad import before re-analysing. Nodes are not deleted. Large deletes are done in batches, so if one fails part way the earlier batches stay deleted. Use dryRun first to see how many relationships would be removed. Returns the number deleted (or that would be deleted)."),
+		mcp.WithString("relationshipType",
+			mcp.Required(),
+			mcp.Description("The relationship type to delete (e.g. 'CALLS')."),
+		),
+		mcp.WithBoolean("dryRun",
+			mcp.Description("If true, only counts the relationships that would be deleted. Defaults to false."),
+		),
+	)
+	s.addTool(deleteRelationshipsByTypeTool, s.handleDeleteRelationshipsByTypeTool)
+
+	pruneLowConfidenceTool := mcp.NewTool("prune_low_confidence",
+		mcp.WithDescription("Deletes relationships whose confidence property is below a threshold, e.g. to clear out noisy agent-inferred relationships (optionally after decay_confidence has reduced the confidence of stale ones). Relationships without a confidence are kept, and nodes are not deleted. Large deletes are done in batches, so if one fails part way the earlier batches stay deleted. Use dryRun first to see how many relationships would be removed. Returns the number deleted (or that would be deleted)."),
+		mcp.WithNumber("threshold",
+			mcp.Required(),
+			mcp.Description("Relationships with a confidence below this value (e.g. 0.3) are deleted. Confidence ranges from 0.0 to 1.0."),
+		),
+		mcp.WithArray("relationshipTypes",
+			mcp.Description("Optional list of relationship types to prune (e.g., ['INFERRED_DEPENDS_ON']). If omitted or empty, relationships of all types are pruned."),
+			mcp.Items(map[string]interface{}{"type": "string"}),
+		),
+		mcp.WithBoolean("dryRun",
+			mcp.Description("If true, only counts the relationships that would be deleted. Defaults to false."),
+		),
+	)
+	s.addTool(pruneLowConfidenceTool, s.handlePruneLowConfidenceTool)
+
+	bulkUpdateEntitiesTool := mcp.NewTool("bulk_update_entities",
+		mcp.WithDescription("Sets and/or removes properties on every entity matching the given labels and property filters, e.g. setting schemaVersion to 2 on all entities missing it ({\"property\": \"schemaVersion\", \"operator\": \"missing\"}). lastModifiedAt is updated on every matching entity. Large updates are done in batches, so if one fails part way the earlier batches stay updated. Use dryRun first to see how many entities would be updated. Returns the number updated (or that would be updated)."),
+		mcp.WithArray("labels",
+			mcp.Description("Optional list of labels; entities must have at least one of them. If omitted or empty, entities with any label are updated."),
+			mcp.Items(map[string]interface{}{"type": "string"}),
+		),
+		mcp.WithArray("filters",
+			mcp.Description(propertyFiltersDescription),
+			mcp.Items(propertyFilterSchema),
+		),
+		mcp.WithObject("setProperties",
+			mcp.Description("Properties to set on each matching entity, overwriting existing values (e.g. {\"schemaVersion\": 2})."),
+		),
+		mcp.WithArray("removeProperties",
+			mcp.Description("Property keys to remove from each matching entity."),
+			mcp.Items(map[string]interface{}{"type": "string"}),
+		),
+		mcp.WithBoolean("dryRun",
+			mcp.Description("If true, only counts the entities that would be updated. Defaults to false."),
+		),
+	)
+	s.addTool(bulkUpdateEntitiesTool, s.handleBulkUpdateEntitiesTool)
+
+	materializeTransitiveClosureTool := mcp.NewTool("materialize_transitive_closure",
+		mcp.WithDescription("Creates a direct relationship of closureType (e.g. TRANSITIVELY_DEPENDS_ON) from each entity to every entity it reaches by a path of relationshipType (e.g. DEPENDS_ON), so that 'does A transitively depend on B?' becomes a single-relationship lookup. Pairs that already have a closure relationship are skipped, so it is safe to run again after the graph changes; closure relationships whose path has gone are not removed, so delete the closure type first (delete_relationships_by_type) to rebuild it. Work is done in batches, so if one fails part way the earlier batches are kept. New relationships record the followed type in 'derivedFrom'. Returns the number of connected pairs and how many relationships were created."),
+		mcp.WithString("relationshipType",
+			mcp.Required(),
+			mcp.Description("The relationship type whose paths are followed (e.g. 'DEPENDS_ON')."),
+		),
+		mcp.WithString("closureType",
+			mcp.Required(),
+			mcp.Description("The type of the relationships to create (e.g. 'TRANSITIVELY_DEPENDS_ON'). Must differ from relationshipType."),
+		),
+		mcp.WithNumber("maxDepth",
+			mcp.Description("Maximum path length to follow. Defaults to 10 if not provided or invalid."),
+		),
+	)
+	s.addTool(materializeTransitiveClosureTool, s.handleMaterializeTransitiveClosureTool)
+
+	sweepExpiredTool := mcp.NewTool("sweep_expired",
+		mcp.WithDescription("Deletes nodes whose 'expiresAt' property has passed, together with their relationships, e.g. short-lived inferred annotations mixed in with long-lived architecture data. expiresAt may be a datetime or an ISO-8601 string in UTC (e.g. '2024-05-01T09:30:00Z'); nodes without it never expire. New nodes with a label that has a configured TTL get expiresAt automatically. Large deletes are done in batches, so if one fails part way the earlier batches stay deleted. Use dryRun first to see how many nodes would be removed. Returns the number deleted (or that would be deleted)."),
+		mcp.WithArray("labels",
+			mcp.Description("Optional list of labels; only expired nodes with at least one of them are deleted. If omitted or empty, expired nodes with any label are deleted."),
+			mcp.Items(map[string]interface{}{"type": "string"}),
+		),
+		mcp.WithBoolean("dryRun",
+			mcp.Description("If true, only counts the nodes that would be deleted. Defaults to false."),
+		),
+	)
+	s.addTool(sweepExpiredTool, s.handleSweepExpiredTool)
+
+	checkIntegrityTool := mcp.NewTool("check_integrity",
+		mcp.WithDescription("Checks the relationship graph against the server's configured integrity rules, e.g. after an import in which some relationships failed to be created. Reports entities missing a required relationship (missingRelationships) and relationships whose start or end node has an unexpected label (unexpectedEndpoints). Each broken rule is listed with the number of entities or relationships breaking it and the first 100 of them; rules that hold are left out. rulesChecked is 0 if no rules are configured."),
+	)
+	s.addTool(checkIntegrityTool, s.handleCheckIntegrityTool)
+}
+
+// handleFindDuplicatesTool handles the find_duplicates tool
+func (s *Server) handleFindDuplicatesTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	label, ok := request.Params.Arguments["label"].(string)
+	if !ok || label == "" {
+		return nil, errors.New("label must be a non-empty string")
+	}
+	keyProperties, err := parseOptionalStringArray(request, "keyProperties")
+	if err != nil {
+		return nil, err
+	}
+	if len(keyProperties) == 0 {
+		return nil, errors.New("at least one key property is required")
+	}
+
+	// Call graph store method
+	groups, err := s.graph.FindDuplicates(ctx, label, keyProperties)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find duplicates: %w", err)
+	}
+
+	// Return the result
+	resultJSON, err := json.Marshal(groups)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal duplicate groups: %w", err)
+	}
+	return mcp.NewToolResultText(string(resultJSON)), nil
+}
+
+// handleCopyPropertiesTool handles the copy_properties tool
+func (s *Server) handleCopyPropertiesTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	from, err := parseEntityLocator(request.Params.Arguments["from"])
+	if err != nil {
+		return nil, fmt.Errorf("invalid from: %w", err)
+	}
+	to, err := parseEntityLocator(request.Params.Arguments["to"])
+	if err != nil {
+		return nil, fmt.Errorf("invalid to: %w", err)
+	}
+	keys, err := parseOptionalStringArray(request, "keys")
+	if err != nil {
+		return nil, err
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("at least one key is required")
+	}
+	overwrite, err := parseOptionalBool(request, "overwrite", false)
+	if err != nil {
+		return nil, err
+	}
+
+	// Call graph store method
+	details, err := s.graph.CopyProperties(ctx, from, to, keys, overwrite)
+	if err != nil {
+		return nil, fmt.Errorf("failed to copy properties: %w", err)
+	}
+
+	// Return the result
+	resultJSON, err := json.Marshal(details)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal entity details: %w", err)
+	}
+	return mcp.NewToolResultText(string(resultJSON)), nil
+}
+
+// handleDecayConfidenceTool handles the decay_confidence tool
+func (s *Server) handleDecayConfidenceTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	halfLifeStr, ok := request.Params.Arguments["halfLife"].(string)
+	if !ok || halfLifeStr == "" {
+		return nil, errors.New("halfLife must be a non-empty string")
+	}
+	halfLife, err := time.ParseDuration(halfLifeStr)
+	if err != nil {
+		return nil, fmt.Errorf("halfLife must be a duration such as '720h': %w", err)
+	}
+	if halfLife <= 0 {
+		return nil, errors.New("halfLife must be positive")
+	}
+	relTypes, err := parseOptionalRelationshipTypes(request)
+	if err != nil {
+		return nil, err
+	}
+
+	// Call graph store method
+	updated, err := s.graph.DecayConfidence(ctx, relTypes, halfLife)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decay confidence: %w", err)
+	}
+
+	// Return the result
+	resultJSON, err := json.Marshal(map[string]interface{}{"updated": updated})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal decay result: %w", err)
+	}
+	return mcp.NewToolResultText(string(resultJSON)), nil
+}
+
+// handleFindMissingRelationshipsTool handles the find_missing_relationships tool
+func (s *Server) handleFindMissingRelationshipsTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	labels, err := parseOptionalStringArray(request, "labels")
+	if err != nil {
+		return nil, err
+	}
+	if len(labels) == 0 {
+		return nil, errors.New("at least one label is required")
+	}
+	relType, ok := request.Params.Arguments["relationshipType"].(string)
+	if !ok || relType == "" {
+		return nil, errors.New("relationshipType must be a non-empty string")
+	}
+	direction := graph.DirectionOutgoing
+	if directionArg, exists := request.Params.Arguments["direction"]; exists && directionArg != nil {
+		direction, ok = directionArg.(string)
+		if !ok {
+			return nil, errors.New("direction must be a string")
+		}
+	}
+
+	// Call graph store method
+	entities, err := s.graph.FindMissingRelationships(ctx, labels, relType, direction)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find missing relationships: %w", err)
+	}
+
+	// Return the result
+	resultJSON, err := json.Marshal(entities)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal entities: %w", err)
+	}
+	return mcp.NewToolResultText(string(resultJSON)), nil
+}
+
+// handleSetEntityStatusTool handles the set_entity_status tool
+func (s *Server) handleSetEntityStatusTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	locator, err := parseEntityLocator(request.Params.Arguments)
+	if err != nil {
+		return nil, err
+	}
+	var status string
+	if statusArg, exists := request.Params.Arguments["status"]; exists && statusArg != nil {
+		var ok bool
+		status, ok = statusArg.(string)
+		if !ok {
+			return nil, errors.New("status must be a string")
+		}
+	}
+
+	// Call graph store method
+	details, err := s.graph.SetEntityStatus(ctx, locator, status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set entity status: %w", err)
+	}
+
+	// Return the result
+	resultJSON, err := json.Marshal(details)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal entity details: %w", err)
+	}
+	return mcp.NewToolResultText(string(resultJSON)), nil
+}
+
+// handleDeleteRelationshipsByTypeTool handles the delete_relationships_by_type tool
+func (s *Server) handleDeleteRelationshipsByTypeTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	relType, ok := request.Params.Arguments["relationshipType"].(string)
+	if !ok || relType == "" {
+		return nil, errors.New("relationshipType must be a non-empty string")
+	}
+	dryRun, err := parseOptionalBool(request, "dryRun", false)
+	if err != nil {
+		return nil, err
+	}
+
+	// Call graph store method
+	count, err := s.graph.DeleteRelationshipsByType(ctx, relType, dryRun)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete relationships: %w", err)
+	}
+
+	// Return the result
+	resultJSON, err := json.Marshal(map[string]interface{}{
+		"relationshipType": relType,
+		"dryRun":           dryRun,
+		"deleted":          count,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal delete result: %w", err)
+	}
+	return mcp.NewToolResultText(string(resultJSON)), nil
+}
+
+// handlePruneLowConfidenceTool handles the prune_low_confidence tool
+func (s *Server) handlePruneLowConfidenceTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	threshold, ok := request.Params.Arguments["threshold"].(float64)
+	if !ok {
+		return nil, errors.New("threshold must be a number")
+	}
+	relTypes, err := parseOptionalRelationshipTypes(request)
+	if err != nil {
+		return nil, err
+	}
+	dryRun, err := parseOptionalBool(request, "dryRun", false)
+	if err != nil {
+		return nil, err
+	}
+
+	// Call graph store method
+	count, err := s.graph.PruneLowConfidence(ctx, relTypes, threshold, dryRun)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prune low-confidence relationships: %w", err)
+	}
+
+	// Return the result
+	resultJSON, err := json.Marshal(map[string]interface{}{
+		"threshold": threshold,
+		"dryRun":    dryRun,
+		"deleted":   count,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal prune result: %w", err)
+	}
+	return mcp.NewToolResultText(string(resultJSON)), nil
+}
+
+// handleBulkUpdateEntitiesTool handles the bulk_update_entities tool
+func (s *Server) handleBulkUpdateEntitiesTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	labels, err := parseOptionalStringArray(request, "labels")
+	if err != nil {
+		return nil, err
+	}
+	filters, err := parsePropertyFilters(request)
+	if err != nil {
+		return nil, err
+	}
+	setProps, err := parseOptionalObject(request, "setProperties")
+	if err != nil {
+		return nil, err
+	}
+	removeKeys, err := parseOptionalStringArray(request, "removeProperties")
+	if err != nil {
+		return nil, err
+	}
+	if len(setProps) == 0 && len(removeKeys) == 0 {
+		return nil, errors.New("at least one of setProperties or removeProperties is required")
+	}
+	dryRun, err := parseOptionalBool(request, "dryRun", false)
+	if err != nil {
+		return nil, err
+	}
+
+	// Call graph store method
+	count, err := s.graph.BulkUpdateEntities(ctx, labels, filters, setProps, removeKeys, dryRun)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update entities: %w", err)
+	}
+
+	// Return the result
+	resultJSON, err := json.Marshal(map[string]interface{}{
+		"dryRun":  dryRun,
+		"updated": count,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal update result: %w", err)
+	}
+	return mcp.NewToolResultText(string(resultJSON)), nil
+}
+
+// handleMaterializeTransitiveClosureTool handles the materialize_transitive_closure tool
+func (s *Server) handleMaterializeTransitiveClosureTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	relType, ok := request.Params.Arguments["relationshipType"].(string)
+	if !ok || relType == "" {
+		return nil, errors.New("relationshipType must be a non-empty string")
+	}
+	closureType, ok := request.Params.Arguments["closureType"].(string)
+	if !ok || closureType == "" {
+		return nil, errors.New("closureType must be a non-empty string")
+	}
+	maxDepth, err := parseOptionalInt(request, "maxDepth", 10)
+	if err != nil {
+		return nil, err
+	}
+
+	// Call graph store method
+	closure, err := s.graph.MaterializeTransitiveClosure(ctx, relType, closureType, maxDepth)
+	if err != nil {
+		return nil, fmt.Errorf("failed to materialise transitive closure: %w", err)
+	}
+
+	// Return the result
+	resultJSON, err := json.Marshal(closure)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal closure result: %w", err)
+	}
+	return mcp.NewToolResultText(string(resultJSON)), nil
+}
+
+// handleSweepExpiredTool handles the sweep_expired tool
+func (s *Server) handleSweepExpiredTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	labels, err := parseOptionalStringArray(request, "labels")
+	if err != nil {
+		return nil, err
+	}
+	dryRun, err := parseOptionalBool(request, "dryRun", false)
+	if err != nil {
+		return nil, err
+	}
+
+	// Call graph store method
+	count, err := s.graph.SweepExpired(ctx, labels, dryRun)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sweep expired nodes: %w", err)
+	}
+
+	// Return the result
+	resultJSON, err := json.Marshal(map[string]interface{}{
+		"dryRun":  dryRun,
+		"deleted": count,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal sweep result: %w", err)
+	}
+	return mcp.NewToolResultText(string(resultJSON)), nil
+}
+
+// handleCheckIntegrityTool handles the check_integrity tool
+func (s *Server) handleCheckIntegrityTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	// Call graph store method
+	report, err := s.graph.CheckIntegrity(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check integrity: %w", err)
+	}
+
+	// Return the result
+	resultJSON, err := json.Marshal(report)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal integrity report: %w", err)
+	}
+	return mcp.NewToolResultText(string(resultJSON)), nil
+}
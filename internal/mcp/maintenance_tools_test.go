@@ -0,0 +1,563 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sammcj/mcp-graph/internal/graph"
+	"github.com/sammcj/mcp-graph/internal/mcp/mocks"
+)
+
+// TestHandleFindDuplicatesTool tests the find_duplicates tool handler
+func TestHandleFindDuplicatesTool(t *testing.T) {
+	// Create a new mock controller
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Create mock graph store and service
+	mockGraph := mocks.NewMockStore(ctrl)
+	mockService := mocks.NewMockKnowledgeManager(ctrl)
+
+	// Create MCP server with mocks
+	server := &Server{
+		graph:   mockGraph,
+		service: mockService,
+	}
+
+	// Mock duplicate groups
+	groups := []graph.DuplicateGroup{
+		{
+			Key: map[string]interface{}{"name": "billing"},
+			Entities: []graph.EntityDetails{
+				{Labels: []string{"Service"}, Properties: map[string]interface{}{"id": "4:abc:1", "name": "billing"}},
+				{Labels: []string{"Service"}, Properties: map[string]interface{}{"id": "4:abc:7", "name": "billing"}},
+			},
+		},
+	}
+
+	// Set up expectations
+	mockGraph.EXPECT().FindDuplicates(gomock.Any(), gomock.Eq("Service"), gomock.Eq([]string{"name"})).Return(groups, nil)
+
+	// Create tool request
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{
+		"label":         "Service",
+		"keyProperties": []interface{}{"name"},
+	}
+
+	// Call the handler
+	result, err := server.handleFindDuplicatesTool(context.Background(), request)
+
+	// Assert the results
+	assert.NoError(t, err)
+
+	// Verify the result content
+	var resultData []map[string]interface{}
+	err = json.Unmarshal([]byte(getResultText(result)), &resultData)
+	assert.NoError(t, err)
+	assert.Len(t, resultData, 1)
+	assert.Len(t, resultData[0]["entities"], 2)
+}
+
+// TestHandleFindDuplicatesTool_MissingKeyProperties tests the find_duplicates tool handler without key properties
+func TestHandleFindDuplicatesTool_MissingKeyProperties(t *testing.T) {
+	// Create a new mock controller
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Create MCP server with mocks; no store calls are expected
+	server := &Server{
+		graph:   mocks.NewMockStore(ctrl),
+		service: mocks.NewMockKnowledgeManager(ctrl),
+	}
+
+	// Create tool request
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{
+		"label": "Service",
+	}
+
+	// Call the handler
+	_, err := server.handleFindDuplicatesTool(context.Background(), request)
+
+	// Assert the error
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "key property")
+}
+
+// TestHandleCopyPropertiesTool tests the copy_properties tool handler
+func TestHandleCopyPropertiesTool(t *testing.T) {
+	// Create a new mock controller
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Create mock graph store and service
+	mockGraph := mocks.NewMockStore(ctrl)
+	mockService := mocks.NewMockKnowledgeManager(ctrl)
+
+	// Create MCP server with mocks
+	server := &Server{
+		graph:   mockGraph,
+		service: mockService,
+	}
+
+	// Set up expectations
+	mockGraph.EXPECT().CopyProperties(
+		gomock.Any(),
+		gomock.Eq(graph.EntityLocator{Labels: []string{"Service"}, IdentifyingProperties: map[string]interface{}{"name": "billing-old"}}),
+		gomock.Eq(graph.EntityLocator{Labels: []string{"Service"}, IdentifyingProperties: map[string]interface{}{"name": "billing"}}),
+		gomock.Eq([]string{"ownerTeam"}),
+		gomock.Eq(true),
+	).Return(graph.EntityDetails{
+		Labels:     []string{"Service"},
+		Properties: map[string]interface{}{"id": "4:abc:1", "name": "billing", "ownerTeam": "payments"},
+	}, nil)
+
+	// Create tool request
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{
+		"from": map[string]interface{}{
+			"labels":                []interface{}{"Service"},
+			"identifyingProperties": map[string]interface{}{"name": "billing-old"},
+		},
+		"to": map[string]interface{}{
+			"labels":                []interface{}{"Service"},
+			"identifyingProperties": map[string]interface{}{"name": "billing"},
+		},
+		"keys":      []interface{}{"ownerTeam"},
+		"overwrite": true,
+	}
+
+	// Call the handler
+	result, err := server.handleCopyPropertiesTool(context.Background(), request)
+
+	// Assert the results
+	assert.NoError(t, err)
+
+	// Verify the result content
+	var resultData map[string]interface{}
+	err = json.Unmarshal([]byte(getResultText(result)), &resultData)
+	assert.NoError(t, err)
+	assert.Equal(t, "payments", resultData["properties"].(map[string]interface{})["ownerTeam"])
+}
+
+// TestHandleDecayConfidenceTool tests the decay_confidence tool handler
+func TestHandleDecayConfidenceTool(t *testing.T) {
+	// Create a new mock controller
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Create mock graph store and service
+	mockGraph := mocks.NewMockStore(ctrl)
+	mockService := mocks.NewMockKnowledgeManager(ctrl)
+
+	// Create MCP server with mocks
+	server := &Server{
+		graph:   mockGraph,
+		service: mockService,
+	}
+
+	// Set up expectations
+	mockGraph.EXPECT().DecayConfidence(
+		gomock.Any(),
+		gomock.Eq([]string{"INFERRED_DEPENDS_ON"}),
+		gomock.Eq(720*time.Hour),
+	).Return(int64(12), nil)
+
+	// Create tool request
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{
+		"halfLife":          "720h",
+		"relationshipTypes": []interface{}{"INFERRED_DEPENDS_ON"},
+	}
+
+	// Call the handler
+	result, err := server.handleDecayConfidenceTool(context.Background(), request)
+
+	// Assert the results
+	assert.NoError(t, err)
+
+	// Verify the result content
+	var resultData map[string]interface{}
+	err = json.Unmarshal([]byte(getResultText(result)), &resultData)
+	assert.NoError(t, err)
+	assert.Equal(t, float64(12), resultData["updated"])
+}
+
+// TestHandleDecayConfidenceTool_InvalidHalfLife tests that a malformed half-life is rejected
+func TestHandleDecayConfidenceTool_InvalidHalfLife(t *testing.T) {
+	// Create a new mock controller
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Create MCP server with mocks; no store calls are expected
+	server := &Server{
+		graph:   mocks.NewMockStore(ctrl),
+		service: mocks.NewMockKnowledgeManager(ctrl),
+	}
+
+	// Create tool request
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{
+		"halfLife": "30 days",
+	}
+
+	// Call the handler
+	_, err := server.handleDecayConfidenceTool(context.Background(), request)
+
+	// Assert the error
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "halfLife")
+}
+
+// TestHandleFindMissingRelationshipsTool tests the find_missing_relationships tool handler
+func TestHandleFindMissingRelationshipsTool(t *testing.T) {
+	// Create a new mock controller
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Create mock graph store and service
+	mockGraph := mocks.NewMockStore(ctrl)
+	mockService := mocks.NewMockKnowledgeManager(ctrl)
+
+	// Create MCP server with mocks
+	server := &Server{
+		graph:   mockGraph,
+		service: mockService,
+	}
+
+	// Mock entities
+	entities := []graph.EntityDetails{
+		{Labels: []string{"Function"}, Properties: map[string]interface{}{"id": "4:abc:3", "name": "parseArgs"}},
+	}
+
+	// Set up expectations: direction defaults to outgoing
+	mockGraph.EXPECT().FindMissingRelationships(gomock.Any(), gomock.Eq([]string{"Function"}), gomock.Eq("DEFINED_IN"), gomock.Eq(graph.DirectionOutgoing)).Return(entities, nil)
+
+	// Create tool request
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{
+		"labels":           []interface{}{"Function"},
+		"relationshipType": "DEFINED_IN",
+	}
+
+	// Call the handler
+	result, err := server.handleFindMissingRelationshipsTool(context.Background(), request)
+
+	// Assert the results
+	assert.NoError(t, err)
+
+	// Verify the result content
+	var resultData []map[string]interface{}
+	err = json.Unmarshal([]byte(getResultText(result)), &resultData)
+	assert.NoError(t, err)
+	assert.Len(t, resultData, 1)
+}
+
+// TestHandleSetEntityStatusTool tests the set_entity_status tool handler
+func TestHandleSetEntityStatusTool(t *testing.T) {
+	// Create a new mock controller
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Create mock graph store and service
+	mockGraph := mocks.NewMockStore(ctrl)
+	mockService := mocks.NewMockKnowledgeManager(ctrl)
+
+	// Create MCP server with mocks
+	server := &Server{
+		graph:   mockGraph,
+		service: mockService,
+	}
+
+	// Set up expectations
+	locator := graph.EntityLocator{Labels: []string{"Function"}, IdentifyingProperties: map[string]interface{}{"name": "main"}}
+	details := graph.EntityDetails{Labels: []string{"Function"}, Properties: map[string]interface{}{"id": "4:abc:1", "name": "main", "status": "fully_analysed"}}
+	mockGraph.EXPECT().SetEntityStatus(gomock.Any(), gomock.Eq(locator), gomock.Eq(graph.StatusFullyAnalysed)).Return(details, nil)
+
+	// Create tool request
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{
+		"labels":                []interface{}{"Function"},
+		"identifyingProperties": map[string]interface{}{"name": "main"},
+		"status":                "fully_analysed",
+	}
+
+	// Call the handler
+	result, err := server.handleSetEntityStatusTool(context.Background(), request)
+
+	// Assert the results
+	assert.NoError(t, err)
+
+	// Verify the result content
+	var resultData graph.EntityDetails
+	err = json.Unmarshal([]byte(getResultText(result)), &resultData)
+	assert.NoError(t, err)
+	assert.Equal(t, "fully_analysed", resultData.Properties["status"])
+}
+
+// TestHandleDeleteRelationshipsByTypeTool tests the delete_relationships_by_type tool handler with a dry run
+func TestHandleDeleteRelationshipsByTypeTool(t *testing.T) {
+	// Create a new mock controller
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Create mock graph store
+	mockGraph := mocks.NewMockStore(ctrl)
+
+	// Create MCP server with mocks
+	server := &Server{graph: mockGraph}
+
+	// Set up expectations
+	mockGraph.EXPECT().DeleteRelationshipsByType(gomock.Any(), "CALLS", true).Return(4200, nil)
+
+	// Create tool request
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{
+		"relationshipType": "CALLS",
+		"dryRun":           true,
+	}
+
+	// Call the handler
+	result, err := server.handleDeleteRelationshipsByTypeTool(context.Background(), request)
+
+	// Assert the results
+	assert.NoError(t, err)
+
+	// Verify the result content
+	var resultData map[string]interface{}
+	err = json.Unmarshal([]byte(getResultText(result)), &resultData)
+	assert.NoError(t, err)
+	assert.Equal(t, float64(4200), resultData["deleted"])
+	assert.Equal(t, true, resultData["dryRun"])
+
+	// A relationship type is required
+	request.Params.Arguments = map[string]interface{}{"dryRun": true}
+	_, err = server.handleDeleteRelationshipsByTypeTool(context.Background(), request)
+	assert.Error(t, err)
+}
+
+// TestHandlePruneLowConfidenceTool tests the prune_low_confidence tool handler
+func TestHandlePruneLowConfidenceTool(t *testing.T) {
+	// Create a new mock controller
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Create mock graph store
+	mockGraph := mocks.NewMockStore(ctrl)
+
+	// Create MCP server with mocks
+	server := &Server{graph: mockGraph}
+
+	// Set up expectations
+	mockGraph.EXPECT().PruneLowConfidence(gomock.Any(), []string{"INFERRED_DEPENDS_ON"}, 0.3, false).Return(17, nil)
+
+	// Create tool request
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{
+		"threshold":         0.3,
+		"relationshipTypes": []interface{}{"INFERRED_DEPENDS_ON"},
+	}
+
+	// Call the handler
+	result, err := server.handlePruneLowConfidenceTool(context.Background(), request)
+
+	// Assert the results
+	assert.NoError(t, err)
+
+	// Verify the result content
+	var resultData map[string]interface{}
+	err = json.Unmarshal([]byte(getResultText(result)), &resultData)
+	assert.NoError(t, err)
+	assert.Equal(t, float64(17), resultData["deleted"])
+	assert.Equal(t, false, resultData["dryRun"])
+
+	// A threshold is required
+	request.Params.Arguments = map[string]interface{}{"dryRun": true}
+	_, err = server.handlePruneLowConfidenceTool(context.Background(), request)
+	assert.Error(t, err)
+}
+
+// TestHandleSweepExpiredTool tests the sweep_expired tool handler
+func TestHandleSweepExpiredTool(t *testing.T) {
+	// Create a new mock controller
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Create mock graph store
+	mockGraph := mocks.NewMockStore(ctrl)
+
+	// Create MCP server with mocks
+	server := &Server{graph: mockGraph}
+
+	// Set up expectations
+	mockGraph.EXPECT().SweepExpired(gomock.Any(), []string{"Annotation"}, false).Return(12, nil)
+
+	// Create tool request
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{
+		"labels": []interface{}{"Annotation"},
+	}
+
+	// Call the handler
+	result, err := server.handleSweepExpiredTool(context.Background(), request)
+
+	// Assert the results
+	assert.NoError(t, err)
+
+	// Verify the result content
+	var resultData map[string]interface{}
+	err = json.Unmarshal([]byte(getResultText(result)), &resultData)
+	assert.NoError(t, err)
+	assert.Equal(t, float64(12), resultData["deleted"])
+	assert.Equal(t, false, resultData["dryRun"])
+
+	// Labels must be strings
+	request.Params.Arguments = map[string]interface{}{"labels": []interface{}{1}}
+	_, err = server.handleSweepExpiredTool(context.Background(), request)
+	assert.Error(t, err)
+}
+
+// TestHandleBulkUpdateEntitiesTool tests the bulk_update_entities tool handler
+func TestHandleBulkUpdateEntitiesTool(t *testing.T) {
+	// Create a new mock controller
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Create mock graph store
+	mockGraph := mocks.NewMockStore(ctrl)
+
+	// Create MCP server with mocks
+	server := &Server{graph: mockGraph}
+
+	// Set up expectations
+	filters := []graph.PropertyFilter{{Property: "schemaVersion", Operator: graph.FilterOpMissing}}
+	setProps := map[string]interface{}{"schemaVersion": float64(2)}
+	mockGraph.EXPECT().BulkUpdateEntities(gomock.Any(), []string{"Service"}, filters, setProps, []string{"legacyId"}, false).Return(37, nil)
+
+	// Create tool request
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{
+		"labels":           []interface{}{"Service"},
+		"filters":          []interface{}{map[string]interface{}{"property": "schemaVersion", "operator": "missing"}},
+		"setProperties":    map[string]interface{}{"schemaVersion": float64(2)},
+		"removeProperties": []interface{}{"legacyId"},
+	}
+
+	// Call the handler
+	result, err := server.handleBulkUpdateEntitiesTool(context.Background(), request)
+
+	// Assert the results
+	assert.NoError(t, err)
+
+	// Verify the result content
+	var resultData map[string]interface{}
+	err = json.Unmarshal([]byte(getResultText(result)), &resultData)
+	assert.NoError(t, err)
+	assert.Equal(t, float64(37), resultData["updated"])
+	assert.Equal(t, false, resultData["dryRun"])
+
+	// Something to set or remove is required
+	request.Params.Arguments = map[string]interface{}{"labels": []interface{}{"Service"}}
+	_, err = server.handleBulkUpdateEntitiesTool(context.Background(), request)
+	assert.Error(t, err)
+}
+
+// TestHandleMaterializeTransitiveClosureTool tests the materialize_transitive_closure tool handler
+func TestHandleMaterializeTransitiveClosureTool(t *testing.T) {
+	// Create a new mock controller
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Create mock graph store
+	mockGraph := mocks.NewMockStore(ctrl)
+
+	// Create MCP server with mocks
+	server := &Server{graph: mockGraph}
+
+	// Set up expectations - maxDepth defaults to 10
+	mockGraph.EXPECT().MaterializeTransitiveClosure(gomock.Any(), "DEPENDS_ON", "TRANSITIVELY_DEPENDS_ON", 10).Return(graph.TransitiveClosureResult{
+		RelationshipType: "DEPENDS_ON",
+		ClosureType:      "TRANSITIVELY_DEPENDS_ON",
+		Pairs:            120,
+		Created:          15,
+	}, nil)
+
+	// Create tool request
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{
+		"relationshipType": "DEPENDS_ON",
+		"closureType":      "TRANSITIVELY_DEPENDS_ON",
+	}
+
+	// Call the handler
+	result, err := server.handleMaterializeTransitiveClosureTool(context.Background(), request)
+
+	// Assert the results
+	assert.NoError(t, err)
+
+	// Verify the result content
+	var resultData graph.TransitiveClosureResult
+	err = json.Unmarshal([]byte(getResultText(result)), &resultData)
+	assert.NoError(t, err)
+	assert.Equal(t, 120, resultData.Pairs)
+	assert.Equal(t, 15, resultData.Created)
+
+	// The closure type is required
+	request.Params.Arguments = map[string]interface{}{"relationshipType": "DEPENDS_ON"}
+	_, err = server.handleMaterializeTransitiveClosureTool(context.Background(), request)
+	assert.Error(t, err)
+}
+
+// TestHandleCheckIntegrityTool tests the check_integrity tool handler
+func TestHandleCheckIntegrityTool(t *testing.T) {
+	// Create a new mock controller
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Create mock graph store
+	mockGraph := mocks.NewMockStore(ctrl)
+
+	// Create MCP server with mocks
+	server := &Server{graph: mockGraph}
+
+	// Set up expectations
+	report := graph.IntegrityReport{
+		RulesChecked: 2,
+		IssueCount:   1,
+		MissingRelationships: []graph.MissingRelationshipIssue{
+			{
+				Rule:  graph.RequiredRelationshipRule{Label: "Function", RelationshipType: "DEFINED_IN"},
+				Count: 1,
+				Entities: []graph.EntityDetails{
+					{Labels: []string{"Function"}, Properties: map[string]interface{}{"id": "4:abc:1", "name": "orphan"}},
+				},
+			},
+		},
+		UnexpectedEndpoints: []graph.UnexpectedEndpointIssue{},
+	}
+	mockGraph.EXPECT().CheckIntegrity(gomock.Any()).Return(report, nil)
+
+	// Call the handler
+	result, err := server.handleCheckIntegrityTool(context.Background(), mcp.CallToolRequest{})
+
+	// Assert the results
+	assert.NoError(t, err)
+
+	// Verify the result content
+	var resultData graph.IntegrityReport
+	err = json.Unmarshal([]byte(getResultText(result)), &resultData)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, resultData.RulesChecked)
+	assert.Equal(t, int64(1), resultData.IssueCount)
+	assert.Len(t, resultData.MissingRelationships, 1)
+	assert.Equal(t, "DEFINED_IN", resultData.MissingRelationships[0].Rule.RelationshipType)
+	assert.Equal(t, "orphan", resultData.MissingRelationships[0].Entities[0].Properties["name"])
+	assert.Empty(t, resultData.UnexpectedEndpoints)
+}
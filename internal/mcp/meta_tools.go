@@ -0,0 +1,91 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/sammcj/mcp-graph/internal/usage"
+)
+
+// listToolsRequest is the JSON-RPC tools/list request used to read the registered tools back from the MCP server
+var listToolsRequest = json.RawMessage(`{"jsonrpc":"2.0","id":"list_tools","method":"tools/list"}`)
+
+// setupMetaTools configures the tools describing the server itself
+func (s *Server) setupMetaTools() {
+	listToolsTool := mcp.NewTool("list_tools",
+		mcp.WithDescription("Lists every tool provided by this server with its description and JSON schema for its arguments, sorted by name. Useful for documentation generators and for clients that need to discover the available tools without an MCP tools/list call."),
+	)
+	s.addTool(listToolsTool, s.handleListToolsTool)
+
+	usageStatsTool := mcp.NewTool("usage_stats",
+		mcp.WithDescription("Reports how many tool calls each client session made, per tool and identified by an opaque alias, and the total calls to each tool by all clients within a recent window. Only available when usage tracking is enabled; intended for operators checking how agents use the server."),
+		mcp.WithString("window",
+			mcp.Description("Period to report on, ending now, as a duration such as '1h' or '15m'. Defaults to, and is capped at, the configured usage window."),
+		),
+	)
+	s.addTool(usageStatsTool, s.handleUsageStatsTool)
+}
+
+// SetUsageTracker sets the tracker that counts the tool calls made by each client session
+func (s *Server) SetUsageTracker(tracker *usage.Tracker) {
+	s.usage = tracker
+}
+
+// ListTools returns the definitions (name, description and argument schema) of all registered tools, sorted by name
+func (s *Server) ListTools(ctx context.Context) ([]mcp.Tool, error) {
+	response := s.server.HandleMessage(ctx, listToolsRequest)
+	switch resp := response.(type) {
+	case mcp.JSONRPCResponse:
+		result, ok := resp.Result.(mcp.ListToolsResult)
+		if !ok {
+			return nil, fmt.Errorf("unexpected tools/list result type %T", resp.Result)
+		}
+		return result.Tools, nil
+	case mcp.JSONRPCError:
+		return nil, fmt.Errorf("failed to list tools: %s", resp.Error.Message)
+	default:
+		return nil, fmt.Errorf("unexpected tools/list response type %T", response)
+	}
+}
+
+// handleListToolsTool handles the list_tools tool
+func (s *Server) handleListToolsTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	tools, err := s.ListTools(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Return the result
+	resultJSON, err := json.Marshal(tools)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal tool definitions: %w", err)
+	}
+	return mcp.NewToolResultText(string(resultJSON)), nil
+}
+
+// handleUsageStatsTool handles the usage_stats tool
+func (s *Server) handleUsageStatsTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if !s.usage.Enabled() {
+		return nil, errors.New("usage tracking is not enabled")
+	}
+	var window time.Duration
+	if windowStr, ok := request.Params.Arguments["window"].(string); ok && windowStr != "" {
+		var err error
+		window, err = time.ParseDuration(windowStr)
+		if err != nil {
+			return nil, fmt.Errorf("window must be a duration such as '1h': %w", err)
+		}
+	}
+
+	// Return the result
+	resultJSON, err := json.Marshal(s.usage.Stats(window))
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal usage stats: %w", err)
+	}
+	return mcp.NewToolResultText(string(resultJSON)), nil
+}
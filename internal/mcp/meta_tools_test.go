@@ -0,0 +1,117 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sammcj/mcp-graph/internal/mcp/mocks"
+	"github.com/sammcj/mcp-graph/internal/usage"
+)
+
+// TestListTools tests that the registered tool definitions are returned, sorted by name
+func TestListTools(t *testing.T) {
+	// Create a new mock controller
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Create MCP server with all tools registered; no store calls are expected
+	server := NewServer("test", "0.0.0", mocks.NewMockStore(ctrl))
+	server.SetupTools()
+
+	tools, err := server.ListTools(context.Background())
+	assert.NoError(t, err)
+
+	names := make([]string, len(tools))
+	for i, tool := range tools {
+		names[i] = tool.Name
+	}
+	assert.Contains(t, names, "query_knowledge_graph")
+	assert.Contains(t, names, "list_tools")
+	assert.IsNonDecreasing(t, names)
+}
+
+// TestHandleListToolsTool tests the list_tools tool handler
+func TestHandleListToolsTool(t *testing.T) {
+	// Create a new mock controller
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Create MCP server with all tools registered; no store calls are expected
+	server := NewServer("test", "0.0.0", mocks.NewMockStore(ctrl))
+	server.SetupTools()
+
+	// Call the handler
+	result, err := server.handleListToolsTool(context.Background(), mcp.CallToolRequest{})
+
+	// Assert the results
+	assert.NoError(t, err)
+
+	// Verify the result content includes the argument schemas
+	var resultData []map[string]interface{}
+	err = json.Unmarshal([]byte(getResultText(result)), &resultData)
+	assert.NoError(t, err)
+	var queryTool map[string]interface{}
+	for _, tool := range resultData {
+		if tool["name"] == "query_knowledge_graph" {
+			queryTool = tool
+		}
+	}
+	if assert.NotNil(t, queryTool) {
+		assert.NotEmpty(t, queryTool["description"])
+		schema := queryTool["inputSchema"].(map[string]interface{})
+		assert.Contains(t, schema["properties"], "query")
+	}
+}
+
+// TestHandleUsageStatsTool tests that tool calls are counted per client and reported by the usage_stats tool
+func TestHandleUsageStatsTool(t *testing.T) {
+	// Create a new mock controller
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Create MCP server with all tools registered and usage tracking enabled
+	server := NewServer("test", "0.0.0", mocks.NewMockStore(ctrl))
+	server.SetUsageTracker(usage.NewTracker(time.Hour))
+	server.SetupTools()
+
+	// Make two calls through the MCP server, without a client session
+	for i := 0; i < 2; i++ {
+		response := server.server.HandleMessage(context.Background(), json.RawMessage(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"list_tools"}}`))
+		_, ok := response.(mcp.JSONRPCResponse)
+		assert.True(t, ok)
+	}
+
+	// Call the handler
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{"window": "10m"}
+	result, err := server.handleUsageStatsTool(context.Background(), request)
+
+	// Assert the results
+	assert.NoError(t, err)
+	var stats usage.Stats
+	err = json.Unmarshal([]byte(getResultText(result)), &stats)
+	assert.NoError(t, err)
+	assert.Equal(t, "10m0s", stats.Window)
+	assert.Equal(t, int64(2), stats.Total)
+	assert.Equal(t, map[string]int64{"list_tools": 2}, stats.Clients[server.usage.Alias("unknown")].Operations)
+	assert.Equal(t, map[string]int64{"list_tools": 2}, stats.Operations)
+
+	// Invalid windows are rejected
+	request.Params.Arguments = map[string]interface{}{"window": "soon"}
+	_, err = server.handleUsageStatsTool(context.Background(), request)
+	assert.Error(t, err)
+}
+
+// TestHandleUsageStatsTool_Disabled tests that usage_stats fails when usage tracking isn't enabled
+func TestHandleUsageStatsTool_Disabled(t *testing.T) {
+	server := &Server{}
+
+	_, err := server.handleUsageStatsTool(context.Background(), mcp.CallToolRequest{})
+	assert.EqualError(t, err, "usage tracking is not enabled")
+}
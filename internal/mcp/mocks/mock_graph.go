@@ -7,6 +7,7 @@ package mocks
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 	graph "github.com/sammcj/mcp-graph/internal/graph"
@@ -35,6 +36,21 @@ func (m *MockStore) EXPECT() *MockStoreMockRecorder {
 	return m.recorder
 }
 
+// BatchCreateNodes mocks base method.
+func (m *MockStore) BatchCreateNodes(ctx context.Context, nodeType string, properties []map[string]interface{}) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BatchCreateNodes", ctx, nodeType, properties)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BatchCreateNodes indicates an expected call of BatchCreateNodes.
+func (mr *MockStoreMockRecorder) BatchCreateNodes(ctx, nodeType, properties interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchCreateNodes", reflect.TypeOf((*MockStore)(nil).BatchCreateNodes), ctx, nodeType, properties)
+}
+
 // BatchFindOrCreateEntities mocks base method.
 func (m *MockStore) BatchFindOrCreateEntities(ctx context.Context, inputs []graph.EntityInput) ([]graph.EntityDetails, []error, error) {
 	m.ctrl.T.Helper()
@@ -67,6 +83,126 @@ func (mr *MockStoreMockRecorder) BatchFindOrCreateRelationships(ctx, inputs inte
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchFindOrCreateRelationships", reflect.TypeOf((*MockStore)(nil).BatchFindOrCreateRelationships), ctx, inputs)
 }
 
+// BulkUpdateEntities mocks base method.
+func (m *MockStore) BulkUpdateEntities(ctx context.Context, labels []string, filters []graph.PropertyFilter, setProps map[string]interface{}, removeKeys []string, dryRun bool) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BulkUpdateEntities", ctx, labels, filters, setProps, removeKeys, dryRun)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BulkUpdateEntities indicates an expected call of BulkUpdateEntities.
+func (mr *MockStoreMockRecorder) BulkUpdateEntities(ctx, labels, filters, setProps, removeKeys, dryRun interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BulkUpdateEntities", reflect.TypeOf((*MockStore)(nil).BulkUpdateEntities), ctx, labels, filters, setProps, removeKeys, dryRun)
+}
+
+// Capabilities mocks base method.
+func (m *MockStore) Capabilities(ctx context.Context) (graph.Capabilities, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Capabilities", ctx)
+	ret0, _ := ret[0].(graph.Capabilities)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Capabilities indicates an expected call of Capabilities.
+func (mr *MockStoreMockRecorder) Capabilities(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Capabilities", reflect.TypeOf((*MockStore)(nil).Capabilities), ctx)
+}
+
+// Centrality mocks base method.
+func (m *MockStore) Centrality(ctx context.Context, labels, relationshipTypes []string, topN int) (graph.CentralityResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Centrality", ctx, labels, relationshipTypes, topN)
+	ret0, _ := ret[0].(graph.CentralityResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Centrality indicates an expected call of Centrality.
+func (mr *MockStoreMockRecorder) Centrality(ctx, labels, relationshipTypes, topN interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Centrality", reflect.TypeOf((*MockStore)(nil).Centrality), ctx, labels, relationshipTypes, topN)
+}
+
+// CheckIntegrity mocks base method.
+func (m *MockStore) CheckIntegrity(ctx context.Context) (graph.IntegrityReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckIntegrity", ctx)
+	ret0, _ := ret[0].(graph.IntegrityReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckIntegrity indicates an expected call of CheckIntegrity.
+func (mr *MockStoreMockRecorder) CheckIntegrity(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckIntegrity", reflect.TypeOf((*MockStore)(nil).CheckIntegrity), ctx)
+}
+
+// CommonDependencies mocks base method.
+func (m *MockStore) CommonDependencies(ctx context.Context, locators []graph.EntityLocator, relationshipTypes []string, maxDepth int) (graph.CommonDependenciesResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CommonDependencies", ctx, locators, relationshipTypes, maxDepth)
+	ret0, _ := ret[0].(graph.CommonDependenciesResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CommonDependencies indicates an expected call of CommonDependencies.
+func (mr *MockStoreMockRecorder) CommonDependencies(ctx, locators, relationshipTypes, maxDepth interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CommonDependencies", reflect.TypeOf((*MockStore)(nil).CommonDependencies), ctx, locators, relationshipTypes, maxDepth)
+}
+
+// CopyProperties mocks base method.
+func (m *MockStore) CopyProperties(ctx context.Context, from, to graph.EntityLocator, keys []string, overwrite bool) (graph.EntityDetails, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CopyProperties", ctx, from, to, keys, overwrite)
+	ret0, _ := ret[0].(graph.EntityDetails)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CopyProperties indicates an expected call of CopyProperties.
+func (mr *MockStoreMockRecorder) CopyProperties(ctx, from, to, keys, overwrite interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CopyProperties", reflect.TypeOf((*MockStore)(nil).CopyProperties), ctx, from, to, keys, overwrite)
+}
+
+// CountEntities mocks base method.
+func (m *MockStore) CountEntities(ctx context.Context, labels []string, filters []graph.PropertyFilter) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountEntities", ctx, labels, filters)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountEntities indicates an expected call of CountEntities.
+func (mr *MockStoreMockRecorder) CountEntities(ctx, labels, filters interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountEntities", reflect.TypeOf((*MockStore)(nil).CountEntities), ctx, labels, filters)
+}
+
+// CouplingMetrics mocks base method.
+func (m *MockStore) CouplingMetrics(ctx context.Context, label string, relationshipTypes []string) ([]graph.CouplingMetric, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CouplingMetrics", ctx, label, relationshipTypes)
+	ret0, _ := ret[0].([]graph.CouplingMetric)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CouplingMetrics indicates an expected call of CouplingMetrics.
+func (mr *MockStoreMockRecorder) CouplingMetrics(ctx, label, relationshipTypes interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CouplingMetrics", reflect.TypeOf((*MockStore)(nil).CouplingMetrics), ctx, label, relationshipTypes)
+}
+
 // CreateEdge mocks base method.
 func (m *MockStore) CreateEdge(ctx context.Context, fromID, toID, relationshipType string, properties map[string]interface{}) (string, error) {
 	m.ctrl.T.Helper()
@@ -97,6 +233,37 @@ func (mr *MockStoreMockRecorder) CreateNode(ctx, nodeType, properties interface{
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateNode", reflect.TypeOf((*MockStore)(nil).CreateNode), ctx, nodeType, properties)
 }
 
+// CreateNodeIfNotExists mocks base method.
+func (m *MockStore) CreateNodeIfNotExists(ctx context.Context, nodeType, key string, value interface{}, properties map[string]interface{}) (string, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateNodeIfNotExists", ctx, nodeType, key, value, properties)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CreateNodeIfNotExists indicates an expected call of CreateNodeIfNotExists.
+func (mr *MockStoreMockRecorder) CreateNodeIfNotExists(ctx, nodeType, key, value, properties interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateNodeIfNotExists", reflect.TypeOf((*MockStore)(nil).CreateNodeIfNotExists), ctx, nodeType, key, value, properties)
+}
+
+// DecayConfidence mocks base method.
+func (m *MockStore) DecayConfidence(ctx context.Context, relationshipTypes []string, halfLife time.Duration) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DecayConfidence", ctx, relationshipTypes, halfLife)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DecayConfidence indicates an expected call of DecayConfidence.
+func (mr *MockStoreMockRecorder) DecayConfidence(ctx, relationshipTypes, halfLife interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DecayConfidence", reflect.TypeOf((*MockStore)(nil).DecayConfidence), ctx, relationshipTypes, halfLife)
+}
+
 // DeleteEdge mocks base method.
 func (m *MockStore) DeleteEdge(ctx context.Context, id string) error {
 	m.ctrl.T.Helper()
@@ -125,19 +292,109 @@ func (mr *MockStoreMockRecorder) DeleteNode(ctx, id interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteNode", reflect.TypeOf((*MockStore)(nil).DeleteNode), ctx, id)
 }
 
+// DeleteRelationshipsByType mocks base method.
+func (m *MockStore) DeleteRelationshipsByType(ctx context.Context, relType string, dryRun bool) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteRelationshipsByType", ctx, relType, dryRun)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteRelationshipsByType indicates an expected call of DeleteRelationshipsByType.
+func (mr *MockStoreMockRecorder) DeleteRelationshipsByType(ctx, relType, dryRun interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRelationshipsByType", reflect.TypeOf((*MockStore)(nil).DeleteRelationshipsByType), ctx, relType, dryRun)
+}
+
+// DependencyPath mocks base method.
+func (m *MockStore) DependencyPath(ctx context.Context, from, to graph.EntityLocator, relationshipTypes []string, maxDepth int) (graph.PathResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DependencyPath", ctx, from, to, relationshipTypes, maxDepth)
+	ret0, _ := ret[0].(graph.PathResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DependencyPath indicates an expected call of DependencyPath.
+func (mr *MockStoreMockRecorder) DependencyPath(ctx, from, to, relationshipTypes, maxDepth interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DependencyPath", reflect.TypeOf((*MockStore)(nil).DependencyPath), ctx, from, to, relationshipTypes, maxDepth)
+}
+
+// DescribeEntityModel mocks base method.
+func (m *MockStore) DescribeEntityModel(ctx context.Context) (graph.EntityModel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DescribeEntityModel", ctx)
+	ret0, _ := ret[0].(graph.EntityModel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeEntityModel indicates an expected call of DescribeEntityModel.
+func (mr *MockStoreMockRecorder) DescribeEntityModel(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeEntityModel", reflect.TypeOf((*MockStore)(nil).DescribeEntityModel), ctx)
+}
+
+// ExportSubgraph mocks base method.
+func (m *MockStore) ExportSubgraph(ctx context.Context, labels []string) (graph.GraphSnapshot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportSubgraph", ctx, labels)
+	ret0, _ := ret[0].(graph.GraphSnapshot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExportSubgraph indicates an expected call of ExportSubgraph.
+func (mr *MockStoreMockRecorder) ExportSubgraph(ctx, labels interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportSubgraph", reflect.TypeOf((*MockStore)(nil).ExportSubgraph), ctx, labels)
+}
+
+// FindByFilePath mocks base method.
+func (m *MockStore) FindByFilePath(ctx context.Context, labels []string, filePath string, prefix bool, limit int) ([]graph.EntityDetails, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByFilePath", ctx, labels, filePath, prefix, limit)
+	ret0, _ := ret[0].([]graph.EntityDetails)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByFilePath indicates an expected call of FindByFilePath.
+func (mr *MockStoreMockRecorder) FindByFilePath(ctx, labels, filePath, prefix, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByFilePath", reflect.TypeOf((*MockStore)(nil).FindByFilePath), ctx, labels, filePath, prefix, limit)
+}
+
+// FindBySource mocks base method.
+func (m *MockStore) FindBySource(ctx context.Context, labels []string, source string, limit int) ([]graph.EntityDetails, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindBySource", ctx, labels, source, limit)
+	ret0, _ := ret[0].([]graph.EntityDetails)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindBySource indicates an expected call of FindBySource.
+func (mr *MockStoreMockRecorder) FindBySource(ctx, labels, source, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindBySource", reflect.TypeOf((*MockStore)(nil).FindBySource), ctx, labels, source, limit)
+}
+
 // FindDependencies mocks base method.
-func (m *MockStore) FindDependencies(ctx context.Context, labels []string, identifyingProperties map[string]interface{}, relationshipTypes []string, maxDepth int) (graph.DependencyResult, error) {
+func (m *MockStore) FindDependencies(ctx context.Context, labels []string, identifyingProperties map[string]interface{}, relationshipTypes []string, relationshipPropertyFilters map[string]interface{}, maxDepth int) (graph.DependencyResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindDependencies", ctx, labels, identifyingProperties, relationshipTypes, maxDepth)
+	ret := m.ctrl.Call(m, "FindDependencies", ctx, labels, identifyingProperties, relationshipTypes, relationshipPropertyFilters, maxDepth)
 	ret0, _ := ret[0].(graph.DependencyResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // FindDependencies indicates an expected call of FindDependencies.
-func (mr *MockStoreMockRecorder) FindDependencies(ctx, labels, identifyingProperties, relationshipTypes, maxDepth interface{}) *gomock.Call {
+func (mr *MockStoreMockRecorder) FindDependencies(ctx, labels, identifyingProperties, relationshipTypes, relationshipPropertyFilters, maxDepth interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindDependencies", reflect.TypeOf((*MockStore)(nil).FindDependencies), ctx, labels, identifyingProperties, relationshipTypes, maxDepth)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindDependencies", reflect.TypeOf((*MockStore)(nil).FindDependencies), ctx, labels, identifyingProperties, relationshipTypes, relationshipPropertyFilters, maxDepth)
 }
 
 // FindDependents mocks base method.
@@ -155,6 +412,81 @@ func (mr *MockStoreMockRecorder) FindDependents(ctx, labels, identifyingProperti
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindDependents", reflect.TypeOf((*MockStore)(nil).FindDependents), ctx, labels, identifyingProperties, relationshipTypes, maxDepth)
 }
 
+// FindDuplicates mocks base method.
+func (m *MockStore) FindDuplicates(ctx context.Context, label string, keyProperties []string) ([]graph.DuplicateGroup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindDuplicates", ctx, label, keyProperties)
+	ret0, _ := ret[0].([]graph.DuplicateGroup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindDuplicates indicates an expected call of FindDuplicates.
+func (mr *MockStoreMockRecorder) FindDuplicates(ctx, label, keyProperties interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindDuplicates", reflect.TypeOf((*MockStore)(nil).FindDuplicates), ctx, label, keyProperties)
+}
+
+// FindEntities mocks base method.
+func (m *MockStore) FindEntities(ctx context.Context, labels []string, filters []graph.PropertyFilter, limit int) ([]graph.EntityDetails, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindEntities", ctx, labels, filters, limit)
+	ret0, _ := ret[0].([]graph.EntityDetails)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindEntities indicates an expected call of FindEntities.
+func (mr *MockStoreMockRecorder) FindEntities(ctx, labels, filters, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindEntities", reflect.TypeOf((*MockStore)(nil).FindEntities), ctx, labels, filters, limit)
+}
+
+// FindMissingRelationships mocks base method.
+func (m *MockStore) FindMissingRelationships(ctx context.Context, labels []string, relationshipType, direction string) ([]graph.EntityDetails, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindMissingRelationships", ctx, labels, relationshipType, direction)
+	ret0, _ := ret[0].([]graph.EntityDetails)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindMissingRelationships indicates an expected call of FindMissingRelationships.
+func (mr *MockStoreMockRecorder) FindMissingRelationships(ctx, labels, relationshipType, direction interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindMissingRelationships", reflect.TypeOf((*MockStore)(nil).FindMissingRelationships), ctx, labels, relationshipType, direction)
+}
+
+// FindModifiedSince mocks base method.
+func (m *MockStore) FindModifiedSince(ctx context.Context, labels []string, since time.Time, limit int) ([]graph.EntityDetails, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindModifiedSince", ctx, labels, since, limit)
+	ret0, _ := ret[0].([]graph.EntityDetails)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindModifiedSince indicates an expected call of FindModifiedSince.
+func (mr *MockStoreMockRecorder) FindModifiedSince(ctx, labels, since, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindModifiedSince", reflect.TypeOf((*MockStore)(nil).FindModifiedSince), ctx, labels, since, limit)
+}
+
+// FindNearestByLabel mocks base method.
+func (m *MockStore) FindNearestByLabel(ctx context.Context, from graph.EntityLocator, targetLabel string, relationshipTypes []string, maxDepth int) (graph.PathResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindNearestByLabel", ctx, from, targetLabel, relationshipTypes, maxDepth)
+	ret0, _ := ret[0].(graph.PathResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindNearestByLabel indicates an expected call of FindNearestByLabel.
+func (mr *MockStoreMockRecorder) FindNearestByLabel(ctx, from, targetLabel, relationshipTypes, maxDepth interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindNearestByLabel", reflect.TypeOf((*MockStore)(nil).FindNearestByLabel), ctx, from, targetLabel, relationshipTypes, maxDepth)
+}
+
 // FindNeighbors mocks base method.
 func (m *MockStore) FindNeighbors(ctx context.Context, labels []string, identifyingProperties map[string]interface{}, maxDepth int) (graph.NeighborsResult, error) {
 	m.ctrl.T.Helper()
@@ -185,6 +517,21 @@ func (mr *MockStoreMockRecorder) FindOrCreateEntity(ctx, input interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindOrCreateEntity", reflect.TypeOf((*MockStore)(nil).FindOrCreateEntity), ctx, input)
 }
 
+// FindOrCreateEntityWithChanges mocks base method.
+func (m *MockStore) FindOrCreateEntityWithChanges(ctx context.Context, input graph.EntityInput) (graph.EntityUpsertResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindOrCreateEntityWithChanges", ctx, input)
+	ret0, _ := ret[0].(graph.EntityUpsertResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindOrCreateEntityWithChanges indicates an expected call of FindOrCreateEntityWithChanges.
+func (mr *MockStoreMockRecorder) FindOrCreateEntityWithChanges(ctx, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindOrCreateEntityWithChanges", reflect.TypeOf((*MockStore)(nil).FindOrCreateEntityWithChanges), ctx, input)
+}
+
 // FindOrCreateRelationship mocks base method.
 func (m *MockStore) FindOrCreateRelationship(ctx context.Context, input graph.RelationshipInput) (map[string]interface{}, error) {
 	m.ctrl.T.Helper()
@@ -200,6 +547,81 @@ func (mr *MockStoreMockRecorder) FindOrCreateRelationship(ctx, input interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindOrCreateRelationship", reflect.TypeOf((*MockStore)(nil).FindOrCreateRelationship), ctx, input)
 }
 
+// FindReachable mocks base method.
+func (m *MockStore) FindReachable(ctx context.Context, locator graph.EntityLocator, maxDepth, limit int) ([]graph.ReachableEntity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindReachable", ctx, locator, maxDepth, limit)
+	ret0, _ := ret[0].([]graph.ReachableEntity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindReachable indicates an expected call of FindReachable.
+func (mr *MockStoreMockRecorder) FindReachable(ctx, locator, maxDepth, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindReachable", reflect.TypeOf((*MockStore)(nil).FindReachable), ctx, locator, maxDepth, limit)
+}
+
+// FindShortestPath mocks base method.
+func (m *MockStore) FindShortestPath(ctx context.Context, from, to graph.EntityLocator, relationshipTypes []string, weightProperty string, maxDepth int) (graph.ShortestPathResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindShortestPath", ctx, from, to, relationshipTypes, weightProperty, maxDepth)
+	ret0, _ := ret[0].(graph.ShortestPathResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindShortestPath indicates an expected call of FindShortestPath.
+func (mr *MockStoreMockRecorder) FindShortestPath(ctx, from, to, relationshipTypes, weightProperty, maxDepth interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindShortestPath", reflect.TypeOf((*MockStore)(nil).FindShortestPath), ctx, from, to, relationshipTypes, weightProperty, maxDepth)
+}
+
+// GetAncestors mocks base method.
+func (m *MockStore) GetAncestors(ctx context.Context, locator graph.EntityLocator, relationshipType string, maxDepth int) ([]graph.HierarchyEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAncestors", ctx, locator, relationshipType, maxDepth)
+	ret0, _ := ret[0].([]graph.HierarchyEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAncestors indicates an expected call of GetAncestors.
+func (mr *MockStoreMockRecorder) GetAncestors(ctx, locator, relationshipType, maxDepth interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAncestors", reflect.TypeOf((*MockStore)(nil).GetAncestors), ctx, locator, relationshipType, maxDepth)
+}
+
+// GetDefinitionLocation mocks base method.
+func (m *MockStore) GetDefinitionLocation(ctx context.Context, locator graph.EntityLocator) (graph.DefinitionLocation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDefinitionLocation", ctx, locator)
+	ret0, _ := ret[0].(graph.DefinitionLocation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDefinitionLocation indicates an expected call of GetDefinitionLocation.
+func (mr *MockStoreMockRecorder) GetDefinitionLocation(ctx, locator interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDefinitionLocation", reflect.TypeOf((*MockStore)(nil).GetDefinitionLocation), ctx, locator)
+}
+
+// GetDescendants mocks base method.
+func (m *MockStore) GetDescendants(ctx context.Context, locator graph.EntityLocator, relationshipType string, maxDepth int) ([]graph.HierarchyEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDescendants", ctx, locator, relationshipType, maxDepth)
+	ret0, _ := ret[0].([]graph.HierarchyEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDescendants indicates an expected call of GetDescendants.
+func (mr *MockStoreMockRecorder) GetDescendants(ctx, locator, relationshipType, maxDepth interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDescendants", reflect.TypeOf((*MockStore)(nil).GetDescendants), ctx, locator, relationshipType, maxDepth)
+}
+
 // GetEdge mocks base method.
 func (m *MockStore) GetEdge(ctx context.Context, id string) (map[string]interface{}, error) {
 	m.ctrl.T.Helper()
@@ -216,33 +638,63 @@ func (mr *MockStoreMockRecorder) GetEdge(ctx, id interface{}) *gomock.Call {
 }
 
 // GetEntityDetails mocks base method.
-func (m *MockStore) GetEntityDetails(ctx context.Context, labels []string, identifyingProperties map[string]interface{}) (graph.EntityDetails, error) {
+func (m *MockStore) GetEntityDetails(ctx context.Context, labels []string, identifyingProperties map[string]interface{}, properties []string) (graph.EntityDetails, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetEntityDetails", ctx, labels, identifyingProperties)
+	ret := m.ctrl.Call(m, "GetEntityDetails", ctx, labels, identifyingProperties, properties)
 	ret0, _ := ret[0].(graph.EntityDetails)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetEntityDetails indicates an expected call of GetEntityDetails.
-func (mr *MockStoreMockRecorder) GetEntityDetails(ctx, labels, identifyingProperties interface{}) *gomock.Call {
+func (mr *MockStoreMockRecorder) GetEntityDetails(ctx, labels, identifyingProperties, properties interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEntityDetails", reflect.TypeOf((*MockStore)(nil).GetEntityDetails), ctx, labels, identifyingProperties)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEntityDetails", reflect.TypeOf((*MockStore)(nil).GetEntityDetails), ctx, labels, identifyingProperties, properties)
 }
 
 // GetEntitySubgraph mocks base method.
-func (m *MockStore) GetEntitySubgraph(ctx context.Context, labels []string, identifyingProperties map[string]interface{}, maxDepth int) (graph.SubgraphResult, error) {
+func (m *MockStore) GetEntitySubgraph(ctx context.Context, labels []string, identifyingProperties map[string]interface{}, maxDepth int, includeLabels []string) (graph.SubgraphResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetEntitySubgraph", ctx, labels, identifyingProperties, maxDepth)
+	ret := m.ctrl.Call(m, "GetEntitySubgraph", ctx, labels, identifyingProperties, maxDepth, includeLabels)
 	ret0, _ := ret[0].(graph.SubgraphResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetEntitySubgraph indicates an expected call of GetEntitySubgraph.
-func (mr *MockStoreMockRecorder) GetEntitySubgraph(ctx, labels, identifyingProperties, maxDepth interface{}) *gomock.Call {
+func (mr *MockStoreMockRecorder) GetEntitySubgraph(ctx, labels, identifyingProperties, maxDepth, includeLabels interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEntitySubgraph", reflect.TypeOf((*MockStore)(nil).GetEntitySubgraph), ctx, labels, identifyingProperties, maxDepth, includeLabels)
+}
+
+// GetEntitySubgraphPage mocks base method.
+func (m *MockStore) GetEntitySubgraphPage(ctx context.Context, labels []string, identifyingProperties map[string]interface{}, maxDepth int, includeLabels []string, offset, pageSize int) (graph.SubgraphPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEntitySubgraphPage", ctx, labels, identifyingProperties, maxDepth, includeLabels, offset, pageSize)
+	ret0, _ := ret[0].(graph.SubgraphPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEntitySubgraphPage indicates an expected call of GetEntitySubgraphPage.
+func (mr *MockStoreMockRecorder) GetEntitySubgraphPage(ctx, labels, identifyingProperties, maxDepth, includeLabels, offset, pageSize interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEntitySubgraph", reflect.TypeOf((*MockStore)(nil).GetEntitySubgraph), ctx, labels, identifyingProperties, maxDepth)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEntitySubgraphPage", reflect.TypeOf((*MockStore)(nil).GetEntitySubgraphPage), ctx, labels, identifyingProperties, maxDepth, includeLabels, offset, pageSize)
+}
+
+// GetEntityWithRelationships mocks base method.
+func (m *MockStore) GetEntityWithRelationships(ctx context.Context, locator graph.EntityLocator) (graph.EntityWithRelationships, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEntityWithRelationships", ctx, locator)
+	ret0, _ := ret[0].(graph.EntityWithRelationships)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEntityWithRelationships indicates an expected call of GetEntityWithRelationships.
+func (mr *MockStoreMockRecorder) GetEntityWithRelationships(ctx, locator interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEntityWithRelationships", reflect.TypeOf((*MockStore)(nil).GetEntityWithRelationships), ctx, locator)
 }
 
 // GetNode mocks base method.
@@ -260,6 +712,155 @@ func (mr *MockStoreMockRecorder) GetNode(ctx, id interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNode", reflect.TypeOf((*MockStore)(nil).GetNode), ctx, id)
 }
 
+// GetNodeRelationships mocks base method.
+func (m *MockStore) GetNodeRelationships(ctx context.Context, id, direction string) ([]graph.EntityRelationship, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNodeRelationships", ctx, id, direction)
+	ret0, _ := ret[0].([]graph.EntityRelationship)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNodeRelationships indicates an expected call of GetNodeRelationships.
+func (mr *MockStoreMockRecorder) GetNodeRelationships(ctx, id, direction interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNodeRelationships", reflect.TypeOf((*MockStore)(nil).GetNodeRelationships), ctx, id, direction)
+}
+
+// GetNodesByIDs mocks base method.
+func (m *MockStore) GetNodesByIDs(ctx context.Context, ids []string) ([]map[string]interface{}, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNodesByIDs", ctx, ids)
+	ret0, _ := ret[0].([]map[string]interface{})
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNodesByIDs indicates an expected call of GetNodesByIDs.
+func (mr *MockStoreMockRecorder) GetNodesByIDs(ctx, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNodesByIDs", reflect.TypeOf((*MockStore)(nil).GetNodesByIDs), ctx, ids)
+}
+
+// GraphOverview mocks base method.
+func (m *MockStore) GraphOverview(ctx context.Context) (graph.GraphOverview, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GraphOverview", ctx)
+	ret0, _ := ret[0].(graph.GraphOverview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GraphOverview indicates an expected call of GraphOverview.
+func (mr *MockStoreMockRecorder) GraphOverview(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GraphOverview", reflect.TypeOf((*MockStore)(nil).GraphOverview), ctx)
+}
+
+// LabelHistogram mocks base method.
+func (m *MockStore) LabelHistogram(ctx context.Context) (map[string]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LabelHistogram", ctx)
+	ret0, _ := ret[0].(map[string]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LabelHistogram indicates an expected call of LabelHistogram.
+func (mr *MockStoreMockRecorder) LabelHistogram(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LabelHistogram", reflect.TypeOf((*MockStore)(nil).LabelHistogram), ctx)
+}
+
+// ListRelationships mocks base method.
+func (m *MockStore) ListRelationships(ctx context.Context, locator graph.EntityLocator, direction string, relationshipTypes, otherNodeLabels []string) ([]graph.EntityRelationship, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRelationships", ctx, locator, direction, relationshipTypes, otherNodeLabels)
+	ret0, _ := ret[0].([]graph.EntityRelationship)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRelationships indicates an expected call of ListRelationships.
+func (mr *MockStoreMockRecorder) ListRelationships(ctx, locator, direction, relationshipTypes, otherNodeLabels interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRelationships", reflect.TypeOf((*MockStore)(nil).ListRelationships), ctx, locator, direction, relationshipTypes, otherNodeLabels)
+}
+
+// ListRelationshipsByType mocks base method.
+func (m *MockStore) ListRelationshipsByType(ctx context.Context, relType string, skip, limit int) (graph.RelationshipList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRelationshipsByType", ctx, relType, skip, limit)
+	ret0, _ := ret[0].(graph.RelationshipList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRelationshipsByType indicates an expected call of ListRelationshipsByType.
+func (mr *MockStoreMockRecorder) ListRelationshipsByType(ctx, relType, skip, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRelationshipsByType", reflect.TypeOf((*MockStore)(nil).ListRelationshipsByType), ctx, relType, skip, limit)
+}
+
+// MaterializeTransitiveClosure mocks base method.
+func (m *MockStore) MaterializeTransitiveClosure(ctx context.Context, relType, closureType string, maxDepth int) (graph.TransitiveClosureResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MaterializeTransitiveClosure", ctx, relType, closureType, maxDepth)
+	ret0, _ := ret[0].(graph.TransitiveClosureResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MaterializeTransitiveClosure indicates an expected call of MaterializeTransitiveClosure.
+func (mr *MockStoreMockRecorder) MaterializeTransitiveClosure(ctx, relType, closureType, maxDepth interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MaterializeTransitiveClosure", reflect.TypeOf((*MockStore)(nil).MaterializeTransitiveClosure), ctx, relType, closureType, maxDepth)
+}
+
+// MaxDependencyDepth mocks base method.
+func (m *MockStore) MaxDependencyDepth(ctx context.Context, locator graph.EntityLocator, relationshipTypes []string, maxDepth int) (graph.DependencyDepthResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MaxDependencyDepth", ctx, locator, relationshipTypes, maxDepth)
+	ret0, _ := ret[0].(graph.DependencyDepthResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MaxDependencyDepth indicates an expected call of MaxDependencyDepth.
+func (mr *MockStoreMockRecorder) MaxDependencyDepth(ctx, locator, relationshipTypes, maxDepth interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MaxDependencyDepth", reflect.TypeOf((*MockStore)(nil).MaxDependencyDepth), ctx, locator, relationshipTypes, maxDepth)
+}
+
+// Ping mocks base method.
+func (m *MockStore) Ping(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Ping", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Ping indicates an expected call of Ping.
+func (mr *MockStoreMockRecorder) Ping(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ping", reflect.TypeOf((*MockStore)(nil).Ping), ctx)
+}
+
+// PruneLowConfidence mocks base method.
+func (m *MockStore) PruneLowConfidence(ctx context.Context, relTypes []string, threshold float64, dryRun bool) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PruneLowConfidence", ctx, relTypes, threshold, dryRun)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PruneLowConfidence indicates an expected call of PruneLowConfidence.
+func (mr *MockStoreMockRecorder) PruneLowConfidence(ctx, relTypes, threshold, dryRun interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PruneLowConfidence", reflect.TypeOf((*MockStore)(nil).PruneLowConfidence), ctx, relTypes, threshold, dryRun)
+}
+
 // Query mocks base method.
 func (m *MockStore) Query(ctx context.Context, query string, params map[string]interface{}) ([]map[string]interface{}, error) {
 	m.ctrl.T.Helper()
@@ -275,6 +876,125 @@ func (mr *MockStoreMockRecorder) Query(ctx, query, params interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Query", reflect.TypeOf((*MockStore)(nil).Query), ctx, query, params)
 }
 
+// QueryLanguage mocks base method.
+func (m *MockStore) QueryLanguage() graph.QueryLanguage {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueryLanguage")
+	ret0, _ := ret[0].(graph.QueryLanguage)
+	return ret0
+}
+
+// QueryLanguage indicates an expected call of QueryLanguage.
+func (mr *MockStoreMockRecorder) QueryLanguage() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryLanguage", reflect.TypeOf((*MockStore)(nil).QueryLanguage))
+}
+
+// QueryWithTypes mocks base method.
+func (m *MockStore) QueryWithTypes(ctx context.Context, query string, params map[string]interface{}) (graph.TypedQueryResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueryWithTypes", ctx, query, params)
+	ret0, _ := ret[0].(graph.TypedQueryResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueryWithTypes indicates an expected call of QueryWithTypes.
+func (mr *MockStoreMockRecorder) QueryWithTypes(ctx, query, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryWithTypes", reflect.TypeOf((*MockStore)(nil).QueryWithTypes), ctx, query, params)
+}
+
+// RelationshipTypeCounts mocks base method.
+func (m *MockStore) RelationshipTypeCounts(ctx context.Context, locator graph.EntityLocator, direction string) (map[string]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RelationshipTypeCounts", ctx, locator, direction)
+	ret0, _ := ret[0].(map[string]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RelationshipTypeCounts indicates an expected call of RelationshipTypeCounts.
+func (mr *MockStoreMockRecorder) RelationshipTypeCounts(ctx, locator, direction interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RelationshipTypeCounts", reflect.TypeOf((*MockStore)(nil).RelationshipTypeCounts), ctx, locator, direction)
+}
+
+// RestoreSubgraph mocks base method.
+func (m *MockStore) RestoreSubgraph(ctx context.Context, snapshot graph.GraphSnapshot) (graph.RestoreResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreSubgraph", ctx, snapshot)
+	ret0, _ := ret[0].(graph.RestoreResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreSubgraph indicates an expected call of RestoreSubgraph.
+func (mr *MockStoreMockRecorder) RestoreSubgraph(ctx, snapshot interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreSubgraph", reflect.TypeOf((*MockStore)(nil).RestoreSubgraph), ctx, snapshot)
+}
+
+// SearchEntities mocks base method.
+func (m *MockStore) SearchEntities(ctx context.Context, labels []string, text string, limit int) ([]graph.EntityDetails, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchEntities", ctx, labels, text, limit)
+	ret0, _ := ret[0].([]graph.EntityDetails)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchEntities indicates an expected call of SearchEntities.
+func (mr *MockStoreMockRecorder) SearchEntities(ctx, labels, text, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchEntities", reflect.TypeOf((*MockStore)(nil).SearchEntities), ctx, labels, text, limit)
+}
+
+// SetEntityStatus mocks base method.
+func (m *MockStore) SetEntityStatus(ctx context.Context, locator graph.EntityLocator, status string) (graph.EntityDetails, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetEntityStatus", ctx, locator, status)
+	ret0, _ := ret[0].(graph.EntityDetails)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetEntityStatus indicates an expected call of SetEntityStatus.
+func (mr *MockStoreMockRecorder) SetEntityStatus(ctx, locator, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetEntityStatus", reflect.TypeOf((*MockStore)(nil).SetEntityStatus), ctx, locator, status)
+}
+
+// SimilarEntities mocks base method.
+func (m *MockStore) SimilarEntities(ctx context.Context, locator graph.EntityLocator, relationshipTypes []string, topK int) ([]graph.SimilarEntity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SimilarEntities", ctx, locator, relationshipTypes, topK)
+	ret0, _ := ret[0].([]graph.SimilarEntity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SimilarEntities indicates an expected call of SimilarEntities.
+func (mr *MockStoreMockRecorder) SimilarEntities(ctx, locator, relationshipTypes, topK interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SimilarEntities", reflect.TypeOf((*MockStore)(nil).SimilarEntities), ctx, locator, relationshipTypes, topK)
+}
+
+// SweepExpired mocks base method.
+func (m *MockStore) SweepExpired(ctx context.Context, labels []string, dryRun bool) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SweepExpired", ctx, labels, dryRun)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SweepExpired indicates an expected call of SweepExpired.
+func (mr *MockStoreMockRecorder) SweepExpired(ctx, labels, dryRun interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SweepExpired", reflect.TypeOf((*MockStore)(nil).SweepExpired), ctx, labels, dryRun)
+}
+
 // UpdateEdge mocks base method.
 func (m *MockStore) UpdateEdge(ctx context.Context, id string, properties map[string]interface{}) error {
 	m.ctrl.T.Helper()
@@ -303,6 +1023,21 @@ func (mr *MockStoreMockRecorder) UpdateNode(ctx, id, properties interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateNode", reflect.TypeOf((*MockStore)(nil).UpdateNode), ctx, id, properties)
 }
 
+// UpsertEntityWithRelationship mocks base method.
+func (m *MockStore) UpsertEntityWithRelationship(ctx context.Context, entity graph.EntityInput, rel graph.RelationshipInput) (graph.EntityWithRelationship, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertEntityWithRelationship", ctx, entity, rel)
+	ret0, _ := ret[0].(graph.EntityWithRelationship)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertEntityWithRelationship indicates an expected call of UpsertEntityWithRelationship.
+func (mr *MockStoreMockRecorder) UpsertEntityWithRelationship(ctx, entity, rel interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertEntityWithRelationship", reflect.TypeOf((*MockStore)(nil).UpsertEntityWithRelationship), ctx, entity, rel)
+}
+
 // UpsertSchema mocks base method.
 func (m *MockStore) UpsertSchema(ctx context.Context, schema string) error {
 	m.ctrl.T.Helper()
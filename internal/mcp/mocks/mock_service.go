@@ -9,6 +9,7 @@ import (
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
+	graph "github.com/sammcj/mcp-graph/internal/graph"
 	service "github.com/sammcj/mcp-graph/internal/service"
 )
 
@@ -35,6 +36,22 @@ func (m *MockKnowledgeManager) EXPECT() *MockKnowledgeManagerMockRecorder {
 	return m.recorder
 }
 
+// BatchCreateDocuments mocks base method.
+func (m *MockKnowledgeManager) BatchCreateDocuments(ctx context.Context, docs []service.DocumentInput) ([]string, []error, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BatchCreateDocuments", ctx, docs)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].([]error)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// BatchCreateDocuments indicates an expected call of BatchCreateDocuments.
+func (mr *MockKnowledgeManagerMockRecorder) BatchCreateDocuments(ctx, docs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchCreateDocuments", reflect.TypeOf((*MockKnowledgeManager)(nil).BatchCreateDocuments), ctx, docs)
+}
+
 // CreateConcept mocks base method.
 func (m *MockKnowledgeManager) CreateConcept(ctx context.Context, name string, properties map[string]interface{}) (string, error) {
 	m.ctrl.T.Helper()
@@ -79,6 +96,22 @@ func (mr *MockKnowledgeManagerMockRecorder) DeleteDocument(ctx, id interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDocument", reflect.TypeOf((*MockKnowledgeManager)(nil).DeleteDocument), ctx, id)
 }
 
+// FindOrCreateDocument mocks base method.
+func (m *MockKnowledgeManager) FindOrCreateDocument(ctx context.Context, title, content string, metadata map[string]interface{}) (string, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindOrCreateDocument", ctx, title, content, metadata)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// FindOrCreateDocument indicates an expected call of FindOrCreateDocument.
+func (mr *MockKnowledgeManagerMockRecorder) FindOrCreateDocument(ctx, title, content, metadata interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindOrCreateDocument", reflect.TypeOf((*MockKnowledgeManager)(nil).FindOrCreateDocument), ctx, title, content, metadata)
+}
+
 // GetConcept mocks base method.
 func (m *MockKnowledgeManager) GetConcept(ctx context.Context, id string) (*service.Concept, error) {
 	m.ctrl.T.Helper()
@@ -94,6 +127,21 @@ func (mr *MockKnowledgeManagerMockRecorder) GetConcept(ctx, id interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConcept", reflect.TypeOf((*MockKnowledgeManager)(nil).GetConcept), ctx, id)
 }
 
+// GetConceptByUUID mocks base method.
+func (m *MockKnowledgeManager) GetConceptByUUID(ctx context.Context, uuid string) (*service.Concept, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConceptByUUID", ctx, uuid)
+	ret0, _ := ret[0].(*service.Concept)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetConceptByUUID indicates an expected call of GetConceptByUUID.
+func (mr *MockKnowledgeManagerMockRecorder) GetConceptByUUID(ctx, uuid interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConceptByUUID", reflect.TypeOf((*MockKnowledgeManager)(nil).GetConceptByUUID), ctx, uuid)
+}
+
 // GetDocument mocks base method.
 func (m *MockKnowledgeManager) GetDocument(ctx context.Context, id string) (*service.Document, error) {
 	m.ctrl.T.Helper()
@@ -109,6 +157,51 @@ func (mr *MockKnowledgeManagerMockRecorder) GetDocument(ctx, id interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDocument", reflect.TypeOf((*MockKnowledgeManager)(nil).GetDocument), ctx, id)
 }
 
+// GetDocumentByUUID mocks base method.
+func (m *MockKnowledgeManager) GetDocumentByUUID(ctx context.Context, uuid string) (*service.Document, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDocumentByUUID", ctx, uuid)
+	ret0, _ := ret[0].(*service.Document)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDocumentByUUID indicates an expected call of GetDocumentByUUID.
+func (mr *MockKnowledgeManagerMockRecorder) GetDocumentByUUID(ctx, uuid interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDocumentByUUID", reflect.TypeOf((*MockKnowledgeManager)(nil).GetDocumentByUUID), ctx, uuid)
+}
+
+// GetDocumentContext mocks base method.
+func (m *MockKnowledgeManager) GetDocumentContext(ctx context.Context, id string) (*service.DocumentContext, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDocumentContext", ctx, id)
+	ret0, _ := ret[0].(*service.DocumentContext)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDocumentContext indicates an expected call of GetDocumentContext.
+func (mr *MockKnowledgeManagerMockRecorder) GetDocumentContext(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDocumentContext", reflect.TypeOf((*MockKnowledgeManager)(nil).GetDocumentContext), ctx, id)
+}
+
+// GetEntityDocuments mocks base method.
+func (m *MockKnowledgeManager) GetEntityDocuments(ctx context.Context, locator graph.EntityLocator) ([]service.EntityDocument, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEntityDocuments", ctx, locator)
+	ret0, _ := ret[0].([]service.EntityDocument)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEntityDocuments indicates an expected call of GetEntityDocuments.
+func (mr *MockKnowledgeManagerMockRecorder) GetEntityDocuments(ctx, locator interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEntityDocuments", reflect.TypeOf((*MockKnowledgeManager)(nil).GetEntityDocuments), ctx, locator)
+}
+
 // InitialiseSchema mocks base method.
 func (m *MockKnowledgeManager) InitialiseSchema(ctx context.Context) error {
 	m.ctrl.T.Helper()
@@ -138,6 +231,21 @@ func (mr *MockKnowledgeManagerMockRecorder) LinkConcepts(ctx, fromID, toID, rela
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LinkConcepts", reflect.TypeOf((*MockKnowledgeManager)(nil).LinkConcepts), ctx, fromID, toID, relationshipType, properties)
 }
 
+// LinkDocument mocks base method.
+func (m *MockKnowledgeManager) LinkDocument(ctx context.Context, docID, targetID, relationshipType string, properties map[string]interface{}) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LinkDocument", ctx, docID, targetID, relationshipType, properties)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LinkDocument indicates an expected call of LinkDocument.
+func (mr *MockKnowledgeManagerMockRecorder) LinkDocument(ctx, docID, targetID, relationshipType, properties interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LinkDocument", reflect.TypeOf((*MockKnowledgeManager)(nil).LinkDocument), ctx, docID, targetID, relationshipType, properties)
+}
+
 // SearchConcepts mocks base method.
 func (m *MockKnowledgeManager) SearchConcepts(ctx context.Context, query string) ([]*service.Concept, error) {
 	m.ctrl.T.Helper()
@@ -168,6 +276,21 @@ func (mr *MockKnowledgeManagerMockRecorder) SearchDocuments(ctx, query interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchDocuments", reflect.TypeOf((*MockKnowledgeManager)(nil).SearchDocuments), ctx, query)
 }
 
+// SearchDocumentsPage mocks base method.
+func (m *MockKnowledgeManager) SearchDocumentsPage(ctx context.Context, query string, skip, limit int) (*service.DocumentPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchDocumentsPage", ctx, query, skip, limit)
+	ret0, _ := ret[0].(*service.DocumentPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchDocumentsPage indicates an expected call of SearchDocumentsPage.
+func (mr *MockKnowledgeManagerMockRecorder) SearchDocumentsPage(ctx, query, skip, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchDocumentsPage", reflect.TypeOf((*MockKnowledgeManager)(nil).SearchDocumentsPage), ctx, query, skip, limit)
+}
+
 // UpdateDocument mocks base method.
 func (m *MockKnowledgeManager) UpdateDocument(ctx context.Context, id, title, content string, metadata map[string]interface{}) error {
 	m.ctrl.T.Helper()
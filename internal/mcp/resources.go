@@ -0,0 +1,132 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Resource URIs. IDs are used as they are, since Neo4j element IDs contain colons; the templates use reserved
+// expansion so that they still match.
+const (
+	documentsResourceURI        = "graph://documents"
+	documentResourceURIPrefix   = "graph://document/"
+	documentResourceURITemplate = documentResourceURIPrefix + "{+id}"
+	nodeResourceURIPrefix       = "graph://node/"
+	nodeResourceURITemplate     = nodeResourceURIPrefix + "{+id}"
+)
+
+// maxListedDocuments caps the number of documents listed by the documents resource
+const maxListedDocuments = 1000
+
+// documentListEntry describes a document in the documents resource
+type documentListEntry struct {
+	URI   string `json:"uri"`
+	ID    string `json:"id"`
+	Title string `json:"title"`
+}
+
+// SetupResources configures the MCP resources, which let clients browse and read documents and nodes through the
+// resources API instead of tool calls
+func (s *Server) SetupResources() {
+	documentsResource := mcp.NewResource(documentsResourceURI, "Documents",
+		mcp.WithResourceDescription(fmt.Sprintf("Lists the documents in the knowledge graph (up to %d), each with its ID, title and the URI to read it from.", maxListedDocuments)),
+		mcp.WithMIMEType("application/json"),
+	)
+	s.server.AddResource(documentsResource, s.handleDocumentsResource)
+
+	documentTemplate := mcp.NewResourceTemplate(documentResourceURITemplate, "Document",
+		mcp.WithTemplateDescription("A document in the knowledge graph, by ID, with its title, content and metadata."),
+		mcp.WithTemplateMIMEType("application/json"),
+	)
+	s.server.AddResourceTemplate(documentTemplate, s.handleDocumentResource)
+
+	nodeTemplate := mcp.NewResourceTemplate(nodeResourceURITemplate, "Node",
+		mcp.WithTemplateDescription("Any node in the knowledge graph, by ID, with its id, labels and properties."),
+		mcp.WithTemplateMIMEType("application/json"),
+	)
+	s.server.AddResourceTemplate(nodeTemplate, s.handleNodeResource)
+}
+
+// handleDocumentsResource handles reads of the documents resource
+func (s *Server) handleDocumentsResource(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	documents, err := s.graph.FindEntities(ctx, []string{"Document"}, nil, maxListedDocuments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list documents: %w", err)
+	}
+
+	entries := make([]documentListEntry, 0, len(documents))
+	for _, document := range documents {
+		id, _ := document.Properties["id"].(string)
+		title, _ := document.Properties["title"].(string)
+		entries = append(entries, documentListEntry{URI: documentResourceURIPrefix + id, ID: id, Title: title})
+	}
+	return s.jsonResourceContents(request.Params.URI, entries)
+}
+
+// handleDocumentResource handles reads of a document resource
+func (s *Server) handleDocumentResource(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	id, err := resourceID(request)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := s.service.GetDocument(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get document: %w", err)
+	}
+	return s.jsonResourceContents(request.Params.URI, doc)
+}
+
+// handleNodeResource handles reads of a node resource
+func (s *Server) handleNodeResource(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	id, err := resourceID(request)
+	if err != nil {
+		return nil, err
+	}
+	node, err := s.graph.GetNode(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get node: %w", err)
+	}
+	return s.jsonResourceContents(request.Params.URI, node)
+}
+
+// resourceID returns the ID matched by a resource template's {id} variable, which the server passes on as a list of
+// strings
+func resourceID(request mcp.ReadResourceRequest) (string, error) {
+	var id string
+	switch v := request.Params.Arguments["id"].(type) {
+	case string:
+		id = v
+	case []string:
+		if len(v) == 1 {
+			id = v[0]
+		}
+	}
+	if id == "" {
+		return "", errors.New("resource URI must include an ID")
+	}
+	return id, nil
+}
+
+// jsonResourceContents returns v as the JSON contents of the resource at uri, redacted in the same way as tool results
+func (s *Server) jsonResourceContents(uri string, v interface{}) ([]mcp.ResourceContents, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal resource: %w", err)
+	}
+	if s.redactor.Enabled() {
+		if redacted, ok := s.redactor.RedactJSON(data); ok {
+			data = redacted
+		}
+	}
+	return []mcp.ResourceContents{
+		mcp.TextResourceContents{
+			URI:      uri,
+			MIMEType: "application/json",
+			Text:     string(data),
+		},
+	}, nil
+}
@@ -0,0 +1,103 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sammcj/mcp-graph/internal/graph"
+	"github.com/sammcj/mcp-graph/internal/mcp/mocks"
+	"github.com/sammcj/mcp-graph/internal/service"
+)
+
+// readResource sends a resources/read request for uri and returns the text of the first contents, or the error
+// message if the read failed
+func readResource(t *testing.T, server *Server, uri string) (string, string) {
+	message := fmt.Sprintf(`{"jsonrpc": "2.0", "id": 1, "method": "resources/read", "params": {"uri": %q}}`, uri)
+	response, err := json.Marshal(server.server.HandleMessage(context.Background(), json.RawMessage(message)))
+	assert.NoError(t, err)
+
+	var decoded struct {
+		Result struct {
+			Contents []struct {
+				URI      string `json:"uri"`
+				MIMEType string `json:"mimeType"`
+				Text     string `json:"text"`
+			} `json:"contents"`
+		} `json:"result"`
+		Error struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	assert.NoError(t, json.Unmarshal(response, &decoded))
+	if decoded.Error.Message != "" {
+		return "", decoded.Error.Message
+	}
+	assert.Len(t, decoded.Result.Contents, 1)
+	assert.Equal(t, uri, decoded.Result.Contents[0].URI)
+	assert.Equal(t, "application/json", decoded.Result.Contents[0].MIMEType)
+	return decoded.Result.Contents[0].Text, ""
+}
+
+// TestResources tests listing documents and reading documents and nodes by Neo4j element ID
+func TestResources(t *testing.T) {
+	// Create a new mock controller
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Create mock graph store and service
+	mockGraph := mocks.NewMockStore(ctrl)
+	mockService := mocks.NewMockKnowledgeManager(ctrl)
+
+	// Create MCP server with resources registered
+	server := NewServer("test", "0.0.0", mockGraph)
+	server.SetService(mockService)
+	server.SetupResources()
+
+	// Set up expectations
+	mockGraph.EXPECT().FindEntities(gomock.Any(), []string{"Document"}, gomock.Nil(), maxListedDocuments).Return([]graph.EntityDetails{
+		{Labels: []string{"Document"}, Properties: map[string]interface{}{"id": "4:abc:1", "title": "Billing design"}},
+	}, nil)
+	mockService.EXPECT().GetDocument(gomock.Any(), "4:abc:1").Return(&service.Document{ID: "4:abc:1", Title: "Billing design", Content: "How billing works"}, nil)
+	mockGraph.EXPECT().GetNode(gomock.Any(), "4:abc:2").Return(graph.NewNodeMap("4:abc:2", []string{"Service"}, map[string]interface{}{"name": "billing"}), nil)
+
+	// List the documents
+	text, errMessage := readResource(t, server, "graph://documents")
+	assert.Empty(t, errMessage)
+	assert.JSONEq(t, `[{"uri": "graph://document/4:abc:1", "id": "4:abc:1", "title": "Billing design"}]`, text)
+
+	// Read a document from its listed URI
+	text, errMessage = readResource(t, server, "graph://document/4:abc:1")
+	assert.Empty(t, errMessage)
+	assert.JSONEq(t, `{"id": "4:abc:1", "title": "Billing design", "content": "How billing works"}`, text)
+
+	// Read any node
+	text, errMessage = readResource(t, server, "graph://node/4:abc:2")
+	assert.Empty(t, errMessage)
+	assert.JSONEq(t, `{"id": "4:abc:2", "labels": ["Service"], "name": "billing"}`, text)
+}
+
+// TestResources_NodeNotFound tests that store errors are returned from resource reads
+func TestResources_NodeNotFound(t *testing.T) {
+	// Create a new mock controller
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Create mock graph store
+	mockGraph := mocks.NewMockStore(ctrl)
+
+	// Create MCP server with resources registered
+	server := NewServer("test", "0.0.0", mockGraph)
+	server.SetupResources()
+
+	// Set up expectations
+	mockGraph.EXPECT().GetNode(gomock.Any(), "0x99").Return(nil, errors.New("node not found"))
+
+	_, errMessage := readResource(t, server, "graph://node/0x99")
+	assert.Contains(t, errMessage, "failed to get node")
+}
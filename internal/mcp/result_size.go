@@ -0,0 +1,45 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Logger defines the interface for logging
+type Logger interface {
+	Printf(format string, v ...interface{})
+}
+
+// SetResultSizeWarning logs a warning to logger whenever a tool result is larger than thresholdBytes.
+// A non-positive threshold disables the warning.
+func (s *Server) SetResultSizeWarning(thresholdBytes int, logger Logger) {
+	s.resultSizeWarning = thresholdBytes
+	s.logger = logger
+}
+
+// checkResultSize reports the size of a tool result in its _meta.resultSize field and logs a warning,
+// with the tool's (redacted) arguments, if it exceeds the configured threshold
+func (s *Server) checkResultSize(ctx context.Context, id any, request *mcp.CallToolRequest, result *mcp.CallToolResult) {
+	if result == nil {
+		return
+	}
+	size := 0
+	for _, content := range result.Content {
+		if textContent, ok := content.(mcp.TextContent); ok {
+			size += len(textContent.Text)
+		}
+	}
+	if result.Meta == nil {
+		result.Meta = map[string]interface{}{}
+	}
+	result.Meta["resultSize"] = size
+
+	if s.resultSizeWarning <= 0 || size <= s.resultSizeWarning || s.logger == nil {
+		return
+	}
+	arguments, _ := json.Marshal(s.redactor.Redact(request.Params.Arguments))
+	s.logger.Printf("Warning: %s returned %d bytes, exceeding the result size warning threshold of %d bytes; arguments: %s",
+		request.Params.Name, size, s.resultSizeWarning, arguments)
+}
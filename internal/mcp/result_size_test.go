@@ -0,0 +1,48 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sammcj/mcp-graph/internal/graph"
+)
+
+// recordingLogger records logged messages
+type recordingLogger struct {
+	messages []string
+}
+
+func (l *recordingLogger) Printf(format string, v ...interface{}) {
+	l.messages = append(l.messages, fmt.Sprintf(format, v...))
+}
+
+// TestCheckResultSize tests that result sizes are reported and large results are logged
+func TestCheckResultSize(t *testing.T) {
+	logger := &recordingLogger{}
+	server := &Server{redactor: graph.NewRedactor([]string{"password"})}
+	server.SetResultSizeWarning(10, logger)
+
+	request := &mcp.CallToolRequest{}
+	request.Params.Name = "query_knowledge_graph"
+	request.Params.Arguments = map[string]interface{}{"query": "MATCH (n) RETURN n", "password": "hunter2"}
+
+	// Small results are not logged
+	small := mcp.NewToolResultText(`[]`)
+	server.checkResultSize(context.Background(), 1, request, small)
+	assert.Equal(t, 2, small.Meta["resultSize"])
+	assert.Empty(t, logger.messages)
+
+	// Large results are logged with their redacted arguments
+	large := mcp.NewToolResultText(`[{"name":"billing"}]`)
+	server.checkResultSize(context.Background(), 2, request, large)
+	assert.Equal(t, 20, large.Meta["resultSize"])
+	if assert.Len(t, logger.messages, 1) {
+		assert.Contains(t, logger.messages[0], "query_knowledge_graph returned 20 bytes")
+		assert.Contains(t, logger.messages[0], "MATCH (n) RETURN n")
+		assert.NotContains(t, logger.messages[0], "hunter2")
+	}
+}
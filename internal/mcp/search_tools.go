@@ -0,0 +1,408 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/sammcj/mcp-graph/internal/graph"
+)
+
+// setupSearchTools configures the tools for finding entities by their properties
+func (s *Server) setupSearchTools() {
+	recentlyModifiedTool := mcp.NewTool("recently_modified",
+		mcp.WithDescription("Lists entities modified (lastModifiedAt) at or after a given timestamp, most recently modified first. Useful for incremental sync workflows that only need to process entities touched since a previous run."),
+		mcp.WithString("since",
+			mcp.Required(),
+			mcp.Description("RFC 3339 / ISO-8601 timestamp (e.g. '2024-05-01T00:00:00Z'). Entities modified at or after this time are returned."),
+		),
+		mcp.WithArray("labels",
+			mcp.Description("Optional list of labels restricting the entities returned (e.g. ['Service']). If omitted or empty, entities with any label are returned."),
+			mcp.Items(map[string]interface{}{"type": "string"}),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("Maximum number of entities to return. Defaults to 100 if not provided or invalid."),
+		),
+	)
+	s.addTool(recentlyModifiedTool, s.handleRecentlyModifiedTool)
+
+	findEntitiesTool := mcp.NewTool("find_entities",
+		mcp.WithDescription("Finds entities by label and property filters (e.g. all Services tagged 'public-api'). All filters must match."),
+		mcp.WithArray("labels",
+			mcp.Description("Optional list of labels; entities with any of these labels are considered. If omitted or empty, entities with any label are considered."),
+			mcp.Items(map[string]interface{}{"type": "string"}),
+		),
+		mcp.WithArray("filters",
+			mcp.Description(propertyFiltersDescription),
+			mcp.Items(propertyFilterSchema),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("Maximum number of entities to return. Defaults to 100 if not provided or invalid."),
+		),
+		mcp.WithNumber("sample",
+			mcp.Description(sampleDescription),
+		),
+	)
+	s.addTool(findEntitiesTool, s.handleFindEntitiesTool)
+
+	countEntitiesTool := mcp.NewTool("count_entities",
+		mcp.WithDescription("Counts entities by label and property filters (e.g. how many Services are tagged 'public-api'). All filters must match."),
+		mcp.WithArray("labels",
+			mcp.Description("Optional list of labels; entities with any of these labels are counted. If omitted or empty, entities with any label are counted."),
+			mcp.Items(map[string]interface{}{"type": "string"}),
+		),
+		mcp.WithArray("filters",
+			mcp.Description(propertyFiltersDescription),
+			mcp.Items(propertyFilterSchema),
+		),
+	)
+	s.addTool(countEntitiesTool, s.handleCountEntitiesTool)
+
+	searchEntitiesTool := mcp.NewTool("search_entities",
+		mcp.WithDescription("Finds entities with any string property containing the given text, ignoring case (e.g. 'payment' finds a Service named 'PaymentGateway' or a File with path 'src/payments/api.go'). Use this to locate an entity when you only know part of its name or path, then use the returned labels and properties with the other entity tools. Restrict the labels where possible, as every property of every candidate is checked."),
+		mcp.WithString("text",
+			mcp.Required(),
+			mcp.Description("The text to search for within property values."),
+		),
+		mcp.WithArray("labels",
+			mcp.Description("Optional list of labels; entities with any of these labels are searched. If omitted or empty, entities with any label are searched."),
+			mcp.Items(map[string]interface{}{"type": "string"}),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("Maximum number of entities to return. Defaults to 100 if not provided or invalid."),
+		),
+		mcp.WithNumber("sample",
+			mcp.Description(sampleDescription),
+		),
+	)
+	s.addTool(searchEntitiesTool, s.handleSearchEntitiesTool)
+
+	findBySourceTool := mcp.NewTool("find_by_source",
+		mcp.WithDescription("Finds entities by their provenance: the 'source' property recording what created them, e.g. 'manual', 'static-analysis' or 'agent-inference'. Use this to review all agent-inferred entities for accuracy, or to find the output of a bad analysis run before deleting it. Most recently created first."),
+		mcp.WithString("source",
+			mcp.Required(),
+			mcp.Description("The source to match exactly (e.g. 'agent-inference')."),
+		),
+		mcp.WithArray("labels",
+			mcp.Description("Optional list of labels; only entities with any of these labels are returned. If omitted or empty, entities with any label are returned."),
+			mcp.Items(map[string]interface{}{"type": "string"}),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("Maximum number of entities to return. Defaults to 100 if not provided or invalid."),
+		),
+	)
+	s.addTool(findBySourceTool, s.handleFindBySourceTool)
+
+	findByFilePathTool := mcp.NewTool("find_by_filepath",
+		mcp.WithDescription("Finds the entities (Files, Functions, Classes, etc.) whose filePath property equals a given path, e.g. to find what is defined in a file before editing it. With prefix set, finds every entity within a directory instead. Ordered by filePath."),
+		mcp.WithString("filePath",
+			mcp.Required(),
+			mcp.Description("The path to match exactly (e.g. 'src/api/handlers.go'), or the directory to search with prefix (e.g. 'src/api')."),
+		),
+		mcp.WithBoolean("prefix",
+			mcp.Description("If true, also match entities whose filePath is within the directory filePath ('src/api' matches 'src/api/handlers.go' but not 'src/api2/main.go'). Defaults to false."),
+		),
+		mcp.WithArray("labels",
+			mcp.Description("Optional list of labels; only entities with any of these labels are returned. If omitted or empty, entities with any label are returned."),
+			mcp.Items(map[string]interface{}{"type": "string"}),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("Maximum number of entities to return. Defaults to 100 if not provided or invalid."),
+		),
+	)
+	s.addTool(findByFilePathTool, s.handleFindByFilePathTool)
+
+	listRelationshipsByTypeTool := mcp.NewTool("list_relationships_by_type",
+		mcp.WithDescription("Lists every relationship of a given type across the graph, with the labels and IDs of its start and end nodes and its properties (e.g. reviewing all COMMUNICATES_WITH relationships to verify their protocols). Results are paged in a stable order; the response includes the total number of relationships of that type."),
+		mcp.WithString("relationshipType",
+			mcp.Required(),
+			mcp.Description("The relationship type to list (e.g. 'COMMUNICATES_WITH')."),
+		),
+		mcp.WithNumber("skip",
+			mcp.Description("Number of relationships to skip, for fetching later pages. Defaults to 0."),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("Maximum number of relationships to return. Defaults to 100 if not provided or invalid."),
+		),
+	)
+	s.addTool(listRelationshipsByTypeTool, s.handleListRelationshipsByTypeTool)
+}
+
+// propertyFiltersDescription describes the filters argument shared by the entity search tools
+const propertyFiltersDescription = "Optional list of property filters, each {property, operator, value}. Operators: 'eq' (default), 'neq', 'gt', 'gte', 'lt', 'lte', 'contains' for list properties (e.g. {\"property\": \"tags\", \"operator\": \"contains\", \"value\": \"public-api\"}), and 'exists' or 'missing' to test whether the property is set at all, which take no value."
+
+// propertyFilterSchema describes a single property filter, for use in array items
+var propertyFilterSchema = map[string]interface{}{
+	"type": "object",
+	"properties": map[string]interface{}{
+		"property": map[string]interface{}{"type": "string"},
+		"operator": map[string]interface{}{
+			"type": "string",
+			"enum": []string{
+				graph.FilterOpEquals, graph.FilterOpNotEquals,
+				graph.FilterOpGreaterThan, graph.FilterOpGreaterOrEq,
+				graph.FilterOpLessThan, graph.FilterOpLessOrEq,
+				graph.FilterOpContains,
+				graph.FilterOpExists, graph.FilterOpMissing,
+			},
+		},
+		"value": map[string]interface{}{},
+	},
+	"required": []string{"property"},
+}
+
+// parsePropertyFilters parses the optional filters argument into property filters
+func parsePropertyFilters(request mcp.CallToolRequest) ([]graph.PropertyFilter, error) {
+	arg, exists := request.Params.Arguments["filters"]
+	if !exists || arg == nil {
+		return nil, nil
+	}
+	filtersInterface, ok := arg.([]interface{})
+	if !ok {
+		return nil, errors.New("filters must be an array of objects")
+	}
+	filters := make([]graph.PropertyFilter, len(filtersInterface))
+	for i, f := range filtersInterface {
+		filterMap, ok := f.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("filter at index %d is not an object", i)
+		}
+		property, ok := filterMap["property"].(string)
+		if !ok || property == "" {
+			return nil, fmt.Errorf("filter at index %d must have a non-empty property", i)
+		}
+		var operator string
+		if opArg, exists := filterMap["operator"]; exists && opArg != nil {
+			operator, ok = opArg.(string)
+			if !ok {
+				return nil, fmt.Errorf("filter at index %d has a non-string operator", i)
+			}
+		}
+		value, exists := filterMap["value"]
+		if !exists && operator != graph.FilterOpExists && operator != graph.FilterOpMissing {
+			return nil, fmt.Errorf("filter at index %d must have a value", i)
+		}
+		filters[i] = graph.PropertyFilter{Property: property, Operator: operator, Value: value}
+	}
+	return filters, nil
+}
+
+// handleRecentlyModifiedTool handles the recently_modified tool
+func (s *Server) handleRecentlyModifiedTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	sinceStr, ok := request.Params.Arguments["since"].(string)
+	if !ok || sinceStr == "" {
+		return nil, errors.New("since must be a non-empty string")
+	}
+	since, err := time.Parse(time.RFC3339, sinceStr)
+	if err != nil {
+		return nil, fmt.Errorf("since must be an RFC 3339 timestamp: %w", err)
+	}
+	labels, err := parseOptionalStringArray(request, "labels")
+	if err != nil {
+		return nil, err
+	}
+	limit, err := parseOptionalInt(request, "limit", 100)
+	if err != nil {
+		return nil, err
+	}
+
+	// Call graph store method
+	entities, err := s.graph.FindModifiedSince(ctx, labels, since, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find recently modified entities: %w", err)
+	}
+
+	// Return the result
+	resultJSON, err := json.Marshal(entities)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal recently modified entities: %w", err)
+	}
+	return mcp.NewToolResultText(string(resultJSON)), nil
+}
+
+// handleFindEntitiesTool handles the find_entities tool
+func (s *Server) handleFindEntitiesTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	labels, err := parseOptionalStringArray(request, "labels")
+	if err != nil {
+		return nil, err
+	}
+	filters, err := parsePropertyFilters(request)
+	if err != nil {
+		return nil, err
+	}
+	limit, err := parseOptionalInt(request, "limit", 100)
+	if err != nil {
+		return nil, err
+	}
+	ctx, err = withSample(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	// Call graph store method
+	entities, err := s.graph.FindEntities(ctx, labels, filters, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find entities: %w", err)
+	}
+
+	// Return the result
+	resultJSON, err := json.Marshal(entities)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal entities: %w", err)
+	}
+	return mcp.NewToolResultText(string(resultJSON)), nil
+}
+
+// handleCountEntitiesTool handles the count_entities tool
+func (s *Server) handleCountEntitiesTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	labels, err := parseOptionalStringArray(request, "labels")
+	if err != nil {
+		return nil, err
+	}
+	filters, err := parsePropertyFilters(request)
+	if err != nil {
+		return nil, err
+	}
+
+	// Call graph store method
+	count, err := s.graph.CountEntities(ctx, labels, filters)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count entities: %w", err)
+	}
+
+	// Return the result
+	resultJSON, err := json.Marshal(map[string]interface{}{"count": count})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal entity count: %w", err)
+	}
+	return mcp.NewToolResultText(string(resultJSON)), nil
+}
+
+// handleSearchEntitiesTool handles the search_entities tool
+func (s *Server) handleSearchEntitiesTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	text, ok := request.Params.Arguments["text"].(string)
+	if !ok || strings.TrimSpace(text) == "" {
+		return nil, errors.New("text must be a non-empty string")
+	}
+	labels, err := parseOptionalStringArray(request, "labels")
+	if err != nil {
+		return nil, err
+	}
+	limit, err := parseOptionalInt(request, "limit", 100)
+	if err != nil {
+		return nil, err
+	}
+	ctx, err = withSample(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	// Call graph store method
+	entities, err := s.graph.SearchEntities(ctx, labels, text, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search entities: %w", err)
+	}
+
+	// Return the result
+	resultJSON, err := json.Marshal(entities)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal entities: %w", err)
+	}
+	return mcp.NewToolResultText(string(resultJSON)), nil
+}
+
+// handleListRelationshipsByTypeTool handles the list_relationships_by_type tool
+func (s *Server) handleListRelationshipsByTypeTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	relType, ok := request.Params.Arguments["relationshipType"].(string)
+	if !ok || relType == "" {
+		return nil, errors.New("relationshipType must be a non-empty string")
+	}
+	skip, err := parseOptionalInt(request, "skip", 0)
+	if err != nil {
+		return nil, err
+	}
+	limit, err := parseOptionalInt(request, "limit", 100)
+	if err != nil {
+		return nil, err
+	}
+
+	// Call graph store method
+	relList, err := s.graph.ListRelationshipsByType(ctx, relType, skip, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list relationships: %w", err)
+	}
+
+	// Return the result
+	resultJSON, err := json.Marshal(relList)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal relationship list: %w", err)
+	}
+	return mcp.NewToolResultText(string(resultJSON)), nil
+}
+
+// handleFindBySourceTool handles the find_by_source tool
+func (s *Server) handleFindBySourceTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	source, ok := request.Params.Arguments["source"].(string)
+	if !ok || source == "" {
+		return nil, errors.New("source must be a non-empty string")
+	}
+	labels, err := parseOptionalStringArray(request, "labels")
+	if err != nil {
+		return nil, err
+	}
+	limit, err := parseOptionalInt(request, "limit", 100)
+	if err != nil {
+		return nil, err
+	}
+
+	// Call graph store method
+	entities, err := s.graph.FindBySource(ctx, labels, source, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find entities by source: %w", err)
+	}
+
+	// Return the result
+	resultJSON, err := json.Marshal(entities)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal entities: %w", err)
+	}
+	return mcp.NewToolResultText(string(resultJSON)), nil
+}
+
+// handleFindByFilePathTool handles the find_by_filepath tool
+func (s *Server) handleFindByFilePathTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	filePath, ok := request.Params.Arguments["filePath"].(string)
+	if !ok || filePath == "" {
+		return nil, errors.New("filePath must be a non-empty string")
+	}
+	prefix, err := parseOptionalBool(request, "prefix", false)
+	if err != nil {
+		return nil, err
+	}
+	labels, err := parseOptionalStringArray(request, "labels")
+	if err != nil {
+		return nil, err
+	}
+	limit, err := parseOptionalInt(request, "limit", 100)
+	if err != nil {
+		return nil, err
+	}
+
+	// Call graph store method
+	entities, err := s.graph.FindByFilePath(ctx, labels, filePath, prefix, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find entities by file path: %w", err)
+	}
+
+	// Return the result
+	resultJSON, err := json.Marshal(entities)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal entities: %w", err)
+	}
+	return mcp.NewToolResultText(string(resultJSON)), nil
+}
@@ -0,0 +1,446 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sammcj/mcp-graph/internal/graph"
+	"github.com/sammcj/mcp-graph/internal/mcp/mocks"
+)
+
+// TestHandleRecentlyModifiedTool tests the recently_modified tool handler
+func TestHandleRecentlyModifiedTool(t *testing.T) {
+	// Create a new mock controller
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Create mock graph store and service
+	mockGraph := mocks.NewMockStore(ctrl)
+	mockService := mocks.NewMockKnowledgeManager(ctrl)
+
+	// Create MCP server with mocks
+	server := &Server{
+		graph:   mockGraph,
+		service: mockService,
+	}
+
+	since := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	// Set up expectations
+	mockGraph.EXPECT().FindModifiedSince(
+		gomock.Any(),
+		gomock.Eq([]string{"Service"}),
+		gomock.Eq(since),
+		gomock.Eq(100),
+	).Return([]graph.EntityDetails{
+		{
+			Labels:     []string{"Service"},
+			Properties: map[string]interface{}{"id": "4:abc:1", "name": "billing"},
+		},
+	}, nil)
+
+	// Create tool request
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{
+		"since":  "2024-05-01T00:00:00Z",
+		"labels": []interface{}{"Service"},
+	}
+
+	// Call the handler
+	result, err := server.handleRecentlyModifiedTool(context.Background(), request)
+
+	// Assert the results
+	assert.NoError(t, err)
+	assert.NotNil(t, result)
+
+	// Verify the result content
+	var resultData []map[string]interface{}
+	err = json.Unmarshal([]byte(getResultText(result)), &resultData)
+	assert.NoError(t, err)
+	assert.Len(t, resultData, 1)
+}
+
+// TestHandleRecentlyModifiedTool_InvalidSince tests the recently_modified tool handler with a malformed timestamp
+func TestHandleRecentlyModifiedTool_InvalidSince(t *testing.T) {
+	// Create a new mock controller
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Create MCP server with mocks; no store calls are expected
+	server := &Server{
+		graph:   mocks.NewMockStore(ctrl),
+		service: mocks.NewMockKnowledgeManager(ctrl),
+	}
+
+	// Create tool request
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{
+		"since": "yesterday",
+	}
+
+	// Call the handler
+	_, err := server.handleRecentlyModifiedTool(context.Background(), request)
+
+	// Assert the error
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "RFC 3339")
+}
+
+// TestHandleFindEntitiesTool tests the find_entities tool handler with a contains filter
+func TestHandleFindEntitiesTool(t *testing.T) {
+	// Create a new mock controller
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Create mock graph store and service
+	mockGraph := mocks.NewMockStore(ctrl)
+	mockService := mocks.NewMockKnowledgeManager(ctrl)
+
+	// Create MCP server with mocks
+	server := &Server{
+		graph:   mockGraph,
+		service: mockService,
+	}
+
+	// Set up expectations
+	mockGraph.EXPECT().FindEntities(
+		gomock.Any(),
+		gomock.Eq([]string{"Service"}),
+		gomock.Eq([]graph.PropertyFilter{{Property: "tags", Operator: graph.FilterOpContains, Value: "public-api"}}),
+		gomock.Eq(20),
+	).Return([]graph.EntityDetails{
+		{
+			Labels:     []string{"Service"},
+			Properties: map[string]interface{}{"id": "4:abc:1", "tags": []interface{}{"public-api"}},
+		},
+	}, nil)
+
+	// Create tool request
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{
+		"labels": []interface{}{"Service"},
+		"filters": []interface{}{
+			map[string]interface{}{"property": "tags", "operator": "contains", "value": "public-api"},
+		},
+		"limit": float64(20),
+	}
+
+	// Call the handler
+	result, err := server.handleFindEntitiesTool(context.Background(), request)
+
+	// Assert the results
+	assert.NoError(t, err)
+	assert.NotNil(t, result)
+
+	// Verify the result content
+	var resultData []map[string]interface{}
+	err = json.Unmarshal([]byte(getResultText(result)), &resultData)
+	assert.NoError(t, err)
+	assert.Len(t, resultData, 1)
+}
+
+// TestHandleCountEntitiesTool tests the count_entities tool handler
+func TestHandleCountEntitiesTool(t *testing.T) {
+	// Create a new mock controller
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Create mock graph store and service
+	mockGraph := mocks.NewMockStore(ctrl)
+	mockService := mocks.NewMockKnowledgeManager(ctrl)
+
+	// Create MCP server with mocks
+	server := &Server{
+		graph:   mockGraph,
+		service: mockService,
+	}
+
+	// Set up expectations - operator defaults to empty (equals)
+	mockGraph.EXPECT().CountEntities(
+		gomock.Any(),
+		gomock.Nil(),
+		gomock.Eq([]graph.PropertyFilter{{Property: "status", Value: "active"}}),
+	).Return(int64(7), nil)
+
+	// Create tool request
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{
+		"filters": []interface{}{
+			map[string]interface{}{"property": "status", "value": "active"},
+		},
+	}
+
+	// Call the handler
+	result, err := server.handleCountEntitiesTool(context.Background(), request)
+
+	// Assert the results
+	assert.NoError(t, err)
+
+	// Verify the result content
+	var resultData map[string]interface{}
+	err = json.Unmarshal([]byte(getResultText(result)), &resultData)
+	assert.NoError(t, err)
+	assert.Equal(t, float64(7), resultData["count"])
+}
+
+// TestHandleListRelationshipsByTypeTool tests the list_relationships_by_type tool handler
+func TestHandleListRelationshipsByTypeTool(t *testing.T) {
+	// Create a new mock controller
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Create mock graph store and service
+	mockGraph := mocks.NewMockStore(ctrl)
+	mockService := mocks.NewMockKnowledgeManager(ctrl)
+
+	// Create MCP server with mocks
+	server := &Server{
+		graph:   mockGraph,
+		service: mockService,
+	}
+
+	// Mock relationship list
+	relList := graph.RelationshipList{
+		Relationships: []graph.RelationshipDetails{
+			{
+				ID:         "5:abc:1",
+				Type:       "COMMUNICATES_WITH",
+				StartNode:  graph.RelationshipEndpoint{ID: "4:abc:1", Labels: []string{"Service"}},
+				EndNode:    graph.RelationshipEndpoint{ID: "4:abc:2", Labels: []string{"Service"}},
+				Properties: map[string]interface{}{"protocol": "grpc"},
+			},
+		},
+		Total: 11,
+		Skip:  10,
+		Limit: 100,
+	}
+
+	// Set up expectations - limit defaults to 100
+	mockGraph.EXPECT().ListRelationshipsByType(
+		gomock.Any(),
+		gomock.Eq("COMMUNICATES_WITH"),
+		gomock.Eq(10),
+		gomock.Eq(100),
+	).Return(relList, nil)
+
+	// Create tool request
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{
+		"relationshipType": "COMMUNICATES_WITH",
+		"skip":             float64(10),
+	}
+
+	// Call the handler
+	result, err := server.handleListRelationshipsByTypeTool(context.Background(), request)
+
+	// Assert the results
+	assert.NoError(t, err)
+
+	// Verify the result content
+	var resultData map[string]interface{}
+	err = json.Unmarshal([]byte(getResultText(result)), &resultData)
+	assert.NoError(t, err)
+	assert.Equal(t, float64(11), resultData["total"])
+	rels := resultData["relationships"].([]interface{})
+	assert.Len(t, rels, 1)
+	rel := rels[0].(map[string]interface{})
+	assert.Equal(t, "4:abc:1", rel["startNode"].(map[string]interface{})["id"])
+	assert.Equal(t, "grpc", rel["properties"].(map[string]interface{})["protocol"])
+}
+
+// TestHandleSearchEntitiesTool tests the search_entities tool handler
+func TestHandleSearchEntitiesTool(t *testing.T) {
+	// Create a new mock controller
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Create mock graph store
+	mockGraph := mocks.NewMockStore(ctrl)
+
+	// Create MCP server with mocks
+	server := &Server{graph: mockGraph}
+
+	// Set up expectations - limit defaults to 100
+	mockGraph.EXPECT().SearchEntities(
+		gomock.Any(),
+		gomock.Eq([]string{"Service", "File"}),
+		gomock.Eq("payment"),
+		gomock.Eq(100),
+	).Return([]graph.EntityDetails{
+		{Labels: []string{"Service"}, Properties: map[string]interface{}{"id": "4:abc:1", "name": "PaymentGateway"}},
+		{Labels: []string{"File"}, Properties: map[string]interface{}{"id": "4:abc:2", "path": "src/payments/api.go"}},
+	}, nil)
+
+	// Create tool request
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{
+		"text":   "payment",
+		"labels": []interface{}{"Service", "File"},
+	}
+
+	// Call the handler
+	result, err := server.handleSearchEntitiesTool(context.Background(), request)
+
+	// Assert the results
+	assert.NoError(t, err)
+
+	// Verify the result content
+	var resultData []graph.EntityDetails
+	err = json.Unmarshal([]byte(getResultText(result)), &resultData)
+	assert.NoError(t, err)
+	assert.Len(t, resultData, 2)
+	assert.Equal(t, "PaymentGateway", resultData[0].Properties["name"])
+
+	// Blank search text is rejected without querying the store
+	request.Params.Arguments = map[string]interface{}{"text": "  "}
+	_, err = server.handleSearchEntitiesTool(context.Background(), request)
+	assert.Error(t, err)
+}
+
+// TestHandleFindEntitiesTool_Sample tests that the sample option asks the store for a sample
+func TestHandleFindEntitiesTool_Sample(t *testing.T) {
+	// Create a new mock controller
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Create mock graph store
+	mockGraph := mocks.NewMockStore(ctrl)
+
+	// Create MCP server with mocks
+	server := &Server{graph: mockGraph}
+
+	// Set up expectations - the sample size is passed to the store in the context
+	mockGraph.EXPECT().FindEntities(gomock.Any(), gomock.Eq([]string{"Function"}), gomock.Nil(), gomock.Eq(100)).
+		DoAndReturn(func(ctx context.Context, labels []string, filters []graph.PropertyFilter, limit int) ([]graph.EntityDetails, error) {
+			assert.Equal(t, 20, graph.SampleSizeFromContext(ctx))
+			return []graph.EntityDetails{}, nil
+		})
+
+	// Create tool request
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{
+		"labels": []interface{}{"Function"},
+		"sample": float64(20),
+	}
+
+	// Call the handler
+	_, err := server.handleFindEntitiesTool(context.Background(), request)
+	assert.NoError(t, err)
+
+	// A sample size that isn't a number is rejected without querying the store
+	request.Params.Arguments["sample"] = "twenty"
+	_, err = server.handleFindEntitiesTool(context.Background(), request)
+	assert.Error(t, err)
+
+	// As are sample sizes that aren't positive
+	for _, sample := range []float64{0, -5} {
+		request.Params.Arguments["sample"] = sample
+		_, err = server.handleFindEntitiesTool(context.Background(), request)
+		assert.Error(t, err)
+	}
+}
+
+// TestHandleFindBySourceTool tests the find_by_source tool handler
+func TestHandleFindBySourceTool(t *testing.T) {
+	// Create a new mock controller
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Create mock graph store
+	mockGraph := mocks.NewMockStore(ctrl)
+
+	// Create MCP server with mocks
+	server := &Server{graph: mockGraph}
+
+	// Set up expectations
+	mockGraph.EXPECT().FindBySource(
+		gomock.Any(),
+		gomock.Nil(),
+		gomock.Eq("agent-inference"),
+		gomock.Eq(20),
+	).Return([]graph.EntityDetails{
+		{Labels: []string{"Service"}, Properties: map[string]interface{}{"id": "4:abc:1", "name": "billing", "source": "agent-inference"}},
+	}, nil)
+
+	// Create tool request
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{
+		"source": "agent-inference",
+		"limit":  float64(20),
+	}
+
+	// Call the handler
+	result, err := server.handleFindBySourceTool(context.Background(), request)
+
+	// Assert the results
+	assert.NoError(t, err)
+
+	// Verify the result content
+	var resultData []graph.EntityDetails
+	err = json.Unmarshal([]byte(getResultText(result)), &resultData)
+	assert.NoError(t, err)
+	assert.Len(t, resultData, 1)
+	assert.Equal(t, "agent-inference", resultData[0].Properties["source"])
+
+	// A missing source is rejected without querying the store
+	request.Params.Arguments = map[string]interface{}{}
+	_, err = server.handleFindBySourceTool(context.Background(), request)
+	assert.Error(t, err)
+}
+
+// TestHandleFindByFilePathTool tests the find_by_filepath tool handler
+func TestHandleFindByFilePathTool(t *testing.T) {
+	// Create a new mock controller
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Create mock graph store
+	mockGraph := mocks.NewMockStore(ctrl)
+
+	// Create MCP server with mocks
+	server := &Server{graph: mockGraph}
+
+	// Set up expectations
+	mockGraph.EXPECT().FindByFilePath(
+		gomock.Any(),
+		gomock.Eq([]string{"Function"}),
+		gomock.Eq("src/api"),
+		gomock.Eq(true),
+		gomock.Eq(100),
+	).Return([]graph.EntityDetails{
+		{Labels: []string{"Function"}, Properties: map[string]interface{}{"id": "4:abc:1", "name": "handleLogin", "filePath": "src/api/auth.go"}},
+		{Labels: []string{"Function"}, Properties: map[string]interface{}{"id": "4:abc:2", "name": "handleLogout", "filePath": "src/api/auth.go"}},
+	}, nil)
+
+	// Create tool request
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{
+		"filePath": "src/api",
+		"prefix":   true,
+		"labels":   []interface{}{"Function"},
+	}
+
+	// Call the handler
+	result, err := server.handleFindByFilePathTool(context.Background(), request)
+
+	// Assert the results
+	assert.NoError(t, err)
+
+	// Verify the result content
+	var resultData []graph.EntityDetails
+	err = json.Unmarshal([]byte(getResultText(result)), &resultData)
+	assert.NoError(t, err)
+	assert.Len(t, resultData, 2)
+	assert.Equal(t, "src/api/auth.go", resultData[0].Properties["filePath"])
+
+	// A missing file path is rejected without querying the store
+	request.Params.Arguments = map[string]interface{}{"prefix": true}
+	_, err = server.handleFindByFilePathTool(context.Background(), request)
+	assert.Error(t, err)
+}
@@ -2,38 +2,145 @@ package mcp
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net"
 	"net/http"
+	"sort"
+	"strconv"
+	"strings"
 
+	"github.com/google/uuid"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 
+	"github.com/sammcj/mcp-graph/internal/audit"
 	"github.com/sammcj/mcp-graph/internal/graph"
 	"github.com/sammcj/mcp-graph/internal/service"
+	"github.com/sammcj/mcp-graph/internal/snapshot"
+	"github.com/sammcj/mcp-graph/internal/usage"
 )
 
 // Server represents the MCP server for the knowledge graph
 type Server struct {
-	server   *server.MCPServer
-	graph    graph.Store
-	service  service.KnowledgeManager
+	server    *server.MCPServer
+	graph     graph.Store
+	service   service.KnowledgeManager
+	redactor  *graph.Redactor
+	auditLog  *audit.Logger
+	usage     *usage.Tracker
+	logger    Logger
+	snapshots *snapshot.Store
+	toolCalls toolCallTracker
+
+	resultSizeWarning int
 }
 
 // NewServer creates a new MCP server
 func NewServer(name, version string, graph graph.Store) *Server {
+	hooks := &server.Hooks{}
 	s := server.NewMCPServer(
 		name,
 		version,
 		server.WithResourceCapabilities(true, true),
+		server.WithHooks(hooks),
 	)
 
-	return &Server{
+	mcpServer := &Server{
 		server:  s,
 		graph:   graph,
 		service: service.NewService(graph),
 	}
+	hooks.AddAfterCallTool(mcpServer.redactToolResult)
+	hooks.AddAfterCallTool(mcpServer.checkResultSize)
+	hooks.AddBeforeCallTool(mcpServer.auditToolCallStart)
+	hooks.AddAfterCallTool(mcpServer.auditToolCallResult)
+	hooks.AddOnError(mcpServer.auditToolCallError)
+
+	return mcpServer
+}
+
+// SetService replaces the knowledge manager used by the document and concept tools, so that it can share
+// configuration with the one used by the API server
+func (s *Server) SetService(knowledgeService service.KnowledgeManager) {
+	s.service = knowledgeService
+}
+
+// SetRedactor sets the redactor applied to every tool result before it is returned to the client
+func (s *Server) SetRedactor(redactor *graph.Redactor) {
+	s.redactor = redactor
+}
+
+// redactToolResult replaces sensitive property values in JSON tool results. Results in other formats (e.g. CSV or
+// Mermaid) are returned unchanged, as their values can't be told apart from the rest of the text, so the handlers
+// producing them must redact the data before formatting it.
+func (s *Server) redactToolResult(ctx context.Context, id any, request *mcp.CallToolRequest, result *mcp.CallToolResult) {
+	if !s.redactor.Enabled() || result == nil {
+		return
+	}
+	for i, content := range result.Content {
+		textContent, ok := content.(mcp.TextContent)
+		if !ok {
+			continue
+		}
+		if redacted, ok := s.redactor.RedactJSON([]byte(textContent.Text)); ok {
+			textContent.Text = string(redacted)
+			result.Content[i] = textContent
+		}
+	}
+}
+
+// addTool registers a tool whose handler runs with query metadata identifying the tool call, so that the
+// database transactions it causes can be attributed to it
+func (s *Server) addTool(tool mcp.Tool, handler server.ToolHandlerFunc) {
+	s.server.AddTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		// Track the call so that shutdown waits for it
+		if !s.toolCalls.start() {
+			return nil, errShuttingDown
+		}
+		defer s.toolCalls.finish()
+		s.usage.Record(clientID(ctx), tool.Name)
+		if err := checkArgumentsProvided(tool, request); err != nil {
+			return nil, err
+		}
+		return handler(withToolCallMetadata(ctx, tool.Name), request)
+	})
+}
+
+// checkArgumentsProvided returns an error naming the tool's required arguments if the call has none at all, e.g.
+// because the client sent no arguments object. Handlers would otherwise report the first missing field with a
+// generic type error.
+func checkArgumentsProvided(tool mcp.Tool, request mcp.CallToolRequest) error {
+	if len(request.Params.Arguments) > 0 || len(tool.InputSchema.Required) == 0 {
+		return nil
+	}
+	return fmt.Errorf("no arguments provided: %s requires %s", tool.Name, strings.Join(tool.InputSchema.Required, ", "))
+}
+
+// withToolCallMetadata returns a context carrying query metadata naming the tool, a unique request ID and,
+// if known, the ID of the client session making the call
+func withToolCallMetadata(ctx context.Context, toolName string) context.Context {
+	metadata := map[string]interface{}{
+		"source":    "mcp",
+		"tool":      toolName,
+		"requestId": uuid.NewString(),
+	}
+	if session := server.ClientSessionFromContext(ctx); session != nil {
+		metadata["clientId"] = session.SessionID()
+	}
+	return graph.WithQueryMetadata(ctx, metadata)
+}
+
+// clientID returns the ID of the client session making a tool call, or "unknown" if there is no session
+func clientID(ctx context.Context) string {
+	if session := server.ClientSessionFromContext(ctx); session != nil {
+		return session.SessionID()
+	}
+	return "unknown"
 }
 
 // SetupTools configures the MCP tools
@@ -48,8 +155,11 @@ func (s *Server) SetupTools() {
 		mcp.WithObject("params",
 			mcp.Description("Optional map of parameters to bind to the Cypher query. Keys should match placeholders in the query string (without the $)."),
 		),
+		mcp.WithBoolean("includeTypes",
+			mcp.Description("If true, returns {columns: [{name, type}], rows: [[...]]} instead of a list of objects, where type is the Neo4j type of the column's values (e.g. 'String', 'Integer', 'DateTime', 'Node'; 'Any' if mixed). Useful for telling apart values that look alike in JSON, such as datetimes and strings. Defaults to false."),
+		),
 	)
-	s.server.AddTool(queryTool, s.handleQueryTool)
+	s.addTool(queryTool, s.handleQueryTool)
 
 	// Document tools
 	createDocumentTool := mcp.NewTool("create_document",
@@ -66,16 +176,84 @@ func (s *Server) SetupTools() {
 			mcp.Description("Optional map of key-value pairs for additional metadata (e.g., {'source_url': '...', 'author': '...'})."),
 		),
 	)
-	s.server.AddTool(createDocumentTool, s.handleCreateDocumentTool)
+	s.addTool(createDocumentTool, s.handleCreateDocumentTool)
+
+	findOrCreateDocumentTool := mcp.NewTool("find_or_create_document",
+		mcp.WithDescription("Returns the existing 'Document' whose title matches ignoring case and whitespace (e.g. 'Design Notes' and ' design  notes'), or creates one if there is none. Use this instead of create_document when ingesting sources that may already have been stored. Existing documents are returned unchanged. Returns the document's ID and whether it was created."),
+		mcp.WithString("title",
+			mcp.Required(),
+			mcp.Description("The title of the document (e.g., file name, article title)."),
+		),
+		mcp.WithString("content",
+			mcp.Required(),
+			mcp.Description("The main textual content of the document, used if it is created."),
+		),
+		mcp.WithObject("metadata",
+			mcp.Description("Optional map of key-value pairs for additional metadata, used if the document is created."),
+		),
+	)
+	s.addTool(findOrCreateDocumentTool, s.handleFindOrCreateDocumentTool)
+
+	batchCreateDocumentsTool := mcp.NewTool("batch_create_documents",
+		mcp.WithDescription("Creates multiple 'Document' nodes in a single operation. This is significantly more efficient than calling create_document for each one, e.g. when ingesting a set of files or articles. Returns the IDs of the created documents in input order, with an error for each document that couldn't be created. If any input is malformed, nothing is processed and an error is returned listing every invalid field as {index, field, reason} in validationErrors."),
+		mcp.WithArray("documents",
+			mcp.Required(),
+			mcp.Description("Array of documents to create. Each document follows the same structure as the input to create_document."),
+			mcp.Items(map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"title": map[string]interface{}{
+						"type":        "string",
+						"description": "The title of the document (e.g., file name, article title).",
+					},
+					"content": map[string]interface{}{
+						"type":        "string",
+						"description": "The main textual content of the document.",
+					},
+					"metadata": map[string]interface{}{
+						"type":        "object",
+						"description": "Optional map of key-value pairs for additional metadata.",
+					},
+				},
+				"required": []string{"title", "content"},
+			}),
+		),
+	)
+	s.addTool(batchCreateDocumentsTool, s.handleBatchCreateDocumentsTool)
 
 	getDocumentTool := mcp.NewTool("get_document",
-		mcp.WithDescription("Retrieves a 'Document' node from the knowledge graph using its unique ID."),
+		mcp.WithDescription("Retrieves a 'Document' node from the knowledge graph using its unique ID, or its portable UUID if UUID assignment is enabled."),
+		mcp.WithString("id",
+			mcp.Description("The unique identifier (elementId) of the 'Document' node to retrieve. Either id or uuid is required."),
+		),
+		mcp.WithString("uuid",
+			mcp.Description("The uuid property of the 'Document' node to retrieve, as an alternative to id."),
+		),
+	)
+	s.addTool(getDocumentTool, s.handleGetDocumentTool)
+
+	getDocumentContextTool := mcp.NewTool("get_document_context",
+		mcp.WithDescription("Retrieves a 'Document' node together with every concept or entity it links to (one hop, via relationships created by link_document), as a single context card. Useful for loading everything known about a document in one call."),
 		mcp.WithString("id",
 			mcp.Required(),
-			mcp.Description("The unique identifier (elementId) of the 'Document' node to retrieve."),
+			mcp.Description("The unique identifier (elementId) of the 'Document' node."),
+		),
+	)
+	s.addTool(getDocumentContextTool, s.handleGetDocumentContextTool)
+
+	getEntityDocumentsTool := mcp.NewTool("get_entity_documents",
+		mcp.WithDescription("Retrieves every 'Document' linked to an entity (via relationships created by link_document, of any type), e.g. the design documents describing a Service, each with the relationship linking it. Useful for finding the human-written knowledge about a code or architecture entity. Returns at most 1000 documents."),
+		mcp.WithArray("labels",
+			mcp.Required(),
+			mcp.Description("List of labels for the entity."),
+			mcp.Items(map[string]interface{}{"type": "string"}),
+		),
+		mcp.WithObject("identifyingProperties",
+			mcp.Required(),
+			mcp.Description("Map of properties to uniquely identify the entity."),
 		),
 	)
-	s.server.AddTool(getDocumentTool, s.handleGetDocumentTool)
+	s.addTool(getEntityDocumentsTool, s.handleGetEntityDocumentsTool)
 
 	searchDocumentsTool := mcp.NewTool("search_documents",
 		mcp.WithDescription("Performs a text-based search across 'Document' nodes in the knowledge graph. (Note: Specific search implementation depends on the underlying graph store)."),
@@ -84,7 +262,7 @@ func (s *Server) SetupTools() {
 			mcp.Description("The text query string to search for within document content or titles."),
 		),
 	)
-	s.server.AddTool(searchDocumentsTool, s.handleSearchDocumentsTool)
+	s.addTool(searchDocumentsTool, s.handleSearchDocumentsTool)
 
 	// Concept tools
 	createConceptTool := mcp.NewTool("create_concept",
@@ -97,16 +275,18 @@ func (s *Server) SetupTools() {
 			mcp.Description("Optional map of key-value pairs for additional properties describing the concept."),
 		),
 	)
-	s.server.AddTool(createConceptTool, s.handleCreateConceptTool)
+	s.addTool(createConceptTool, s.handleCreateConceptTool)
 
 	getConceptTool := mcp.NewTool("get_concept",
-		mcp.WithDescription("Retrieves a 'Concept' node from the knowledge graph using its unique ID."),
+		mcp.WithDescription("Retrieves a 'Concept' node from the knowledge graph using its unique ID, or its portable UUID if UUID assignment is enabled."),
 		mcp.WithString("id",
-			mcp.Required(),
-			mcp.Description("The unique identifier (elementId) of the 'Concept' node to retrieve."),
+			mcp.Description("The unique identifier (elementId) of the 'Concept' node to retrieve. Either id or uuid is required."),
+		),
+		mcp.WithString("uuid",
+			mcp.Description("The uuid property of the 'Concept' node to retrieve, as an alternative to id."),
 		),
 	)
-	s.server.AddTool(getConceptTool, s.handleGetConceptTool)
+	s.addTool(getConceptTool, s.handleGetConceptTool)
 
 	linkConceptsTool := mcp.NewTool("link_concepts",
 		mcp.WithDescription("Creates a directed relationship between two existing 'Concept' nodes."),
@@ -126,7 +306,26 @@ func (s *Server) SetupTools() {
 			mcp.Description("Optional map of key-value pairs for properties of the relationship itself."),
 		),
 	)
-	s.server.AddTool(linkConceptsTool, s.handleLinkConceptsTool)
+	s.addTool(linkConceptsTool, s.handleLinkConceptsTool)
+
+	linkDocumentTool := mcp.NewTool("link_document",
+		mcp.WithDescription("Creates a directed relationship from an existing 'Document' node to a concept or architecture entity (e.g. a design document DESCRIBES a Service), connecting documents to the rest of the graph."),
+		mcp.WithString("documentId",
+			mcp.Required(),
+			mcp.Description("The unique ID (elementId) of the source 'Document' node."),
+		),
+		mcp.WithString("targetId",
+			mcp.Required(),
+			mcp.Description("The unique ID (elementId) of the target node, e.g. a 'Concept' or a 'Service' (as returned in the 'id' property by get_entity_details)."),
+		),
+		mcp.WithString("relationshipType",
+			mcp.Description("The type of the relationship (e.g., 'DESCRIBES', 'REFERENCES_TO'). Defaults to 'DESCRIBES'."),
+		),
+		mcp.WithObject("properties",
+			mcp.Description("Optional map of key-value pairs for properties of the relationship itself."),
+		),
+	)
+	s.addTool(linkDocumentTool, s.handleLinkDocumentTool)
 
 	// Legacy tools for backward compatibility (Consider deprecating or removing if not needed)
 	createNodeTool := mcp.NewTool("create_node",
@@ -140,7 +339,7 @@ func (s *Server) SetupTools() {
 			mcp.Description("Map of key-value pairs for the node's properties."),
 		),
 	)
-	s.server.AddTool(createNodeTool, s.handleCreateNodeTool)
+	s.addTool(createNodeTool, s.handleCreateNodeTool)
 
 	getNodeTool := mcp.NewTool("get_node",
 		mcp.WithDescription("[Legacy] Retrieves a generic node by its unique ID (elementId). Prefer using specific tools like 'get_document', 'get_concept', or 'get_entity_details'."),
@@ -149,7 +348,17 @@ func (s *Server) SetupTools() {
 			mcp.Description("The unique identifier (elementId) of the node to retrieve."),
 		),
 	)
-	s.server.AddTool(getNodeTool, s.handleGetNodeTool)
+	s.addTool(getNodeTool, s.handleGetNodeTool)
+
+	getNodesTool := mcp.NewTool("get_nodes",
+		mcp.WithDescription("Retrieves several generic nodes by their unique IDs (elementId) in a single call, in the order given. Use this instead of calling get_node repeatedly for IDs collected from an earlier traversal. IDs that don't match a node are skipped. At most 1000 IDs can be requested."),
+		mcp.WithArray("ids",
+			mcp.Required(),
+			mcp.Description("The unique identifiers (elementId) of the nodes to retrieve."),
+			mcp.Items(map[string]interface{}{"type": "string"}),
+		),
+	)
+	s.addTool(getNodesTool, s.handleGetNodesTool)
 
 	createEdgeTool := mcp.NewTool("create_edge",
 		mcp.WithDescription("[Legacy] Creates a directed relationship between two existing nodes identified by their IDs. Prefer using specific tools like 'link_concepts' or 'find_or_create_relationship'."),
@@ -169,7 +378,7 @@ func (s *Server) SetupTools() {
 			mcp.Description("Optional map of key-value pairs for properties of the relationship itself."),
 		),
 	)
-	s.server.AddTool(createEdgeTool, s.handleCreateEdgeTool)
+	s.addTool(createEdgeTool, s.handleCreateEdgeTool)
 
 	schemaTool := mcp.NewTool("upsert_schema",
 		mcp.WithDescription("Applies schema definitions (like constraints and indexes) to the graph database. Accepts Cypher DDL statements."),
@@ -178,7 +387,7 @@ func (s *Server) SetupTools() {
 			mcp.Description("A string containing one or more Cypher DDL statements (e.g., 'CREATE CONSTRAINT FOR (n:User) REQUIRE n.uuid IS UNIQUE;'). Statements can be separated by newlines or semicolons."),
 		),
 	)
-	s.server.AddTool(schemaTool, s.handleSchemaTool)
+	s.addTool(schemaTool, s.handleSchemaTool)
 
 	// --- Software Architecture Tools ---
 
@@ -197,8 +406,11 @@ func (s *Server) SetupTools() {
 			mcp.Required(),
 			mcp.Description("Map of all properties (including identifying ones and any others like 'description', 'source', 'tags', 'language', 'signature', etc.) to set on create or merge/update on match. 'lastModifiedAt' will always be updated."),
 		),
+		mcp.WithBoolean("returnChanges",
+			mcp.Description("If true, the result also includes 'created' and 'changedProperties', a map of each property whose value the call changed to its {old, new} values (old is null for added properties), so you can tell whether an update was a no-op. lastModifiedAt is not included. Defaults to false."),
+		),
 	)
-	s.server.AddTool(findOrCreateEntityTool, s.handleFindOrCreateEntityTool)
+	s.addTool(findOrCreateEntityTool, s.handleFindOrCreateEntityTool)
 
 	findOrCreateRelationshipTool := mcp.NewTool("find_or_create_relationship",
 		mcp.WithDescription("Idempotently finds or creates a directed relationship between two existing nodes (identified by labels and properties), merging provided properties on the relationship. Use this to represent connections like CALLS, DEPENDS_ON, IMPLEMENTS etc. Automatically handles 'createdAt' and 'lastModifiedAt' timestamps for the relationship."),
@@ -228,10 +440,42 @@ func (s *Server) SetupTools() {
 			mcp.Description("Optional map of properties to set on create or merge/update on match for the relationship itself (e.g., {'lineNumber': 123} for a CALLS relationship). 'lastModifiedAt' will always be updated."),
 		),
 	)
-	s.server.AddTool(findOrCreateRelationshipTool, s.handleFindOrCreateRelationshipTool)
+	s.addTool(findOrCreateRelationshipTool, s.handleFindOrCreateRelationshipTool)
+
+	upsertEntityWithRelationshipTool := mcp.NewTool("upsert_entity_with_relationship",
+		mcp.WithDescription("Idempotently finds or creates an entity and a relationship between it and an existing target node in a single transaction, e.g. a Function and its DEFINED_IN relationship to a File. Either both are written or neither is: if the target doesn't exist, nothing is created. Returns the entity and the relationship's properties."),
+		mcp.WithArray("labels",
+			mcp.Required(),
+			mcp.Description("List of labels for the entity (e.g., ['Function'])."),
+			mcp.Items(map[string]interface{}{"type": "string"}),
+		),
+		mcp.WithObject("identifyingProperties",
+			mcp.Required(),
+			mcp.Description("Map of properties used to uniquely identify the entity for matching."),
+		),
+		mcp.WithObject("properties",
+			mcp.Description("Optional map of properties to set on create or merge/update on match for the entity. 'lastModifiedAt' will always be updated."),
+		),
+		mcp.WithString("relationshipType",
+			mcp.Required(),
+			mcp.Description("The type name for the relationship (e.g., 'DEFINED_IN')."),
+		),
+		mcp.WithObject("target",
+			mcp.Required(),
+			mcp.Description("The existing node at the other end of the relationship, as {labels, identifyingProperties}."),
+		),
+		mcp.WithString("direction",
+			mcp.Description("Direction of the relationship relative to the entity: 'outgoing' (entity to target) or 'incoming' (target to entity). Defaults to 'outgoing'."),
+			mcp.Enum(graph.DirectionOutgoing, graph.DirectionIncoming),
+		),
+		mcp.WithObject("relationshipProperties",
+			mcp.Description("Optional map of properties to set on create or merge/update on match for the relationship."),
+		),
+	)
+	s.addTool(upsertEntityWithRelationshipTool, s.handleUpsertEntityWithRelationshipTool)
 
 	getEntityDetailsTool := mcp.NewTool("get_entity_details",
-		mcp.WithDescription("Retrieves the full labels and properties of a specific entity identified by its labels and unique properties."),
+		mcp.WithDescription("Retrieves the labels and properties of a specific entity identified by its labels and unique properties. Optionally returns only selected properties."),
 		mcp.WithArray("labels",
 			mcp.Required(),
 			mcp.Description("List of labels used to find the entity (e.g., ['Function'])."),
@@ -241,8 +485,12 @@ func (s *Server) SetupTools() {
 			mcp.Required(),
 			mcp.Description("Map of properties used to uniquely identify the entity (e.g., {'filePath': '/path/to/file.go', 'name': 'MyFunc'})."),
 		),
+		mcp.WithArray("properties",
+			mcp.Description("Optional list of property keys to return (e.g., ['name', 'filePath']). The id is always included. If omitted or empty, all properties are returned; use this to avoid fetching large properties such as document content."),
+			mcp.Items(map[string]interface{}{"type": "string"}),
+		),
 	)
-	s.server.AddTool(getEntityDetailsTool, s.handleGetEntityDetailsTool)
+	s.addTool(getEntityDetailsTool, s.handleGetEntityDetailsTool)
 
 	findNeighborsTool := mcp.NewTool("find_neighbors",
 		mcp.WithDescription("Finds the direct neighbors (nodes connected by a single relationship) of a specific entity, up to a specified depth. Returns the central node details and a list of neighbors including relationship type and direction."),
@@ -259,10 +507,10 @@ func (s *Server) SetupTools() {
 			mcp.Description("Maximum relationship path depth to search for neighbors (e.g., 1 for direct neighbors, 2 for neighbors-of-neighbors). Defaults to 1 if not provided or invalid."),
 		),
 	)
-	s.server.AddTool(findNeighborsTool, s.handleFindNeighborsTool)
+	s.addTool(findNeighborsTool, s.handleFindNeighborsTool)
 
 	findDependenciesTool := mcp.NewTool("find_dependencies",
-		mcp.WithDescription("Finds entities that the target entity depends on by following outgoing relationships (e.g., A depends on B if A -> B). Allows filtering by relationship types and specifying search depth."),
+		mcp.WithDescription("Finds entities that the target entity depends on by following outgoing relationships (e.g., A depends on B if A -> B). Allows filtering by relationship types and specifying search depth. Each result includes its depth, the types of the relationships leading into it (e.g. CALLS vs IMPORTS) and, for direct dependencies, the connecting relationships with their properties."),
 		mcp.WithArray("labels",
 			mcp.Required(),
 			mcp.Description("List of labels for the target entity whose dependencies are being sought."),
@@ -276,14 +524,23 @@ func (s *Server) SetupTools() {
 			mcp.Description("Optional list of specific relationship types to follow when searching for dependencies (e.g., ['DEPENDS_ON', 'CALLS']). If omitted or empty, all outgoing relationship types will be followed."),
 			mcp.Items(map[string]interface{}{"type": "string"}),
 		),
+		mcp.WithObject("relationshipPropertyFilters",
+			mcp.Description("Optional map of relationship property values that every followed relationship must match (e.g., {\"scope\": \"compile\"} to follow only compile-scope dependencies). If omitted or empty, relationships are not filtered by property."),
+		),
 		mcp.WithNumber("maxDepth",
 			mcp.Description("Maximum relationship path depth to search for dependencies (e.g., 1 for direct dependencies). Defaults to 1 if not provided or invalid."),
 		),
+		mcp.WithNumber("sample",
+			mcp.Description(sampleDescription),
+		),
+		mcp.WithBoolean("estimateCost",
+			mcp.Description(estimateCostDescription),
+		),
 	)
-	s.server.AddTool(findDependenciesTool, s.handleFindDependenciesTool)
+	s.addTool(findDependenciesTool, s.handleFindDependenciesTool)
 
 	findDependentsTool := mcp.NewTool("find_dependents",
-		mcp.WithDescription("Finds entities that depend on the target entity by following incoming relationships (e.g., B depends on A if B -> A). Allows filtering by relationship types and specifying search depth. Useful for impact analysis."),
+		mcp.WithDescription("Finds entities that depend on the target entity by following incoming relationships (e.g., B depends on A if B -> A). Allows filtering by relationship types and specifying search depth. Each result includes its depth, the types of the relationships leading out of it and, for direct dependents, the connecting relationships with their properties. Useful for impact analysis."),
 		mcp.WithArray("labels",
 			mcp.Required(),
 			mcp.Description("List of labels for the target entity whose dependents are being sought."),
@@ -300,8 +557,14 @@ func (s *Server) SetupTools() {
 		mcp.WithNumber("maxDepth",
 			mcp.Description("Maximum relationship path depth to search for dependents (e.g., 1 for direct dependents). Defaults to 1 if not provided or invalid."),
 		),
+		mcp.WithNumber("sample",
+			mcp.Description(sampleDescription),
+		),
+		mcp.WithBoolean("estimateCost",
+			mcp.Description(estimateCostDescription),
+		),
 	)
-	s.server.AddTool(findDependentsTool, s.handleFindDependentsTool)
+	s.addTool(findDependentsTool, s.handleFindDependentsTool)
 
 	getEntitySubgraphTool := mcp.NewTool("get_entity_subgraph",
 		mcp.WithDescription("Retrieves a subgraph containing nodes and relationships within a specified depth around a central entity. The result format is designed for easy conversion into visualisation formats like Mermaid diagrams. Requires the APOC plugin to be installed on the Neo4j server."),
@@ -317,13 +580,27 @@ func (s *Server) SetupTools() {
 		mcp.WithNumber("maxDepth",
 			mcp.Description("Maximum relationship path depth to include in the subgraph (e.g., 1 for direct neighbors, 2 includes neighbors-of-neighbors). Defaults to 1 if not provided or invalid."),
 		),
+		mcp.WithNumber("pageSize",
+			mcp.Description("Optional maximum number of nodes to return per call. When set, the subgraph is returned in pages: each page contains up to pageSize nodes and the relationships starting at them, plus totalNodes, hasMore and a continuationToken for fetching the next page."),
+		),
+		mcp.WithString("continuationToken",
+			mcp.Description("Token returned by a previous paged call, used to fetch the next page. Pass the same labels, identifyingProperties, maxDepth, includeLabels and pageSize as the original call; tokens used with different ones are rejected."),
+		),
+		mcp.WithArray("includeLabels",
+			mcp.Description("Optional list of labels to restrict the subgraph to (e.g. ['Service', 'DataStore'] for just the service-to-datastore layer). The central entity, the nodes within maxDepth with any of these labels and the relationships among them are returned; other nodes are still traversed but left out. If omitted or empty, every node is returned."),
+			mcp.Items(map[string]interface{}{"type": "string"}),
+		),
+		mcp.WithString("format",
+			mcp.Description("Result format: 'json' (default) for the nodes and relationships, or 'mermaid' for a Mermaid flowchart ready to render. In the flowchart nodes are given Mermaid-safe IDs (n0, n1, ...) instead of their element IDs. 'mermaid' can't be combined with paging."),
+			mcp.Enum(subgraphFormatJSON, subgraphFormatMermaid),
+		),
 	)
-	s.server.AddTool(getEntitySubgraphTool, s.handleGetEntitySubgraphTool)
+	s.addTool(getEntitySubgraphTool, s.handleGetEntitySubgraphTool)
 
 	// --- Batch Operation Tools ---
 
 	batchFindOrCreateEntitiesToolTool := mcp.NewTool("batch_find_or_create_entities",
-		mcp.WithDescription("Creates or updates multiple entities in a single operation. This is significantly more efficient than making individual calls, especially when creating many related entities. Use this to add multiple software architecture elements like Functions, Classes, Files, etc., to the graph in one request."),
+		mcp.WithDescription("Creates or updates multiple entities in a single operation. This is significantly more efficient than making individual calls, especially when creating many related entities. Use this to add multiple software architecture elements like Functions, Classes, Files, etc., to the graph in one request. The response has a result for every input, in order; if an input fails, its result is empty and its error is listed in individualErrors, while the other inputs are still processed. If any input is malformed, nothing is processed and an error is returned listing every invalid field as {index, field, reason} in validationErrors."),
 		mcp.WithArray("entities",
 			mcp.Required(),
 			mcp.Description("Array of entity definitions to create or update. Each entity follows the same structure as the input to find_or_create_entity."),
@@ -350,10 +627,10 @@ func (s *Server) SetupTools() {
 			}),
 		),
 	)
-	s.server.AddTool(batchFindOrCreateEntitiesToolTool, s.handleBatchFindOrCreateEntitiesToolTool)
+	s.addTool(batchFindOrCreateEntitiesToolTool, s.handleBatchFindOrCreateEntitiesToolTool)
 
 	batchFindOrCreateRelationshipsToolTool := mcp.NewTool("batch_find_or_create_relationships",
-		mcp.WithDescription("Creates or updates multiple relationships in a single operation. This is significantly more efficient than making individual calls, especially when creating many relationships between entities. Use this to add multiple connections like CALLS, DEPENDS_ON, IMPLEMENTS, etc., in one request."),
+		mcp.WithDescription("Creates or updates multiple relationships in a single operation. This is significantly more efficient than making individual calls, especially when creating many relationships between entities. Use this to add multiple connections like CALLS, DEPENDS_ON, IMPLEMENTS, etc., in one request. The response has a result for every input, in order; if an input fails, its result is null and its error is listed in individualErrors, while the other inputs are still processed. If any input is malformed, nothing is processed and an error is returned listing every invalid field as {index, field, reason} in validationErrors."),
 		mcp.WithArray("relationships",
 			mcp.Required(),
 			mcp.Description("Array of relationship definitions to create or update. Each relationship follows the same structure as the input to find_or_create_relationship."),
@@ -395,7 +672,28 @@ func (s *Server) SetupTools() {
 			}),
 		),
 	)
-	s.server.AddTool(batchFindOrCreateRelationshipsToolTool, s.handleBatchFindOrCreateRelationshipsToolTool)
+	s.addTool(batchFindOrCreateRelationshipsToolTool, s.handleBatchFindOrCreateRelationshipsToolTool)
+
+	// --- Analysis Tools ---
+	s.setupAnalysisTools()
+
+	// --- Traversal Tools ---
+	s.setupTraversalTools()
+
+	// --- Search Tools ---
+	s.setupSearchTools()
+
+	// --- Maintenance Tools ---
+	s.setupMaintenanceTools()
+
+	// --- Snapshot Tools ---
+	s.setupSnapshotTools()
+
+	// --- Export Tools ---
+	s.setupExportTools()
+
+	// --- Meta Tools ---
+	s.setupMetaTools()
 }
 
 // handleQueryTool handles the query_knowledge_graph tool
@@ -415,10 +713,26 @@ func (s *Server) handleQueryTool(ctx context.Context, request mcp.CallToolReques
 		}
 	}
 
+	includeTypes, err := parseOptionalBool(request, "includeTypes", false)
+	if err != nil {
+		return nil, err
+	}
+	if includeTypes {
+		typedResult, err := s.graph.QueryWithTypes(ctx, query, params)
+		if err != nil {
+			return nil, s.queryFailure(query, err)
+		}
+		resultJSON, err := json.Marshal(typedResult)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal results: %w", err)
+		}
+		return mcp.NewToolResultText(string(resultJSON)), nil
+	}
+
 	// Execute query against graph
 	results, err := s.graph.Query(ctx, query, params)
 	if err != nil {
-		return nil, fmt.Errorf("query failed: %w", err)
+		return nil, s.queryFailure(query, err)
 	}
 
 	// Format and return results
@@ -429,6 +743,16 @@ func (s *Server) handleQueryTool(ctx context.Context, request mcp.CallToolReques
 	return mcp.NewToolResultText(string(resultJSON)), nil
 }
 
+// queryFailure wraps the error from a failed custom query. If the query looks like it's written in a
+// different language from the one the backend expects (e.g. DQL sent to Neo4j), the mismatch is explained
+// ahead of the driver's error, which is rarely helpful on its own.
+func (s *Server) queryFailure(query string, err error) error {
+	if mismatch := graph.CheckQueryLanguage(s.graph.QueryLanguage(), query); mismatch != nil {
+		return fmt.Errorf("query failed: %w (%v)", mismatch, err)
+	}
+	return fmt.Errorf("query failed: %w", err)
+}
+
 // handleCreateNodeTool handles the create_node tool
 func (s *Server) handleCreateNodeTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	nodeType, ok := request.Params.Arguments["type"].(string)
@@ -477,6 +801,36 @@ func (s *Server) handleGetNodeTool(ctx context.Context, request mcp.CallToolRequ
 	return mcp.NewToolResultText(string(nodeJSON)), nil
 }
 
+// maxGetNodesIDs is the maximum number of IDs get_nodes accepts
+const maxGetNodesIDs = 1000
+
+// handleGetNodesTool handles the get_nodes tool
+func (s *Server) handleGetNodesTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	ids, err := parseOptionalStringArray(request, "ids")
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return nil, errors.New("ids must be a non-empty array of strings")
+	}
+	if len(ids) > maxGetNodesIDs {
+		return nil, fmt.Errorf("at most %d ids can be requested at once", maxGetNodesIDs)
+	}
+
+	// Get nodes
+	nodes, err := s.graph.GetNodesByIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get nodes: %w", err)
+	}
+
+	// Return the nodes
+	nodesJSON, err := json.Marshal(nodes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal nodes: %w", err)
+	}
+	return mcp.NewToolResultText(string(nodesJSON)), nil
+}
+
 // handleCreateEdgeTool handles the create_edge tool
 func (s *Server) handleCreateEdgeTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	fromID, ok := request.Params.Arguments["fromId"].(string)
@@ -542,15 +896,110 @@ func (s *Server) handleCreateDocumentTool(ctx context.Context, request mcp.CallT
 	return mcp.NewToolResultText(fmt.Sprintf(`{"id":"%s"}`, id)), nil
 }
 
-// handleGetDocumentTool handles the get_document tool
-func (s *Server) handleGetDocumentTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	id, ok := request.Params.Arguments["id"].(string)
+// handleFindOrCreateDocumentTool handles the find_or_create_document tool
+func (s *Server) handleFindOrCreateDocumentTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	title, ok := request.Params.Arguments["title"].(string)
 	if !ok {
-		return nil, errors.New("id must be a string")
+		return nil, errors.New("title must be a string")
+	}
+
+	content, ok := request.Params.Arguments["content"].(string)
+	if !ok {
+		return nil, errors.New("content must be a string")
+	}
+
+	metadata, err := parseOptionalObject(request, "metadata")
+	if err != nil {
+		return nil, err
+	}
+
+	// Find or create document
+	id, created, err := s.service.FindOrCreateDocument(ctx, title, content, metadata)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find or create document: %w", err)
+	}
+
+	// Return the document ID
+	resultJSON, err := json.Marshal(map[string]interface{}{"id": id, "created": created})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+	return mcp.NewToolResultText(string(resultJSON)), nil
+}
+
+// handleBatchCreateDocumentsTool handles the batch_create_documents tool
+func (s *Server) handleBatchCreateDocumentsTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	documentsInterface, ok := request.Params.Arguments["documents"].([]interface{})
+	if !ok {
+		return nil, errors.New("documents must be an array")
+	}
+	if len(documentsInterface) == 0 {
+		return nil, errors.New("at least one document is required")
+	}
+
+	// Convert to DocumentInput array, collecting every invalid field
+	var validator batchValidator
+	docs := make([]service.DocumentInput, len(documentsInterface))
+	for i, documentInterface := range documentsInterface {
+		docs[i] = validator.documentInput(i, documentInterface)
+	}
+	if result, err := validator.result(len(docs)); result != nil || err != nil {
+		return result, err
+	}
+
+	// Create documents
+	ids, individualErrors, err := s.service.BatchCreateDocuments(ctx, docs)
+
+	// Prepare response
+	response := struct {
+		IDs              []string `json:"ids"`
+		IndividualErrors []string `json:"individualErrors,omitempty"`
+		Error            string   `json:"error,omitempty"`
+	}{
+		IDs: ids,
+	}
+
+	// Handle individual errors
+	for i, individualErr := range individualErrors {
+		if individualErr != nil {
+			response.IndividualErrors = append(response.IndividualErrors, fmt.Sprintf("Error at index %d: %s", i, individualErr.Error()))
+		}
+	}
+
+	// Handle overall error
+	if err != nil {
+		response.Error = err.Error()
+	}
+
+	// Return the results
+	responseJSON, err := json.Marshal(response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal batch results: %w", err)
 	}
 
-	// Get document
-	doc, err := s.service.GetDocument(ctx, id)
+	return mcp.NewToolResultText(string(responseJSON)), nil
+}
+
+// handleGetDocumentTool handles the get_document tool
+func (s *Server) handleGetDocumentTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var doc *service.Document
+	var err error
+	if uuidArg, exists := request.Params.Arguments["uuid"]; exists && uuidArg != nil {
+		// Look up by portable UUID
+		uuid, ok := uuidArg.(string)
+		if !ok || uuid == "" {
+			return nil, errors.New("uuid must be a non-empty string")
+		}
+		doc, err = s.service.GetDocumentByUUID(ctx, uuid)
+	} else {
+		id, ok := request.Params.Arguments["id"].(string)
+		if !ok {
+			return nil, errors.New("id must be a string")
+		}
+
+		// Get document
+		doc, err = s.service.GetDocument(ctx, id)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get document: %w", err)
 	}
@@ -563,6 +1012,48 @@ func (s *Server) handleGetDocumentTool(ctx context.Context, request mcp.CallTool
 	return mcp.NewToolResultText(string(docJSON)), nil
 }
 
+// handleGetDocumentContextTool handles the get_document_context tool
+func (s *Server) handleGetDocumentContextTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	id, ok := request.Params.Arguments["id"].(string)
+	if !ok || id == "" {
+		return nil, errors.New("id must be a non-empty string")
+	}
+
+	// Get the document and its links
+	docContext, err := s.service.GetDocumentContext(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get document context: %w", err)
+	}
+
+	// Return the context card
+	contextJSON, err := json.Marshal(docContext)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal document context: %w", err)
+	}
+	return mcp.NewToolResultText(string(contextJSON)), nil
+}
+
+// handleGetEntityDocumentsTool handles the get_entity_documents tool
+func (s *Server) handleGetEntityDocumentsTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	locator, err := parseEntityLocator(request.Params.Arguments)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get the documents linked to the entity
+	documents, err := s.service.GetEntityDocuments(ctx, locator)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get entity documents: %w", err)
+	}
+
+	// Return the documents
+	documentsJSON, err := json.Marshal(documents)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal entity documents: %w", err)
+	}
+	return mcp.NewToolResultText(string(documentsJSON)), nil
+}
+
 // handleSearchDocumentsTool handles the search_documents tool
 func (s *Server) handleSearchDocumentsTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	query, ok := request.Params.Arguments["query"].(string)
@@ -611,13 +1102,24 @@ func (s *Server) handleCreateConceptTool(ctx context.Context, request mcp.CallTo
 
 // handleGetConceptTool handles the get_concept tool
 func (s *Server) handleGetConceptTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	id, ok := request.Params.Arguments["id"].(string)
-	if !ok {
-		return nil, errors.New("id must be a string")
-	}
+	var concept *service.Concept
+	var err error
+	if uuidArg, exists := request.Params.Arguments["uuid"]; exists && uuidArg != nil {
+		// Look up by portable UUID
+		uuid, ok := uuidArg.(string)
+		if !ok || uuid == "" {
+			return nil, errors.New("uuid must be a non-empty string")
+		}
+		concept, err = s.service.GetConceptByUUID(ctx, uuid)
+	} else {
+		id, ok := request.Params.Arguments["id"].(string)
+		if !ok {
+			return nil, errors.New("id must be a string")
+		}
 
-	// Get concept
-	concept, err := s.service.GetConcept(ctx, id)
+		// Get concept
+		concept, err = s.service.GetConcept(ctx, id)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get concept: %w", err)
 	}
@@ -665,6 +1167,41 @@ func (s *Server) handleLinkConceptsTool(ctx context.Context, request mcp.CallToo
 	return mcp.NewToolResultText(fmt.Sprintf(`{"id":"%s"}`, id)), nil
 }
 
+// handleLinkDocumentTool handles the link_document tool
+func (s *Server) handleLinkDocumentTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	documentID, ok := request.Params.Arguments["documentId"].(string)
+	if !ok || documentID == "" {
+		return nil, errors.New("documentId must be a non-empty string")
+	}
+
+	targetID, ok := request.Params.Arguments["targetId"].(string)
+	if !ok || targetID == "" {
+		return nil, errors.New("targetId must be a non-empty string")
+	}
+
+	var relationshipType string
+	if relTypeArg, ok := request.Params.Arguments["relationshipType"]; ok && relTypeArg != nil {
+		relationshipType, ok = relTypeArg.(string)
+		if !ok {
+			return nil, errors.New("relationshipType must be a string")
+		}
+	}
+
+	properties, err := parseOptionalObject(request, "properties")
+	if err != nil {
+		return nil, err
+	}
+
+	// Link document
+	id, err := s.service.LinkDocument(ctx, documentID, targetID, relationshipType, properties)
+	if err != nil {
+		return nil, fmt.Errorf("failed to link document: %w", err)
+	}
+
+	// Return the edge ID
+	return mcp.NewToolResultText(fmt.Sprintf(`{"id":"%s"}`, id)), nil
+}
+
 // handleSchemaTool handles the upsert_schema tool
 func (s *Server) handleSchemaTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	schema, ok := request.Params.Arguments["schema"].(string)
@@ -730,6 +1267,21 @@ func (s *Server) handleFindOrCreateEntityTool(ctx context.Context, request mcp.C
 	if !ok {
 		return nil, errors.New("properties must be an object")
 	}
+	returnChanges, err := parseOptionalBool(request, "returnChanges", false)
+	if err != nil {
+		return nil, err
+	}
+	if returnChanges {
+		upsert, err := s.graph.FindOrCreateEntityWithChanges(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find or create entity: %w", err)
+		}
+		upsertJSON, err := json.Marshal(upsert)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal entity details: %w", err)
+		}
+		return mcp.NewToolResultText(string(upsertJSON)), nil
+	}
 
 	// Call graph store method
 	details, err := s.graph.FindOrCreateEntity(ctx, input)
@@ -817,6 +1369,71 @@ func (s *Server) handleFindOrCreateRelationshipTool(ctx context.Context, request
 	return mcp.NewToolResultText(string(relPropsJSON)), nil
 }
 
+// handleUpsertEntityWithRelationshipTool handles the upsert_entity_with_relationship tool
+func (s *Server) handleUpsertEntityWithRelationshipTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	locator, err := parseEntityLocator(request.Params.Arguments)
+	if err != nil {
+		return nil, err
+	}
+	props, err := parseOptionalObject(request, "properties")
+	if err != nil {
+		return nil, err
+	}
+	if props == nil {
+		props = make(map[string]interface{})
+	}
+	relType, ok := request.Params.Arguments["relationshipType"].(string)
+	if !ok || relType == "" {
+		return nil, errors.New("relationshipType must be a non-empty string")
+	}
+	target, err := parseEntityLocator(request.Params.Arguments["target"])
+	if err != nil {
+		return nil, fmt.Errorf("invalid target: %w", err)
+	}
+	direction := graph.DirectionOutgoing
+	if directionArg, exists := request.Params.Arguments["direction"]; exists && directionArg != nil {
+		direction, ok = directionArg.(string)
+		if !ok {
+			return nil, errors.New("direction must be a string")
+		}
+	}
+	relProps, err := parseOptionalObject(request, "relationshipProperties")
+	if err != nil {
+		return nil, err
+	}
+	if relProps == nil {
+		relProps = make(map[string]interface{})
+	}
+
+	entity := graph.EntityInput{
+		Labels:                locator.Labels,
+		IdentifyingProperties: locator.IdentifyingProperties,
+		Properties:            props,
+	}
+	rel := graph.RelationshipInput{RelationshipType: relType, Properties: relProps}
+	switch direction {
+	case graph.DirectionOutgoing:
+		rel.EndNodeLabels, rel.EndNodeIdentifyingProperties = target.Labels, target.IdentifyingProperties
+	case graph.DirectionIncoming:
+		rel.StartNodeLabels, rel.StartNodeIdentifyingProperties = target.Labels, target.IdentifyingProperties
+	default:
+		return nil, fmt.Errorf("invalid direction %q: must be 'outgoing' or 'incoming'", direction)
+	}
+
+	// Call graph store method
+	result, err := s.graph.UpsertEntityWithRelationship(ctx, entity, rel)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upsert entity with relationship: %w", err)
+	}
+
+	// Return the result
+	resultJSON, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result: %w", err)
+	}
+	return mcp.NewToolResultText(string(resultJSON)), nil
+}
+
 // handleGetEntityDetailsTool handles the get_entity_details tool
 func (s *Server) handleGetEntityDetailsTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	var labels []string
@@ -842,8 +1459,14 @@ func (s *Server) handleGetEntityDetailsTool(ctx context.Context, request mcp.Cal
 	if !ok { return nil, errors.New("identifyingProperties must be an object") }
 	if len(identifyingProperties) == 0 { return nil, errors.New("at least one identifying property is required") }
 
+	// Parse properties projection (optional)
+	properties, err := parseOptionalStringArray(request, "properties")
+	if err != nil {
+		return nil, err
+	}
+
 	// Call graph store method
-	details, err := s.graph.GetEntityDetails(ctx, labels, identifyingProperties)
+	details, err := s.graph.GetEntityDetails(ctx, labels, identifyingProperties, properties)
 	if err != nil {
 		// Consider returning a structured error for "not found"
 		return nil, fmt.Errorf("failed to get entity details: %w", err)
@@ -964,6 +1587,115 @@ func parseOptionalRelationshipTypes(request mcp.CallToolRequest) ([]string, erro
 	return relTypes, nil
 }
 
+// Helper function to parse an optional array of strings
+func parseOptionalStringArray(request mcp.CallToolRequest, key string) ([]string, error) {
+	var values []string
+	if arg, exists := request.Params.Arguments[key]; exists && arg != nil {
+		valuesInterface, ok := arg.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s must be an array of strings", key)
+		}
+		values = make([]string, len(valuesInterface))
+		for i, v := range valuesInterface {
+			values[i], ok = v.(string)
+			if !ok {
+				return nil, fmt.Errorf("%s item at index %d is not a string", key, i)
+			}
+		}
+	}
+	return values, nil
+}
+
+// Helper function to parse an optional positive integer, using the default when absent or not positive
+func parseOptionalInt(request mcp.CallToolRequest, key string, defaultValue int) (int, error) {
+	arg, exists := request.Params.Arguments[key]
+	if !exists || arg == nil {
+		return defaultValue, nil
+	}
+	valueFloat, ok := arg.(float64) // JSON numbers are often float64
+	if !ok {
+		return 0, fmt.Errorf("%s must be a number", key)
+	}
+	if int(valueFloat) <= 0 {
+		return defaultValue, nil
+	}
+	return int(valueFloat), nil
+}
+
+// Helper function to parse an optional object argument
+func parseOptionalObject(request mcp.CallToolRequest, key string) (map[string]interface{}, error) {
+	arg, exists := request.Params.Arguments[key]
+	if !exists || arg == nil {
+		return nil, nil
+	}
+	value, ok := arg.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s must be an object", key)
+	}
+	return value, nil
+}
+
+// Helper function to parse an optional boolean argument
+func parseOptionalBool(request mcp.CallToolRequest, key string, defaultValue bool) (bool, error) {
+	arg, exists := request.Params.Arguments[key]
+	if !exists || arg == nil {
+		return defaultValue, nil
+	}
+	value, ok := arg.(bool)
+	if !ok {
+		return false, fmt.Errorf("%s must be a boolean", key)
+	}
+	return value, nil
+}
+
+// Helper function to parse a single entity locator object ({labels, identifyingProperties})
+func parseEntityLocator(arg interface{}) (graph.EntityLocator, error) {
+	locatorMap, ok := arg.(map[string]interface{})
+	if !ok {
+		return graph.EntityLocator{}, errors.New("entity locator must be an object")
+	}
+
+	labelsInterface, ok := locatorMap["labels"].([]interface{})
+	if !ok || len(labelsInterface) == 0 {
+		return graph.EntityLocator{}, errors.New("labels must be a non-empty array of strings")
+	}
+	labels := make([]string, len(labelsInterface))
+	for i, l := range labelsInterface {
+		labels[i], ok = l.(string)
+		if !ok {
+			return graph.EntityLocator{}, fmt.Errorf("label item at index %d is not a string", i)
+		}
+	}
+
+	idProps, ok := locatorMap["identifyingProperties"].(map[string]interface{})
+	if !ok || len(idProps) == 0 {
+		return graph.EntityLocator{}, errors.New("identifyingProperties must be a non-empty object")
+	}
+
+	return graph.EntityLocator{Labels: labels, IdentifyingProperties: idProps}, nil
+}
+
+// Helper function to parse a required array of entity locators
+func parseEntityLocators(request mcp.CallToolRequest, key string) ([]graph.EntityLocator, error) {
+	arg, exists := request.Params.Arguments[key]
+	if !exists || arg == nil {
+		return nil, fmt.Errorf("%s are required", key)
+	}
+	locatorsInterface, ok := arg.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s must be an array of objects", key)
+	}
+	locators := make([]graph.EntityLocator, len(locatorsInterface))
+	for i, l := range locatorsInterface {
+		locator, err := parseEntityLocator(l)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s item at index %d: %w", key, i, err)
+		}
+		locators[i] = locator
+	}
+	return locators, nil
+}
+
 
 // handleFindDependenciesTool handles the find_dependencies tool
 func (s *Server) handleFindDependenciesTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
@@ -975,9 +1707,29 @@ func (s *Server) handleFindDependenciesTool(ctx context.Context, request mcp.Cal
 	if err != nil {
 		return nil, err
 	}
+	relPropFilters, err := parseOptionalObject(request, "relationshipPropertyFilters")
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, err = withSample(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	estimateCost, err := parseOptionalBool(request, "estimateCost", false)
+	if err != nil {
+		return nil, err
+	}
+	if estimateCost {
+		return s.estimateTraversalCost(ctx, func(ctx context.Context) error {
+			_, err := s.graph.FindDependencies(ctx, labels, idProps, relTypes, relPropFilters, maxDepth)
+			return err
+		})
+	}
 
 	// Call graph store method
-	depResult, err := s.graph.FindDependencies(ctx, labels, idProps, relTypes, maxDepth)
+	depResult, err := s.graph.FindDependencies(ctx, labels, idProps, relTypes, relPropFilters, maxDepth)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find dependencies: %w", err)
 	}
@@ -1001,6 +1753,22 @@ func (s *Server) handleFindDependentsTool(ctx context.Context, request mcp.CallT
 		return nil, err
 	}
 
+	ctx, err = withSample(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	estimateCost, err := parseOptionalBool(request, "estimateCost", false)
+	if err != nil {
+		return nil, err
+	}
+	if estimateCost {
+		return s.estimateTraversalCost(ctx, func(ctx context.Context) error {
+			_, err := s.graph.FindDependents(ctx, labels, idProps, relTypes, maxDepth)
+			return err
+		})
+	}
+
 	// Call graph store method
 	depResult, err := s.graph.FindDependents(ctx, labels, idProps, relTypes, maxDepth)
 	if err != nil {
@@ -1021,12 +1789,42 @@ func (s *Server) handleGetEntitySubgraphTool(ctx context.Context, request mcp.Ca
 	if err != nil {
 		return nil, err
 	}
+	format := subgraphFormatJSON
+	if formatArg, exists := request.Params.Arguments["format"]; exists && formatArg != nil {
+		format, _ = formatArg.(string)
+		if format != subgraphFormatJSON && format != subgraphFormatMermaid {
+			return nil, fmt.Errorf("format must be %q or %q", subgraphFormatJSON, subgraphFormatMermaid)
+		}
+	}
+	includeLabels, err := parseOptionalStringArray(request, "includeLabels")
+	if err != nil {
+		return nil, err
+	}
+
+	// Return the subgraph in pages if requested
+	_, hasPageSize := request.Params.Arguments["pageSize"]
+	_, hasToken := request.Params.Arguments["continuationToken"]
+	if hasPageSize || hasToken {
+		if format == subgraphFormatMermaid {
+			return nil, errors.New("format 'mermaid' can't be combined with pageSize or continuationToken")
+		}
+		return s.handleGetEntitySubgraphPage(ctx, request, labels, idProps, maxDepth, includeLabels)
+	}
 
 	// Call graph store method
-	subgraphResult, err := s.graph.GetEntitySubgraph(ctx, labels, idProps, maxDepth)
+	subgraphResult, err := s.graph.GetEntitySubgraph(ctx, labels, idProps, maxDepth, includeLabels)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get entity subgraph: %w", err)
 	}
+	if format == subgraphFormatMermaid {
+		// Redact before rendering, as redactToolResult only redacts JSON results; only node names are rendered
+		if s.redactor.Matches("name") {
+			for i := range subgraphResult.Nodes {
+				subgraphResult.Nodes[i].Name = graph.RedactedValue
+			}
+		}
+		return mcp.NewToolResultText(graph.RenderMermaid(subgraphResult)), nil
+	}
 
 	// Return the result
 	resultJSON, err := json.Marshal(subgraphResult)
@@ -1036,6 +1834,110 @@ func (s *Server) handleGetEntitySubgraphTool(ctx context.Context, request mcp.Ca
 	return mcp.NewToolResultText(string(resultJSON)), nil
 }
 
+// Result formats of get_entity_subgraph
+const (
+	subgraphFormatJSON    = "json"
+	subgraphFormatMermaid = "mermaid"
+)
+
+// defaultSubgraphPageSize is the page size used when a continuation token is given without a pageSize
+const defaultSubgraphPageSize = 100
+
+// subgraphPageResponse is the get_entity_subgraph result when paging is requested
+type subgraphPageResponse struct {
+	graph.SubgraphPage
+	ContinuationToken string `json:"continuationToken,omitempty"`
+}
+
+// handleGetEntitySubgraphPage returns a single page of the subgraph for the get_entity_subgraph tool
+func (s *Server) handleGetEntitySubgraphPage(ctx context.Context, request mcp.CallToolRequest, labels []string, idProps map[string]interface{}, maxDepth int, includeLabels []string) (*mcp.CallToolResult, error) {
+	pageSize, err := parseOptionalInt(request, "pageSize", defaultSubgraphPageSize)
+	if err != nil {
+		return nil, err
+	}
+	offset := 0
+	fingerprint := subgraphPageFingerprint(labels, idProps, maxDepth, includeLabels)
+	if tokenArg, exists := request.Params.Arguments["continuationToken"]; exists && tokenArg != nil {
+		token, ok := tokenArg.(string)
+		if !ok {
+			return nil, errors.New("continuationToken must be a string")
+		}
+		if token != "" {
+			offset, err = decodeContinuationToken(token, fingerprint)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// Call graph store method
+	page, err := s.graph.GetEntitySubgraphPage(ctx, labels, idProps, maxDepth, includeLabels, offset, pageSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get entity subgraph page: %w", err)
+	}
+
+	response := subgraphPageResponse{SubgraphPage: page}
+	if page.HasMore {
+		response.ContinuationToken = encodeContinuationToken(page.NextOffset, fingerprint)
+	}
+
+	// Return the result
+	resultJSON, err := json.Marshal(response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal subgraph page: %w", err)
+	}
+	return mcp.NewToolResultText(string(resultJSON)), nil
+}
+
+// subgraphPageFingerprint summarises the arguments deciding which nodes a paged get_entity_subgraph call returns,
+// so that a continuation token can't be used with different ones and return misaligned pages. The order of the
+// labels doesn't matter.
+func subgraphPageFingerprint(labels []string, idProps map[string]interface{}, maxDepth int, includeLabels []string) string {
+	sortedLabels := append([]string(nil), labels...)
+	sort.Strings(sortedLabels)
+	sortedIncludeLabels := append([]string(nil), includeLabels...)
+	sort.Strings(sortedIncludeLabels)
+	data, _ := json.Marshal(map[string]interface{}{ // Map keys are marshalled in order
+		"labels":                sortedLabels,
+		"identifyingProperties": idProps,
+		"maxDepth":              maxDepth,
+		"includeLabels":         sortedIncludeLabels,
+	})
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
+
+// encodeContinuationToken builds an opaque token for resuming a paged result at the given offset, for the
+// arguments with the given fingerprint
+func encodeContinuationToken(offset int, fingerprint string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte("offset:" + strconv.Itoa(offset) + ";args:" + fingerprint))
+}
+
+// decodeContinuationToken extracts the offset from a token produced by encodeContinuationToken, checking that it was
+// issued for arguments with the given fingerprint
+func decodeContinuationToken(token, fingerprint string) (int, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, errors.New("invalid continuationToken")
+	}
+	rest, ok := strings.CutPrefix(string(decoded), "offset:")
+	if !ok {
+		return 0, errors.New("invalid continuationToken")
+	}
+	offsetStr, tokenFingerprint, ok := strings.Cut(rest, ";args:")
+	if !ok {
+		return 0, errors.New("invalid continuationToken")
+	}
+	offset, err := strconv.Atoi(offsetStr)
+	if err != nil || offset < 0 {
+		return 0, errors.New("invalid continuationToken")
+	}
+	if tokenFingerprint != fingerprint {
+		return 0, errors.New("continuationToken was issued for different labels, identifyingProperties, maxDepth or includeLabels; pass the same ones as the original call")
+	}
+	return offset, nil
+}
+
 // handleBatchFindOrCreateEntitiesToolTool handles the batch_find_or_create_entities tool
 func (s *Server) handleBatchFindOrCreateEntitiesToolTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	// Parse entities array
@@ -1053,82 +1955,27 @@ func (s *Server) handleBatchFindOrCreateEntitiesToolTool(ctx context.Context, re
 		return nil, errors.New("at least one entity is required")
 	}
 
-	// Convert to EntityInput array
+	// Convert to EntityInput array, collecting every invalid field
+	var validator batchValidator
 	inputs := make([]graph.EntityInput, len(entitiesInterface))
 	for i, entityInterface := range entitiesInterface {
-		entityMap, ok := entityInterface.(map[string]interface{})
-		if !ok {
-			return nil, fmt.Errorf("entity at index %d is not an object", i)
-		}
-
-		// Parse labels
-		labelsArg, ok := entityMap["labels"]
-		if !ok {
-			return nil, fmt.Errorf("labels are required for entity at index %d", i)
-		}
-		labelsInterface, ok := labelsArg.([]interface{})
-		if !ok {
-			return nil, fmt.Errorf("labels must be an array of strings for entity at index %d", i)
-		}
-
-		inputs[i].Labels = make([]string, len(labelsInterface))
-		for j, l := range labelsInterface {
-			inputs[i].Labels[j], ok = l.(string)
-			if !ok {
-				return nil, fmt.Errorf("label item at index %d for entity at index %d is not a string", j, i)
-			}
-		}
-		if len(inputs[i].Labels) == 0 {
-			return nil, fmt.Errorf("at least one label is required for entity at index %d", i)
-		}
-
-		// Parse identifyingProperties
-		idPropsArg, ok := entityMap["identifyingProperties"]
-		if !ok {
-			return nil, fmt.Errorf("identifyingProperties are required for entity at index %d", i)
-		}
-		inputs[i].IdentifyingProperties, ok = idPropsArg.(map[string]interface{})
-		if !ok {
-			return nil, fmt.Errorf("identifyingProperties must be an object for entity at index %d", i)
-		}
-		if len(inputs[i].IdentifyingProperties) == 0 {
-			return nil, fmt.Errorf("at least one identifying property is required for entity at index %d", i)
-		}
-
-		// Parse properties
-		propsArg, ok := entityMap["properties"]
-		if !ok {
-			return nil, fmt.Errorf("properties are required for entity at index %d", i)
-		}
-		inputs[i].Properties, ok = propsArg.(map[string]interface{})
-		if !ok {
-			return nil, fmt.Errorf("properties must be an object for entity at index %d", i)
-		}
+		inputs[i] = validator.entityInput(i, entityInterface)
+	}
+	if result, err := validator.result(len(inputs)); result != nil || err != nil {
+		return result, err
 	}
 
 	// Call graph store method
 	results, individualErrors, err := s.graph.BatchFindOrCreateEntities(ctx, inputs)
 
-	// Prepare response
+	// Prepare response, with a result for every input even if the store failed outright
 	response := struct {
-		Results         []graph.EntityDetails `json:"results"`
-		IndividualErrors []string             `json:"individualErrors,omitempty"`
-		Error           string                `json:"error,omitempty"`
+		Results          []graph.EntityDetails `json:"results"`
+		IndividualErrors []string              `json:"individualErrors,omitempty"`
+		Error            string                `json:"error,omitempty"`
 	}{
-		Results: results,
-	}
-
-	// Handle individual errors
-	if individualErrors != nil {
-		errorMessages := make([]string, 0)
-		for i, err := range individualErrors {
-			if err != nil {
-				errorMessages = append(errorMessages, fmt.Sprintf("Error at index %d: %s", i, err.Error()))
-			}
-		}
-		if len(errorMessages) > 0 {
-			response.IndividualErrors = errorMessages
-		}
+		Results:          padBatchResults(results, len(inputs)),
+		IndividualErrors: individualErrorMessages(individualErrors),
 	}
 
 	// Handle overall error
@@ -1145,6 +1992,28 @@ func (s *Server) handleBatchFindOrCreateEntitiesToolTool(ctx context.Context, re
 	return mcp.NewToolResultText(string(responseJSON)), nil
 }
 
+// padBatchResults extends batch results with zero values to n entries, so that results[i] always corresponds to
+// input i, even when a store returns fewer results (or none) alongside an overall error
+func padBatchResults[T any](results []T, n int) []T {
+	if len(results) >= n {
+		return results
+	}
+	padded := make([]T, n)
+	copy(padded, results)
+	return padded
+}
+
+// individualErrorMessages describes each failed input of a batch operation, naming its index
+func individualErrorMessages(individualErrors []error) []string {
+	var messages []string
+	for i, err := range individualErrors {
+		if err != nil {
+			messages = append(messages, fmt.Sprintf("Error at index %d: %s", i, err.Error()))
+		}
+	}
+	return messages
+}
+
 // handleBatchFindOrCreateRelationshipsToolTool handles the batch_find_or_create_relationships tool
 func (s *Server) handleBatchFindOrCreateRelationshipsToolTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	// Parse relationships array
@@ -1162,126 +2031,27 @@ func (s *Server) handleBatchFindOrCreateRelationshipsToolTool(ctx context.Contex
 		return nil, errors.New("at least one relationship is required")
 	}
 
-	// Convert to RelationshipInput array
+	// Convert to RelationshipInput array, collecting every invalid field
+	var validator batchValidator
 	inputs := make([]graph.RelationshipInput, len(relationshipsInterface))
 	for i, relInterface := range relationshipsInterface {
-		relMap, ok := relInterface.(map[string]interface{})
-		if !ok {
-			return nil, fmt.Errorf("relationship at index %d is not an object", i)
-		}
-
-		// Parse startNodeLabels
-		startLabelsArg, ok := relMap["startNodeLabels"]
-		if !ok {
-			return nil, fmt.Errorf("startNodeLabels are required for relationship at index %d", i)
-		}
-		startLabelsInterface, ok := startLabelsArg.([]interface{})
-		if !ok {
-			return nil, fmt.Errorf("startNodeLabels must be an array of strings for relationship at index %d", i)
-		}
-
-		inputs[i].StartNodeLabels = make([]string, len(startLabelsInterface))
-		for j, l := range startLabelsInterface {
-			inputs[i].StartNodeLabels[j], ok = l.(string)
-			if !ok {
-				return nil, fmt.Errorf("startNodeLabel item at index %d for relationship at index %d is not a string", j, i)
-			}
-		}
-		if len(inputs[i].StartNodeLabels) == 0 {
-			return nil, fmt.Errorf("at least one startNodeLabel is required for relationship at index %d", i)
-		}
-
-		// Parse startNodeIdentifyingProperties
-		startIdPropsArg, ok := relMap["startNodeIdentifyingProperties"]
-		if !ok {
-			return nil, fmt.Errorf("startNodeIdentifyingProperties are required for relationship at index %d", i)
-		}
-		inputs[i].StartNodeIdentifyingProperties, ok = startIdPropsArg.(map[string]interface{})
-		if !ok {
-			return nil, fmt.Errorf("startNodeIdentifyingProperties must be an object for relationship at index %d", i)
-		}
-		if len(inputs[i].StartNodeIdentifyingProperties) == 0 {
-			return nil, fmt.Errorf("at least one startNodeIdentifyingProperty is required for relationship at index %d", i)
-		}
-
-		// Parse endNodeLabels
-		endLabelsArg, ok := relMap["endNodeLabels"]
-		if !ok {
-			return nil, fmt.Errorf("endNodeLabels are required for relationship at index %d", i)
-		}
-		endLabelsInterface, ok := endLabelsArg.([]interface{})
-		if !ok {
-			return nil, fmt.Errorf("endNodeLabels must be an array of strings for relationship at index %d", i)
-		}
-
-		inputs[i].EndNodeLabels = make([]string, len(endLabelsInterface))
-		for j, l := range endLabelsInterface {
-			inputs[i].EndNodeLabels[j], ok = l.(string)
-			if !ok {
-				return nil, fmt.Errorf("endNodeLabel item at index %d for relationship at index %d is not a string", j, i)
-			}
-		}
-		if len(inputs[i].EndNodeLabels) == 0 {
-			return nil, fmt.Errorf("at least one endNodeLabel is required for relationship at index %d", i)
-		}
-
-		// Parse endNodeIdentifyingProperties
-		endIdPropsArg, ok := relMap["endNodeIdentifyingProperties"]
-		if !ok {
-			return nil, fmt.Errorf("endNodeIdentifyingProperties are required for relationship at index %d", i)
-		}
-		inputs[i].EndNodeIdentifyingProperties, ok = endIdPropsArg.(map[string]interface{})
-		if !ok {
-			return nil, fmt.Errorf("endNodeIdentifyingProperties must be an object for relationship at index %d", i)
-		}
-		if len(inputs[i].EndNodeIdentifyingProperties) == 0 {
-			return nil, fmt.Errorf("at least one endNodeIdentifyingProperty is required for relationship at index %d", i)
-		}
-
-		// Parse relationshipType
-		relTypeArg, ok := relMap["relationshipType"]
-		if !ok {
-			return nil, fmt.Errorf("relationshipType is required for relationship at index %d", i)
-		}
-		inputs[i].RelationshipType, ok = relTypeArg.(string)
-		if !ok || inputs[i].RelationshipType == "" {
-			return nil, fmt.Errorf("relationshipType must be a non-empty string for relationship at index %d", i)
-		}
-
-		// Parse properties (optional)
-		if propsArg, exists := relMap["properties"]; exists && propsArg != nil {
-			inputs[i].Properties, ok = propsArg.(map[string]interface{})
-			if !ok {
-				return nil, fmt.Errorf("properties must be an object for relationship at index %d", i)
-			}
-		} else {
-			inputs[i].Properties = make(map[string]interface{}) // Ensure it's not nil
-		}
+		inputs[i] = validator.relationshipInput(i, relInterface)
+	}
+	if result, err := validator.result(len(inputs)); result != nil || err != nil {
+		return result, err
 	}
 
 	// Call graph store method
 	results, individualErrors, err := s.graph.BatchFindOrCreateRelationships(ctx, inputs)
 
-	// Prepare response
+	// Prepare response, with a result for every input even if the store failed outright
 	response := struct {
-		Results         []map[string]interface{} `json:"results"`
-		IndividualErrors []string                `json:"individualErrors,omitempty"`
-		Error           string                   `json:"error,omitempty"`
+		Results          []map[string]interface{} `json:"results"`
+		IndividualErrors []string                 `json:"individualErrors,omitempty"`
+		Error            string                   `json:"error,omitempty"`
 	}{
-		Results: results,
-	}
-
-	// Handle individual errors
-	if individualErrors != nil {
-		errorMessages := make([]string, 0)
-		for i, err := range individualErrors {
-			if err != nil {
-				errorMessages = append(errorMessages, fmt.Sprintf("Error at index %d: %s", i, err.Error()))
-			}
-		}
-		if len(errorMessages) > 0 {
-			response.IndividualErrors = errorMessages
-		}
+		Results:          padBatchResults(results, len(inputs)),
+		IndividualErrors: individualErrorMessages(individualErrors),
 	}
 
 	// Handle overall error
@@ -1313,3 +2083,12 @@ func (s *Server) ServeSSE(addr string) error {
 	}
 	return httpServer.ListenAndServe()
 }
+
+// ServeSSEListener serves the MCP server over SSE on an existing listener, such as one from ListenSSE
+func (s *Server) ServeSSEListener(ln net.Listener) error {
+	sseServer := server.NewSSEServer(s.server)
+	httpServer := &http.Server{
+		Handler: sseServer,
+	}
+	return httpServer.Serve(ln)
+}
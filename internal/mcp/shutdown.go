@@ -0,0 +1,74 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// errShuttingDown is returned for tool calls received after Drain has been called
+var errShuttingDown = errors.New("server is shutting down")
+
+// toolCallTracker counts the tool calls in progress so that shutdown can wait for them to finish.
+// The zero value is ready to use.
+type toolCallTracker struct {
+	mu       sync.Mutex
+	active   int
+	draining bool
+	idle     chan struct{} // Closed when draining and the last active call finishes
+}
+
+// start records the start of a tool call. It returns false, and records nothing, once draining has begun.
+func (t *toolCallTracker) start() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.draining {
+		return false
+	}
+	t.active++
+	return true
+}
+
+// finish records the end of a tool call started with start
+func (t *toolCallTracker) finish() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.active--
+	if t.active == 0 && t.idle != nil {
+		close(t.idle)
+		t.idle = nil
+	}
+}
+
+// drain stops new tool calls from starting and waits until the active ones finish or ctx is done
+func (t *toolCallTracker) drain(ctx context.Context) error {
+	t.mu.Lock()
+	t.draining = true
+	if t.active == 0 {
+		t.mu.Unlock()
+		return nil
+	}
+	if t.idle == nil {
+		t.idle = make(chan struct{})
+	}
+	idle := t.idle
+	t.mu.Unlock()
+
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		t.mu.Lock()
+		active := t.active
+		t.mu.Unlock()
+		return fmt.Errorf("%d tool calls still running: %w", active, ctx.Err())
+	}
+}
+
+// Drain stops the server accepting new tool calls, which fail with an error from then on, and waits for the
+// tool calls in progress to finish, so that the graph store isn't closed part way through a multi-step operation.
+// It returns an error if ctx is done first.
+func (s *Server) Drain(ctx context.Context) error {
+	return s.toolCalls.drain(ctx)
+}
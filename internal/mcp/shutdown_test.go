@@ -0,0 +1,58 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestToolCallTracker_DrainWaitsForActiveCalls tests that draining waits for active calls and rejects new ones
+func TestToolCallTracker_DrainWaitsForActiveCalls(t *testing.T) {
+	var tracker toolCallTracker
+	assert.True(t, tracker.start())
+
+	drained := make(chan error, 1)
+	go func() {
+		drained <- tracker.drain(context.Background())
+	}()
+
+	// New calls are rejected once draining has begun
+	assert.Eventually(t, func() bool {
+		tracker.mu.Lock()
+		defer tracker.mu.Unlock()
+		return tracker.draining
+	}, time.Second, time.Millisecond)
+	assert.False(t, tracker.start())
+
+	select {
+	case <-drained:
+		t.Fatal("drain returned while a call was still active")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	tracker.finish()
+	select {
+	case err := <-drained:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("drain did not return after the last call finished")
+	}
+}
+
+// TestToolCallTracker_DrainTimeout tests that draining gives up when the context is done
+func TestToolCallTracker_DrainTimeout(t *testing.T) {
+	var tracker toolCallTracker
+	assert.True(t, tracker.start())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := tracker.drain(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Contains(t, err.Error(), "1 tool calls still running")
+
+	// Draining with nothing active returns immediately
+	tracker.finish()
+	assert.NoError(t, tracker.drain(context.Background()))
+}
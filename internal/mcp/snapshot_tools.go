@@ -0,0 +1,130 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/sammcj/mcp-graph/internal/snapshot"
+)
+
+// SetSnapshotStore sets where snapshot_subgraph saves snapshots and restore_snapshot reads them from.
+// The snapshot tools fail if no store is set.
+func (s *Server) SetSnapshotStore(store *snapshot.Store) {
+	s.snapshots = store
+}
+
+// snapshotSummary represents the output for snapshot_subgraph
+type snapshotSummary struct {
+	Name          string    `json:"name"`
+	CreatedAt     time.Time `json:"createdAt"`
+	Labels        []string  `json:"labels"`
+	Nodes         int       `json:"nodes"`
+	Relationships int       `json:"relationships"`
+}
+
+// setupSnapshotTools configures the tools for checkpointing and restoring parts of the graph
+func (s *Server) setupSnapshotTools() {
+	snapshotSubgraphTool := mcp.NewTool("snapshot_subgraph",
+		mcp.WithDescription("Saves a named, server-side snapshot of every node with any of the given labels (with all of their properties) and every relationship touching them. Use this to checkpoint part of the graph before a risky bulk change, then call restore_snapshot to roll back if needed. At most 10000 nodes can be snapshotted."),
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.Description("Name for the snapshot (letters, digits, '.', '_' and '-'), e.g. 'before-service-cleanup'."),
+		),
+		mcp.WithArray("labels",
+			mcp.Required(),
+			mcp.Description("Labels of the nodes to snapshot (e.g. ['Service', 'Library']). Nodes with any of these labels are included."),
+			mcp.Items(map[string]interface{}{"type": "string"}),
+		),
+		mcp.WithBoolean("overwrite",
+			mcp.Description("If true, replaces an existing snapshot with the same name. Defaults to false."),
+		),
+	)
+	s.addTool(snapshotSubgraphTool, s.handleSnapshotSubgraphTool)
+
+	restoreSnapshotTool := mcp.NewTool("restore_snapshot",
+		mcp.WithDescription("Restores a snapshot saved by snapshot_subgraph. Every node that currently has any of the snapshot's labels is deleted (including nodes created since the snapshot), then the snapshot's nodes and relationships are recreated, all in one transaction. Relationships to nodes outside the snapshot are reconnected if those nodes still exist. Restored nodes get new IDs, and date and time properties are restored as strings."),
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.Description("Name of the snapshot to restore."),
+		),
+	)
+	s.addTool(restoreSnapshotTool, s.handleRestoreSnapshotTool)
+}
+
+// handleSnapshotSubgraphTool handles the snapshot_subgraph tool
+func (s *Server) handleSnapshotSubgraphTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if s.snapshots == nil {
+		return nil, errors.New("snapshots are not configured on this server")
+	}
+	name, ok := request.Params.Arguments["name"].(string)
+	if !ok || name == "" {
+		return nil, errors.New("name must be a non-empty string")
+	}
+	labels, err := parseOptionalStringArray(request, "labels")
+	if err != nil {
+		return nil, err
+	}
+	if len(labels) == 0 {
+		return nil, errors.New("labels must be a non-empty array")
+	}
+	overwrite, err := parseOptionalBool(request, "overwrite", false)
+	if err != nil {
+		return nil, err
+	}
+
+	// Export the subgraph and save it
+	graphSnapshot, err := s.graph.ExportSubgraph(ctx, labels)
+	if err != nil {
+		return nil, fmt.Errorf("failed to snapshot subgraph: %w", err)
+	}
+	graphSnapshot.Name = name
+	if err := s.snapshots.Save(graphSnapshot, overwrite); err != nil {
+		return nil, fmt.Errorf("failed to save snapshot: %w", err)
+	}
+
+	// Return a summary rather than the snapshot itself, which may be large
+	resultJSON, err := json.Marshal(snapshotSummary{
+		Name:          name,
+		CreatedAt:     graphSnapshot.CreatedAt,
+		Labels:        graphSnapshot.Labels,
+		Nodes:         len(graphSnapshot.Nodes),
+		Relationships: len(graphSnapshot.Relationships),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal snapshot summary: %w", err)
+	}
+	return mcp.NewToolResultText(string(resultJSON)), nil
+}
+
+// handleRestoreSnapshotTool handles the restore_snapshot tool
+func (s *Server) handleRestoreSnapshotTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if s.snapshots == nil {
+		return nil, errors.New("snapshots are not configured on this server")
+	}
+	name, ok := request.Params.Arguments["name"].(string)
+	if !ok || name == "" {
+		return nil, errors.New("name must be a non-empty string")
+	}
+
+	// Load the snapshot and restore it
+	graphSnapshot, err := s.snapshots.Load(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load snapshot: %w", err)
+	}
+	restoreResult, err := s.graph.RestoreSubgraph(ctx, graphSnapshot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore snapshot: %w", err)
+	}
+
+	// Return the result
+	resultJSON, err := json.Marshal(restoreResult)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal restore result: %w", err)
+	}
+	return mcp.NewToolResultText(string(resultJSON)), nil
+}
@@ -0,0 +1,82 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sammcj/mcp-graph/internal/graph"
+	"github.com/sammcj/mcp-graph/internal/mcp/mocks"
+	"github.com/sammcj/mcp-graph/internal/snapshot"
+)
+
+// TestSnapshotAndRestoreTools tests that a snapshot saved by snapshot_subgraph is restored by restore_snapshot
+func TestSnapshotAndRestoreTools(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockGraph := mocks.NewMockStore(ctrl)
+	server := &Server{graph: mockGraph}
+	server.SetSnapshotStore(snapshot.NewStore(t.TempDir()))
+
+	exported := graph.GraphSnapshot{
+		Labels: []string{"Service"},
+		Nodes: []graph.SnapshotNode{
+			{ID: "4:abc:1", Labels: []string{"Service"}, Properties: map[string]interface{}{"name": "api"}},
+			{ID: "4:abc:2", Labels: []string{"Service"}, Properties: map[string]interface{}{"name": "billing"}},
+		},
+		Relationships: []graph.SnapshotRelationship{
+			{Type: "CALLS", StartNode: "4:abc:1", EndNode: "4:abc:2", Properties: map[string]interface{}{}},
+		},
+	}
+	mockGraph.EXPECT().ExportSubgraph(gomock.Any(), []string{"Service"}).Return(exported, nil)
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{
+		"name":   "before-cleanup",
+		"labels": []interface{}{"Service"},
+	}
+	result, err := server.handleSnapshotSubgraphTool(context.Background(), request)
+	assert.NoError(t, err)
+
+	var summary snapshotSummary
+	assert.NoError(t, json.Unmarshal([]byte(getResultText(result)), &summary))
+	assert.Equal(t, "before-cleanup", summary.Name)
+	assert.Equal(t, 2, summary.Nodes)
+	assert.Equal(t, 1, summary.Relationships)
+
+	// Restoring passes the saved snapshot to the store
+	exported.Name = "before-cleanup"
+	mockGraph.EXPECT().RestoreSubgraph(gomock.Any(), exported).Return(graph.RestoreResult{NodesDeleted: 3, NodesCreated: 2, RelationshipsCreated: 1}, nil)
+
+	request.Params.Arguments = map[string]interface{}{"name": "before-cleanup"}
+	result, err = server.handleRestoreSnapshotTool(context.Background(), request)
+	assert.NoError(t, err)
+
+	var restoreResult graph.RestoreResult
+	assert.NoError(t, json.Unmarshal([]byte(getResultText(result)), &restoreResult))
+	assert.Equal(t, int64(3), restoreResult.NodesDeleted)
+	assert.Equal(t, int64(2), restoreResult.NodesCreated)
+
+	// Unknown snapshots are reported without touching the graph
+	request.Params.Arguments = map[string]interface{}{"name": "missing"}
+	_, err = server.handleRestoreSnapshotTool(context.Background(), request)
+	assert.ErrorIs(t, err, snapshot.ErrNotFound)
+}
+
+// TestSnapshotTools_NotConfigured tests that the snapshot tools fail without a snapshot store
+func TestSnapshotTools_NotConfigured(t *testing.T) {
+	server := &Server{}
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{"name": "x", "labels": []interface{}{"Service"}}
+
+	_, err := server.handleSnapshotSubgraphTool(context.Background(), request)
+	assert.Error(t, err)
+	_, err = server.handleRestoreSnapshotTool(context.Background(), request)
+	assert.Error(t, err)
+}
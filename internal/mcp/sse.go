@@ -0,0 +1,46 @@
+package mcp
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// ErrAddressInUse is returned by ListenSSE when the address, and any fallback ports, are already in use
+var ErrAddressInUse = errors.New("address already in use")
+
+// ListenSSE listens on addr for the SSE server. If the address is already in use, each of the next fallbackPorts
+// ports on the same host is tried in turn. Fallback ports are only tried for addresses with an explicit, non-zero
+// port. It returns the listener and the address it listens on, which is addr unless a fallback port was used. If
+// every address is in use, the error wraps ErrAddressInUse and names the addresses tried.
+func ListenSSE(addr string, fallbackPorts int) (net.Listener, string, error) {
+	ln, err := net.Listen("tcp", addr)
+	if err == nil {
+		return ln, addr, nil
+	}
+	if !errors.Is(err, syscall.EADDRINUSE) {
+		return nil, "", err
+	}
+
+	tried := []string{addr}
+	host, portStr, splitErr := net.SplitHostPort(addr)
+	port, atoiErr := strconv.Atoi(portStr)
+	if splitErr == nil && atoiErr == nil && port > 0 {
+		for i := 1; i <= fallbackPorts && port+i <= 65535; i++ {
+			fallbackAddr := net.JoinHostPort(host, strconv.Itoa(port+i))
+			ln, err := net.Listen("tcp", fallbackAddr)
+			if err == nil {
+				return ln, fallbackAddr, nil
+			}
+			if !errors.Is(err, syscall.EADDRINUSE) {
+				return nil, "", err
+			}
+			tried = append(tried, fallbackAddr)
+		}
+	}
+
+	return nil, "", fmt.Errorf("%w: %s", ErrAddressInUse, strings.Join(tried, ", "))
+}
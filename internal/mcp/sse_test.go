@@ -0,0 +1,39 @@
+package mcp
+
+import (
+	"net"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestListenSSE_FallsBackToNextPort tests that ListenSSE tries the following ports when the address is in use
+func TestListenSSE_FallsBackToNextPort(t *testing.T) {
+	// Occupy a port
+	occupied, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer occupied.Close()
+	addr := occupied.Addr().String()
+	port := occupied.Addr().(*net.TCPAddr).Port
+
+	// Without fallbacks the conflict is reported
+	_, _, err = ListenSSE(addr, 0)
+	assert.ErrorIs(t, err, ErrAddressInUse)
+	assert.ErrorContains(t, err, addr)
+
+	// With fallbacks a following port is used
+	ln, listenAddr, err := ListenSSE(addr, 5)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer ln.Close()
+	assert.NotEqual(t, addr, listenAddr)
+	assert.Equal(t, listenAddr, ln.Addr().String())
+	_, portStr, _ := net.SplitHostPort(listenAddr)
+	fallbackPort, _ := strconv.Atoi(portStr)
+	assert.Greater(t, fallbackPort, port)
+	assert.LessOrEqual(t, fallbackPort, port+5)
+}
@@ -0,0 +1,687 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/sammcj/mcp-graph/internal/graph"
+)
+
+// estimateCostDescription describes the estimateCost option shared by the traversal tools
+const estimateCostDescription = "If true, the traversal is not run. Instead the query planner's estimate of its cost is returned (the rows each query is expected to return, and the largest row estimate of any step as a proxy for database hits), so that maxDepth can be reduced before running an expensive traversal. Only supported by the Neo4j backend. Defaults to false."
+
+// sampleDescription describes the sample option shared by the traversal and search tools
+const sampleDescription = "Optional number of results to return as a pseudo-random sample, instead of the first results in the usual order (e.g. to explore a traversal that would return thousands of entities). Must be positive, and is capped at the usual result limit. Sampling is non-deterministic: repeating the call may return different results, and they aren't in any particular order. Only supported by the Neo4j backend."
+
+// entityLocatorSchema describes an object identifying a single entity, for use in array items
+var entityLocatorSchema = map[string]interface{}{
+	"type": "object",
+	"properties": map[string]interface{}{
+		"labels": map[string]interface{}{
+			"type":        "array",
+			"description": "List of labels for the entity.",
+			"items":       map[string]interface{}{"type": "string"},
+		},
+		"identifyingProperties": map[string]interface{}{
+			"type":        "object",
+			"description": "Map of properties to uniquely identify the entity.",
+		},
+	},
+	"required": []string{"labels", "identifyingProperties"},
+}
+
+// setupTraversalTools configures the multi-entity graph traversal tools
+func (s *Server) setupTraversalTools() {
+	commonDependenciesTool := mcp.NewTool("common_dependencies",
+		mcp.WithDescription("Finds entities that all of the given entities depend on (e.g. 'what do these three services all depend on?') by intersecting their dependency sets, following outgoing relationships. Useful when planning refactoring."),
+		mcp.WithArray("entities",
+			mcp.Required(),
+			mcp.Description("List of at least two entities whose shared dependencies are being sought, each identified by labels and identifyingProperties."),
+			mcp.Items(entityLocatorSchema),
+		),
+		mcp.WithArray("relationshipTypes",
+			mcp.Description("Optional list of specific relationship types to follow when searching for dependencies (e.g., ['DEPENDS_ON', 'CALLS']). If omitted or empty, all outgoing relationship types will be followed."),
+			mcp.Items(map[string]interface{}{"type": "string"}),
+		),
+		mcp.WithNumber("maxDepth",
+			mcp.Description("Maximum relationship path depth to search for dependencies (e.g., 1 for direct dependencies). Defaults to 1 if not provided or invalid."),
+		),
+		mcp.WithBoolean("estimateCost",
+			mcp.Description(estimateCostDescription),
+		),
+	)
+	s.addTool(commonDependenciesTool, s.handleCommonDependenciesTool)
+
+	nearestOfLabelTool := mcp.NewTool("nearest_of_label",
+		mcp.WithDescription("Finds the closest entity with a given label to the start entity, and the path to it (e.g. 'what File is this Function defined in, possibly indirectly?'). Relationships are followed in either direction. Returns found=false if no such entity is within maxDepth. With APOC installed this is a breadth-first search that stops at the first match; without it every path up to maxDepth is enumerated, so deep searches on dense graphs are expensive."),
+		mcp.WithArray("labels",
+			mcp.Required(),
+			mcp.Description("List of labels for the start entity."),
+			mcp.Items(map[string]interface{}{"type": "string"}),
+		),
+		mcp.WithObject("identifyingProperties",
+			mcp.Required(),
+			mcp.Description("Map of properties to uniquely identify the start entity."),
+		),
+		mcp.WithString("targetLabel",
+			mcp.Required(),
+			mcp.Description("Label of the entity to search for (e.g. 'File')."),
+		),
+		mcp.WithArray("relationshipTypes",
+			mcp.Description("Optional list of specific relationship types to follow (e.g., ['DEFINED_IN', 'CONTAINS']). If omitted or empty, all relationship types will be followed."),
+			mcp.Items(map[string]interface{}{"type": "string"}),
+		),
+		mcp.WithNumber("maxDepth",
+			mcp.Description(fmt.Sprintf("Maximum path length to search, at most %d. Defaults to 5 if not provided or invalid.", graph.MaxNearestByLabelDepth)),
+		),
+		mcp.WithBoolean("estimateCost",
+			mcp.Description(estimateCostDescription),
+		),
+	)
+	s.addTool(nearestOfLabelTool, s.handleNearestOfLabelTool)
+
+	findShortestPathTool := mcp.NewTool("find_shortest_path",
+		mcp.WithDescription("Finds the shortest path between two entities, following relationships in either direction. By default this is the path with the fewest relationships. If weightProperty is given and the APOC library is installed, it is instead the path with the lowest total of that numeric relationship property (e.g. 'latencyMs' or 'cost'), found with Dijkstra's algorithm; relationships without the property count as 1. The algorithm used is reported in the result. Returns found=false if there is no path."),
+		mcp.WithObject("from",
+			mcp.Required(),
+			mcp.Description("The start entity, as {labels, identifyingProperties}."),
+		),
+		mcp.WithObject("to",
+			mcp.Required(),
+			mcp.Description("The end entity, as {labels, identifyingProperties}."),
+		),
+		mcp.WithArray("relationshipTypes",
+			mcp.Description("Optional list of specific relationship types to follow (e.g., ['CALLS', 'DEPENDS_ON']). If omitted or empty, all relationship types will be followed."),
+			mcp.Items(map[string]interface{}{"type": "string"}),
+		),
+		mcp.WithString("weightProperty",
+			mcp.Description("Optional numeric relationship property to minimise the total of, instead of the number of relationships. Requires APOC; without it the unweighted shortest path is returned."),
+		),
+		mcp.WithNumber("maxDepth",
+			mcp.Description("Maximum path length for unweighted searches. Defaults to 5 if not provided or invalid. Weighted searches are not limited."),
+		),
+		mcp.WithBoolean("estimateCost",
+			mcp.Description(estimateCostDescription),
+		),
+	)
+	s.addTool(findShortestPathTool, s.handleFindShortestPathTool)
+
+	dependencyPathTool := mcp.NewTool("dependency_path",
+		mcp.WithDescription("Finds the shortest dependency route from one entity to another, answering 'how does A end up depending on B?'. Only outgoing relationships are followed, so each entity in the returned chain depends on the next; the relationships between them, with their types, are returned in order. Returns found=false if A doesn't depend on B within maxDepth."),
+		mcp.WithObject("from",
+			mcp.Required(),
+			mcp.Description("The dependent entity (A), as {labels, identifyingProperties}."),
+		),
+		mcp.WithObject("to",
+			mcp.Required(),
+			mcp.Description("The dependency (B), as {labels, identifyingProperties}."),
+		),
+		mcp.WithArray("relationshipTypes",
+			mcp.Description("Optional list of specific relationship types to follow (e.g., ['DEPENDS_ON', 'IMPORTS']). If omitted or empty, all outgoing relationship types will be followed."),
+			mcp.Items(map[string]interface{}{"type": "string"}),
+		),
+		mcp.WithNumber("maxDepth",
+			mcp.Description("Maximum path length to search. Defaults to 5 if not provided or invalid."),
+		),
+		mcp.WithBoolean("estimateCost",
+			mcp.Description(estimateCostDescription),
+		),
+	)
+	s.addTool(dependencyPathTool, s.handleDependencyPathTool)
+
+	getAncestorsTool := mcp.NewTool("get_ancestors",
+		hierarchyToolOptions(
+			"Finds the entities above an entity in a containment hierarchy formed by a single relationship type, e.g. the Module, then Application, containing a File. Returns at most 1000 entities, each with its depth (1 for the parent), ordered by depth.",
+		)...,
+	)
+	s.addTool(getAncestorsTool, s.handleGetAncestorsTool)
+
+	getDescendantsTool := mcp.NewTool("get_descendants",
+		hierarchyToolOptions(
+			"Finds the entities below an entity in a containment hierarchy formed by a single relationship type, e.g. the Files in a Module and the Functions in those Files. Returns at most 1000 entities, each with its depth (1 for children), ordered by depth.",
+		)...,
+	)
+	s.addTool(getDescendantsTool, s.handleGetDescendantsTool)
+
+	findReachableTool := mcp.NewTool("find_reachable",
+		mcp.WithDescription("Finds every distinct entity within a number of hops of an entity, following relationships of any type in either direction, each with its distance (the fewest relationships between them). Answers 'what is in the vicinity of this entity, and how far away?'. Results are ordered by distance, nearest first."),
+		mcp.WithArray("labels",
+			mcp.Required(),
+			mcp.Description("List of labels for the central entity."),
+			mcp.Items(map[string]interface{}{"type": "string"}),
+		),
+		mcp.WithObject("identifyingProperties",
+			mcp.Required(),
+			mcp.Description("Map of properties that uniquely identify the central entity."),
+		),
+		mcp.WithNumber("maxDepth",
+			mcp.Description("Maximum number of hops. Defaults to 2 if not provided or invalid."),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("Maximum number of entities to return, nearest first. Defaults to 100."),
+		),
+		mcp.WithNumber("sample",
+			mcp.Description(sampleDescription),
+		),
+		mcp.WithBoolean("estimateCost",
+			mcp.Description(estimateCostDescription),
+		),
+	)
+	s.addTool(findReachableTool, s.handleFindReachableTool)
+
+	getEntityWithRelationshipsTool := mcp.NewTool("get_entity_with_relationships",
+		mcp.WithDescription("Retrieves an entity together with all of its relationships (type, direction and properties) and the node at the other end of each, in a single call. Use this instead of get_entity_details followed by find_neighbors when inspecting an entity. Returns at most 1000 relationships."),
+		mcp.WithArray("labels",
+			mcp.Required(),
+			mcp.Description("List of labels for the entity."),
+			mcp.Items(map[string]interface{}{"type": "string"}),
+		),
+		mcp.WithObject("identifyingProperties",
+			mcp.Required(),
+			mcp.Description("Map of properties to uniquely identify the entity."),
+		),
+	)
+	s.addTool(getEntityWithRelationshipsTool, s.handleGetEntityWithRelationshipsTool)
+
+	listRelationshipsTool := mcp.NewTool("list_relationships",
+		mcp.WithDescription("Lists an entity's relationships (type, direction and properties) and the node at the other end of each, optionally filtered by relationship type and by the labels of the other node (e.g. 'only this Service's relationships to DataStore nodes'). Returns at most 1000 relationships."),
+		mcp.WithArray("labels",
+			mcp.Required(),
+			mcp.Description("List of labels for the entity."),
+			mcp.Items(map[string]interface{}{"type": "string"}),
+		),
+		mcp.WithObject("identifyingProperties",
+			mcp.Required(),
+			mcp.Description("Map of properties to uniquely identify the entity."),
+		),
+		mcp.WithString("direction",
+			mcp.Description("Which relationships to list: 'outgoing', 'incoming' or 'both'. Defaults to 'both'."),
+			mcp.Enum(graph.DirectionOutgoing, graph.DirectionIncoming, graph.DirectionBoth),
+		),
+		mcp.WithArray("relationshipTypes",
+			mcp.Description("Optional list of relationship types to list. Defaults to all types."),
+			mcp.Items(map[string]interface{}{"type": "string"}),
+		),
+		mcp.WithArray("otherNodeLabels",
+			mcp.Description("Optional list of labels; only relationships whose other node has at least one of them are listed. Defaults to any label."),
+			mcp.Items(map[string]interface{}{"type": "string"}),
+		),
+	)
+	s.addTool(listRelationshipsTool, s.handleListRelationshipsTool)
+
+	getDefinitionLocationTool := mcp.NewTool("get_definition_location",
+		mcp.WithDescription("Finds where a code entity (e.g. a Function or Class) is defined: the 'File' node it has a DEFINED_IN relationship to, and the startLine and endLine recorded on that relationship. Lines are omitted if they weren't recorded."),
+		mcp.WithArray("labels",
+			mcp.Required(),
+			mcp.Description("List of labels for the entity (e.g., ['Function'])."),
+			mcp.Items(map[string]interface{}{"type": "string"}),
+		),
+		mcp.WithObject("identifyingProperties",
+			mcp.Required(),
+			mcp.Description("Map of properties to uniquely identify the entity."),
+		),
+	)
+	s.addTool(getDefinitionLocationTool, s.handleGetDefinitionLocationTool)
+}
+
+// withSample returns ctx asking the store to sample its results if the request sets the sample option, which must
+// be positive
+func withSample(ctx context.Context, request mcp.CallToolRequest) (context.Context, error) {
+	if arg, ok := request.Params.Arguments["sample"]; !ok || arg == nil {
+		return ctx, nil
+	}
+	sample, err := parseOptionalInt(request, "sample", 0)
+	if err != nil {
+		return nil, err
+	}
+	if sample <= 0 {
+		return nil, errors.New("sample must be a positive integer")
+	}
+	return graph.WithSample(ctx, sample), nil
+}
+
+// estimateTraversalCost runs a traversal with cost estimation enabled, returning the planner's estimates for its
+// queries instead of its results
+func (s *Server) estimateTraversalCost(ctx context.Context, traverse func(ctx context.Context) error) (*mcp.CallToolResult, error) {
+	if s.graph.QueryLanguage() != graph.QueryLanguageCypher {
+		return nil, errors.New("estimateCost is only supported by the Neo4j backend")
+	}
+
+	estimateCtx, estimate := graph.WithCostEstimate(ctx)
+	if err := traverse(estimateCtx); err != nil {
+		return nil, fmt.Errorf("failed to estimate traversal cost: %w", err)
+	}
+
+	queries := estimate.Queries()
+	response := struct {
+		Estimated       bool              `json:"estimated"`
+		EstimatedRows   float64           `json:"estimatedRows"`
+		MaxOperatorRows float64           `json:"maxOperatorRows"`
+		Queries         []graph.QueryCost `json:"queries"`
+	}{
+		Estimated: true,
+		Queries:   queries,
+	}
+	if response.Queries == nil {
+		response.Queries = []graph.QueryCost{}
+	}
+	for _, q := range queries {
+		response.EstimatedRows += q.EstimatedRows
+		if q.MaxOperatorRows > response.MaxOperatorRows {
+			response.MaxOperatorRows = q.MaxOperatorRows
+		}
+	}
+
+	// Return the estimate
+	resultJSON, err := json.Marshal(response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal cost estimate: %w", err)
+	}
+	return mcp.NewToolResultText(string(resultJSON)), nil
+}
+
+// handleCommonDependenciesTool handles the common_dependencies tool
+func (s *Server) handleCommonDependenciesTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	locators, err := parseEntityLocators(request, "entities")
+	if err != nil {
+		return nil, err
+	}
+	if len(locators) < 2 {
+		return nil, errors.New("at least two entities are required")
+	}
+	relTypes, err := parseOptionalRelationshipTypes(request)
+	if err != nil {
+		return nil, err
+	}
+	maxDepth, err := parseOptionalInt(request, "maxDepth", 1)
+	if err != nil {
+		return nil, err
+	}
+
+	estimateCost, err := parseOptionalBool(request, "estimateCost", false)
+	if err != nil {
+		return nil, err
+	}
+	if estimateCost {
+		return s.estimateTraversalCost(ctx, func(ctx context.Context) error {
+			_, err := s.graph.CommonDependencies(ctx, locators, relTypes, maxDepth)
+			return err
+		})
+	}
+
+	// Call graph store method
+	commonResult, err := s.graph.CommonDependencies(ctx, locators, relTypes, maxDepth)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find common dependencies: %w", err)
+	}
+
+	// Return the result
+	resultJSON, err := json.Marshal(commonResult)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal common dependencies result: %w", err)
+	}
+	return mcp.NewToolResultText(string(resultJSON)), nil
+}
+
+// handleNearestOfLabelTool handles the nearest_of_label tool
+func (s *Server) handleNearestOfLabelTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	from, err := parseEntityLocator(request.Params.Arguments)
+	if err != nil {
+		return nil, err
+	}
+	targetLabel, ok := request.Params.Arguments["targetLabel"].(string)
+	if !ok || targetLabel == "" {
+		return nil, errors.New("targetLabel must be a non-empty string")
+	}
+	relTypes, err := parseOptionalRelationshipTypes(request)
+	if err != nil {
+		return nil, err
+	}
+	maxDepth, err := parseOptionalInt(request, "maxDepth", 5)
+	if err != nil {
+		return nil, err
+	}
+	if maxDepth > graph.MaxNearestByLabelDepth {
+		return nil, fmt.Errorf("maxDepth must be at most %d", graph.MaxNearestByLabelDepth)
+	}
+
+	estimateCost, err := parseOptionalBool(request, "estimateCost", false)
+	if err != nil {
+		return nil, err
+	}
+	if estimateCost {
+		return s.estimateTraversalCost(ctx, func(ctx context.Context) error {
+			_, err := s.graph.FindNearestByLabel(ctx, from, targetLabel, relTypes, maxDepth)
+			return err
+		})
+	}
+
+	// Call graph store method
+	pathResult, err := s.graph.FindNearestByLabel(ctx, from, targetLabel, relTypes, maxDepth)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find nearest entity: %w", err)
+	}
+
+	// Return the result
+	resultJSON, err := json.Marshal(pathResult)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal path result: %w", err)
+	}
+	return mcp.NewToolResultText(string(resultJSON)), nil
+}
+
+// handleFindShortestPathTool handles the find_shortest_path tool
+func (s *Server) handleFindShortestPathTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	from, err := parseEntityLocator(request.Params.Arguments["from"])
+	if err != nil {
+		return nil, fmt.Errorf("invalid from: %w", err)
+	}
+	to, err := parseEntityLocator(request.Params.Arguments["to"])
+	if err != nil {
+		return nil, fmt.Errorf("invalid to: %w", err)
+	}
+	relTypes, err := parseOptionalRelationshipTypes(request)
+	if err != nil {
+		return nil, err
+	}
+	weightProperty := ""
+	if weightArg, exists := request.Params.Arguments["weightProperty"]; exists && weightArg != nil {
+		weightProperty, _ = weightArg.(string)
+		if weightProperty == "" {
+			return nil, errors.New("weightProperty must be a non-empty string")
+		}
+	}
+	maxDepth, err := parseOptionalInt(request, "maxDepth", 5)
+	if err != nil {
+		return nil, err
+	}
+
+	estimateCost, err := parseOptionalBool(request, "estimateCost", false)
+	if err != nil {
+		return nil, err
+	}
+	if estimateCost {
+		return s.estimateTraversalCost(ctx, func(ctx context.Context) error {
+			_, err := s.graph.FindShortestPath(ctx, from, to, relTypes, weightProperty, maxDepth)
+			return err
+		})
+	}
+
+	// Call graph store method
+	pathResult, err := s.graph.FindShortestPath(ctx, from, to, relTypes, weightProperty, maxDepth)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find shortest path: %w", err)
+	}
+
+	// Return the result
+	resultJSON, err := json.Marshal(pathResult)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal path result: %w", err)
+	}
+	return mcp.NewToolResultText(string(resultJSON)), nil
+}
+
+// handleDependencyPathTool handles the dependency_path tool
+func (s *Server) handleDependencyPathTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	from, err := parseEntityLocator(request.Params.Arguments["from"])
+	if err != nil {
+		return nil, fmt.Errorf("invalid from: %w", err)
+	}
+	to, err := parseEntityLocator(request.Params.Arguments["to"])
+	if err != nil {
+		return nil, fmt.Errorf("invalid to: %w", err)
+	}
+	relTypes, err := parseOptionalRelationshipTypes(request)
+	if err != nil {
+		return nil, err
+	}
+	maxDepth, err := parseOptionalInt(request, "maxDepth", 5)
+	if err != nil {
+		return nil, err
+	}
+	estimateCost, err := parseOptionalBool(request, "estimateCost", false)
+	if err != nil {
+		return nil, err
+	}
+	if estimateCost {
+		return s.estimateTraversalCost(ctx, func(ctx context.Context) error {
+			_, err := s.graph.DependencyPath(ctx, from, to, relTypes, maxDepth)
+			return err
+		})
+	}
+
+	// Call graph store method
+	pathResult, err := s.graph.DependencyPath(ctx, from, to, relTypes, maxDepth)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find dependency path: %w", err)
+	}
+
+	// Return the result
+	resultJSON, err := json.Marshal(pathResult)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal path result: %w", err)
+	}
+	return mcp.NewToolResultText(string(resultJSON)), nil
+}
+
+// hierarchyToolOptions returns the description and arguments of get_ancestors or get_descendants, which share
+// their arguments
+func hierarchyToolOptions(description string) []mcp.ToolOption {
+	return []mcp.ToolOption{
+		mcp.WithDescription(description),
+		mcp.WithArray("labels",
+			mcp.Required(),
+			mcp.Description("List of labels for the entity."),
+			mcp.Items(map[string]interface{}{"type": "string"}),
+		),
+		mcp.WithObject("identifyingProperties",
+			mcp.Required(),
+			mcp.Description("Map of properties that uniquely identify the entity."),
+		),
+		mcp.WithString("relationshipType",
+			mcp.Required(),
+			mcp.Description("The hierarchical relationship type to follow (e.g. 'CONTAINS' or 'PART_OF')."),
+		),
+		mcp.WithBoolean("childToParent",
+			mcp.Description("Set to true if the relationship points from child to parent, as PART_OF does. Defaults to false, for relationships that point from parent to child, such as CONTAINS."),
+		),
+		mcp.WithNumber("maxDepth",
+			mcp.Description("Maximum number of levels to follow. Defaults to 10 if not provided or invalid."),
+		),
+		mcp.WithNumber("sample",
+			mcp.Description(sampleDescription),
+		),
+		mcp.WithBoolean("estimateCost",
+			mcp.Description(estimateCostDescription),
+		),
+	}
+}
+
+// handleGetAncestorsTool handles the get_ancestors tool
+func (s *Server) handleGetAncestorsTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.handleHierarchyTool(ctx, request, true)
+}
+
+// handleGetDescendantsTool handles the get_descendants tool
+func (s *Server) handleGetDescendantsTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return s.handleHierarchyTool(ctx, request, false)
+}
+
+// handleHierarchyTool finds an entity's ancestors, or its descendants if ancestors is false. Ancestors along a
+// child-to-parent relationship are the descendants along the same relationship read the other way, and vice versa.
+func (s *Server) handleHierarchyTool(ctx context.Context, request mcp.CallToolRequest, ancestors bool) (*mcp.CallToolResult, error) {
+	locator, err := parseEntityLocator(request.Params.Arguments)
+	if err != nil {
+		return nil, err
+	}
+	relType, ok := request.Params.Arguments["relationshipType"].(string)
+	if !ok || relType == "" {
+		return nil, errors.New("relationshipType must be a non-empty string")
+	}
+	childToParent, err := parseOptionalBool(request, "childToParent", false)
+	if err != nil {
+		return nil, err
+	}
+	maxDepth, err := parseOptionalInt(request, "maxDepth", 10)
+	if err != nil {
+		return nil, err
+	}
+	ctx, err = withSample(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	estimateCost, err := parseOptionalBool(request, "estimateCost", false)
+	if err != nil {
+		return nil, err
+	}
+
+	find := s.graph.GetDescendants
+	if ancestors != childToParent {
+		find = s.graph.GetAncestors
+	}
+	if estimateCost {
+		return s.estimateTraversalCost(ctx, func(ctx context.Context) error {
+			_, err := find(ctx, locator, relType, maxDepth)
+			return err
+		})
+	}
+
+	// Call graph store method
+	entries, err := find(ctx, locator, relType, maxDepth)
+	if err != nil {
+		return nil, fmt.Errorf("failed to traverse hierarchy: %w", err)
+	}
+
+	// Return the result
+	resultJSON, err := json.Marshal(entries)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal hierarchy: %w", err)
+	}
+	return mcp.NewToolResultText(string(resultJSON)), nil
+}
+
+// handleFindReachableTool handles the find_reachable tool
+func (s *Server) handleFindReachableTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	locator, err := parseEntityLocator(request.Params.Arguments)
+	if err != nil {
+		return nil, err
+	}
+	maxDepth, err := parseOptionalInt(request, "maxDepth", 2)
+	if err != nil {
+		return nil, err
+	}
+	limit, err := parseOptionalInt(request, "limit", 100)
+	if err != nil {
+		return nil, err
+	}
+	ctx, err = withSample(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	estimateCost, err := parseOptionalBool(request, "estimateCost", false)
+	if err != nil {
+		return nil, err
+	}
+	if estimateCost {
+		return s.estimateTraversalCost(ctx, func(ctx context.Context) error {
+			_, err := s.graph.FindReachable(ctx, locator, maxDepth, limit)
+			return err
+		})
+	}
+
+	// Call graph store method
+	reachable, err := s.graph.FindReachable(ctx, locator, maxDepth, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find reachable entities: %w", err)
+	}
+
+	// Return the result
+	resultJSON, err := json.Marshal(reachable)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal reachable entities: %w", err)
+	}
+	return mcp.NewToolResultText(string(resultJSON)), nil
+}
+
+// handleGetEntityWithRelationshipsTool handles the get_entity_with_relationships tool
+func (s *Server) handleGetEntityWithRelationshipsTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	locator, err := parseEntityLocator(request.Params.Arguments)
+	if err != nil {
+		return nil, err
+	}
+
+	// Call graph store method
+	result, err := s.graph.GetEntityWithRelationships(ctx, locator)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get entity with relationships: %w", err)
+	}
+
+	// Return the result
+	resultJSON, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal entity with relationships: %w", err)
+	}
+	return mcp.NewToolResultText(string(resultJSON)), nil
+}
+
+// handleListRelationshipsTool handles the list_relationships tool
+func (s *Server) handleListRelationshipsTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	locator, err := parseEntityLocator(request.Params.Arguments)
+	if err != nil {
+		return nil, err
+	}
+	direction := graph.DirectionBoth
+	if directionArg, exists := request.Params.Arguments["direction"]; exists && directionArg != nil {
+		var ok bool
+		direction, ok = directionArg.(string)
+		if !ok {
+			return nil, errors.New("direction must be a string")
+		}
+	}
+	relTypes, err := parseOptionalRelationshipTypes(request)
+	if err != nil {
+		return nil, err
+	}
+	otherNodeLabels, err := parseOptionalStringArray(request, "otherNodeLabels")
+	if err != nil {
+		return nil, err
+	}
+
+	// Call graph store method
+	relationships, err := s.graph.ListRelationships(ctx, locator, direction, relTypes, otherNodeLabels)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list relationships: %w", err)
+	}
+
+	// Return the result
+	resultJSON, err := json.Marshal(relationships)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal relationships: %w", err)
+	}
+	return mcp.NewToolResultText(string(resultJSON)), nil
+}
+
+// handleGetDefinitionLocationTool handles the get_definition_location tool
+func (s *Server) handleGetDefinitionLocationTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	locator, err := parseEntityLocator(request.Params.Arguments)
+	if err != nil {
+		return nil, err
+	}
+
+	// Call graph store method
+	location, err := s.graph.GetDefinitionLocation(ctx, locator)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get definition location: %w", err)
+	}
+
+	// Return the result
+	resultJSON, err := json.Marshal(location)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal definition location: %w", err)
+	}
+	return mcp.NewToolResultText(string(resultJSON)), nil
+}
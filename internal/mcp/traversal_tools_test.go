@@ -0,0 +1,705 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sammcj/mcp-graph/internal/graph"
+	"github.com/sammcj/mcp-graph/internal/mcp/mocks"
+)
+
+// TestHandleCommonDependenciesTool tests the common_dependencies tool handler
+func TestHandleCommonDependenciesTool(t *testing.T) {
+	// Create a new mock controller
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Create mock graph store and service
+	mockGraph := mocks.NewMockStore(ctrl)
+	mockService := mocks.NewMockKnowledgeManager(ctrl)
+
+	// Create MCP server with mocks
+	server := &Server{
+		graph:   mockGraph,
+		service: mockService,
+	}
+
+	expectedLocators := []graph.EntityLocator{
+		{Labels: []string{"Service"}, IdentifyingProperties: map[string]interface{}{"name": "billing"}},
+		{Labels: []string{"Service"}, IdentifyingProperties: map[string]interface{}{"name": "orders"}},
+	}
+
+	// Mock common dependencies result
+	commonResult := graph.CommonDependenciesResult{
+		Results: []graph.EntityDetails{
+			{
+				Labels:     []string{"Library"},
+				Properties: map[string]interface{}{"id": "4:abc:3", "name": "auth"},
+			},
+		},
+		Depth: 2,
+	}
+
+	// Set up expectations
+	mockGraph.EXPECT().CommonDependencies(
+		gomock.Any(),
+		gomock.Eq(expectedLocators),
+		gomock.Eq([]string{"DEPENDS_ON"}),
+		gomock.Eq(2),
+	).Return(commonResult, nil)
+
+	// Create tool request
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{
+		"entities": []interface{}{
+			map[string]interface{}{
+				"labels":                []interface{}{"Service"},
+				"identifyingProperties": map[string]interface{}{"name": "billing"},
+			},
+			map[string]interface{}{
+				"labels":                []interface{}{"Service"},
+				"identifyingProperties": map[string]interface{}{"name": "orders"},
+			},
+		},
+		"relationshipTypes": []interface{}{"DEPENDS_ON"},
+		"maxDepth":          float64(2),
+	}
+
+	// Call the handler
+	result, err := server.handleCommonDependenciesTool(context.Background(), request)
+
+	// Assert the results
+	assert.NoError(t, err)
+	assert.NotNil(t, result)
+
+	// Verify the result content
+	var resultData map[string]interface{}
+	err = json.Unmarshal([]byte(getResultText(result)), &resultData)
+	assert.NoError(t, err)
+	assert.Len(t, resultData["results"], 1)
+	assert.Equal(t, float64(2), resultData["depth"])
+}
+
+// TestHandleCommonDependenciesTool_TooFewEntities tests that a single entity is rejected
+func TestHandleCommonDependenciesTool_TooFewEntities(t *testing.T) {
+	// Create a new mock controller
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Create MCP server with mocks; no store calls are expected
+	server := &Server{
+		graph:   mocks.NewMockStore(ctrl),
+		service: mocks.NewMockKnowledgeManager(ctrl),
+	}
+
+	// Create tool request
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{
+		"entities": []interface{}{
+			map[string]interface{}{
+				"labels":                []interface{}{"Service"},
+				"identifyingProperties": map[string]interface{}{"name": "billing"},
+			},
+		},
+	}
+
+	// Call the handler
+	result, err := server.handleCommonDependenciesTool(context.Background(), request)
+
+	// Assert the results
+	assert.Error(t, err)
+	assert.Nil(t, result)
+}
+
+// TestHandleNearestOfLabelTool tests the nearest_of_label tool handler
+func TestHandleNearestOfLabelTool(t *testing.T) {
+	// Create a new mock controller
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Create mock graph store and service
+	mockGraph := mocks.NewMockStore(ctrl)
+	mockService := mocks.NewMockKnowledgeManager(ctrl)
+
+	// Create MCP server with mocks
+	server := &Server{
+		graph:   mockGraph,
+		service: mockService,
+	}
+
+	// Mock path result
+	pathResult := graph.PathResult{
+		Found: true,
+		Nodes: []graph.EntityDetails{
+			{Labels: []string{"Function"}, Properties: map[string]interface{}{"id": "4:abc:1", "name": "main"}},
+			{Labels: []string{"File"}, Properties: map[string]interface{}{"id": "4:abc:2", "path": "main.go"}},
+		},
+		Relationships: []graph.SubgraphRelationship{
+			{ID: "5:abc:1", StartNode: "4:abc:1", EndNode: "4:abc:2", Type: "DEFINED_IN"},
+		},
+		Length: 1,
+	}
+
+	// Set up expectations - maxDepth defaults to 5
+	mockGraph.EXPECT().FindNearestByLabel(
+		gomock.Any(),
+		gomock.Eq(graph.EntityLocator{Labels: []string{"Function"}, IdentifyingProperties: map[string]interface{}{"name": "main"}}),
+		gomock.Eq("File"),
+		gomock.Nil(),
+		gomock.Eq(5),
+	).Return(pathResult, nil)
+
+	// Create tool request
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{
+		"labels":                []interface{}{"Function"},
+		"identifyingProperties": map[string]interface{}{"name": "main"},
+		"targetLabel":           "File",
+	}
+
+	// Call the handler
+	result, err := server.handleNearestOfLabelTool(context.Background(), request)
+
+	// Assert the results
+	assert.NoError(t, err)
+	assert.NotNil(t, result)
+
+	// Verify the result content
+	var resultData map[string]interface{}
+	err = json.Unmarshal([]byte(getResultText(result)), &resultData)
+	assert.NoError(t, err)
+	assert.Equal(t, true, resultData["found"])
+	assert.Len(t, resultData["nodes"], 2)
+	assert.Equal(t, float64(1), resultData["length"])
+}
+
+// TestHandleNearestOfLabelToolDepthCap tests that nearest_of_label rejects depths above the cap
+func TestHandleNearestOfLabelToolDepthCap(t *testing.T) {
+	// Create a new mock controller
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Create mock graph store; no store calls are expected
+	mockGraph := mocks.NewMockStore(ctrl)
+	server := &Server{graph: mockGraph}
+
+	// Create tool request
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{
+		"labels":                []interface{}{"Function"},
+		"identifyingProperties": map[string]interface{}{"name": "main"},
+		"targetLabel":           "File",
+		"maxDepth":              float64(graph.MaxNearestByLabelDepth + 1),
+	}
+
+	// Call the handler
+	result, err := server.handleNearestOfLabelTool(context.Background(), request)
+
+	// Assert the results
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.Contains(t, err.Error(), "maxDepth must be at most 10")
+}
+
+// TestHandleFindShortestPathTool tests the find_shortest_path tool handler with a weight property
+func TestHandleFindShortestPathTool(t *testing.T) {
+	// Create a new mock controller
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Create mock graph store
+	mockGraph := mocks.NewMockStore(ctrl)
+
+	// Create MCP server with mocks
+	server := &Server{graph: mockGraph}
+
+	// Mock weighted path result
+	totalWeight := 12.5
+	pathResult := graph.ShortestPathResult{
+		PathResult: graph.PathResult{
+			Found: true,
+			Nodes: []graph.EntityDetails{
+				{Labels: []string{"Service"}, Properties: map[string]interface{}{"id": "4:abc:1", "name": "api"}},
+				{Labels: []string{"Service"}, Properties: map[string]interface{}{"id": "4:abc:2", "name": "billing"}},
+			},
+			Relationships: []graph.SubgraphRelationship{
+				{ID: "5:abc:1", StartNode: "4:abc:1", EndNode: "4:abc:2", Type: "CALLS", Props: map[string]interface{}{"latencyMs": 12.5}},
+			},
+			Length: 1,
+		},
+		Algorithm:   "dijkstra",
+		TotalWeight: &totalWeight,
+	}
+
+	// Set up expectations - maxDepth defaults to 5
+	mockGraph.EXPECT().FindShortestPath(
+		gomock.Any(),
+		gomock.Eq(graph.EntityLocator{Labels: []string{"Service"}, IdentifyingProperties: map[string]interface{}{"name": "api"}}),
+		gomock.Eq(graph.EntityLocator{Labels: []string{"Service"}, IdentifyingProperties: map[string]interface{}{"name": "billing"}}),
+		gomock.Eq([]string{"CALLS"}),
+		gomock.Eq("latencyMs"),
+		gomock.Eq(5),
+	).Return(pathResult, nil)
+
+	// Create tool request
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{
+		"from": map[string]interface{}{
+			"labels":                []interface{}{"Service"},
+			"identifyingProperties": map[string]interface{}{"name": "api"},
+		},
+		"to": map[string]interface{}{
+			"labels":                []interface{}{"Service"},
+			"identifyingProperties": map[string]interface{}{"name": "billing"},
+		},
+		"relationshipTypes": []interface{}{"CALLS"},
+		"weightProperty":    "latencyMs",
+	}
+
+	// Call the handler
+	result, err := server.handleFindShortestPathTool(context.Background(), request)
+
+	// Assert the results
+	assert.NoError(t, err)
+	assert.NotNil(t, result)
+
+	// Verify the path fields and the weighting are returned together
+	var resultData map[string]interface{}
+	err = json.Unmarshal([]byte(getResultText(result)), &resultData)
+	assert.NoError(t, err)
+	assert.Equal(t, true, resultData["found"])
+	assert.Len(t, resultData["nodes"], 2)
+	assert.Equal(t, "dijkstra", resultData["algorithm"])
+	assert.Equal(t, 12.5, resultData["totalWeight"])
+}
+
+// TestHandleNearestOfLabelTool_MissingTargetLabel tests the nearest_of_label tool handler without a target label
+func TestHandleNearestOfLabelTool_MissingTargetLabel(t *testing.T) {
+	// Create a new mock controller
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Create MCP server with mocks; no store calls are expected
+	server := &Server{
+		graph:   mocks.NewMockStore(ctrl),
+		service: mocks.NewMockKnowledgeManager(ctrl),
+	}
+
+	// Create tool request
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{
+		"labels":                []interface{}{"Function"},
+		"identifyingProperties": map[string]interface{}{"name": "main"},
+	}
+
+	// Call the handler
+	_, err := server.handleNearestOfLabelTool(context.Background(), request)
+
+	// Assert the error
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "targetLabel")
+}
+
+// TestHandleGetEntityWithRelationshipsTool tests the get_entity_with_relationships tool handler
+func TestHandleGetEntityWithRelationshipsTool(t *testing.T) {
+	// Create a new mock controller
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Create mock graph store and service
+	mockGraph := mocks.NewMockStore(ctrl)
+	mockService := mocks.NewMockKnowledgeManager(ctrl)
+
+	// Create MCP server with mocks
+	server := &Server{
+		graph:   mockGraph,
+		service: mockService,
+	}
+
+	// Mock entity with relationships
+	entityResult := graph.EntityWithRelationships{
+		Entity: graph.EntityDetails{Labels: []string{"Function"}, Properties: map[string]interface{}{"id": "4:abc:1", "name": "main"}},
+		Relationships: []graph.EntityRelationship{
+			{
+				ID:         "5:abc:1",
+				Type:       "DEFINED_IN",
+				Direction:  graph.DirectionOutgoing,
+				Properties: map[string]interface{}{},
+				Node:       graph.EntityDetails{Labels: []string{"File"}, Properties: map[string]interface{}{"id": "4:abc:2", "path": "main.go"}},
+			},
+		},
+	}
+
+	// Set up expectations
+	mockGraph.EXPECT().GetEntityWithRelationships(
+		gomock.Any(),
+		gomock.Eq(graph.EntityLocator{Labels: []string{"Function"}, IdentifyingProperties: map[string]interface{}{"name": "main"}}),
+	).Return(entityResult, nil)
+
+	// Create tool request
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{
+		"labels":                []interface{}{"Function"},
+		"identifyingProperties": map[string]interface{}{"name": "main"},
+	}
+
+	// Call the handler
+	result, err := server.handleGetEntityWithRelationshipsTool(context.Background(), request)
+
+	// Assert the results
+	assert.NoError(t, err)
+
+	// Verify the result content
+	var resultData graph.EntityWithRelationships
+	err = json.Unmarshal([]byte(getResultText(result)), &resultData)
+	assert.NoError(t, err)
+	assert.Equal(t, "main", resultData.Entity.Properties["name"])
+	if assert.Len(t, resultData.Relationships, 1) {
+		assert.Equal(t, graph.DirectionOutgoing, resultData.Relationships[0].Direction)
+		assert.Equal(t, "main.go", resultData.Relationships[0].Node.Properties["path"])
+	}
+}
+
+// TestHandleListRelationshipsTool tests the list_relationships tool handler
+func TestHandleListRelationshipsTool(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockGraph := mocks.NewMockStore(ctrl)
+	server := &Server{graph: mockGraph}
+
+	relationships := []graph.EntityRelationship{
+		{
+			ID:         "5:abc:1",
+			Type:       "WRITES_TO",
+			Direction:  graph.DirectionOutgoing,
+			Properties: map[string]interface{}{},
+			Node:       graph.EntityDetails{Labels: []string{"DataStore"}, Properties: map[string]interface{}{"name": "orders-db"}},
+		},
+	}
+
+	// Set up expectations
+	mockGraph.EXPECT().ListRelationships(
+		gomock.Any(),
+		gomock.Eq(graph.EntityLocator{Labels: []string{"Service"}, IdentifyingProperties: map[string]interface{}{"name": "orders"}}),
+		graph.DirectionOutgoing,
+		[]string(nil),
+		[]string{"DataStore"},
+	).Return(relationships, nil)
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{
+		"labels":                []interface{}{"Service"},
+		"identifyingProperties": map[string]interface{}{"name": "orders"},
+		"direction":             "outgoing",
+		"otherNodeLabels":       []interface{}{"DataStore"},
+	}
+
+	// Call the handler
+	result, err := server.handleListRelationshipsTool(context.Background(), request)
+	assert.NoError(t, err)
+
+	var resultData []graph.EntityRelationship
+	err = json.Unmarshal([]byte(getResultText(result)), &resultData)
+	assert.NoError(t, err)
+	if assert.Len(t, resultData, 1) {
+		assert.Equal(t, "WRITES_TO", resultData[0].Type)
+		assert.Equal(t, "orders-db", resultData[0].Node.Properties["name"])
+	}
+
+	// A non-array label filter is rejected
+	request.Params.Arguments["otherNodeLabels"] = "DataStore"
+	_, err = server.handleListRelationshipsTool(context.Background(), request)
+	assert.Error(t, err)
+}
+
+// TestHandleGetDefinitionLocationTool tests the get_definition_location tool handler
+func TestHandleGetDefinitionLocationTool(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockGraph := mocks.NewMockStore(ctrl)
+	server := &Server{graph: mockGraph}
+
+	location := graph.DefinitionLocation{
+		Entity:    graph.EntityDetails{Labels: []string{"Function"}, Properties: map[string]interface{}{"id": "4:abc:1", "name": "main"}},
+		File:      graph.EntityDetails{Labels: []string{"File"}, Properties: map[string]interface{}{"id": "4:abc:2", "filePath": "cmd/server/main.go"}},
+		StartLine: 30,
+		EndLine:   120,
+	}
+	mockGraph.EXPECT().GetDefinitionLocation(
+		gomock.Any(),
+		graph.EntityLocator{Labels: []string{"Function"}, IdentifyingProperties: map[string]interface{}{"name": "main"}},
+	).Return(location, nil)
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{
+		"labels":                []interface{}{"Function"},
+		"identifyingProperties": map[string]interface{}{"name": "main"},
+	}
+
+	result, err := server.handleGetDefinitionLocationTool(context.Background(), request)
+	assert.NoError(t, err)
+
+	var resultData graph.DefinitionLocation
+	assert.NoError(t, json.Unmarshal([]byte(getResultText(result)), &resultData))
+	assert.Equal(t, "cmd/server/main.go", resultData.File.Properties["filePath"])
+	assert.Equal(t, 30, resultData.StartLine)
+	assert.Equal(t, 120, resultData.EndLine)
+}
+
+// TestHandleFindShortestPathTool_EstimateCost tests that estimateCost returns the recorded estimates instead of a path
+func TestHandleFindShortestPathTool_EstimateCost(t *testing.T) {
+	// Create a new mock controller
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Create mock graph store
+	mockGraph := mocks.NewMockStore(ctrl)
+
+	// Create MCP server with mocks
+	server := &Server{graph: mockGraph}
+
+	// Set up expectations - the store records its plan estimates rather than finding a path
+	mockGraph.EXPECT().QueryLanguage().Return(graph.QueryLanguageCypher)
+	mockGraph.EXPECT().FindShortestPath(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "", 8).DoAndReturn(
+		func(ctx context.Context, from, to graph.EntityLocator, relationshipTypes []string, weightProperty string, maxDepth int) (graph.ShortestPathResult, error) {
+			estimate := graph.CostEstimateFromContext(ctx)
+			assert.NotNil(t, estimate)
+			estimate.Add(graph.QueryCost{Operator: "ProduceResults", EstimatedRows: 1, MaxOperatorRows: 90000})
+			return graph.ShortestPathResult{}, nil
+		})
+
+	// Create tool request
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{
+		"from": map[string]interface{}{
+			"labels":                []interface{}{"Service"},
+			"identifyingProperties": map[string]interface{}{"name": "api"},
+		},
+		"to": map[string]interface{}{
+			"labels":                []interface{}{"Service"},
+			"identifyingProperties": map[string]interface{}{"name": "billing"},
+		},
+		"maxDepth":     float64(8),
+		"estimateCost": true,
+	}
+
+	// Call the handler
+	result, err := server.handleFindShortestPathTool(context.Background(), request)
+
+	// Assert the results
+	assert.NoError(t, err)
+
+	var resultData map[string]interface{}
+	err = json.Unmarshal([]byte(getResultText(result)), &resultData)
+	assert.NoError(t, err)
+	assert.Equal(t, true, resultData["estimated"])
+	assert.Equal(t, 1.0, resultData["estimatedRows"])
+	assert.Equal(t, 90000.0, resultData["maxOperatorRows"])
+	assert.Len(t, resultData["queries"], 1)
+	assert.NotContains(t, resultData, "found")
+}
+
+// TestHandleNearestOfLabelTool_EstimateCostUnsupported tests that estimateCost is rejected by backends that can't plan queries
+func TestHandleNearestOfLabelTool_EstimateCostUnsupported(t *testing.T) {
+	// Create a new mock controller
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Create mock graph store
+	mockGraph := mocks.NewMockStore(ctrl)
+
+	// Create MCP server with mocks
+	server := &Server{graph: mockGraph}
+
+	// Set up expectations - the traversal is never run
+	mockGraph.EXPECT().QueryLanguage().Return(graph.QueryLanguageDQL)
+
+	// Create tool request
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{
+		"labels":                []interface{}{"Function"},
+		"identifyingProperties": map[string]interface{}{"name": "main"},
+		"targetLabel":           "File",
+		"estimateCost":          true,
+	}
+
+	// Call the handler
+	result, err := server.handleNearestOfLabelTool(context.Background(), request)
+
+	// Assert the results
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.Contains(t, err.Error(), "only supported by the Neo4j backend")
+}
+
+// TestHandleDependencyPathTool tests the dependency_path tool handler
+func TestHandleDependencyPathTool(t *testing.T) {
+	// Create a new mock controller
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Create mock graph store
+	mockGraph := mocks.NewMockStore(ctrl)
+
+	// Create MCP server with mocks
+	server := &Server{graph: mockGraph}
+
+	// Mock a two-hop dependency chain
+	pathResult := graph.PathResult{
+		Found: true,
+		Nodes: []graph.EntityDetails{
+			{Labels: []string{"Service"}, Properties: map[string]interface{}{"id": "4:abc:1", "name": "api"}},
+			{Labels: []string{"Library"}, Properties: map[string]interface{}{"id": "4:abc:2", "name": "auth"}},
+			{Labels: []string{"Library"}, Properties: map[string]interface{}{"id": "4:abc:3", "name": "crypto"}},
+		},
+		Relationships: []graph.SubgraphRelationship{
+			{ID: "5:abc:1", StartNode: "4:abc:1", EndNode: "4:abc:2", Type: "DEPENDS_ON", Props: map[string]interface{}{}},
+			{ID: "5:abc:2", StartNode: "4:abc:2", EndNode: "4:abc:3", Type: "IMPORTS", Props: map[string]interface{}{}},
+		},
+		Length: 2,
+	}
+
+	// Set up expectations - maxDepth defaults to 5
+	mockGraph.EXPECT().DependencyPath(
+		gomock.Any(),
+		gomock.Eq(graph.EntityLocator{Labels: []string{"Service"}, IdentifyingProperties: map[string]interface{}{"name": "api"}}),
+		gomock.Eq(graph.EntityLocator{Labels: []string{"Library"}, IdentifyingProperties: map[string]interface{}{"name": "crypto"}}),
+		gomock.Nil(),
+		gomock.Eq(5),
+	).Return(pathResult, nil)
+
+	// Create tool request
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{
+		"from": map[string]interface{}{
+			"labels":                []interface{}{"Service"},
+			"identifyingProperties": map[string]interface{}{"name": "api"},
+		},
+		"to": map[string]interface{}{
+			"labels":                []interface{}{"Library"},
+			"identifyingProperties": map[string]interface{}{"name": "crypto"},
+		},
+	}
+
+	// Call the handler
+	result, err := server.handleDependencyPathTool(context.Background(), request)
+
+	// Assert the results
+	assert.NoError(t, err)
+
+	var resultData graph.PathResult
+	err = json.Unmarshal([]byte(getResultText(result)), &resultData)
+	assert.NoError(t, err)
+	assert.True(t, resultData.Found)
+	assert.Equal(t, 2, resultData.Length)
+	assert.Equal(t, "crypto", resultData.Nodes[2].Properties["name"])
+	assert.Equal(t, "DEPENDS_ON", resultData.Relationships[0].Type)
+	assert.Equal(t, "IMPORTS", resultData.Relationships[1].Type)
+}
+
+// TestHandleGetAncestorsTool tests the get_ancestors tool handler, including following a child-to-parent relationship
+func TestHandleGetAncestorsTool(t *testing.T) {
+	// Create a new mock controller
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Create mock graph store
+	mockGraph := mocks.NewMockStore(ctrl)
+
+	// Create MCP server with mocks
+	server := &Server{graph: mockGraph}
+
+	locator := graph.EntityLocator{Labels: []string{"File"}, IdentifyingProperties: map[string]interface{}{"path": "cmd/main.go"}}
+	entries := []graph.HierarchyEntry{
+		{Depth: 1, Entity: graph.EntityDetails{Labels: []string{"Module"}, Properties: map[string]interface{}{"id": "4:abc:2", "name": "cmd"}}},
+		{Depth: 2, Entity: graph.EntityDetails{Labels: []string{"Application"}, Properties: map[string]interface{}{"id": "4:abc:1", "name": "server"}}},
+	}
+
+	// Set up expectations - maxDepth defaults to 10, and ancestors along PART_OF are found as its descendants
+	mockGraph.EXPECT().GetAncestors(gomock.Any(), gomock.Eq(locator), "CONTAINS", 10).Return(entries, nil)
+	mockGraph.EXPECT().GetDescendants(gomock.Any(), gomock.Eq(locator), "PART_OF", 3).Return(entries, nil)
+
+	// Create tool request
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{
+		"labels":                []interface{}{"File"},
+		"identifyingProperties": map[string]interface{}{"path": "cmd/main.go"},
+		"relationshipType":      "CONTAINS",
+	}
+
+	// Call the handler
+	result, err := server.handleGetAncestorsTool(context.Background(), request)
+
+	// Assert the results
+	assert.NoError(t, err)
+
+	// Verify the result content
+	var resultData []graph.HierarchyEntry
+	err = json.Unmarshal([]byte(getResultText(result)), &resultData)
+	assert.NoError(t, err)
+	assert.Len(t, resultData, 2)
+	assert.Equal(t, 1, resultData[0].Depth)
+	assert.Equal(t, "cmd", resultData[0].Entity.Properties["name"])
+	assert.Equal(t, 2, resultData[1].Depth)
+
+	// Follow a child-to-parent relationship
+	request.Params.Arguments["relationshipType"] = "PART_OF"
+	request.Params.Arguments["childToParent"] = true
+	request.Params.Arguments["maxDepth"] = float64(3)
+	_, err = server.handleGetAncestorsTool(context.Background(), request)
+	assert.NoError(t, err)
+
+	// A relationship type is required
+	delete(request.Params.Arguments, "relationshipType")
+	_, err = server.handleGetAncestorsTool(context.Background(), request)
+	assert.Error(t, err)
+}
+
+// TestHandleFindReachableTool tests the find_reachable tool handler
+func TestHandleFindReachableTool(t *testing.T) {
+	// Create a new mock controller
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Create mock graph store
+	mockGraph := mocks.NewMockStore(ctrl)
+
+	// Create MCP server with mocks
+	server := &Server{graph: mockGraph}
+
+	// Set up expectations - maxDepth defaults to 2
+	locator := graph.EntityLocator{Labels: []string{"Service"}, IdentifyingProperties: map[string]interface{}{"name": "api"}}
+	mockGraph.EXPECT().FindReachable(gomock.Any(), gomock.Eq(locator), 2, 10).Return([]graph.ReachableEntity{
+		{Distance: 1, Entity: graph.EntityDetails{Labels: []string{"Library"}, Properties: map[string]interface{}{"id": "4:abc:2", "name": "auth"}}},
+		{Distance: 2, Entity: graph.EntityDetails{Labels: []string{"Team"}, Properties: map[string]interface{}{"id": "4:abc:3", "name": "identity"}}},
+	}, nil)
+
+	// Create tool request
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{
+		"labels":                []interface{}{"Service"},
+		"identifyingProperties": map[string]interface{}{"name": "api"},
+		"limit":                 float64(10),
+	}
+
+	// Call the handler
+	result, err := server.handleFindReachableTool(context.Background(), request)
+
+	// Assert the results
+	assert.NoError(t, err)
+
+	// Verify the result content
+	var resultData []graph.ReachableEntity
+	err = json.Unmarshal([]byte(getResultText(result)), &resultData)
+	assert.NoError(t, err)
+	assert.Len(t, resultData, 2)
+	assert.Equal(t, 1, resultData[0].Distance)
+	assert.Equal(t, "identity", resultData[1].Entity.Properties["name"])
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/google/uuid"
 	"github.com/sammcj/mcp-graph/internal/graph"
 )
 
@@ -22,6 +23,11 @@ func (s *Service) CreateConcept(ctx context.Context, name string, properties map
 		}
 	}
 
+	// Assign a portable identifier if enabled, unless one was given
+	if _, ok := conceptProps["uuid"]; !ok && s.assignUUIDs {
+		conceptProps["uuid"] = uuid.NewString()
+	}
+
 	// Create concept node
 	return s.graph.CreateNode(ctx, string(graph.NodeTypeConcept), conceptProps)
 }
@@ -34,6 +40,20 @@ func (s *Service) GetConcept(ctx context.Context, id string) (*Concept, error) {
 		return nil, fmt.Errorf("failed to get concept: %w", err)
 	}
 
+	return conceptFromNode(id, node)
+}
+
+// GetConceptByUUID retrieves a concept by its uuid property. The returned concept's ID is the UUID.
+func (s *Service) GetConceptByUUID(ctx context.Context, uuid string) (*Concept, error) {
+	node, err := s.findNodeByUUID(ctx, graph.NodeTypeConcept, uuid)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get concept: %w", err)
+	}
+	return conceptFromNode(uuid, node)
+}
+
+// conceptFromNode converts a node's properties into a concept with the given ID
+func conceptFromNode(id string, node map[string]interface{}) (*Concept, error) {
 	// Check node type
 	nodeType, ok := node["type"].(string)
 	if !ok || nodeType != string(graph.NodeTypeConcept) {
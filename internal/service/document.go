@@ -5,12 +5,91 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 
+	"github.com/google/uuid"
 	"github.com/sammcj/mcp-graph/internal/graph"
 )
 
 // CreateDocument creates a new document in the knowledge graph
 func (s *Service) CreateDocument(ctx context.Context, title, content string, metadata map[string]interface{}) (string, error) {
+	// Create document node
+	return s.graph.CreateNode(ctx, string(graph.NodeTypeDocument), s.documentProperties(title, content, metadata))
+}
+
+// FindOrCreateDocument returns the ID of the document whose titleKey matches the given title's, creating the
+// document if there is none. Reports whether the document was created. Existing documents are left unchanged, and
+// only documents with a titleKey (see SetAssignTitleKeys) are found.
+func (s *Service) FindOrCreateDocument(ctx context.Context, title, content string, metadata map[string]interface{}) (string, bool, error) {
+	if strings.TrimSpace(title) == "" {
+		return "", false, fmt.Errorf("title is required")
+	}
+	titleKey := TitleKey(title)
+
+	// Find or create it in one operation, always with a title key so that it is found next time
+	id, created, err := s.graph.CreateNodeIfNotExists(ctx, string(graph.NodeTypeDocument), "titleKey", titleKey, s.documentProperties(title, content, metadata))
+	if err != nil {
+		return "", false, fmt.Errorf("failed to find or create document: %w", err)
+	}
+	return id, created, nil
+}
+
+// TitleKey normalises a document title for deduplication: it is lowercased, and leading, trailing and repeated
+// whitespace is removed. Example: "  Design  Notes " -> "design notes"
+func TitleKey(title string) string {
+	return strings.ToLower(strings.Join(strings.Fields(title), " "))
+}
+
+// BatchCreateDocuments creates multiple documents in a single operation.
+// Returns the IDs of the created documents in the same order as the input array, along with any individual errors.
+func (s *Service) BatchCreateDocuments(ctx context.Context, docs []DocumentInput) ([]string, []error, error) {
+	if len(docs) == 0 {
+		return nil, nil, fmt.Errorf("at least one document is required")
+	}
+
+	ids := make([]string, len(docs))
+	individualErrors := make([]error, len(docs))
+
+	// Validate each document, keeping track of where the valid ones came from
+	properties := make([]map[string]interface{}, 0, len(docs))
+	indexes := make([]int, 0, len(docs))
+	for i, doc := range docs {
+		if doc.Title == "" {
+			individualErrors[i] = fmt.Errorf("title is required for document at index %d", i)
+			continue
+		}
+		properties = append(properties, s.documentProperties(doc.Title, doc.Content, doc.Metadata))
+		indexes = append(indexes, i)
+	}
+
+	// Create the valid documents together
+	if len(properties) > 0 {
+		created, err := s.graph.BatchCreateNodes(ctx, string(graph.NodeTypeDocument), properties)
+		for j, i := range indexes {
+			if err != nil {
+				individualErrors[i] = fmt.Errorf("error creating document at index %d: %w", i, err)
+				continue
+			}
+			ids[i] = created[j]
+		}
+	}
+
+	// Check if any individual operations failed
+	var failedCount int
+	for _, err := range individualErrors {
+		if err != nil {
+			failedCount++
+		}
+	}
+	if failedCount > 0 {
+		return ids, individualErrors, fmt.Errorf("%d out of %d documents failed", failedCount, len(docs))
+	}
+
+	return ids, individualErrors, nil
+}
+
+// documentProperties builds the properties of a new document node
+func (s *Service) documentProperties(title, content string, metadata map[string]interface{}) map[string]interface{} {
 	// Create properties map
 	properties := map[string]interface{}{
 		"title":   title,
@@ -22,8 +101,19 @@ func (s *Service) CreateDocument(ctx context.Context, title, content string, met
 		properties["metadata"] = metadata
 	}
 
-	// Create document node
-	return s.graph.CreateNode(ctx, string(graph.NodeTypeDocument), properties)
+	// Keep a portable identifier given in the metadata, otherwise assign one if enabled
+	if id, ok := metadata["uuid"].(string); ok && id != "" {
+		properties["uuid"] = id
+	} else if s.assignUUIDs {
+		properties["uuid"] = uuid.NewString()
+	}
+
+	// Assign a normalised title for deduplication if enabled
+	if s.assignTitleKeys {
+		properties["titleKey"] = TitleKey(title)
+	}
+
+	return properties
 }
 
 // GetDocument retrieves a document by ID
@@ -34,6 +124,20 @@ func (s *Service) GetDocument(ctx context.Context, id string) (*Document, error)
 		return nil, fmt.Errorf("failed to get document: %w", err)
 	}
 
+	return documentFromNode(id, node)
+}
+
+// GetDocumentByUUID retrieves a document by its uuid property. The returned document's ID is the UUID.
+func (s *Service) GetDocumentByUUID(ctx context.Context, uuid string) (*Document, error) {
+	node, err := s.findNodeByUUID(ctx, graph.NodeTypeDocument, uuid)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get document: %w", err)
+	}
+	return documentFromNode(uuid, node)
+}
+
+// documentFromNode converts a node's properties into a document with the given ID
+func documentFromNode(id string, node map[string]interface{}) (*Document, error) {
 	// Check node type
 	nodeType, ok := node["type"].(string)
 	if !ok || nodeType != string(graph.NodeTypeDocument) {
@@ -80,6 +184,11 @@ func (s *Service) UpdateDocument(ctx context.Context, id, title, content string,
 		properties["metadata"] = metadata
 	}
 
+	// Keep the normalised title in step with the title
+	if s.assignTitleKeys {
+		properties["titleKey"] = TitleKey(title)
+	}
+
 	// Update node
 	return s.graph.UpdateNode(ctx, id, properties)
 }
@@ -90,12 +199,92 @@ func (s *Service) DeleteDocument(ctx context.Context, id string) error {
 	return s.graph.DeleteNode(ctx, id)
 }
 
+// LinkDocument creates a relationship from a document to any other node, such as a concept or an
+// architecture entity (e.g. a Document DESCRIBES a Service). The relationship type defaults to DESCRIBES.
+func (s *Service) LinkDocument(ctx context.Context, docID, targetID string, relationshipType string, properties map[string]interface{}) (string, error) {
+	// Check the source is a document
+	node, err := s.graph.GetNode(ctx, docID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get document: %w", err)
+	}
+	if nodeType, ok := node["type"].(string); !ok || nodeType != string(graph.NodeTypeDocument) {
+		return "", errors.New("node is not a document")
+	}
+
+	// Check the target exists
+	if _, err := s.graph.GetNode(ctx, targetID); err != nil {
+		return "", fmt.Errorf("failed to get target node: %w", err)
+	}
+
+	if relationshipType == "" {
+		relationshipType = string(graph.EdgeTypeDescribes)
+	}
+
+	// Create edge
+	return s.graph.CreateEdge(ctx, docID, targetID, relationshipType, properties)
+}
+
+// GetDocumentContext retrieves a document together with the nodes it links to (as created by LinkDocument),
+// one hop away, so the whole neighbourhood can be handed to a model in a single call
+func (s *Service) GetDocumentContext(ctx context.Context, id string) (*DocumentContext, error) {
+	doc, err := s.GetDocument(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	rels, err := s.graph.GetNodeRelationships(ctx, id, graph.DirectionOutgoing)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get document links: %w", err)
+	}
+
+	links := make([]LinkedNode, 0, len(rels))
+	for _, rel := range rels {
+		links = append(links, LinkedNode{
+			RelationshipID:   rel.ID,
+			RelationshipType: rel.Type,
+			Properties:       rel.Properties,
+			Node:             rel.Node,
+		})
+	}
+
+	return &DocumentContext{
+		Document: doc,
+		Links:    links,
+	}, nil
+}
+
+// GetEntityDocuments retrieves the documents linked to an entity, such as the design documents describing a
+// Service. Every relationship from a Document to the entity counts as a link, whatever its type.
+func (s *Service) GetEntityDocuments(ctx context.Context, locator graph.EntityLocator) ([]EntityDocument, error) {
+	rels, err := s.graph.ListRelationships(ctx, locator, graph.DirectionIncoming, nil, []string{string(graph.NodeTypeDocument)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get entity documents: %w", err)
+	}
+
+	documents := make([]EntityDocument, 0, len(rels))
+	for _, rel := range rels {
+		id, _ := rel.Node.Properties["id"].(string)
+		doc, err := documentFromNode(id, rel.Node.Properties)
+		if err != nil {
+			continue // Labelled Document but not created as one
+		}
+		documents = append(documents, EntityDocument{
+			RelationshipID:   rel.ID,
+			RelationshipType: rel.Type,
+			Properties:       rel.Properties,
+			Document:         doc,
+		})
+	}
+
+	return documents, nil
+}
+
 // SearchDocuments searches for documents matching the query
 func (s *Service) SearchDocuments(ctx context.Context, query string) ([]*Document, error) {
 	// Create GraphQL query
 	graphQuery := `
 		{
-			documents(func: type(Document)) @filter(anyoftext(title, content, "` + query + `")) {
+			documents(func: type(Document)) ` + documentSearchFilter(query) + ` {
 				uid
 				title
 				content
@@ -110,7 +299,73 @@ func (s *Service) SearchDocuments(ctx context.Context, query string) ([]*Documen
 		return nil, fmt.Errorf("search failed: %w", err)
 	}
 
-	// Convert results to documents
+	return documentsFromResults(results)
+}
+
+// SearchDocumentsPage returns one page of the documents matching the query, ordered by ID so that pages are
+// stable, along with the total number of matching documents. A limit of zero or less uses the default of 100.
+func (s *Service) SearchDocumentsPage(ctx context.Context, query string, skip, limit int) (*DocumentPage, error) {
+	if skip < 0 {
+		skip = 0
+	}
+	if limit <= 0 {
+		limit = defaultDocumentPageSize
+	}
+
+	// Count every matching document
+	countQuery := `
+		{
+			documents(func: type(Document)) ` + documentSearchFilter(query) + ` {
+				total: count(uid)
+			}
+		}
+	`
+	countResults, err := s.graph.Query(ctx, countQuery, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count documents: %w", err)
+	}
+	var total int64
+	if len(countResults) > 0 {
+		if totalVal, ok := countResults[0]["total"].(float64); ok {
+			total = int64(totalVal)
+		}
+	}
+
+	// Fetch the page
+	graphQuery := fmt.Sprintf(`
+		{
+			documents(func: type(Document), orderasc: uid, first: %d, offset: %d) %s {
+				uid
+				title
+				content
+				metadata
+			}
+		}
+	`, limit, skip, documentSearchFilter(query))
+	results, err := s.graph.Query(ctx, graphQuery, nil)
+	if err != nil {
+		return nil, fmt.Errorf("search failed: %w", err)
+	}
+	documents, err := documentsFromResults(results)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DocumentPage{
+		Data:  documents,
+		Total: total,
+		Skip:  skip,
+		Limit: limit,
+	}, nil
+}
+
+// documentSearchFilter returns the filter matching documents whose title or content contains any of the query's terms
+func documentSearchFilter(query string) string {
+	return `@filter(anyoftext(title, content, "` + query + `"))`
+}
+
+// documentsFromResults converts the results of a document search into documents
+func documentsFromResults(results []map[string]interface{}) ([]*Document, error) {
 	documents := make([]*Document, 0, len(results))
 	for _, result := range results {
 		id, _ := result["uid"].(string)
@@ -0,0 +1,137 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sammcj/mcp-graph/internal/graph"
+	"github.com/sammcj/mcp-graph/internal/mcp/mocks"
+	"github.com/sammcj/mcp-graph/internal/service"
+)
+
+// TestFindOrCreateDocument tests that FindOrCreateDocument finds or creates the document in a single call keyed by
+// its title key
+func TestFindOrCreateDocument(t *testing.T) {
+	// Create a new mock controller
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockGraph := mocks.NewMockStore(ctrl)
+	svc := service.NewService(mockGraph)
+
+	// Set up expectations
+	mockGraph.EXPECT().CreateNodeIfNotExists(gomock.Any(), string(graph.NodeTypeDocument), "titleKey", "design notes", gomock.Any()).
+		DoAndReturn(func(_ context.Context, _, _ string, _ interface{}, properties map[string]interface{}) (string, bool, error) {
+			assert.Equal(t, " Design  Notes", properties["title"])
+			return "4:abc:1", true, nil
+		})
+
+	// Call the service
+	id, created, err := svc.FindOrCreateDocument(context.Background(), " Design  Notes", "Notes", nil)
+
+	// Assert the results
+	assert.NoError(t, err)
+	assert.Equal(t, "4:abc:1", id)
+	assert.True(t, created)
+
+	// A title is required
+	_, _, err = svc.FindOrCreateDocument(context.Background(), "  ", "Notes", nil)
+	assert.Error(t, err)
+}
+
+// TestCreateDocument_KeepsUUID tests that CreateDocument keeps a uuid given in the metadata
+func TestCreateDocument_KeepsUUID(t *testing.T) {
+	// Create a new mock controller
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockGraph := mocks.NewMockStore(ctrl)
+	svc := service.NewService(mockGraph)
+	svc.SetAssignUUIDs(true)
+
+	// Set up expectations
+	mockGraph.EXPECT().CreateNode(gomock.Any(), string(graph.NodeTypeDocument), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, properties map[string]interface{}) (string, error) {
+			assert.Equal(t, "5f0c", properties["uuid"])
+			return "4:abc:1", nil
+		})
+
+	// Call the service
+	_, err := svc.CreateDocument(context.Background(), "Design Notes", "Notes", map[string]interface{}{"uuid": "5f0c"})
+
+	// Assert the results
+	assert.NoError(t, err)
+}
+
+// TestSearchDocumentsPage tests that SearchDocumentsPage counts every match and fetches the requested page
+func TestSearchDocumentsPage(t *testing.T) {
+	// Create a new mock controller
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockGraph := mocks.NewMockStore(ctrl)
+	svc := service.NewService(mockGraph)
+
+	// Set up expectations - the count query, then the page query
+	gomock.InOrder(
+		mockGraph.EXPECT().Query(gomock.Any(), gomock.Any(), gomock.Nil()).
+			DoAndReturn(func(_ context.Context, query string, _ map[string]interface{}) ([]map[string]interface{}, error) {
+				assert.Contains(t, query, "total: count(uid)")
+				assert.Contains(t, query, `anyoftext(title, content, "graph")`)
+				return []map[string]interface{}{{"total": float64(21)}}, nil
+			}),
+		mockGraph.EXPECT().Query(gomock.Any(), gomock.Any(), gomock.Nil()).
+			DoAndReturn(func(_ context.Context, query string, _ map[string]interface{}) ([]map[string]interface{}, error) {
+				assert.Contains(t, query, "orderasc: uid, first: 10, offset: 20")
+				assert.Contains(t, query, `anyoftext(title, content, "graph")`)
+				return []map[string]interface{}{
+					{"uid": "0x15", "title": "Graph Notes", "content": "Notes", "metadata": `{"author":"Sam"}`},
+				}, nil
+			}),
+	)
+
+	// Call the service
+	page, err := svc.SearchDocumentsPage(context.Background(), "graph", 20, 10)
+
+	// Assert the results
+	assert.NoError(t, err)
+	assert.Equal(t, int64(21), page.Total)
+	assert.Equal(t, 20, page.Skip)
+	assert.Equal(t, 10, page.Limit)
+	assert.Len(t, page.Data, 1)
+	assert.Equal(t, "0x15", page.Data[0].ID)
+	assert.Equal(t, "Sam", page.Data[0].Metadata["author"])
+}
+
+// TestSearchDocumentsPage_Defaults tests that negative skips and non-positive limits fall back to the defaults
+func TestSearchDocumentsPage_Defaults(t *testing.T) {
+	// Create a new mock controller
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockGraph := mocks.NewMockStore(ctrl)
+	svc := service.NewService(mockGraph)
+
+	// Set up expectations - the count query, then the page query
+	gomock.InOrder(
+		mockGraph.EXPECT().Query(gomock.Any(), gomock.Any(), gomock.Nil()).Return(nil, nil),
+		mockGraph.EXPECT().Query(gomock.Any(), gomock.Any(), gomock.Nil()).
+			DoAndReturn(func(_ context.Context, query string, _ map[string]interface{}) ([]map[string]interface{}, error) {
+				assert.Contains(t, query, "first: 100, offset: 0")
+				return nil, nil
+			}),
+	)
+
+	// Call the service
+	page, err := svc.SearchDocumentsPage(context.Background(), "graph", -3, 0)
+
+	// Assert the results
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), page.Total)
+	assert.Equal(t, 0, page.Skip)
+	assert.Equal(t, 100, page.Limit)
+	assert.Empty(t, page.Data)
+}
@@ -2,7 +2,11 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
+
+	"github.com/sammcj/mcp-graph/internal/graph"
 )
 
 // InitialiseSchema sets up the initial schema for the knowledge graph
@@ -26,10 +30,59 @@ func (s *Service) InitialiseSchema(ctx context.Context) error {
 		CREATE CONSTRAINT FOR (ev:Event) REQUIRE ev.id IS UNIQUE;
 	`
 
+	// Constraints added since the built-in schema was first released. They're applied separately, and only if they
+	// don't already exist, so that they're created on databases where the statements above fail because their
+	// indexes already exist.
+	constraintSchema := `
+		// Create constraints for portable UUIDs
+		CREATE CONSTRAINT IF NOT EXISTS FOR (d:Document) REQUIRE d.uuid IS UNIQUE;
+		CREATE CONSTRAINT IF NOT EXISTS FOR (c:Concept) REQUIRE c.uuid IS UNIQUE;
+
+		// Create a constraint for document title keys, which also stops concurrent FindOrCreateDocument calls
+		// creating the same document twice
+		CREATE CONSTRAINT IF NOT EXISTS FOR (d:Document) REQUIRE d.titleKey IS UNIQUE;
+	`
+
 	// Upsert schema
+	var errs []error
 	if err := s.graph.UpsertSchema(ctx, schema); err != nil {
-		return fmt.Errorf("failed to initialise schema: %w", err)
+		errs = append(errs, fmt.Errorf("failed to initialise schema: %w", err))
+	}
+
+	if s.graph.QueryLanguage() != graph.QueryLanguageDQL {
+		if err := s.graph.UpsertSchema(ctx, constraintSchema); err != nil {
+			errs = append(errs, fmt.Errorf("failed to create constraints: %w", err))
+		}
 	}
 
-	return nil
+	// Create the configured indexes even if the built-in schema couldn't be fully applied
+	if len(s.propertyIndexes) > 0 {
+		if err := s.graph.UpsertSchema(ctx, s.propertyIndexSchema()); err != nil {
+			errs = append(errs, fmt.Errorf("failed to create configured indexes: %w", err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// propertyIndexSchema returns the schema creating the configured property indexes in the store's language. Cypher
+// stores get a range index per label and property, created only if it doesn't already exist. Dgraph predicates
+// aren't scoped to a type, so DQL stores get an exact index per property, whatever its label.
+func (s *Service) propertyIndexSchema() string {
+	var schema strings.Builder
+	seen := make(map[string]bool)
+	for _, index := range s.propertyIndexes {
+		for _, property := range index.Properties {
+			if s.graph.QueryLanguage() == graph.QueryLanguageDQL {
+				if seen[property] {
+					continue
+				}
+				seen[property] = true
+				fmt.Fprintf(&schema, "<%s>: string @index(exact) .\n", property)
+				continue
+			}
+			fmt.Fprintf(&schema, "CREATE INDEX IF NOT EXISTS FOR (n:%s) ON (n.%s);\n", graph.QuoteCypherIdentifier(index.Label), graph.QuoteCypherIdentifier(property))
+		}
+	}
+	return schema.String()
 }
@@ -0,0 +1,76 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sammcj/mcp-graph/internal/graph"
+	"github.com/sammcj/mcp-graph/internal/mcp/mocks"
+	"github.com/sammcj/mcp-graph/internal/service"
+)
+
+// propertyIndexes are the configured indexes used by the schema tests; two labels share the name property
+var propertyIndexes = []service.PropertyIndex{
+	{Label: "Function", Properties: []string{"filePath", "name"}},
+	{Label: "Module`s", Properties: []string{"name"}},
+}
+
+// TestInitialiseSchema_PropertyIndexesCypher tests that Cypher stores get an index per configured label and property
+func TestInitialiseSchema_PropertyIndexesCypher(t *testing.T) {
+	// Create a new mock controller
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockGraph := mocks.NewMockStore(ctrl)
+	svc := service.NewService(mockGraph)
+	svc.SetPropertyIndexes(propertyIndexes)
+
+	// Set up expectations - the built-in schema and constraints, then the configured indexes
+	var schemas []string
+	mockGraph.EXPECT().QueryLanguage().Return(graph.QueryLanguageCypher).AnyTimes()
+	mockGraph.EXPECT().UpsertSchema(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, schema string) error {
+			schemas = append(schemas, schema)
+			return nil
+		}).Times(3)
+
+	// Call the service
+	err := svc.InitialiseSchema(context.Background())
+
+	// Assert the results
+	assert.NoError(t, err)
+	assert.Contains(t, schemas[1], "CREATE CONSTRAINT IF NOT EXISTS FOR (d:Document) REQUIRE d.uuid IS UNIQUE;")
+	assert.Equal(t, "CREATE INDEX IF NOT EXISTS FOR (n:`Function`) ON (n.`filePath`);\n"+
+		"CREATE INDEX IF NOT EXISTS FOR (n:`Function`) ON (n.`name`);\n"+
+		"CREATE INDEX IF NOT EXISTS FOR (n:`Module``s`) ON (n.`name`);\n", schemas[2])
+}
+
+// TestInitialiseSchema_PropertyIndexesDQL tests that DQL stores get one index per configured property
+func TestInitialiseSchema_PropertyIndexesDQL(t *testing.T) {
+	// Create a new mock controller
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockGraph := mocks.NewMockStore(ctrl)
+	svc := service.NewService(mockGraph)
+	svc.SetPropertyIndexes(propertyIndexes)
+
+	// Set up expectations - the built-in schema, then the configured indexes; the Cypher constraints are skipped
+	var schemas []string
+	mockGraph.EXPECT().QueryLanguage().Return(graph.QueryLanguageDQL).AnyTimes()
+	mockGraph.EXPECT().UpsertSchema(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, schema string) error {
+			schemas = append(schemas, schema)
+			return nil
+		}).Times(2)
+
+	// Call the service
+	err := svc.InitialiseSchema(context.Background())
+
+	// Assert the results
+	assert.NoError(t, err)
+	assert.Equal(t, "<filePath>: string @index(exact) .\n<name>: string @index(exact) .\n", schemas[1])
+}
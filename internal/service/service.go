@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/sammcj/mcp-graph/internal/graph"
 )
@@ -10,17 +11,25 @@ import (
 type KnowledgeManager interface {
 	// Document operations
 	CreateDocument(ctx context.Context, title, content string, metadata map[string]interface{}) (string, error)
+	FindOrCreateDocument(ctx context.Context, title, content string, metadata map[string]interface{}) (string, bool, error)
+	BatchCreateDocuments(ctx context.Context, docs []DocumentInput) ([]string, []error, error)
 	GetDocument(ctx context.Context, id string) (*Document, error)
+	GetDocumentByUUID(ctx context.Context, uuid string) (*Document, error)
 	UpdateDocument(ctx context.Context, id, title, content string, metadata map[string]interface{}) error
 	DeleteDocument(ctx context.Context, id string) error
+	LinkDocument(ctx context.Context, docID, targetID string, relationshipType string, properties map[string]interface{}) (string, error)
+	GetDocumentContext(ctx context.Context, id string) (*DocumentContext, error)
+	GetEntityDocuments(ctx context.Context, locator graph.EntityLocator) ([]EntityDocument, error)
 
 	// Concept operations
 	CreateConcept(ctx context.Context, name string, properties map[string]interface{}) (string, error)
 	GetConcept(ctx context.Context, id string) (*Concept, error)
+	GetConceptByUUID(ctx context.Context, uuid string) (*Concept, error)
 	LinkConcepts(ctx context.Context, fromID, toID string, relationshipType string, properties map[string]interface{}) (string, error)
 
 	// Search operations
 	SearchDocuments(ctx context.Context, query string) ([]*Document, error)
+	SearchDocumentsPage(ctx context.Context, query string, skip, limit int) (*DocumentPage, error)
 	SearchConcepts(ctx context.Context, query string) ([]*Concept, error)
 
 	// Schema operations
@@ -35,6 +44,48 @@ type Document struct {
 	Metadata map[string]interface{} `json:"metadata,omitempty"`
 }
 
+// defaultDocumentPageSize is the number of documents in a page of search results when no limit is given
+const defaultDocumentPageSize = 100
+
+// DocumentPage is one page of document search results
+type DocumentPage struct {
+	Data  []*Document `json:"data"`
+	Total int64       `json:"total"` // Number of matching documents across all pages
+	Skip  int         `json:"skip"`
+	Limit int         `json:"limit"`
+}
+
+// DocumentInput describes a document to create
+type DocumentInput struct {
+	Title    string                 `json:"title"`
+	Content  string                 `json:"content"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// DocumentContext is a document together with the concepts and entities it is linked to, for use as a
+// self-contained context card
+type DocumentContext struct {
+	Document *Document    `json:"document"`
+	Links    []LinkedNode `json:"links"`
+}
+
+// LinkedNode is a node a document links to, with the relationship that links them
+type LinkedNode struct {
+	RelationshipID   string                 `json:"relationshipId"`
+	RelationshipType string                 `json:"relationshipType"`
+	Properties       map[string]interface{} `json:"properties,omitempty"`
+	Node             graph.EntityDetails    `json:"node"`
+}
+
+// EntityDocument is a document linked to an entity (as created by LinkDocument), with the relationship that
+// links them
+type EntityDocument struct {
+	RelationshipID   string                 `json:"relationshipId"`
+	RelationshipType string                 `json:"relationshipType"`
+	Properties       map[string]interface{} `json:"properties,omitempty"`
+	Document         *Document              `json:"document"`
+}
+
 // Concept represents a concept in the knowledge graph
 type Concept struct {
 	ID         string                 `json:"id"`
@@ -44,7 +95,16 @@ type Concept struct {
 
 // Service implements the KnowledgeManager interface
 type Service struct {
-	graph graph.Store
+	graph           graph.Store
+	assignUUIDs     bool
+	assignTitleKeys bool
+	propertyIndexes []PropertyIndex
+}
+
+// PropertyIndex lists properties of entities with a label to index when the schema is initialised
+type PropertyIndex struct {
+	Label      string
+	Properties []string
 }
 
 // NewService creates a new knowledge manager service
@@ -53,3 +113,37 @@ func NewService(graph graph.Store) *Service {
 		graph: graph,
 	}
 }
+
+// SetAssignUUIDs enables or disables assigning a random uuid property to new documents and concepts.
+// Unlike the backend's element IDs, these are kept when the graph is exported and reimported elsewhere.
+func (s *Service) SetAssignUUIDs(enabled bool) {
+	s.assignUUIDs = enabled
+}
+
+// SetAssignTitleKeys enables or disables giving new and updated documents a titleKey property (see TitleKey), so
+// that FindOrCreateDocument finds them under titles that differ only in case or whitespace.
+func (s *Service) SetAssignTitleKeys(enabled bool) {
+	s.assignTitleKeys = enabled
+}
+
+// SetPropertyIndexes sets the properties InitialiseSchema indexes in addition to the built-in ones, e.g. the
+// filePath and name of Functions.
+func (s *Service) SetPropertyIndexes(indexes []PropertyIndex) {
+	s.propertyIndexes = indexes
+}
+
+// findNodeByUUID finds the node of the given type with the given uuid property
+func (s *Service) findNodeByUUID(ctx context.Context, nodeType graph.NodeType, uuid string) (map[string]interface{}, error) {
+	if uuid == "" {
+		return nil, fmt.Errorf("uuid is required")
+	}
+	filters := []graph.PropertyFilter{{Property: "uuid", Operator: graph.FilterOpEquals, Value: uuid}}
+	entities, err := s.graph.FindEntities(ctx, []string{string(nodeType)}, filters, 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(entities) == 0 {
+		return nil, fmt.Errorf("no %s found with uuid %s", nodeType, uuid)
+	}
+	return entities[0].Properties, nil
+}
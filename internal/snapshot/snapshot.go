@@ -0,0 +1,142 @@
+// Package snapshot stores graph snapshots as JSON files on the server, so that part of the graph can be
+// checkpointed before a risky change and restored afterwards.
+package snapshot
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/sammcj/mcp-graph/internal/graph"
+)
+
+// Errors returned by Store.
+var (
+	ErrInvalidName = errors.New("snapshot names may only contain letters, digits, '.', '_' and '-', and must start with a letter or digit")
+	ErrNotFound    = errors.New("snapshot not found")
+	ErrExists      = errors.New("snapshot already exists")
+)
+
+// namePattern matches valid snapshot names, which are used as file names
+var namePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,127}$`)
+
+// Store saves and loads snapshots as files in a directory, one file per snapshot.
+type Store struct {
+	dir string
+}
+
+// NewStore creates a store keeping snapshots in dir. The directory is created when the first snapshot is saved.
+func NewStore(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// Save writes a snapshot under its name. Unless overwrite is set, an existing snapshot with the same name
+// is left in place and ErrExists is returned.
+func (s *Store) Save(snapshot graph.GraphSnapshot, overwrite bool) error {
+	path, err := s.path(snapshot.Name)
+	if err != nil {
+		return err
+	}
+	if !overwrite {
+		if _, err := os.Stat(path); err == nil {
+			return fmt.Errorf("%w: %s", ErrExists, snapshot.Name)
+		}
+	}
+
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return fmt.Errorf("failed to marshal snapshot: %w", err)
+	}
+	if err := os.MkdirAll(s.dir, 0700); err != nil {
+		return fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+
+	// Write to a temporary file first so that a failed write can't leave a truncated snapshot behind
+	tmp, err := os.CreateTemp(s.dir, snapshot.Name+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create snapshot file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to save snapshot: %w", err)
+	}
+	return nil
+}
+
+// Load reads the snapshot with the given name. Numbers in properties are returned as int64 if they are whole and
+// float64 otherwise; the types recorded with the properties tell the store which were floats in the graph.
+func (s *Store) Load(name string) (graph.GraphSnapshot, error) {
+	path, err := s.path(name)
+	if err != nil {
+		return graph.GraphSnapshot{}, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return graph.GraphSnapshot{}, fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	if err != nil {
+		return graph.GraphSnapshot{}, fmt.Errorf("failed to read snapshot: %w", err)
+	}
+
+	var snapshot graph.GraphSnapshot
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&snapshot); err != nil {
+		return graph.GraphSnapshot{}, fmt.Errorf("failed to parse snapshot: %w", err)
+	}
+	for _, node := range snapshot.Nodes {
+		convertNumbers(node.Properties)
+	}
+	for _, rel := range snapshot.Relationships {
+		convertNumbers(rel.Properties)
+	}
+	return snapshot, nil
+}
+
+// path returns the file path for a snapshot name after checking the name is valid
+func (s *Store) path(name string) (string, error) {
+	if !namePattern.MatchString(name) {
+		return "", ErrInvalidName
+	}
+	return filepath.Join(s.dir, name+".json"), nil
+}
+
+// convertNumbers replaces json.Number values in properties, including inside lists and maps, with int64 or float64
+func convertNumbers(properties map[string]interface{}) {
+	for k, v := range properties {
+		properties[k] = convertNumber(v)
+	}
+}
+
+// convertNumber converts a json.Number to int64 if it is a whole number, or float64 otherwise
+func convertNumber(value interface{}) interface{} {
+	switch v := value.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	case []interface{}:
+		for i, item := range v {
+			v[i] = convertNumber(item)
+		}
+		return v
+	case map[string]interface{}:
+		convertNumbers(v)
+		return v
+	default:
+		return v
+	}
+}
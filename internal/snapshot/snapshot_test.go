@@ -0,0 +1,48 @@
+package snapshot
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sammcj/mcp-graph/internal/graph"
+)
+
+func TestStore_SaveAndLoad(t *testing.T) {
+	store := NewStore(t.TempDir())
+
+	snapshot := graph.GraphSnapshot{
+		Name:      "before-cleanup",
+		CreatedAt: time.Date(2024, 5, 1, 9, 30, 0, 0, time.UTC),
+		Labels:    []string{"Service"},
+		Nodes: []graph.SnapshotNode{
+			{ID: "4:abc:1", Labels: []string{"Service"}, Properties: map[string]interface{}{"name": "api", "port": int64(8080), "weight": 0.5, "ports": []interface{}{int64(80), int64(443)}}},
+		},
+		Relationships: []graph.SnapshotRelationship{
+			{Type: "CALLS", StartNode: "4:abc:1", EndNode: "4:abc:9", Properties: map[string]interface{}{"count": int64(3)}},
+		},
+	}
+	assert.NoError(t, store.Save(snapshot, false))
+
+	loaded, err := store.Load("before-cleanup")
+	assert.NoError(t, err)
+	assert.Equal(t, snapshot, loaded)
+
+	// Existing snapshots are only replaced when asked
+	assert.ErrorIs(t, store.Save(snapshot, false), ErrExists)
+	assert.NoError(t, store.Save(snapshot, true))
+}
+
+func TestStore_Errors(t *testing.T) {
+	store := NewStore(t.TempDir())
+
+	_, err := store.Load("missing")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	for _, name := range []string{"", "../escape", "a/b", ".hidden"} {
+		_, err := store.Load(name)
+		assert.ErrorIs(t, err, ErrInvalidName, name)
+		assert.ErrorIs(t, store.Save(graph.GraphSnapshot{Name: name}, true), ErrInvalidName, name)
+	}
+}
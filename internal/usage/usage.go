@@ -0,0 +1,141 @@
+// Package usage counts the operations each client calls, so that operators can see how clients use the server
+// and spot abuse.
+package usage
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+// bucketSize is the resolution of the counts; calls are grouped into buckets of this length, so the windows
+// reported by Stats are rounded to it
+const bucketSize = time.Minute
+
+// key identifies the calls counted together
+type key struct {
+	client    string
+	operation string
+}
+
+// aliasLength is the number of hex digits in client aliases
+const aliasLength = 12
+
+// bucket holds the calls made within bucketSize of start
+type bucket struct {
+	start  time.Time
+	counts map[key]int64
+}
+
+// Stats summarises the calls made within a window.
+type Stats struct {
+	Window     string                 `json:"window"`
+	Since      time.Time              `json:"since"`
+	Total      int64                  `json:"total"`
+	Clients    map[string]ClientStats `json:"clients,omitempty"` // By client alias (see Tracker.Alias)
+	Operations map[string]int64       `json:"operations"`        // Calls to each operation by all clients
+}
+
+// ClientStats summarises the calls made by a single client.
+type ClientStats struct {
+	Total      int64            `json:"total"`
+	Operations map[string]int64 `json:"operations"`
+}
+
+// Tracker counts calls in memory, keyed by client and operation (a tool name, or HTTP method and route), for a
+// retention period. Clients are only kept, and reported, by their alias, so that the stats don't reveal session IDs
+// or addresses. A nil Tracker discards calls.
+type Tracker struct {
+	mu        sync.Mutex
+	retention time.Duration
+	buckets   []*bucket // Oldest first
+	now       func() time.Time
+	aliasKey  []byte // Random, so that aliases can't be reversed by hashing guessed identities
+}
+
+// NewTracker creates a tracker keeping counts for the given retention period, which is the longest window Stats
+// can report on.
+func NewTracker(retention time.Duration) *Tracker {
+	aliasKey := make([]byte, sha256.Size)
+	_, _ = rand.Read(aliasKey)
+	return &Tracker{retention: retention, now: time.Now, aliasKey: aliasKey}
+}
+
+// Alias returns the opaque identifier client is reported by, which is stable for the life of the tracker.
+func (t *Tracker) Alias(client string) string {
+	mac := hmac.New(sha256.New, t.aliasKey)
+	mac.Write([]byte(client))
+	return hex.EncodeToString(mac.Sum(nil))[:aliasLength]
+}
+
+// Enabled reports whether calls are being counted.
+func (t *Tracker) Enabled() bool {
+	return t != nil
+}
+
+// Record counts a call to operation by client.
+func (t *Tracker) Record(client, operation string) {
+	if !t.Enabled() {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	t.prune(now)
+	start := now.Truncate(bucketSize)
+	if len(t.buckets) == 0 || !t.buckets[len(t.buckets)-1].start.Equal(start) {
+		t.buckets = append(t.buckets, &bucket{start: start, counts: make(map[key]int64)})
+	}
+	t.buckets[len(t.buckets)-1].counts[key{client: t.Alias(client), operation: operation}]++
+}
+
+// Stats summarises the calls made within window of now. Windows that are non-positive or longer than the
+// retention period are treated as the retention period.
+func (t *Tracker) Stats(window time.Duration) Stats {
+	if window <= 0 || window > t.retention {
+		window = t.retention
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	t.prune(now)
+	since := now.Add(-window).Truncate(bucketSize)
+	stats := Stats{
+		Window:     window.String(),
+		Since:      since,
+		Clients:    make(map[string]ClientStats),
+		Operations: make(map[string]int64),
+	}
+	for _, b := range t.buckets {
+		if b.start.Before(since) {
+			continue
+		}
+		for k, count := range b.counts {
+			client, ok := stats.Clients[k.client]
+			if !ok {
+				client.Operations = make(map[string]int64)
+			}
+			client.Total += count
+			client.Operations[k.operation] += count
+			stats.Clients[k.client] = client
+			stats.Operations[k.operation] += count
+			stats.Total += count
+		}
+	}
+	return stats
+}
+
+// prune drops the buckets that started before the retention period
+func (t *Tracker) prune(now time.Time) {
+	cutoff := now.Add(-t.retention).Truncate(bucketSize)
+	i := 0
+	for i < len(t.buckets) && t.buckets[i].start.Before(cutoff) {
+		i++
+	}
+	t.buckets = t.buckets[i:]
+}
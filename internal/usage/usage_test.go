@@ -0,0 +1,66 @@
+package usage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTracker_Stats(t *testing.T) {
+	now := time.Date(2024, 5, 1, 9, 30, 0, 0, time.UTC)
+	tracker := NewTracker(time.Hour)
+	tracker.now = func() time.Time { return now }
+
+	// Calls from two hours ago fall outside the retention period and are dropped
+	now = now.Add(-2 * time.Hour)
+	tracker.Record("session-a", "create_node")
+	now = now.Add(2 * time.Hour)
+
+	// Calls 30 minutes ago and now
+	now = now.Add(-30 * time.Minute)
+	tracker.Record("session-a", "search_nodes")
+	now = now.Add(30 * time.Minute)
+	tracker.Record("session-a", "search_nodes")
+	tracker.Record("session-a", "create_node")
+	tracker.Record("session-b", "search_nodes")
+
+	stats := tracker.Stats(0)
+	assert.Equal(t, "1h0m0s", stats.Window)
+	assert.Equal(t, now.Add(-time.Hour), stats.Since)
+	assert.Equal(t, int64(4), stats.Total)
+	assert.Equal(t, map[string]ClientStats{
+		tracker.Alias("session-a"): {Total: 3, Operations: map[string]int64{"search_nodes": 2, "create_node": 1}},
+		tracker.Alias("session-b"): {Total: 1, Operations: map[string]int64{"search_nodes": 1}},
+	}, stats.Clients)
+	assert.Equal(t, map[string]int64{"search_nodes": 3, "create_node": 1}, stats.Operations)
+
+	// A shorter window only counts the recent calls
+	stats = tracker.Stats(10 * time.Minute)
+	assert.Equal(t, "10m0s", stats.Window)
+	assert.Equal(t, int64(3), stats.Total)
+	assert.Equal(t, map[string]int64{"search_nodes": 2, "create_node": 1}, stats.Operations)
+
+	// Windows longer than the retention period are capped
+	assert.Equal(t, "1h0m0s", tracker.Stats(24*time.Hour).Window)
+}
+
+func TestTracker_Disabled(t *testing.T) {
+	var tracker *Tracker
+	assert.False(t, tracker.Enabled())
+	tracker.Record("session-a", "create_node") // Must not panic
+}
+
+func TestTracker_Alias(t *testing.T) {
+	tracker := NewTracker(time.Hour)
+
+	// Aliases are stable, distinct and don't contain the identity
+	alias := tracker.Alias("session-a")
+	assert.Len(t, alias, aliasLength)
+	assert.Equal(t, alias, tracker.Alias("session-a"))
+	assert.NotEqual(t, alias, tracker.Alias("session-b"))
+	assert.NotContains(t, alias, "session-a")
+
+	// Each tracker has its own aliases
+	assert.NotEqual(t, alias, NewTracker(time.Hour).Alias("session-a"))
+}